@@ -90,11 +90,27 @@ func main() {
 				cont.AbortHandler.Command(),
 				cont.QueryHandler.Command(),
 				cont.OutputsHandler.Command(),
+				cont.LogsHandler.Command(),
+				cont.VerifyOutputsHandler.Command(),
+				cont.CleanupHandler.Command(),
+				cont.StorageReportHandler.Command(),
 				cont.InputsHandler.Command(),
 				cont.ResourceReportHandler.Command(),
 				cont.DebugHandler.Command(),
 			},
 		},
+		{
+			Name:  "wdl",
+			Usage: "WDL source operations",
+			Subcommands: []*cli.Command{
+				cont.GraphHandler.Command(),
+				cont.SchemaHandler.Command(),
+				cont.WDLIndexHandler.Command(),
+				cont.WDLSearchHandler.Command(),
+				cont.WDLDocsHandler.Command(),
+				cont.WDLDiffHandler.Command(),
+			},
+		},
 		cont.BundleHandler.Command(),
 		cont.DashboardHandler.Command(),
 		cont.ChatHandler.Command(),
@@ -22,12 +22,17 @@ func New() *BundleUseCase {
 type Input struct {
 	MainWorkflowPath string
 	OutputPath       string
+	// Offline resolves http(s) imports from the import cache only.
+	Offline bool
+	// ImportCacheDir overrides where fetched imports are cached.
+	ImportCacheDir string
 }
 
 // Output represents the output of the bundle creation use case.
 type Output struct {
 	MainWDLPath         string
 	DependenciesZipPath string
+	LockfilePath        string
 	Dependencies        []string
 	TotalFiles          int
 }
@@ -47,7 +52,9 @@ func (uc *BundleUseCase) Execute(ctx context.Context, input Input) (*Output, err
 	}
 
 	// Use the wdl package to create the bundle
-	result, err := wdl.CreateBundle(input.MainWorkflowPath, input.OutputPath)
+	opts := wdl.DefaultBundleOptions()
+	opts.Remote = wdl.RemoteImportOptions{CacheDir: input.ImportCacheDir, Offline: input.Offline}
+	result, err := wdl.CreateBundleWithOptions(input.MainWorkflowPath, input.OutputPath, opts)
 	if err != nil {
 		return nil, application.NewUseCaseError(
 			"bundle",
@@ -60,6 +67,7 @@ func (uc *BundleUseCase) Execute(ctx context.Context, input Input) (*Output, err
 	return &Output{
 		MainWDLPath:         result.MainWDLPath,
 		DependenciesZipPath: result.DependenciesZipPath,
+		LockfilePath:        result.LockfilePath,
 		Dependencies:        result.Dependencies,
 		TotalFiles:          result.TotalFiles,
 	}, nil
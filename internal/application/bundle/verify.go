@@ -0,0 +1,94 @@
+package bundle
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/pkg/wdl"
+)
+
+// VerifyUseCase checks a bundle, or what a workflow was submitted with,
+// against the source tree it should have been built from.
+type VerifyUseCase struct {
+	files  ports.FileProvider
+	reader ports.WorkflowMetadataReader
+}
+
+// NewVerify creates a new bundle verification use case.
+func NewVerify(files ports.FileProvider, reader ports.WorkflowMetadataReader) *VerifyUseCase {
+	return &VerifyUseCase{files: files, reader: reader}
+}
+
+// VerifyInput represents the input for verifying a bundle.
+type VerifyInput struct {
+	// MainWorkflowPath is the main workflow in the source tree.
+	MainWorkflowPath string
+	// BundlePath is a dependencies ZIP to check.
+	BundlePath string
+	// WorkflowID checks the files a submitted workflow ran with instead.
+	WorkflowID string
+	// Offline resolves http(s) imports from the import cache only.
+	Offline bool
+	// ImportCacheDir overrides where fetched imports are cached.
+	ImportCacheDir string
+}
+
+// VerifyOutput represents the result of verifying a bundle.
+type VerifyOutput struct {
+	// Against names what was checked: the ZIP path or the workflow ID.
+	Against string
+	// Lock describes the bundle the sources produce.
+	Lock   *wdl.BundleLock
+	Result *wdl.BundleVerification
+}
+
+// Execute rebuilds the bundle from the sources in memory and compares it,
+// file by file, with the ZIP or the submitted workflow.
+func (uc *VerifyUseCase) Execute(ctx context.Context, input VerifyInput) (*VerifyOutput, error) {
+	if input.MainWorkflowPath == "" {
+		return nil, application.NewInputValidationError("mainWorkflowPath", "is required")
+	}
+	if (input.BundlePath == "") == (input.WorkflowID == "") {
+		return nil, application.NewInputValidationError("bundlePath", "give either a bundle ZIP or a workflow ID")
+	}
+
+	opts := wdl.DefaultBundleOptions()
+	opts.Remote = wdl.RemoteImportOptions{CacheDir: input.ImportCacheDir, Offline: input.Offline}
+	expected, err := wdl.AssembleBundle(input.MainWorkflowPath, opts)
+	if err != nil {
+		return nil, application.NewUseCaseError("bundle verify", "failed to build the bundle from sources", err)
+	}
+
+	output := &VerifyOutput{Lock: expected.Lock}
+	if input.BundlePath != "" {
+		data, err := uc.files.ReadBytes(ctx, input.BundlePath)
+		if err != nil {
+			return nil, application.NewUseCaseError("bundle verify", "failed to read bundle", err)
+		}
+		deps, err := wdl.BundleZipFiles(data)
+		if err != nil {
+			return nil, application.NewUseCaseError("bundle verify", "failed to read bundle", err)
+		}
+		output.Against = input.BundlePath
+		output.Result = wdl.VerifyBundle(expected, nil, deps)
+		return output, nil
+	}
+
+	wf, err := uc.reader.GetMetadata(ctx, input.WorkflowID)
+	if err != nil {
+		return nil, application.NewUseCaseError("bundle verify", "failed to get workflow metadata", err)
+	}
+	// Cromwell keys imports by their path in the zip; bundles are flat, so
+	// the basename is the bundle name.
+	deps := make(map[string][]byte, len(wf.SubmittedImports))
+	for path, content := range wf.SubmittedImports {
+		if filepath.Ext(path) == ".wdl" {
+			deps[filepath.Base(path)] = []byte(content)
+		}
+	}
+	output.Against = input.WorkflowID
+	output.Result = wdl.VerifyBundle(expected, []byte(wf.SubmittedWorkflow), deps)
+	return output, nil
+}
@@ -0,0 +1,88 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+type fakeMetadataReader struct {
+	wf *workflow.Workflow
+}
+
+func (f fakeMetadataReader) GetMetadata(ctx context.Context, workflowID string) (*workflow.Workflow, error) {
+	return f.wf, nil
+}
+
+func writeVerifySources(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "tasks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	main := "version 1.0\nimport \"tasks/t.wdl\" as t\nworkflow W {\n    call t.T {}\n}\n"
+	task := "version 1.0\ntask T {\n    command { echo hi }\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "main.wdl"), []byte(main), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tasks", "t.wdl"), []byte(task), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "main.wdl")
+}
+
+func TestVerifyUseCase_Execute_Validation(t *testing.T) {
+	uc := NewVerify(nil, nil)
+	for _, input := range []VerifyInput{
+		{BundlePath: "deps.zip"},
+		{MainWorkflowPath: "main.wdl"},
+		{MainWorkflowPath: "main.wdl", BundlePath: "deps.zip", WorkflowID: "abc"},
+	} {
+		if _, err := uc.Execute(context.Background(), input); !errors.Is(err, application.ErrInvalidInput) {
+			t.Errorf("Execute(%+v) error = %v, want ErrInvalidInput", input, err)
+		}
+	}
+}
+
+func TestVerifyUseCase_Execute_SubmittedWorkflow(t *testing.T) {
+	mainPath := writeVerifySources(t)
+
+	tests := []struct {
+		name    string
+		main    string
+		imports map[string]string
+		wantOK  bool
+	}{
+		{
+			name:    "submitted from this tree",
+			main:    "version 1.0\nimport \"t.wdl\" as t\nworkflow W {\n    call t.T {}\n}\n",
+			imports: map[string]string{"t.wdl": "version 1.0\ntask T {\n    command { echo hi }\n}\n"},
+			wantOK:  true,
+		},
+		{
+			name:    "task edited after submission",
+			main:    "version 1.0\nimport \"t.wdl\" as t\nworkflow W {\n    call t.T {}\n}\n",
+			imports: map[string]string{"t.wdl": "version 1.0\ntask T {\n    command { echo bye }\n}\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := fakeMetadataReader{wf: &workflow.Workflow{SubmittedWorkflow: tt.main, SubmittedImports: tt.imports}}
+			out, err := NewVerify(nil, reader).Execute(context.Background(), VerifyInput{
+				MainWorkflowPath: mainPath,
+				WorkflowID:       "wf-1",
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if out.Result.OK() != tt.wantOK {
+				t.Errorf("OK() = %v, differences %+v", out.Result.OK(), out.Result.Differences)
+			}
+		})
+	}
+}
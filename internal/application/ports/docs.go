@@ -0,0 +1,18 @@
+package ports
+
+// DocsPageData is one documentation page to render.
+type DocsPageData struct {
+	Title string
+	// IndexLink is the relative link from this page to the index page.
+	IndexLink string
+	Markdown  []byte
+	// Mermaid loads Mermaid from a CDN when the page is viewed, to draw the
+	// diagrams. Without it they are shown as their source.
+	Mermaid bool
+}
+
+// DocsHTMLRenderer turns a Markdown documentation page into a standalone HTML
+// page.
+type DocsHTMLRenderer interface {
+	RenderHTML(page DocsPageData) ([]byte, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// RevisionReader reads files as they were at a revision of a version control
+// repository, such as a git commit, branch or tag.
+type RevisionReader interface {
+	// ReadFile returns the content of path at rev.
+	ReadFile(ctx context.Context, rev, path string) ([]byte, error)
+	// ListFiles lists the files under dir at rev, as paths relative to dir.
+	ListFiles(ctx context.Context, rev, dir string) ([]string, error)
+}
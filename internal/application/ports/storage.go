@@ -1,6 +1,9 @@
 package ports
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // FileProvider defines the interface for reading file contents.
 // Implementations may support local files, cloud storage (GCS, S3), or other sources.
@@ -40,6 +43,41 @@ type FileDigests struct {
 	CRC32C string
 }
 
+// FileLister lists the contents of a directory. It is kept apart from
+// FileProvider because most consumers only ever read paths they were given.
+type FileLister interface {
+	// List returns the entries directly under a directory — files and
+	// subdirectories, not their contents — sorted by path. In object stores
+	// a "directory" is a common prefix up to the next "/".
+	List(ctx context.Context, prefix string) ([]FileEntry, error)
+}
+
+// RangeReader reads part of a file. Like FileLister it is kept apart from
+// FileProvider; it serves files too large for Read, such as multi-gigabyte
+// task logs read a page at a time.
+type RangeReader interface {
+	// ReadRange returns up to length bytes of a file starting at offset.
+	// Fewer bytes are returned at the end of the file, and none past it.
+	ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error)
+}
+
+// FileDeleter deletes files. Like FileLister it is kept apart from
+// FileProvider; only cleanup of a finished workflow ever removes anything.
+type FileDeleter interface {
+	// Delete removes a file. A file that is already gone is not an error.
+	Delete(ctx context.Context, path string) error
+}
+
+// FileEntry is one item of a directory listing.
+type FileEntry struct {
+	// Path is the full path, in the form Read accepts ("gs://b/run/x.fq").
+	Path string
+	// Size is the file size in bytes; zero for a directory.
+	Size int64
+	// IsDir marks a subdirectory (a common prefix in an object store).
+	IsDir bool
+}
+
 // FileSizeCache defines the interface for caching file sizes.
 // Implementations may persist the cache to disk or other storage.
 type FileSizeCache interface {
@@ -56,6 +94,31 @@ type FileSizeCache interface {
 	Set(path string, size int64)
 }
 
+// OutputBaseline records what workflow output files looked like when they
+// were last seen intact, so a later check can tell a changed file from one
+// that was always like this. Implementations may persist it to disk.
+type OutputBaseline interface {
+	// Load hydrates the baseline from its persistent storage.
+	Load() error
+
+	// Save persists the baseline to its storage.
+	Save() error
+
+	// Get returns the record for a file path and whether there is one.
+	Get(path string) (OutputRecord, bool)
+
+	// Set records a file path.
+	Set(path string, record OutputRecord)
+}
+
+// OutputRecord is what a file was like when it was recorded in an
+// OutputBaseline.
+type OutputRecord struct {
+	Size       int64
+	Digests    FileDigests
+	RecordedAt time.Time
+}
+
 // StorageBackend defines the interface for individual storage backends.
 // Each implementation handles a specific storage type (local, GCS, S3, etc.)
 // This follows the Strategy Pattern, allowing new backends to be added
@@ -78,4 +141,17 @@ type StorageBackend interface {
 	// GetContentDigests returns the file's content fingerprints, or
 	// ErrHashUnavailable when this backend cannot supply any.
 	GetContentDigests(ctx context.Context, path string) (FileDigests, error)
+
+	// List returns the entries directly under a directory, sorted by path.
+	// A directory that does not exist is ErrFileNotFound.
+	List(ctx context.Context, prefix string) ([]FileEntry, error)
+
+	// ReadRange returns up to length bytes of a file starting at offset,
+	// without the size limit of Read. Cloud backends issue a ranged request
+	// rather than downloading the object.
+	ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error)
+
+	// Delete removes a file. A file that is already gone is not an error;
+	// backends that cannot write return an error.
+	Delete(ctx context.Context, path string) error
 }
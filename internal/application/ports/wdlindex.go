@@ -5,18 +5,19 @@ import "github.com/lmtani/pumbaa/internal/domain/wdlindex"
 // WDLRepository defines the interface for WDL workflow indexing operations.
 // This port allows querying and searching through indexed WDL tasks and workflows.
 type WDLRepository interface {
-	// List returns the complete index of tasks and workflows.
+	// List returns the complete index of files, tasks, workflows and structs.
 	List() (*wdlindex.Index, error)
 
-	// SearchByName searches for tasks and workflows by name.
-	SearchByName(query string) (*wdlindex.Index, error)
-
-	// SearchByCommand searches for tasks by command content.
-	SearchByCommand(query string) ([]*wdlindex.IndexedTask, error)
+	// Search ranks the tasks, workflows and structs matching a free-text
+	// query, best first, with snippets of where it matched.
+	Search(query string, opts wdlindex.SearchOptions) ([]wdlindex.SearchHit, error)
 
 	// GetTask retrieves a specific task by name.
 	GetTask(name string) (*wdlindex.IndexedTask, error)
 
 	// GetWorkflow retrieves a specific workflow by name.
 	GetWorkflow(name string) (*wdlindex.IndexedWorkflow, error)
+
+	// Stats reports how the index was brought up to date when it was opened.
+	Stats() wdlindex.UpdateStats
 }
@@ -83,7 +83,7 @@ func (uc *CacheForecastUseCase) doneReporting() {
 // CacheForecastInput describes the submission to forecast.
 type CacheForecastInput struct {
 	WorkflowFile string
-	InputsFile   string
+	Inputs       InputSources
 	// DependenciesFile is an imports zip. Without it, imports are resolved
 	// from WDL files sitting next to the workflow, and anything still missing
 	// is reported as undetermined rather than assumed unchanged.
@@ -107,7 +107,7 @@ func (uc *CacheForecastUseCase) Execute(ctx context.Context, input CacheForecast
 		return nil, application.NewUseCaseError("cache forecast", "failed to read workflow file", err)
 	}
 
-	deps, depErr := uc.resolveImportSources(ctx, input)
+	deps, depErr := resolveImportSources(ctx, uc.files, input.WorkflowFile, input.DependenciesFile)
 
 	graph, err := wdl.BuildCallGraphWithSources(source, deps)
 	if err != nil {
@@ -118,7 +118,11 @@ func (uc *CacheForecastUseCase) Execute(ctx context.Context, input CacheForecast
 		specs = s
 	}
 
-	pendingInputs, err := uc.readInputs(ctx, input.InputsFile)
+	inputsData, err := assembleInputs(ctx, uc.files, input.Inputs, source, deps)
+	if err != nil {
+		return nil, application.NewUseCaseError("cache forecast", "failed to read inputs file", err)
+	}
+	pendingInputs, err := parsePendingInputs(inputsData)
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +133,7 @@ func (uc *CacheForecastUseCase) Execute(ctx context.Context, input CacheForecast
 		return nil, err
 	}
 
-	forecast := &domain.CacheForecast{Reference: reference.ID}
+	forecast := &domain.CacheForecast{Reference: reference.ID, Inputs: inputsData}
 	if depErr != "" {
 		forecast.Warnings = append(forecast.Warnings, depErr)
 	}
@@ -408,14 +412,11 @@ func (uc *CacheForecastUseCase) fetchReference(ctx context.Context, id string) (
 	return uc.reader.GetMetadata(ctx, id)
 }
 
-func (uc *CacheForecastUseCase) readInputs(ctx context.Context, path string) (map[string]any, error) {
-	if path == "" {
+// parsePendingInputs decodes the assembled inputs of the pending submission.
+func parsePendingInputs(data []byte) (map[string]any, error) {
+	if len(data) == 0 {
 		return map[string]any{}, nil
 	}
-	data, err := uc.files.ReadBytes(ctx, path)
-	if err != nil {
-		return nil, application.NewUseCaseError("cache forecast", "failed to read inputs file", err)
-	}
 	var out map[string]any
 	if err := json.Unmarshal(data, &out); err != nil {
 		return nil, application.NewUseCaseError("cache forecast", "inputs file is not valid JSON", err)
@@ -609,11 +610,11 @@ func unresolvedCalls(graph *wdl.CallGraph) []string {
 // resolveImportSources gathers the WDL sources needed to see inside imports:
 // the dependencies zip when given, otherwise the WDL files sitting beside the
 // workflow, which is how a workflow run from a checkout resolves. It returns a
-// warning string rather than an error — a forecast without imports is degraded,
-// not impossible.
-func (uc *CacheForecastUseCase) resolveImportSources(ctx context.Context, input CacheForecastInput) (wdl.SourceSet, string) {
-	if input.DependenciesFile != "" {
-		data, err := uc.files.ReadBytes(ctx, input.DependenciesFile)
+// warning string rather than an error — a graph or forecast without imports is
+// degraded, not impossible.
+func resolveImportSources(ctx context.Context, files ports.FileProvider, workflowFile, dependenciesFile string) (wdl.SourceSet, string) {
+	if dependenciesFile != "" {
+		data, err := files.ReadBytes(ctx, dependenciesFile)
 		if err != nil {
 			return nil, fmt.Sprintf("could not read the dependencies zip: %v", err)
 		}
@@ -625,10 +626,10 @@ func (uc *CacheForecastUseCase) resolveImportSources(ctx context.Context, input
 	}
 
 	// Only local paths have a directory to scan; a remote WDL must be bundled.
-	if strings.Contains(input.WorkflowFile, "://") {
+	if strings.Contains(workflowFile, "://") {
 		return nil, ""
 	}
-	sources, err := wdl.SourcesFromDir(filepath.Dir(input.WorkflowFile))
+	sources, err := wdl.SourcesFromDir(filepath.Dir(workflowFile))
 	if err != nil {
 		return nil, ""
 	}
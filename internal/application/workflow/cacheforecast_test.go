@@ -185,7 +185,7 @@ func run(t *testing.T, env forecastEnv) *domain.CacheForecast {
 	t.Helper()
 	got, err := env.uc.Execute(context.Background(), CacheForecastInput{
 		WorkflowFile: env.wdlPath,
-		InputsFile:   env.inputPath,
+		Inputs:       InputSources{Files: []string{env.inputPath}},
 		ReferenceID:  "ref-run-id",
 	})
 	if err != nil {
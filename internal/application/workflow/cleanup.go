@@ -0,0 +1,258 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// cleanupParallelism bounds how many files are deleted at once. Each is a
+// single small request, so the cap is about staying polite to the storage
+// API.
+const cleanupParallelism = 16
+
+// CleanupFile is an intermediate file a cleanup would delete.
+type CleanupFile struct {
+	Path string
+	Size int64
+	// Task is the call whose root holds the file; empty for a file outside
+	// every call root.
+	Task string
+}
+
+// TaskReclaim sums the intermediate files of one task.
+type TaskReclaim struct {
+	Task  string
+	Files int
+	Bytes int64
+}
+
+// CleanupPlan lists what a cleanup of a finished workflow would delete:
+// every file under the workflow root that is not a final output.
+type CleanupPlan struct {
+	WorkflowID   string
+	WorkflowName string
+	WorkflowRoot string
+	Files        []CleanupFile
+	// Tasks sums Files per task, largest first.
+	Tasks []TaskReclaim
+	// Bytes is the total size of Files.
+	Bytes int64
+	// KeptFiles and KeptBytes count the final outputs found under the root.
+	KeptFiles int
+	KeptBytes int64
+}
+
+// CleanupFailure is a file that could not be deleted.
+type CleanupFailure struct {
+	Path string
+	Err  error
+}
+
+// CleanupResult is the outcome of applying a CleanupPlan.
+type CleanupResult struct {
+	Deleted int
+	Bytes   int64
+	Failed  []CleanupFailure
+}
+
+// CleanupUseCase finds and deletes the intermediate files of finished
+// workflows. It needs a file provider that can list and delete; with one
+// that cannot, Plan fails.
+type CleanupUseCase struct {
+	reader   ports.WorkflowMetadataReader
+	lister   ports.FileLister
+	deleter  ports.FileDeleter
+	progress ports.ProgressReporter
+	mu       sync.Mutex // serializes progress reports from concurrent work
+}
+
+// NewCleanupUseCase creates a new cleanup use case. progress may be nil.
+func NewCleanupUseCase(reader ports.WorkflowMetadataReader, fp ports.FileProvider, progress ports.ProgressReporter) *CleanupUseCase {
+	uc := &CleanupUseCase{reader: reader, progress: progress}
+	uc.lister, _ = fp.(ports.FileLister)
+	uc.deleter, _ = fp.(ports.FileDeleter)
+	return uc
+}
+
+// ErrCleanupNotSucceeded is returned by Plan for a Failed or Aborted
+// workflow when AllowFailed is not set.
+var ErrCleanupNotSucceeded = errors.New("its outputs are incomplete, so the results of its finished calls and its logs would be deleted too")
+
+// CleanupInput represents the input for planning a cleanup.
+type CleanupInput struct {
+	WorkflowID string
+	// AllowFailed lets a Failed or Aborted workflow be cleaned up. Their
+	// outputs are missing or partial, so nearly everything under the root,
+	// including the results of the calls that did finish and the logs of the
+	// failure, would be planned for deletion.
+	AllowFailed bool
+}
+
+// Plan lists the files under a finished workflow's root that are not among
+// its final outputs, grouped by the task whose call root holds them. Only a
+// workflow that succeeded is planned unless AllowFailed is set. Nothing is
+// deleted.
+func (uc *CleanupUseCase) Plan(ctx context.Context, input CleanupInput) (*CleanupPlan, error) {
+	workflowID := input.WorkflowID
+	if workflowID == "" {
+		return nil, application.NewInputValidationError("workflowID", "is required")
+	}
+	if uc.lister == nil || uc.deleter == nil {
+		return nil, application.NewUseCaseError("cleanup", "storage cannot list or delete files", nil)
+	}
+
+	wf, err := uc.reader.GetMetadata(ctx, workflowID)
+	if err != nil {
+		return nil, application.NewUseCaseError("cleanup", "failed to get workflow metadata", err)
+	}
+	if !wf.IsTerminal() {
+		return nil, application.NewUseCaseError("cleanup", fmt.Sprintf("workflow is %s; only finished workflows can be cleaned up", wf.Status), nil)
+	}
+	if wf.Status != workflow.StatusSucceeded && !input.AllowFailed {
+		return nil, application.NewUseCaseError("cleanup", fmt.Sprintf("workflow is %s", wf.Status), ErrCleanupNotSucceeded)
+	}
+	if wf.WorkflowRoot == "" {
+		return nil, application.NewUseCaseError("cleanup", "workflow has no root directory", nil)
+	}
+
+	root := strings.TrimSuffix(wf.WorkflowRoot, "/")
+	files, err := listTree(ctx, uc.lister, root, func(n int) {
+		uc.step("Listed %d files under %s", n, root)
+	})
+	uc.doneReporting()
+	if err != nil {
+		return nil, application.NewUseCaseError("cleanup", "failed to list workflow root", err)
+	}
+
+	keep := make(map[string]bool)
+	for _, value := range wf.Outputs {
+		collectStrings(value, keep)
+	}
+	callRoots := callRootTasks(wf)
+
+	plan := &CleanupPlan{WorkflowID: wf.ID, WorkflowName: wf.Name, WorkflowRoot: root}
+	byTask := make(map[string]*TaskReclaim)
+	for _, f := range files {
+		if isKept(f.Path, root, keep) {
+			plan.KeptFiles++
+			plan.KeptBytes += f.Size
+			continue
+		}
+		file := CleanupFile{Path: f.Path, Size: f.Size, Task: taskOf(f.Path, callRoots)}
+		plan.Files = append(plan.Files, file)
+		plan.Bytes += file.Size
+		t := byTask[file.Task]
+		if t == nil {
+			t = &TaskReclaim{Task: file.Task}
+			byTask[file.Task] = t
+		}
+		t.Files++
+		t.Bytes += file.Size
+	}
+	for _, t := range byTask {
+		plan.Tasks = append(plan.Tasks, *t)
+	}
+	sort.Slice(plan.Tasks, func(i, j int) bool {
+		if plan.Tasks[i].Bytes != plan.Tasks[j].Bytes {
+			return plan.Tasks[i].Bytes > plan.Tasks[j].Bytes
+		}
+		return plan.Tasks[i].Task < plan.Tasks[j].Task
+	})
+	return plan, nil
+}
+
+// Apply deletes the files of a plan. A file that fails to delete is
+// recorded and does not stop the others.
+func (uc *CleanupUseCase) Apply(ctx context.Context, plan *CleanupPlan) (*CleanupResult, error) {
+	if uc.deleter == nil {
+		return nil, application.NewUseCaseError("cleanup", "storage cannot delete files", nil)
+	}
+
+	result := &CleanupResult{}
+	var mu sync.Mutex
+	var done atomic.Int32
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(cleanupParallelism)
+	for _, f := range plan.Files {
+		group.Go(func() error {
+			err := uc.deleter.Delete(groupCtx, f.Path)
+			mu.Lock()
+			if err != nil {
+				result.Failed = append(result.Failed, CleanupFailure{Path: f.Path, Err: err})
+			} else {
+				result.Deleted++
+				result.Bytes += f.Size
+			}
+			mu.Unlock()
+			uc.step("Deleted %d/%d files", done.Add(1), len(plan.Files))
+			return nil
+		})
+	}
+	_ = group.Wait()
+	uc.doneReporting()
+
+	sort.Slice(result.Failed, func(i, j int) bool { return result.Failed[i].Path < result.Failed[j].Path })
+	return result, ctx.Err()
+}
+
+// collectStrings adds every string in an output value to set, verbatim,
+// descending into arrays, maps, pairs and structs. Deciding what to keep must
+// not depend on what looks like a path: a file output without an extension
+// is still a file.
+func collectStrings(value any, set map[string]bool) {
+	switch v := value.(type) {
+	case string:
+		set[strings.TrimSuffix(v, "/")] = true
+	case []any:
+		for _, item := range v {
+			collectStrings(item, set)
+		}
+	case map[string]any:
+		for _, item := range v {
+			collectStrings(item, set)
+		}
+	}
+}
+
+// isKept reports whether path is an output, or lies inside a directory
+// output, checking each of its parents up to root. Local paths listed on
+// Windows separate with a backslash.
+func isKept(path, root string, keep map[string]bool) bool {
+	for p := path; len(p) > len(root); {
+		if keep[p] {
+			return true
+		}
+		i := strings.LastIndexAny(p, `/\`)
+		if i < 0 {
+			break
+		}
+		p = p[:i]
+	}
+	return false
+}
+
+func (uc *CleanupUseCase) step(format string, args ...any) {
+	if uc.progress == nil {
+		return
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.progress.Step(format, args...)
+}
+
+func (uc *CleanupUseCase) doneReporting() {
+	if uc.progress != nil {
+		uc.progress.Done()
+	}
+}
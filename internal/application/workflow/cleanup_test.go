@@ -0,0 +1,216 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// treeFiles is an object store held as a map of paths to sizes, listed one
+// level at a time like a bucket with a "/" delimiter.
+type treeFiles struct {
+	mockFileProvider
+	mu      sync.Mutex
+	sizes   map[string]int64
+	failing string // a path whose deletion fails
+}
+
+func (f *treeFiles) List(_ context.Context, prefix string) ([]ports.FileEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	dirs := make(map[string]bool)
+	var entries []ports.FileEntry
+	for path, size := range f.sizes {
+		rest, ok := strings.CutPrefix(path, prefix+"/")
+		if !ok {
+			continue
+		}
+		if dir, _, nested := strings.Cut(rest, "/"); nested {
+			if !dirs[dir] {
+				dirs[dir] = true
+				entries = append(entries, ports.FileEntry{Path: prefix + "/" + dir, IsDir: true})
+			}
+			continue
+		}
+		entries = append(entries, ports.FileEntry{Path: path, Size: size})
+	}
+	if len(entries) == 0 {
+		return nil, ports.ErrFileNotFound
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+func (f *treeFiles) Delete(_ context.Context, path string) error {
+	if path == f.failing {
+		return errors.New("permission denied")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.sizes, path)
+	return nil
+}
+
+func TestCleanupUseCase(t *testing.T) {
+	const root = "gs://b/wf/run-1"
+	files := &treeFiles{sizes: map[string]int64{
+		root + "/call-Align/shard-0/execution/out.bam":  100,
+		root + "/call-Align/shard-0/execution/tmp.sam":  400,
+		root + "/call-Align/shard-0/execution/stderr":   1,
+		root + "/call-Align/shard-1/execution/out.bam":  100,
+		root + "/call-Align/shard-1/execution/tmp.sam":  400,
+		root + "/call-Merge/execution/merged.bam":       200,
+		root + "/call-Merge/execution/script":           2,
+		root + "/call-Sub/Sub/sub-1/call-X/execution/x": 50,
+		root + "/workflow.log":                          3,
+	}, failing: root + "/call-Merge/execution/script"}
+	wf := &workflow.Workflow{
+		ID: "run-1", Name: "wf", Status: workflow.StatusSucceeded, WorkflowRoot: root + "/",
+		Outputs: map[string]any{
+			"wf.bams":   []any{root + "/call-Align/shard-0/execution/out.bam", root + "/call-Align/shard-1/execution/out.bam"},
+			"wf.merged": root + "/call-Merge/execution/merged.bam",
+		},
+		Calls: map[string][]workflow.Call{
+			"wf.Align": {
+				{ShardIndex: 0, CallRoot: root + "/call-Align/shard-0"},
+				{ShardIndex: 1, CallRoot: root + "/call-Align/shard-1"},
+			},
+			"wf.Merge": {{ShardIndex: -1, CallRoot: root + "/call-Merge"}},
+			"wf.Sub":   {{ShardIndex: -1, CallRoot: root + "/call-Sub"}},
+		},
+	}
+	repo := &mockWorkflowRepository{getMetadataFunc: func(context.Context, string) (*workflow.Workflow, error) {
+		return wf, nil
+	}}
+	uc := NewCleanupUseCase(repo, files, nil)
+	ctx := context.Background()
+
+	plan, err := uc.Plan(ctx, CleanupInput{WorkflowID: "run-1"})
+	if err != nil {
+		t.Fatalf("Plan() unexpected error: %v", err)
+	}
+	if plan.KeptFiles != 3 || plan.KeptBytes != 400 {
+		t.Errorf("kept %d files (%d bytes), want the 3 outputs (400 bytes)", plan.KeptFiles, plan.KeptBytes)
+	}
+	if len(plan.Files) != 6 || plan.Bytes != 856 {
+		t.Errorf("plan deletes %d files (%d bytes), want 6 (856 bytes)", len(plan.Files), plan.Bytes)
+	}
+	wantTasks := []TaskReclaim{
+		{Task: "wf.Align", Files: 3, Bytes: 801},
+		{Task: "wf.Sub", Files: 1, Bytes: 50},
+		{Task: "", Files: 1, Bytes: 3},
+		{Task: "wf.Merge", Files: 1, Bytes: 2},
+	}
+	if len(plan.Tasks) != len(wantTasks) {
+		t.Fatalf("Tasks = %+v, want %+v", plan.Tasks, wantTasks)
+	}
+	for i, want := range wantTasks {
+		if plan.Tasks[i] != want {
+			t.Errorf("Tasks[%d] = %+v, want %+v", i, plan.Tasks[i], want)
+		}
+	}
+	if len(files.sizes) != 9 {
+		t.Fatal("Plan() deleted files")
+	}
+
+	result, err := uc.Apply(ctx, plan)
+	if err != nil {
+		t.Fatalf("Apply() unexpected error: %v", err)
+	}
+	if result.Deleted != 5 || result.Bytes != 854 {
+		t.Errorf("deleted %d files (%d bytes), want 5 (854 bytes)", result.Deleted, result.Bytes)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Path != files.failing {
+		t.Errorf("Failed = %+v, want only %s", result.Failed, files.failing)
+	}
+	for _, out := range []string{"/call-Align/shard-0/execution/out.bam", "/call-Align/shard-1/execution/out.bam", "/call-Merge/execution/merged.bam"} {
+		if _, ok := files.sizes[root+out]; !ok {
+			t.Errorf("final output %s was deleted", out)
+		}
+	}
+
+	// A workflow still running may yet need its intermediate files.
+	wf.Status = workflow.StatusRunning
+	if _, err := uc.Plan(ctx, CleanupInput{WorkflowID: "run-1"}); err == nil {
+		t.Error("Plan() of a running workflow succeeded, want an error")
+	}
+
+	// A failed workflow's outputs are partial: cleaning it up takes consent.
+	wf.Status = workflow.StatusFailed
+	if _, err := uc.Plan(ctx, CleanupInput{WorkflowID: "run-1"}); !errors.Is(err, ErrCleanupNotSucceeded) {
+		t.Errorf("Plan() of a failed workflow error = %v, want ErrCleanupNotSucceeded", err)
+	}
+	if _, err := uc.Plan(ctx, CleanupInput{WorkflowID: "run-1", AllowFailed: true}); err != nil {
+		t.Errorf("Plan() of a failed workflow with AllowFailed error = %v", err)
+	}
+}
+
+// TestCleanupUseCase_KeepsEveryOutput covers outputs that do not look like
+// paths: a local file without an extension, and a directory output whose
+// files must all survive.
+func TestCleanupUseCase_KeepsEveryOutput(t *testing.T) {
+	const root = "/cromwell-executions/wf/run-1"
+	files := &treeFiles{sizes: map[string]int64{
+		root + "/call-X/execution/result":             10,
+		root + "/call-X/execution/scratch":            20,
+		root + "/call-Y/execution/reports/a.html":     30,
+		root + "/call-Y/execution/reports/sub/b.html": 40,
+		root + "/call-Y/execution/reports-old/c.html": 50,
+	}}
+	wf := &workflow.Workflow{
+		ID: "run-1", Name: "wf", Status: workflow.StatusSucceeded, WorkflowRoot: root,
+		Outputs: map[string]any{
+			"wf.result":  root + "/call-X/execution/result",
+			"wf.reports": map[string]any{"left": root + "/call-Y/execution/reports/", "right": 1},
+		},
+	}
+	repo := &mockWorkflowRepository{getMetadataFunc: func(context.Context, string) (*workflow.Workflow, error) {
+		return wf, nil
+	}}
+
+	plan, err := NewCleanupUseCase(repo, files, nil).Plan(context.Background(), CleanupInput{WorkflowID: "run-1"})
+	if err != nil {
+		t.Fatalf("Plan() unexpected error: %v", err)
+	}
+	if plan.KeptFiles != 3 {
+		t.Errorf("kept %d files, want the result and both files of the reports directory", plan.KeptFiles)
+	}
+	planned := make(map[string]bool)
+	for _, f := range plan.Files {
+		planned[f.Path] = true
+	}
+	for _, kept := range []string{"/call-X/execution/result", "/call-Y/execution/reports/a.html", "/call-Y/execution/reports/sub/b.html"} {
+		if planned[root+kept] {
+			t.Errorf("output %s planned for deletion", kept)
+		}
+	}
+	if len(plan.Files) != 2 {
+		t.Errorf("plan deletes %d files, want scratch and reports-old/c.html", len(plan.Files))
+	}
+}
+
+func TestIsKept(t *testing.T) {
+	keep := map[string]bool{`C:\runs\wf\call-A\reports`: true, "gs://b/wf/out.txt": true}
+	tests := []struct {
+		path, root string
+		want       bool
+	}{
+		{"gs://b/wf/out.txt", "gs://b/wf", true},
+		{"gs://b/wf/call-A/tmp", "gs://b/wf", false},
+		{`C:\runs\wf\call-A\reports\a.html`, `C:\runs\wf`, true},
+		{`C:\runs\wf\call-A\scratch`, `C:\runs\wf`, false},
+		// A listed path without any separator past the root must not panic.
+		{"outside-file", "r", false},
+	}
+	for _, tt := range tests {
+		if got := isKept(tt.path, tt.root, keep); got != tt.want {
+			t.Errorf("isKept(%q, %q) = %v, want %v", tt.path, tt.root, got, tt.want)
+		}
+	}
+}
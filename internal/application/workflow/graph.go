@@ -0,0 +1,166 @@
+// graph.go renders a workflow's call graph, and can paint it with the state of
+// a run so "where is it stuck?" is answered by looking at a picture.
+package workflow
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	domain "github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/pkg/wdl"
+)
+
+// maxSubworkflowDepth bounds how deep subworkflow metadata is followed when
+// collecting a run's statuses.
+const maxSubworkflowDepth = 16
+
+// GraphUseCase renders call graphs.
+type GraphUseCase struct {
+	files  ports.FileProvider
+	reader ports.WorkflowMetadataReader
+}
+
+// NewGraphUseCase creates a new graph use case.
+func NewGraphUseCase(files ports.FileProvider, reader ports.WorkflowMetadataReader) *GraphUseCase {
+	return &GraphUseCase{files: files, reader: reader}
+}
+
+// GraphInput is the input for rendering a graph.
+type GraphInput struct {
+	WorkflowFile string
+	// DependenciesFile is an imports zip. Without it, imports are resolved
+	// from WDL files sitting next to the workflow.
+	DependenciesFile string
+	Format           wdl.GraphFormat
+	// RunID, when set, colours each call with its status in that run.
+	RunID string
+}
+
+// GraphOutput is the rendered graph.
+type GraphOutput struct {
+	Rendered []byte
+	// Unresolved lists calls drawn as opaque boxes because their definition
+	// could not be read.
+	Unresolved []string
+	// Warning explains a degraded graph, such as an unreadable imports zip.
+	Warning string
+}
+
+// Execute renders the graph.
+func (uc *GraphUseCase) Execute(ctx context.Context, input GraphInput) (*GraphOutput, error) {
+	if input.WorkflowFile == "" {
+		return nil, application.NewInputValidationError("workflowFile", "is required")
+	}
+
+	source, err := uc.files.ReadBytes(ctx, input.WorkflowFile)
+	if err != nil {
+		return nil, application.NewUseCaseError("graph", "failed to read workflow file", err)
+	}
+	deps, warning := resolveImportSources(ctx, uc.files, input.WorkflowFile, input.DependenciesFile)
+
+	graph, err := wdl.BuildCallGraphWithSources(source, deps)
+	if err != nil {
+		return nil, application.NewUseCaseError("graph", "failed to parse workflow", err)
+	}
+
+	var statuses map[string]string
+	if input.RunID != "" {
+		run, err := uc.reader.GetMetadata(ctx, input.RunID)
+		if err != nil {
+			return nil, application.NewUseCaseError("graph", "failed to get workflow metadata", err)
+		}
+		collected := make(map[string][]string)
+		uc.collectStatuses(ctx, run, "", collected, 0)
+		statuses = make(map[string]string, len(collected))
+		for path, s := range collected {
+			statuses[path] = aggregateCallStatus(s)
+		}
+	}
+
+	rendered, err := wdl.RenderCallGraph(graph, input.Format, statuses)
+	if err != nil {
+		return nil, application.NewInputValidationError("format", err.Error())
+	}
+	return &GraphOutput{Rendered: rendered, Unresolved: unresolvedCalls(graph), Warning: warning}, nil
+}
+
+// collectStatuses gathers the latest status of every shard of every call,
+// keyed by the call's path in the flattened graph. Subworkflows are followed
+// into their own metadata — inlined when present, fetched otherwise — because
+// the graph draws their leaf calls, not the subworkflow call itself.
+func (uc *GraphUseCase) collectStatuses(ctx context.Context, w *domain.Workflow, prefix string, out map[string][]string, depth int) {
+	if w == nil || depth > maxSubworkflowDepth {
+		return
+	}
+	for fqn, calls := range w.Calls {
+		path := prefix + fqn[strings.LastIndex(fqn, ".")+1:]
+		for _, c := range latestAttempts(calls) {
+			if c.SubWorkflowMetadata == nil && c.SubWorkflowID == "" {
+				out[path] = append(out[path], string(c.Status))
+				continue
+			}
+			sub := c.SubWorkflowMetadata
+			if sub == nil {
+				fetched, err := uc.reader.GetMetadata(ctx, c.SubWorkflowID)
+				if err != nil {
+					// An unreadable subworkflow leaves its calls uncoloured
+					// rather than failing the whole graph.
+					continue
+				}
+				sub = fetched
+			}
+			uc.collectStatuses(ctx, sub, path+".", out, depth+1)
+		}
+	}
+}
+
+// latestAttempts keeps the last attempt of each shard: a retried shard is
+// judged by how its retry went.
+func latestAttempts(calls []domain.Call) []domain.Call {
+	latest := make(map[int]domain.Call, len(calls))
+	order := make([]int, 0, len(calls))
+	for _, c := range calls {
+		prev, seen := latest[c.ShardIndex]
+		if !seen {
+			order = append(order, c.ShardIndex)
+		}
+		if !seen || c.Attempt > prev.Attempt {
+			latest[c.ShardIndex] = c
+		}
+	}
+	out := make([]domain.Call, 0, len(order))
+	for _, shard := range order {
+		out = append(out, latest[shard])
+	}
+	return out
+}
+
+// aggregateCallStatus summarises the shards of one call: a failure anywhere
+// shows, then anything still in flight, and the call is done only when every
+// shard is.
+func aggregateCallStatus(statuses []string) string {
+	done := 0
+	running := false
+	for _, s := range statuses {
+		switch s {
+		case string(domain.StatusFailed):
+			return string(domain.StatusFailed)
+		case "Done", string(domain.StatusSucceeded):
+			done++
+		case string(domain.StatusRunning), string(domain.StatusSubmitted), "Starting", "QueuedInCromwell", "WaitingForReturnCode":
+			running = true
+		}
+	}
+	switch {
+	case running:
+		return string(domain.StatusRunning)
+	case done == len(statuses) && done > 0:
+		return "Done"
+	case len(statuses) > 0:
+		return statuses[0]
+	default:
+		return ""
+	}
+}
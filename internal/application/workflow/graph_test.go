@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	domain "github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/pkg/wdl"
+)
+
+const graphWDL = `version 1.0
+
+workflow Align {
+    input { Array[File] reads }
+    scatter (r in reads) {
+        call BwaMem { input: reads = r }
+    }
+    call Merge { input: bams = BwaMem.bam }
+}
+
+task BwaMem {
+    input { File reads }
+    command <<< bwa ~{reads} >>>
+    output { File bam = "out.bam" }
+}
+
+task Merge {
+    input { Array[File] bams }
+    command <<< merge >>>
+}
+`
+
+func TestGraphUseCaseColoursRunStatuses(t *testing.T) {
+	files := &mockFileProvider{
+		readBytesFunc: func(ctx context.Context, path string) ([]byte, error) {
+			return []byte(graphWDL), nil
+		},
+	}
+	repo := &mockWorkflowRepository{
+		getMetadataFunc: func(ctx context.Context, id string) (*domain.Workflow, error) {
+			return &domain.Workflow{
+				Name: "Align",
+				Calls: map[string][]domain.Call{
+					// Shard 1 failed once and succeeded on retry: the call is done.
+					"Align.BwaMem": {
+						{ShardIndex: 0, Attempt: 1, Status: "Done"},
+						{ShardIndex: 1, Attempt: 1, Status: domain.StatusFailed},
+						{ShardIndex: 1, Attempt: 2, Status: "Done"},
+					},
+					"Align.Merge": {{ShardIndex: -1, Attempt: 1, Status: domain.StatusRunning}},
+				},
+			}, nil
+		},
+	}
+	uc := NewGraphUseCase(files, repo)
+
+	out, err := uc.Execute(context.Background(), GraphInput{
+		WorkflowFile: "/tmp/align.wdl", Format: wdl.GraphJSON, RunID: "run-1",
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	rendered := string(out.Rendered)
+	for _, want := range []string{`"name": "BwaMem"`, `"status": "Done"`, `"name": "Merge"`, `"status": "Running"`} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("graph lacks %s:\n%s", want, rendered)
+		}
+	}
+}
+
+func TestGraphUseCaseRejectsUnknownFormat(t *testing.T) {
+	files := &mockFileProvider{
+		readBytesFunc: func(ctx context.Context, path string) ([]byte, error) {
+			return []byte(graphWDL), nil
+		},
+	}
+	_, err := NewGraphUseCase(files, nil).Execute(context.Background(), GraphInput{WorkflowFile: "/tmp/align.wdl", Format: "png"})
+	if !errors.Is(err, application.ErrInvalidInput) {
+		t.Errorf("error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestAggregateCallStatus(t *testing.T) {
+	tests := []struct {
+		statuses []string
+		want     string
+	}{
+		{[]string{"Done", "Done"}, "Done"},
+		{[]string{"Done", "Failed", "Running"}, "Failed"},
+		{[]string{"Done", "QueuedInCromwell"}, "Running"},
+		{[]string{"Aborted"}, "Aborted"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := aggregateCallStatus(tt.statuses); got != tt.want {
+			t.Errorf("aggregateCallStatus(%v) = %q, want %q", tt.statuses, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// inputs_layers.go assembles one inputs JSON from several sources: JSON or
+// YAML files layered in order, "--set" assignments on top, and ${ENV}
+// references resolved from the environment. Everything downstream — preflight,
+// the cache forecast, Cromwell — sees only the merged document.
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/pkg/wdl"
+)
+
+// InputSources is where a submission's inputs come from.
+type InputSources struct {
+	// Files are inputs files, read as YAML when named .yaml or .yml and as
+	// JSON otherwise. A later file replaces an earlier one's value for the
+	// same input.
+	Files []string
+	// Set are "Workflow.input=value" assignments applied after the files.
+	// The value is read as YAML, so numbers, booleans and [lists] keep their
+	// type, except for String and File inputs, which take it as written.
+	Set []string
+}
+
+// IsEmpty reports whether no inputs were given at all.
+func (s InputSources) IsEmpty() bool {
+	return len(s.Files) == 0 && len(s.Set) == 0
+}
+
+// envReference matches ${NAME} and ${NAME:-default}. "$${" escapes a literal
+// "${".
+var envReference = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// assembleInputs merges the sources into one inputs JSON for the workflow in
+// source, whose declared inputs (resolving imports through sources) keep
+// "--set" values of String and File inputs as text. It returns nil when there
+// are no sources.
+//
+// A lone JSON file with no assignments and no ${...} references is returned
+// exactly as read, so the common case submits the user's own bytes.
+func assembleInputs(ctx context.Context, files ports.FileProvider, src InputSources, source []byte, sources wdl.SourceSet) ([]byte, error) {
+	if src.IsEmpty() {
+		return nil, nil
+	}
+
+	contents := make([][]byte, len(src.Files))
+	for i, path := range src.Files {
+		data, err := files.ReadBytes(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		contents[i] = data
+	}
+	if len(src.Files) == 1 && len(src.Set) == 0 && !isYAMLPath(src.Files[0]) && !bytes.Contains(contents[0], []byte("${")) {
+		return contents[0], nil
+	}
+
+	merged := make(map[string]any)
+	for i, path := range src.Files {
+		layer, err := decodeInputsFile(path, contents[i])
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range layer {
+			merged[key] = value
+		}
+	}
+
+	// A WDL that does not parse declares nothing: every value is read as YAML.
+	declared, _ := wdl.DeclaredInputs(source, sources)
+	textual := make(map[string]bool, len(declared))
+	for _, spec := range declared {
+		base := strings.TrimSuffix(spec.Type, "?")
+		textual[spec.Name] = base == "String" || base == "File" || base == "Directory"
+	}
+	for _, assignment := range src.Set {
+		key, value, err := parseAssignment(assignment, textual)
+		if err != nil {
+			return nil, err
+		}
+		merged[key] = value
+	}
+
+	var missing []string
+	for _, key := range sortedInputKeys(merged) {
+		merged[key] = interpolateEnv(merged[key], key, &missing)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("environment variables are not set: %s", strings.Join(missing, "; "))
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(merged); err != nil {
+		return nil, fmt.Errorf("encoding the merged inputs: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func isYAMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// decodeInputsFile reads one layer into plain JSON-shaped values.
+func decodeInputsFile(path string, data []byte) (map[string]any, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return map[string]any{}, nil
+	}
+	if !isYAMLPath(path) {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		// Keeps large integers exact through the merge.
+		dec.UseNumber()
+		var out map[string]any
+		if err := dec.Decode(&out); err != nil {
+			return nil, fmt.Errorf("%s is not valid JSON: %w", path, err)
+		}
+		return out, nil
+	}
+
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s is not valid YAML: %w", path, err)
+	}
+	out, ok := normalizeYAML(raw).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a mapping of input names to values", path)
+	}
+	return out, nil
+}
+
+// normalizeYAML turns decoded YAML into values encoding/json can write:
+// mappings with non-string keys (a Map[Int, File] input) get string keys, and
+// dates stay dates rather than becoming timestamps.
+func normalizeYAML(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			val[k] = normalizeYAML(item)
+		}
+		return val
+	case map[any]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[fmt.Sprint(k)] = normalizeYAML(item)
+		}
+		return out
+	case []any:
+		for i, item := range val {
+			val[i] = normalizeYAML(item)
+		}
+		return val
+	case time.Time:
+		if val.Equal(val.Truncate(24 * time.Hour)) {
+			return val.Format(time.DateOnly)
+		}
+		return val.Format(time.RFC3339)
+	}
+	return v
+}
+
+// parseAssignment splits a "--set" value into the input name and its value.
+func parseAssignment(assignment string, textual map[string]bool) (string, any, error) {
+	key, raw, ok := strings.Cut(assignment, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", nil, fmt.Errorf("invalid --set %q: expected Workflow.input=value", assignment)
+	}
+	if textual[key] || raw == "" {
+		return key, raw, nil
+	}
+	var value any
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+		// Not YAML, so plainly text.
+		return key, raw, nil
+	}
+	return key, normalizeYAML(value), nil
+}
+
+// interpolateEnv replaces ${NAME} references in every string of a value,
+// recording each unset variable (with the input it appears in) in missing.
+func interpolateEnv(v any, input string, missing *[]string) any {
+	switch val := v.(type) {
+	case string:
+		return envReference.ReplaceAllStringFunc(val, func(ref string) string {
+			if strings.HasPrefix(ref, "$$") {
+				return ref[1:]
+			}
+			m := envReference.FindStringSubmatch(ref)
+			if value, ok := os.LookupEnv(m[1]); ok {
+				return value
+			}
+			if m[2] != "" {
+				return m[3]
+			}
+			*missing = append(*missing, fmt.Sprintf("%s (in %s)", m[1], input))
+			return ref
+		})
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[interpolateEnv(k, input, missing).(string)] = interpolateEnv(item, input, missing)
+		}
+		return out
+	case []any:
+		for i, item := range val {
+			val[i] = interpolateEnv(item, input, missing)
+		}
+		return val
+	}
+	return v
+}
+
+func sortedInputKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,150 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const layersWDL = `version 1.0
+
+workflow Wf {
+    input {
+        String sample_name
+        File reads
+        Int threads = 4
+        Array[Int] lanes = []
+        String? note
+    }
+}
+`
+
+func layerFiles(files map[string]string) *mockFileProvider {
+	return &mockFileProvider{
+		readBytesFunc: func(ctx context.Context, path string) ([]byte, error) {
+			if content, ok := files[path]; ok {
+				return []byte(content), nil
+			}
+			return nil, errors.New("no such file: " + path)
+		},
+	}
+}
+
+func TestAssembleInputs(t *testing.T) {
+	t.Setenv("PUMBAA_TEST_BUCKET", "gs://lab-bucket")
+
+	files := layerFiles(map[string]string{
+		"base.json":   `{"Wf.sample_name": "base", "Wf.reads": "${PUMBAA_TEST_BUCKET}/reads.fq", "Wf.threads": 8}`,
+		"sample.yaml": "Wf.sample_name: NA12878\nWf.lanes: [1, 2]\nWf.note: 'cost: ${PUMBAA_TEST_UNSET:-unknown}, literal $${HOME}'\n",
+		"plain.json":  `{"Wf.sample_name":"x"}`,
+		"env.json":    `{"Wf.reads": "${PUMBAA_TEST_UNSET}/r.fq"}`,
+		"broken.yaml": "Wf.sample_name: [unclosed\n",
+	})
+
+	tests := []struct {
+		name    string
+		src     InputSources
+		want    map[string]any
+		raw     string
+		wantErr string
+	}{
+		{
+			name: "no sources",
+			src:  InputSources{},
+		},
+		{
+			name: "a plain JSON file is passed through untouched",
+			src:  InputSources{Files: []string{"plain.json"}},
+			raw:  `{"Wf.sample_name":"x"}`,
+		},
+		{
+			name: "overlays, assignments and environment",
+			src: InputSources{
+				Files: []string{"base.json", "sample.yaml"},
+				Set:   []string{"Wf.threads=16", "Wf.sample_name=007", "Wf.lanes=[3,4]"},
+			},
+			want: map[string]any{
+				"Wf.sample_name": "007",
+				"Wf.reads":       "gs://lab-bucket/reads.fq",
+				"Wf.threads":     float64(16),
+				"Wf.lanes":       []any{float64(3), float64(4)},
+				"Wf.note":        "cost: unknown, literal ${HOME}",
+			},
+		},
+		{
+			name:    "unset variable",
+			src:     InputSources{Files: []string{"env.json"}},
+			wantErr: "PUMBAA_TEST_UNSET (in Wf.reads)",
+		},
+		{
+			name:    "invalid YAML",
+			src:     InputSources{Files: []string{"broken.yaml"}},
+			wantErr: "broken.yaml is not valid YAML",
+		},
+		{
+			name:    "malformed assignment",
+			src:     InputSources{Set: []string{"Wf.threads"}},
+			wantErr: "expected Workflow.input=value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := assembleInputs(context.Background(), files, tt.src, []byte(layersWDL), nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("assembleInputs() error = %v", err)
+			}
+			switch {
+			case tt.raw != "":
+				if string(got) != tt.raw {
+					t.Errorf("inputs = %s, want %s", got, tt.raw)
+				}
+			case tt.want == nil:
+				if got != nil {
+					t.Errorf("inputs = %s, want nil", got)
+				}
+			default:
+				var decoded map[string]any
+				if err := json.Unmarshal(got, &decoded); err != nil {
+					t.Fatalf("merged inputs are not JSON: %v\n%s", err, got)
+				}
+				if !reflect.DeepEqual(decoded, tt.want) {
+					t.Errorf("inputs = %v, want %v", decoded, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPreflightChecksMergedInputs(t *testing.T) {
+	fp := layerFiles(map[string]string{
+		"align.wdl":   preflightWDL,
+		"base.yaml":   "Align.reads: gs://b/r.fastq\n",
+		"sample.yaml": "Align.threads: many\n",
+	})
+	uc := NewPreflightUseCase(fp, nil)
+
+	report, err := uc.Execute(context.Background(), PreflightInput{
+		WorkflowFile: "align.wdl",
+		Inputs:       InputSources{Files: []string{"base.yaml", "sample.yaml"}, Set: []string{"Align.sample=NA12878"}},
+		SkipPaths:    true,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	inputs := checkByName(t, report, "Inputs")
+	if len(inputs.Items) != 1 || inputs.Items[0].Subject != "Align.threads" {
+		t.Errorf("only the overlay's bad threads value should be reported: %+v", inputs.Items)
+	}
+	if !strings.Contains(string(report.Inputs), `"Align.sample": "NA12878"`) {
+		t.Errorf("report.Inputs should hold the merged document:\n%s", report.Inputs)
+	}
+}
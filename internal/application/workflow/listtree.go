@@ -0,0 +1,96 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// listTreeParallelism bounds how many directories are listed at once.
+const listTreeParallelism = 16
+
+// listTree lists every file under root, sorted by path, listing directories
+// concurrently. listed, when not nil, is called with the running count of
+// files after each directory.
+func listTree(ctx context.Context, lister ports.FileLister, root string, listed func(n int)) ([]ports.FileEntry, error) {
+	var (
+		mu    sync.Mutex
+		files []ports.FileEntry
+	)
+	// A listing starts the listings of its subdirectories, so the group
+	// cannot be limited without deadlocking; the semaphore bounds the
+	// requests in flight instead.
+	sem := make(chan struct{}, listTreeParallelism)
+	group, groupCtx := errgroup.WithContext(ctx)
+	var list func(dir string)
+	list = func(dir string) {
+		group.Go(func() error {
+			sem <- struct{}{}
+			entries, err := lister.List(groupCtx, dir)
+			<-sem
+			if err != nil {
+				if errors.Is(err, ports.ErrFileNotFound) && dir != root {
+					// Removed while being listed.
+					return nil
+				}
+				return err
+			}
+			mu.Lock()
+			for _, e := range entries {
+				if !e.IsDir {
+					files = append(files, e)
+				}
+			}
+			n := len(files)
+			if listed != nil {
+				listed(n)
+			}
+			mu.Unlock()
+			for _, e := range entries {
+				if e.IsDir {
+					list(e.Path)
+				}
+			}
+			return nil
+		})
+	}
+
+	list(root)
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// callRootTasks maps each call root to the name of its task. Attempts and
+// shards of one task share its name.
+func callRootTasks(wf *workflow.Workflow) map[string]string {
+	roots := make(map[string]string)
+	for name, calls := range wf.Calls {
+		for _, c := range calls {
+			if c.CallRoot != "" {
+				roots[strings.TrimSuffix(c.CallRoot, "/")] = name
+			}
+		}
+	}
+	return roots
+}
+
+// taskOf returns the task whose call root is the longest prefix of path.
+func taskOf(path string, callRoots map[string]string) string {
+	task, longest := "", 0
+	for root, name := range callRoots {
+		if len(root) > longest && strings.HasPrefix(path, root+"/") {
+			task, longest = name, len(root)
+		}
+	}
+	return task
+}
@@ -0,0 +1,268 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// DefaultFollowInterval is how often a followed log is polled for new bytes.
+const DefaultFollowInterval = 5 * time.Second
+
+// LogFollower reads what a growing log gained since it was last polled,
+// through ranged reads, so no byte of it is downloaded twice.
+type LogFollower struct {
+	fileProvider ports.FileProvider
+	ranges       ports.RangeReader
+	path         string
+	offset       int64
+}
+
+// NewLogFollower creates a LogFollower that reads a log from offset on. It
+// fails when the file provider cannot read ranges, and for .gz logs, whose
+// compressed stream cannot be picked up in the middle.
+func NewLogFollower(fp ports.FileProvider, path string, offset int64) (*LogFollower, error) {
+	ranges, ok := fp.(ports.RangeReader)
+	if !ok {
+		return nil, fmt.Errorf("following logs needs ranged reads, which this file provider does not support")
+	}
+	if strings.HasSuffix(path, ".gz") {
+		return nil, fmt.Errorf("compressed logs cannot be followed: %s", path)
+	}
+	return &LogFollower{fileProvider: fp, ranges: ranges, path: path, offset: offset}, nil
+}
+
+// Offset is how much of the log has been read.
+func (f *LogFollower) Offset() int64 { return f.offset }
+
+// Poll returns what was appended to the log since the last poll. A log that
+// does not exist yet, as before a task starts writing, reads as empty; a log
+// that shrank was rewritten and is read again from the start.
+func (f *LogFollower) Poll(ctx context.Context) ([]byte, error) {
+	size, err := f.fileProvider.GetSize(ctx, f.path)
+	if errors.Is(err, ports.ErrFileNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if size < f.offset {
+		f.offset = 0
+	}
+
+	var data []byte
+	for f.offset < size {
+		chunk, err := f.ranges.ReadRange(ctx, f.path, f.offset, min(logSearchChunk, size-f.offset))
+		if err != nil {
+			return data, err
+		}
+		if len(chunk) == 0 {
+			break
+		}
+		f.offset += int64(len(chunk))
+		data = append(data, chunk...)
+	}
+	return data, nil
+}
+
+// FollowLogsUseCase prints the tail of a task's log and, when following,
+// keeps printing what the task appends until the call finishes.
+type FollowLogsUseCase struct {
+	reader       ports.WorkflowMetadataReader
+	fileProvider ports.FileProvider
+	logs         *LogReader
+}
+
+// NewFollowLogsUseCase creates a new follow logs use case.
+func NewFollowLogsUseCase(reader ports.WorkflowMetadataReader, fp ports.FileProvider) *FollowLogsUseCase {
+	return &FollowLogsUseCase{reader: reader, fileProvider: fp, logs: NewLogReader(fp)}
+}
+
+// FollowLogsInput represents the input for reading a task's log.
+type FollowLogsInput struct {
+	WorkflowID string
+	// Task is the call name, with or without the workflow prefix.
+	Task string
+	// Shard selects a scatter shard; -1 for a call that is not scattered.
+	Shard int
+	// Attempt selects a call attempt; 0 for the latest, in which case a
+	// follow moves on to each retry as it starts.
+	Attempt int
+	// Stream is "stderr" (the default) or "stdout".
+	Stream string
+	// Follow keeps reading the log until the call finishes.
+	Follow bool
+	// Interval is how often the log is polled; DefaultFollowInterval if zero.
+	Interval time.Duration
+}
+
+// FollowLogsOutput represents the result of reading a task's log.
+type FollowLogsOutput struct {
+	Path string
+	Call workflow.Call
+	// Bytes is how much of the log was written out.
+	Bytes int64
+}
+
+// Execute writes the last page of the log to w. When following, it then
+// polls the log and writes what is appended, until the call attempt reaches
+// a terminal status or ctx is cancelled. The log is read once more after the
+// call finishes, so its last lines are not lost. Unless an attempt was
+// given, a retry that starts meanwhile, such as after a preemption, is
+// followed from the first byte of its log.
+func (uc *FollowLogsUseCase) Execute(ctx context.Context, input FollowLogsInput, w io.Writer) (*FollowLogsOutput, error) {
+	if input.WorkflowID == "" {
+		return nil, application.NewInputValidationError("workflowID", "is required")
+	}
+	if input.Task == "" {
+		return nil, application.NewInputValidationError("task", "is required")
+	}
+	stream := strings.ToLower(input.Stream)
+	if stream == "" {
+		stream = "stderr"
+	}
+	if stream != "stderr" && stream != "stdout" {
+		return nil, application.NewInputValidationError("stream", "must be stderr or stdout")
+	}
+	interval := input.Interval
+	if interval <= 0 {
+		interval = DefaultFollowInterval
+	}
+
+	wf, call, err := uc.findCall(ctx, input.WorkflowID, input.Task, input.Shard, input.Attempt)
+	if err != nil {
+		return nil, err
+	}
+	out := &FollowLogsOutput{Path: logPath(call, stream), Call: call}
+	if out.Path == "" {
+		return nil, application.NewUseCaseError("logs", fmt.Sprintf("no %s path reported for %s yet", stream, call.Name), nil)
+	}
+
+	// Start from the tail of what the log holds now; a log not created yet
+	// is followed from its first byte.
+	var offset int64
+	page, err := uc.logs.Tail(ctx, out.Path)
+	switch {
+	case errors.Is(err, ports.ErrFileNotFound) && input.Follow:
+	case err != nil:
+		return nil, application.NewUseCaseError("logs", "failed to read log", err)
+	default:
+		if _, err := io.WriteString(w, page.Content); err != nil {
+			return nil, err
+		}
+		out.Bytes, offset = int64(len(page.Content)), page.End
+	}
+	if !input.Follow {
+		return out, nil
+	}
+
+	follower, err := NewLogFollower(uc.fileProvider, out.Path, offset)
+	if err != nil {
+		return nil, application.NewUseCaseError("logs", "cannot follow log", err)
+	}
+	following := call.Attempt // the attempt whose log follower reads
+	for {
+		// Whether the call had finished is checked before the log is read, so
+		// the last read catches everything written before it finished.
+		done := out.Call.IsTerminal() || wf.IsTerminal()
+		data, err := follower.Poll(ctx)
+		if _, werr := w.Write(data); werr != nil {
+			return out, werr
+		}
+		out.Bytes += int64(len(data))
+		if err != nil {
+			return out, application.NewUseCaseError("logs", "failed to read log", err)
+		}
+		if done {
+			return out, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return out, ctx.Err()
+		case <-time.After(interval):
+		}
+		wf, out.Call, err = uc.findCall(ctx, input.WorkflowID, input.Task, input.Shard, input.Attempt)
+		if err != nil {
+			return out, err
+		}
+		// A retry that has reported its log takes over. What the previous
+		// attempt wrote before it ended is read first.
+		if path := logPath(out.Call, stream); out.Call.Attempt != following && path != "" {
+			data, err := follower.Poll(ctx)
+			if _, werr := w.Write(data); werr != nil {
+				return out, werr
+			}
+			out.Bytes += int64(len(data))
+			if err != nil {
+				return out, application.NewUseCaseError("logs", "failed to read log", err)
+			}
+			if follower, err = NewLogFollower(uc.fileProvider, path, 0); err != nil {
+				return out, application.NewUseCaseError("logs", "cannot follow log", err)
+			}
+			out.Path, following = path, out.Call.Attempt
+		}
+	}
+}
+
+// logPath returns the path of a call's stderr or stdout log.
+func logPath(call workflow.Call, stream string) string {
+	if stream == "stdout" {
+		return call.Stdout
+	}
+	return call.Stderr
+}
+
+// findCall finds a task's call in the workflow by full or short name and
+// shard: the given attempt, or the latest one when attempt is 0.
+func (uc *FollowLogsUseCase) findCall(ctx context.Context, workflowID, task string, shard, attempt int) (*workflow.Workflow, workflow.Call, error) {
+	wf, err := uc.reader.GetMetadata(ctx, workflowID)
+	if err != nil {
+		return nil, workflow.Call{}, application.NewUseCaseError("logs", "failed to get workflow metadata", err)
+	}
+
+	calls, ok := wf.Calls[task]
+	if !ok {
+		for name, c := range wf.Calls {
+			if strings.EqualFold(name[strings.LastIndex(name, ".")+1:], task) {
+				calls, ok = c, true
+				break
+			}
+		}
+	}
+	if !ok {
+		names := make([]string, 0, len(wf.Calls))
+		for name := range wf.Calls {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, workflow.Call{}, application.NewUseCaseError("logs",
+			fmt.Sprintf("task %q not found; available: %s (for tasks inside subworkflows, use the subworkflow's ID)", task, strings.Join(names, ", ")), nil)
+	}
+
+	var found *workflow.Call
+	for i := range calls {
+		c := &calls[i]
+		if c.ShardIndex != shard || (attempt > 0 && c.Attempt != attempt) {
+			continue
+		}
+		if found == nil || c.Attempt > found.Attempt {
+			found = c
+		}
+	}
+	if found == nil && attempt > 0 {
+		return nil, workflow.Call{}, application.NewUseCaseError("logs", fmt.Sprintf("no attempt %d of shard %d of task %q", attempt, shard, task), nil)
+	}
+	if found == nil {
+		return nil, workflow.Call{}, application.NewUseCaseError("logs", fmt.Sprintf("no shard %d of task %q", shard, task), nil)
+	}
+	return wf, *found, nil
+}
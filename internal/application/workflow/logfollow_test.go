@@ -0,0 +1,187 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// growingTask is a workflow whose Align task appends a line to its stderr
+// each time the metadata is fetched, and finishes after the given number of
+// fetches.
+func growingTask(files *rangeFiles, finishAfter int) *mockWorkflowRepository {
+	const stderr = "gs://b/wf/call-Align/stderr"
+	fetches := 0
+	return &mockWorkflowRepository{getMetadataFunc: func(context.Context, string) (*workflow.Workflow, error) {
+		fetches++
+		status := workflow.Status("Running")
+		if fetches > 1 {
+			files.files[stderr] = append(files.files[stderr], []byte("step "+strconv.Itoa(fetches)+"\n")...)
+		}
+		if fetches > finishAfter {
+			status = "Done"
+		}
+		return &workflow.Workflow{
+			ID:     "wf-1",
+			Name:   "wf",
+			Status: workflow.StatusRunning,
+			Calls: map[string][]workflow.Call{"wf.Align": {
+				{Name: "wf.Align", ShardIndex: -1, Attempt: 1, Status: "RetryableFailure", Stderr: "gs://b/wf/call-Align/attempt-1/stderr"},
+				{Name: "wf.Align", ShardIndex: -1, Attempt: 2, Status: status, Stderr: stderr},
+			}},
+		}, nil
+	}}
+}
+
+func TestFollowLogsUseCase_FollowsUntilTheCallFinishes(t *testing.T) {
+	files := &rangeFiles{files: map[string][]byte{"gs://b/wf/call-Align/stderr": []byte("start\n")}}
+	uc := NewFollowLogsUseCase(growingTask(files, 3), files)
+
+	var out strings.Builder
+	result, err := uc.Execute(context.Background(), FollowLogsInput{
+		WorkflowID: "wf-1", Task: "Align", Shard: -1, Follow: true, Interval: time.Millisecond,
+	}, &out)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+
+	// The line written as the call finished is printed too, and nothing twice.
+	if want := "start\nstep 2\nstep 3\nstep 4\n"; out.String() != want {
+		t.Errorf("followed log = %q, want %q", out.String(), want)
+	}
+	if result.Call.Attempt != 2 || !result.Call.IsTerminal() || result.Bytes != int64(out.Len()) {
+		t.Errorf("result = attempt %d, status %s, %d bytes", result.Call.Attempt, result.Call.Status, result.Bytes)
+	}
+}
+
+func TestFollowLogsUseCase_WithoutFollow(t *testing.T) {
+	files := &rangeFiles{files: map[string][]byte{"gs://b/wf/call-Align/stderr": []byte("start\n")}}
+	uc := NewFollowLogsUseCase(growingTask(files, 100), files)
+
+	var out strings.Builder
+	if _, err := uc.Execute(context.Background(), FollowLogsInput{WorkflowID: "wf-1", Task: "wf.Align", Shard: -1}, &out); err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if out.String() != "start\n" {
+		t.Errorf("log = %q, want only what it held", out.String())
+	}
+
+	if _, err := uc.Execute(context.Background(), FollowLogsInput{WorkflowID: "wf-1", Task: "Sort", Shard: -1}, &out); err == nil || !strings.Contains(err.Error(), "wf.Align") {
+		t.Errorf("unknown task error = %v, want the available tasks listed", err)
+	}
+}
+
+func TestFollowLogsUseCase_StopsOnCancel(t *testing.T) {
+	files := &rangeFiles{files: map[string][]byte{}}
+	uc := NewFollowLogsUseCase(growingTask(files, 1000), files)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var out strings.Builder
+	_, err := uc.Execute(ctx, FollowLogsInput{WorkflowID: "wf-1", Task: "Align", Shard: -1, Follow: true, Interval: time.Millisecond}, &out)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Execute() error = %v, want the context's", err)
+	}
+	// The log did not exist when following started, so it is read from its
+	// first line.
+	if !strings.HasPrefix(out.String(), "step 2\n") {
+		t.Errorf("followed log = %q, want it from the first line", out.String())
+	}
+}
+
+// preemptedTask is a workflow whose Align attempt 1 is preempted at the
+// second metadata fetch, writing a last line first, and whose attempt 2
+// then runs until the fourth fetch.
+func preemptedTask(files *rangeFiles) *mockWorkflowRepository {
+	const first, second = "gs://b/wf/call-Align/stderr", "gs://b/wf/call-Align/attempt-2/stderr"
+	fetches := 0
+	return &mockWorkflowRepository{getMetadataFunc: func(context.Context, string) (*workflow.Workflow, error) {
+		fetches++
+		calls := []workflow.Call{{Name: "wf.Align", ShardIndex: -1, Attempt: 1, Status: "Running", Stderr: first}}
+		if fetches == 2 {
+			files.files[first] = append(files.files[first], "preempted\n"...)
+			files.files[second] = []byte("retry\n")
+		}
+		if fetches >= 2 {
+			calls[0].Status = "Preempted"
+			status := workflow.Status("Running")
+			if fetches >= 4 {
+				status = "Done"
+			}
+			files.files[second] = append(files.files[second], []byte("step "+strconv.Itoa(fetches)+"\n")...)
+			calls = append(calls, workflow.Call{Name: "wf.Align", ShardIndex: -1, Attempt: 2, Status: status, Stderr: second})
+		}
+		return &workflow.Workflow{ID: "wf-1", Name: "wf", Status: workflow.StatusRunning,
+			Calls: map[string][]workflow.Call{"wf.Align": calls}}, nil
+	}}
+}
+
+func TestFollowLogsUseCase_FollowsRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		attempt     int
+		want        string
+		wantAttempt int
+	}{
+		{"latest attempt", 0, "start\npreempted\nretry\nstep 2\nstep 3\nstep 4\n", 2},
+		{"given attempt", 1, "start\npreempted\n", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := &rangeFiles{files: map[string][]byte{"gs://b/wf/call-Align/stderr": []byte("start\n")}}
+			uc := NewFollowLogsUseCase(preemptedTask(files), files)
+
+			var out strings.Builder
+			result, err := uc.Execute(context.Background(), FollowLogsInput{
+				WorkflowID: "wf-1", Task: "Align", Shard: -1, Attempt: tt.attempt, Follow: true, Interval: time.Millisecond,
+			}, &out)
+			if err != nil {
+				t.Fatalf("Execute() unexpected error: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("followed log = %q, want %q", out.String(), tt.want)
+			}
+			if result.Call.Attempt != tt.wantAttempt || !result.Call.IsTerminal() || result.Bytes != int64(out.Len()) {
+				t.Errorf("result = attempt %d, status %s, %d bytes", result.Call.Attempt, result.Call.Status, result.Bytes)
+			}
+		})
+	}
+}
+
+func TestLogFollower_Poll(t *testing.T) {
+	files := &rangeFiles{files: map[string][]byte{"stderr": []byte("one\n")}}
+	f, err := NewLogFollower(files, "stderr", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	steps := []struct {
+		content string
+		want    string
+	}{
+		{"one\n", "one\n"},
+		{"one\n", ""},
+		{"one\ntwo\n", "two\n"},
+		{"new\n", "new\n"}, // rewritten: shorter than what was read
+	}
+	for i, step := range steps {
+		files.files["stderr"] = []byte(step.content)
+		got, err := f.Poll(ctx)
+		if err != nil || string(got) != step.want {
+			t.Errorf("step %d: Poll() = %q, %v, want %q", i, got, err, step.want)
+		}
+	}
+
+	if _, err := NewLogFollower(files, "stderr.gz", 0); err == nil {
+		t.Error("NewLogFollower() accepted a .gz log")
+	}
+	if _, err := NewLogFollower(&mockFileProvider{}, "stderr", 0); err == nil {
+		t.Error("NewLogFollower() accepted a provider without ranged reads")
+	}
+}
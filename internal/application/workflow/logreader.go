@@ -0,0 +1,346 @@
+package workflow
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+const (
+	// LogPageSize is how many bytes of a log a page holds, give or take the
+	// partial lines trimmed from its edges.
+	LogPageSize int64 = 64 * 1024
+
+	// logSearchChunk is how much of a log a search reads per request.
+	logSearchChunk int64 = 1024 * 1024
+
+	// maxGunzippedLogSize caps the decompressed size of a .gz log, which has
+	// to be held in memory: a gzip stream cannot be entered in the middle.
+	maxGunzippedLogSize int64 = 256 * 1024 * 1024
+)
+
+// LogPage is a window of whole lines of a log.
+type LogPage struct {
+	Path string
+	// Offset and End delimit the page in the log, in bytes. For a .gz log
+	// they are offsets into the decompressed content.
+	Offset int64
+	End    int64
+	// Size is the size of the whole log.
+	Size    int64
+	Content string
+}
+
+// AtStart reports whether the page begins at the start of the log.
+func (p LogPage) AtStart() bool { return p.Offset == 0 }
+
+// AtEnd reports whether the page ends at the end of the log.
+func (p LogPage) AtEnd() bool { return p.End >= p.Size }
+
+// LogMatch is a line of a log containing a search query.
+type LogMatch struct {
+	// Offset is where the line starts in the log.
+	Offset int64
+	Line   string
+	// Wrapped marks a match found after the search went past the end of the
+	// log and carried on from its start.
+	Wrapped bool
+}
+
+// Next is where a search for the following match starts: the line after
+// this one.
+func (m LogMatch) Next() int64 { return m.Offset + int64(len(m.Line)) + 1 }
+
+// LogReader reads task and workflow logs a page at a time, so a log of any
+// size can be viewed and searched without being held in memory. Logs ending
+// in .gz are decompressed transparently.
+//
+// Paging needs a file provider that also implements ports.RangeReader. With
+// one that does not, a log is read whole and subject to its size limit.
+type LogReader struct {
+	fileProvider ports.FileProvider
+	ranges       ports.RangeReader
+
+	mu sync.Mutex
+	// gunzipped holds the decompressed content of the last .gz log read,
+	// which is usually the one being paged through.
+	gunzippedPath string
+	gunzipped     []byte
+}
+
+// NewLogReader creates a LogReader over a file provider.
+func NewLogReader(fp ports.FileProvider) *LogReader {
+	r := &LogReader{fileProvider: fp}
+	r.ranges, _ = fp.(ports.RangeReader)
+	return r
+}
+
+// Tail returns the last page of a log.
+func (r *LogReader) Tail(ctx context.Context, path string) (LogPage, error) {
+	src, err := r.open(ctx, path)
+	if err != nil {
+		return LogPage{}, err
+	}
+	return src.page(ctx, max(src.size-LogPageSize, 0), src.size)
+}
+
+// PageBefore returns the page of a log that ends at end, which is the Offset
+// of the page after it.
+func (r *LogReader) PageBefore(ctx context.Context, path string, end int64) (LogPage, error) {
+	src, err := r.open(ctx, path)
+	if err != nil {
+		return LogPage{}, err
+	}
+	end = min(end, src.size)
+	return src.page(ctx, max(end-LogPageSize, 0), end)
+}
+
+// PageAfter returns the page of a log that starts at offset, which is the
+// End of the page before it.
+func (r *LogReader) PageAfter(ctx context.Context, path string, offset int64) (LogPage, error) {
+	src, err := r.open(ctx, path)
+	if err != nil {
+		return LogPage{}, err
+	}
+	offset = min(offset, src.size)
+	return src.page(ctx, offset, min(offset+LogPageSize, src.size))
+}
+
+// PageAround returns a page of a log with offset near its middle, as when
+// jumping to a search match.
+func (r *LogReader) PageAround(ctx context.Context, path string, offset int64) (LogPage, error) {
+	src, err := r.open(ctx, path)
+	if err != nil {
+		return LogPage{}, err
+	}
+	start := min(max(offset-LogPageSize/2, 0), max(src.size-LogPageSize, 0))
+	return src.page(ctx, start, min(start+LogPageSize, src.size))
+}
+
+// Search returns the first line at or after from that contains query,
+// ignoring case. The log is read in chunks, never whole; when the end is
+// reached the search carries on from the start up to from.
+func (r *LogReader) Search(ctx context.Context, path, query string, from int64) (LogMatch, bool, error) {
+	if query == "" {
+		return LogMatch{}, false, nil
+	}
+	src, err := r.open(ctx, path)
+	if err != nil {
+		return LogMatch{}, false, err
+	}
+
+	from = min(max(from, 0), src.size)
+	// Start at the beginning of the line holding from, so a match on that
+	// line is found rather than skipped.
+	from, err = src.lineStart(ctx, from)
+	if err != nil {
+		return LogMatch{}, false, err
+	}
+	if match, ok, err := src.search(ctx, strings.ToLower(query), from, src.size); err != nil || ok {
+		return match, ok, err
+	}
+	match, ok, err := src.search(ctx, strings.ToLower(query), 0, from)
+	match.Wrapped = ok
+	return match, ok, err
+}
+
+// open returns the source a log is read from.
+func (r *LogReader) open(ctx context.Context, path string) (logSource, error) {
+	if r.fileProvider == nil {
+		return logSource{}, fmt.Errorf("file provider not initialized")
+	}
+	if strings.HasSuffix(path, ".gz") {
+		data, err := r.gunzip(ctx, path)
+		if err != nil {
+			return logSource{}, err
+		}
+		return memorySource(path, data), nil
+	}
+	if r.ranges == nil {
+		content, err := r.fileProvider.Read(ctx, path)
+		if err != nil {
+			return logSource{}, err
+		}
+		return memorySource(path, []byte(content)), nil
+	}
+
+	size, err := r.fileProvider.GetSize(ctx, path)
+	if err != nil {
+		return logSource{}, err
+	}
+	return logSource{path: path, size: size, readAt: func(ctx context.Context, offset, length int64) ([]byte, error) {
+		return r.ranges.ReadRange(ctx, path, offset, length)
+	}}, nil
+}
+
+// gunzip returns the decompressed content of a .gz log, streaming the
+// compressed bytes through ranged reads when the provider supports them.
+func (r *LogReader) gunzip(ctx context.Context, path string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.gunzippedPath == path {
+		return r.gunzipped, nil
+	}
+
+	var compressed io.Reader
+	if r.ranges != nil {
+		size, err := r.fileProvider.GetSize(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		compressed = bufio.NewReaderSize(&rangeStream{ctx: ctx, ranges: r.ranges, path: path, size: size}, int(logSearchChunk))
+	} else {
+		data, err := r.fileProvider.ReadBytes(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		compressed = bytes.NewReader(data)
+	}
+
+	zr, err := gzip.NewReader(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	defer func() { _ = zr.Close() }()
+	data, err := io.ReadAll(io.LimitReader(zr, maxGunzippedLogSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	if int64(len(data)) > maxGunzippedLogSize {
+		return nil, fmt.Errorf("decompressed log too large (> %d MB limit): %s", maxGunzippedLogSize/(1024*1024), path)
+	}
+
+	r.gunzippedPath, r.gunzipped = path, data
+	return data, nil
+}
+
+// logSource is a log of known size read by ranges, from storage or memory.
+type logSource struct {
+	path   string
+	size   int64
+	readAt func(ctx context.Context, offset, length int64) ([]byte, error)
+}
+
+func memorySource(path string, data []byte) logSource {
+	size := int64(len(data))
+	return logSource{path: path, size: size, readAt: func(_ context.Context, offset, length int64) ([]byte, error) {
+		offset = min(offset, size)
+		return data[offset:min(offset+length, size)], nil
+	}}
+}
+
+// page reads [start, end) of the log, trimmed to whole lines: a line cut by
+// start or end is dropped, unless it is all the page holds.
+func (s logSource) page(ctx context.Context, start, end int64) (LogPage, error) {
+	// One byte before start tells whether start begins a line.
+	readFrom := max(start-1, 0)
+	data, err := s.readAt(ctx, readFrom, end-readFrom)
+	if err != nil {
+		return LogPage{}, err
+	}
+	end = readFrom + int64(len(data))
+	if start > 0 && len(data) > 0 {
+		if data[0] == '\n' {
+			data = data[1:]
+		} else if i := bytes.IndexByte(data, '\n'); i >= 0 && i < len(data)-1 {
+			start, data = readFrom+int64(i)+1, data[i+1:]
+		} else {
+			// The page holds a single cut line; keep it whole.
+			start = readFrom
+		}
+	}
+	if end < s.size {
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			end, data = start+int64(i)+1, data[:i+1]
+		}
+	}
+	return LogPage{Path: s.path, Offset: start, End: end, Size: s.size, Content: string(data)}, nil
+}
+
+// lineStart returns the offset of the start of the line holding offset.
+func (s logSource) lineStart(ctx context.Context, offset int64) (int64, error) {
+	for offset > 0 {
+		from := max(offset-logSearchChunk, 0)
+		data, err := s.readAt(ctx, from, offset-from)
+		if err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			return from + int64(i) + 1, nil
+		}
+		offset = from
+	}
+	return 0, nil
+}
+
+// search scans [from, to) chunk by chunk for a line containing query, which
+// is lowercase. A line split across chunks is carried into the next one.
+func (s logSource) search(ctx context.Context, query string, from, to int64) (LogMatch, bool, error) {
+	var carry []byte
+	carryStart := from
+	for offset := from; offset < to; {
+		if err := ctx.Err(); err != nil {
+			return LogMatch{}, false, err
+		}
+		chunk, err := s.readAt(ctx, offset, min(logSearchChunk, to-offset))
+		if err != nil {
+			return LogMatch{}, false, err
+		}
+		if len(chunk) == 0 {
+			break
+		}
+		offset += int64(len(chunk))
+
+		data := append(carry, chunk...)
+		lineStart := 0
+		for {
+			i := bytes.IndexByte(data[lineStart:], '\n')
+			if i < 0 {
+				break
+			}
+			line := data[lineStart : lineStart+i]
+			if bytes.Contains(bytes.ToLower(line), []byte(query)) {
+				return LogMatch{Offset: carryStart + int64(lineStart), Line: string(line)}, true, nil
+			}
+			lineStart += i + 1
+		}
+		carry = append([]byte(nil), data[lineStart:]...)
+		carryStart += int64(lineStart)
+	}
+	if bytes.Contains(bytes.ToLower(carry), []byte(query)) {
+		return LogMatch{Offset: carryStart, Line: string(carry)}, true, nil
+	}
+	return LogMatch{}, false, nil
+}
+
+// rangeStream reads a file from start to end through ranged reads, one
+// chunk per request.
+type rangeStream struct {
+	ctx    context.Context
+	ranges ports.RangeReader
+	path   string
+	size   int64
+	offset int64
+}
+
+func (s *rangeStream) Read(p []byte) (int, error) {
+	if s.offset >= s.size {
+		return 0, io.EOF
+	}
+	data, err := s.ranges.ReadRange(s.ctx, s.path, s.offset, min(int64(len(p)), s.size-s.offset))
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	s.offset += int64(len(data))
+	return copy(p, data), nil
+}
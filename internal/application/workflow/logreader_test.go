@@ -0,0 +1,204 @@
+package workflow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// rangeFiles is a FileProvider and RangeReader over in-memory files that
+// refuses whole-file reads, as storage does for large logs.
+type rangeFiles struct {
+	files  map[string][]byte
+	ranges int
+}
+
+func (f *rangeFiles) Read(_ context.Context, path string) (string, error) {
+	return "", fmt.Errorf("file too large: %s", path)
+}
+
+func (f *rangeFiles) ReadBytes(_ context.Context, path string) ([]byte, error) {
+	return f.files[path], nil
+}
+
+func (f *rangeFiles) GetSize(_ context.Context, path string) (int64, error) {
+	data, ok := f.files[path]
+	if !ok {
+		return 0, ports.ErrFileNotFound
+	}
+	return int64(len(data)), nil
+}
+
+func (f *rangeFiles) GetContentDigests(context.Context, string) (ports.FileDigests, error) {
+	return ports.FileDigests{}, ports.ErrHashUnavailable
+}
+
+func (f *rangeFiles) ReadRange(_ context.Context, path string, offset, length int64) ([]byte, error) {
+	f.ranges++
+	data := f.files[path]
+	offset = min(offset, int64(len(data)))
+	return data[offset:min(offset+length, int64(len(data)))], nil
+}
+
+// numberedLog returns a log of n lines, "line 0" to "line n-1".
+func numberedLog(n int) []byte {
+	var b bytes.Buffer
+	for i := range n {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestLogReader_PagesFromTheTail(t *testing.T) {
+	log := numberedLog(100000) // ~1.1 MB, several pages
+	files := &rangeFiles{files: map[string][]byte{"stderr": log}}
+	r := NewLogReader(files)
+	ctx := context.Background()
+
+	page, err := r.Tail(ctx, "stderr")
+	if err != nil {
+		t.Fatalf("Tail() unexpected error: %v", err)
+	}
+	if !page.AtEnd() || page.AtStart() {
+		t.Fatalf("Tail() = [%d, %d) of %d, want the end", page.Offset, page.End, page.Size)
+	}
+	if !strings.HasSuffix(page.Content, "line 99999\n") {
+		t.Errorf("Tail() does not end with the last line")
+	}
+
+	// Walking back page by page rebuilds the log exactly, line for line.
+	pages := []string{page.Content}
+	for !page.AtStart() {
+		prev, err := r.PageBefore(ctx, "stderr", page.Offset)
+		if err != nil {
+			t.Fatalf("PageBefore() unexpected error: %v", err)
+		}
+		if prev.End != page.Offset {
+			t.Fatalf("PageBefore(%d) ends at %d", page.Offset, prev.End)
+		}
+		page = prev
+		pages = append([]string{page.Content}, pages...)
+	}
+	if got := strings.Join(pages, ""); got != string(log) {
+		t.Errorf("pages do not add up to the log (%d bytes, want %d)", len(got), len(log))
+	}
+
+	// And forward again from the start.
+	next, err := r.PageAfter(ctx, "stderr", page.End)
+	if err != nil {
+		t.Fatalf("PageAfter() unexpected error: %v", err)
+	}
+	if next.Offset != page.End || !strings.HasPrefix(next.Content, "line ") {
+		t.Errorf("PageAfter(%d) = [%d, %d) starting %q", page.End, next.Offset, next.End, next.Content[:10])
+	}
+	if int64(len(next.Content)) > LogPageSize {
+		t.Errorf("page holds %d bytes, more than %d", len(next.Content), LogPageSize)
+	}
+}
+
+func TestLogReader_Search(t *testing.T) {
+	log := numberedLog(100000)
+	files := &rangeFiles{files: map[string][]byte{"stderr": log}}
+	r := NewLogReader(files)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		query       string
+		from        int64
+		wantLine    string
+		wantWrapped bool
+		wantFound   bool
+	}{
+		{"from the start", "LINE 5000", 0, "line 5000", false, true},
+		{"from the middle of a line", "line 99998", int64(bytes.Index(log, []byte("line 99998"))) + 3, "line 99998", false, true},
+		{"wraps to an earlier match", "line 12", int64(bytes.Index(log, []byte("line 90000"))), "line 12", true, true},
+		{"no match", "OutOfMemoryError", 0, "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, ok, err := r.Search(ctx, "stderr", tt.query, tt.from)
+			if err != nil {
+				t.Fatalf("Search() unexpected error: %v", err)
+			}
+			if ok != tt.wantFound || match.Line != tt.wantLine || match.Wrapped != tt.wantWrapped {
+				t.Errorf("Search() = %+v, %v, want %q (wrapped %v)", match, ok, tt.wantLine, tt.wantWrapped)
+			}
+			if ok && string(log[match.Offset:match.Offset+int64(len(match.Line))]) != match.Line {
+				t.Errorf("match offset %d does not point at %q", match.Offset, match.Line)
+			}
+		})
+	}
+
+	// Next moves past the match, so a repeated search finds the following one.
+	first, _, _ := r.Search(ctx, "stderr", "line 4", 0)
+	second, _, _ := r.Search(ctx, "stderr", "line 4", first.Next())
+	if first.Line != "line 4" || second.Line != "line 40" {
+		t.Errorf("consecutive matches = %q, %q, want %q, %q", first.Line, second.Line, "line 4", "line 40")
+	}
+
+	// The page around a match holds it.
+	page, err := r.PageAround(ctx, "stderr", second.Offset)
+	if err != nil || !strings.Contains(page.Content, "\nline 40\n") {
+		t.Errorf("PageAround() = [%d, %d), %v, does not hold the match", page.Offset, page.End, err)
+	}
+}
+
+func TestLogReader_Gunzip(t *testing.T) {
+	log := numberedLog(1000)
+	files := &rangeFiles{files: map[string][]byte{"stderr.gz": gzipped(t, log)}}
+	r := NewLogReader(files)
+	ctx := context.Background()
+
+	page, err := r.Tail(ctx, "stderr.gz")
+	if err != nil {
+		t.Fatalf("Tail() unexpected error: %v", err)
+	}
+	if page.Content != string(log) || page.Size != int64(len(log)) {
+		t.Errorf("Tail() of a .gz log = %d bytes of %d, want the decompressed log", len(page.Content), page.Size)
+	}
+
+	reads := files.ranges
+	if match, ok, _ := r.Search(ctx, "stderr.gz", "line 999", 0); !ok || match.Line != "line 999" {
+		t.Errorf("Search() in a .gz log = %+v, %v", match, ok)
+	}
+	if files.ranges != reads {
+		t.Errorf("searching a .gz log read it again (%d reads)", files.ranges-reads)
+	}
+
+	files.files["bad.gz"] = []byte("not gzip")
+	if _, err := r.Tail(ctx, "bad.gz"); err == nil || !strings.Contains(err.Error(), "decompress") {
+		t.Errorf("Tail() of a corrupt .gz error = %v", err)
+	}
+}
+
+func TestLogReader_WithoutRangeReads(t *testing.T) {
+	fp := &mockFileProvider{readFunc: func(context.Context, string) (string, error) {
+		return "first\nsecond\n", nil
+	}}
+	page, err := NewLogReader(fp).Tail(context.Background(), "stderr")
+	if err != nil {
+		t.Fatalf("Tail() unexpected error: %v", err)
+	}
+	if page.Content != "first\nsecond\n" || !page.AtStart() || !page.AtEnd() {
+		t.Errorf("Tail() = %+v, want the whole log as one page", page)
+	}
+}
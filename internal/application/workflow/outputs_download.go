@@ -0,0 +1,370 @@
+package workflow
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // matching storage checksums, not a security primitive
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+const (
+	// DefaultDownloadParallelism is how many outputs are downloaded at once.
+	DefaultDownloadParallelism = 4
+
+	// DownloadManifestName is the manifest written to the download directory.
+	DownloadManifestName = "manifest.json"
+
+	// downloadChunk is how much of a file one ranged read fetches. A download
+	// interrupted between chunks resumes from the last one written.
+	downloadChunk int64 = 8 * 1024 * 1024
+
+	// partialSuffix marks a file still being downloaded.
+	partialSuffix = ".part"
+)
+
+// DownloadStatus is the outcome of downloading one file.
+type DownloadStatus string
+
+const (
+	// DownloadVerified is a file whose checksum matched the storage's.
+	DownloadVerified DownloadStatus = "verified"
+	// DownloadUnverified is a file the storage reported no checksum for,
+	// checked by size only.
+	DownloadUnverified DownloadStatus = "unverified"
+	// DownloadSkipped is a file already downloaded and intact.
+	DownloadSkipped DownloadStatus = "skipped"
+	// DownloadFailed is a file that could not be downloaded or whose
+	// checksum did not match.
+	DownloadFailed DownloadStatus = "failed"
+)
+
+// DownloadedFile records one output file in the manifest.
+type DownloadedFile struct {
+	Source string `json:"source"`
+	// Path is where the file was written, relative to the download directory.
+	Path   string         `json:"path"`
+	Size   int64          `json:"size"`
+	MD5    string         `json:"md5,omitempty"`
+	CRC32C string         `json:"crc32c,omitempty"`
+	Status DownloadStatus `json:"status"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// DownloadManifest maps a workflow's output keys to the files downloaded for
+// them. It is written as DownloadManifestName in the download directory.
+type DownloadManifest struct {
+	WorkflowID   string `json:"workflowId"`
+	WorkflowName string `json:"workflowName"`
+	// Outputs maps each output key to the local paths of its files, relative
+	// to the download directory, in the order the output lists them.
+	Outputs map[string][]string `json:"outputs"`
+	Files   []DownloadedFile    `json:"files"`
+}
+
+// DownloadOutputsUseCase downloads the files among a workflow's outputs.
+type DownloadOutputsUseCase struct {
+	reader   ports.WorkflowMetadataReader
+	files    ports.FileProvider
+	progress ports.ProgressReporter
+	mu       sync.Mutex // serializes progress reports from concurrent downloads
+}
+
+// NewDownloadOutputsUseCase creates a new download outputs use case. progress
+// may be nil.
+func NewDownloadOutputsUseCase(reader ports.WorkflowMetadataReader, files ports.FileProvider, progress ports.ProgressReporter) *DownloadOutputsUseCase {
+	return &DownloadOutputsUseCase{reader: reader, files: files, progress: progress}
+}
+
+// DownloadOutputsInput represents the input for downloading outputs.
+type DownloadOutputsInput struct {
+	WorkflowID string
+	Dir        string
+	// Parallelism is how many files are downloaded at once;
+	// DefaultDownloadParallelism if zero.
+	Parallelism int
+}
+
+// DownloadOutputsOutput represents the result of downloading outputs.
+type DownloadOutputsOutput struct {
+	Manifest     DownloadManifest
+	ManifestPath string
+	// Failed counts the files that were not downloaded intact.
+	Failed int
+}
+
+// Execute downloads every file found in the workflow's outputs, including
+// inside arrays and maps, into input.Dir. Files keep their layout under the
+// workflow root, so scattered outputs with the same name do not collide.
+//
+// A download is resumed from where an earlier one stopped, and a file already
+// downloaded intact is skipped. Each file is checked against the MD5 or
+// CRC32C the storage reports. One file failing does not stop the others; the
+// manifest records every outcome.
+func (uc *DownloadOutputsUseCase) Execute(ctx context.Context, input DownloadOutputsInput) (*DownloadOutputsOutput, error) {
+	if input.WorkflowID == "" {
+		return nil, application.NewInputValidationError("workflowID", "is required")
+	}
+	if input.Dir == "" {
+		return nil, application.NewInputValidationError("dir", "is required")
+	}
+	parallelism := input.Parallelism
+	if parallelism <= 0 {
+		parallelism = DefaultDownloadParallelism
+	}
+
+	wf, err := uc.reader.GetMetadata(ctx, input.WorkflowID)
+	if err != nil {
+		return nil, application.NewUseCaseError("outputs", "failed to get workflow outputs", err)
+	}
+
+	manifest := DownloadManifest{WorkflowID: wf.ID, WorkflowName: wf.Name, Outputs: make(map[string][]string)}
+	var sources []string
+	local := make(map[string]string)
+	keys := make([]string, 0, len(wf.Outputs))
+	for key := range wf.Outputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, p := range workflow.ExtractFilePaths(wf.Outputs[key]) {
+			source := p.String()
+			if _, seen := local[source]; !seen {
+				rel, err := localOutputPath(source, wf.WorkflowRoot)
+				if err != nil {
+					return nil, application.NewUseCaseError("outputs", "cannot place output locally", err)
+				}
+				local[source] = rel
+				sources = append(sources, source)
+			}
+			manifest.Outputs[key] = append(manifest.Outputs[key], local[source])
+		}
+	}
+
+	if err := os.MkdirAll(input.Dir, 0o755); err != nil {
+		return nil, application.NewUseCaseError("outputs", "failed to create download directory", err)
+	}
+
+	manifest.Files = make([]DownloadedFile, len(sources))
+	var done atomic.Int32
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(parallelism)
+	for i, source := range sources {
+		group.Go(func() error {
+			file := uc.download(groupCtx, source, input.Dir, local[source])
+			manifest.Files[i] = file
+			uc.step("Downloaded %d/%d: %s", done.Add(1), len(sources), file.Path)
+			return nil
+		})
+	}
+	_ = group.Wait()
+	uc.doneReporting()
+
+	out := &DownloadOutputsOutput{Manifest: manifest, ManifestPath: filepath.Join(input.Dir, DownloadManifestName)}
+	for _, f := range manifest.Files {
+		if f.Status == DownloadFailed {
+			out.Failed++
+		}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, application.NewUseCaseError("outputs", "failed to encode manifest", err)
+	}
+	if err := os.WriteFile(out.ManifestPath, append(data, '\n'), 0o644); err != nil {
+		return nil, application.NewUseCaseError("outputs", "failed to write manifest", err)
+	}
+	return out, ctx.Err()
+}
+
+// download fetches one file to dir/rel and verifies it.
+func (uc *DownloadOutputsUseCase) download(ctx context.Context, source, dir, rel string) DownloadedFile {
+	file := DownloadedFile{Source: source, Path: rel}
+	fail := func(err error) DownloadedFile {
+		file.Status, file.Error = DownloadFailed, err.Error()
+		return file
+	}
+
+	size, err := uc.files.GetSize(ctx, source)
+	if err != nil {
+		return fail(err)
+	}
+	file.Size = size
+	remote, err := uc.files.GetContentDigests(ctx, source)
+	if err != nil && !errors.Is(err, ports.ErrHashUnavailable) {
+		return fail(err)
+	}
+
+	dest := filepath.Join(dir, filepath.FromSlash(rel))
+	if info, err := os.Stat(dest); err == nil && info.Size() == size {
+		if digests, err := hashLocalFile(dest); err == nil && digestsMatch(remote, digests) {
+			file.MD5, file.CRC32C, file.Status = digests.MD5, digests.CRC32C, DownloadSkipped
+			return file
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fail(err)
+	}
+	part := dest + partialSuffix
+	if err := uc.fetch(ctx, source, part, size); err != nil {
+		return fail(err)
+	}
+	digests, err := hashLocalFile(part)
+	if err != nil {
+		return fail(err)
+	}
+	if !digestsMatch(remote, digests) {
+		// A resumed download may have stitched together two versions of
+		// the object; start from scratch next time.
+		_ = os.Remove(part)
+		return fail(fmt.Errorf("checksum mismatch: storage has md5 %q crc32c %q, downloaded md5 %q crc32c %q",
+			remote.MD5, remote.CRC32C, digests.MD5, digests.CRC32C))
+	}
+	if err := os.Rename(part, dest); err != nil {
+		return fail(err)
+	}
+
+	file.MD5, file.CRC32C = digests.MD5, digests.CRC32C
+	file.Status = DownloadVerified
+	if remote.MD5 == "" && remote.CRC32C == "" {
+		file.Status = DownloadUnverified
+	}
+	return file
+}
+
+// fetch writes source to part, continuing after whatever part already holds.
+// Without ranged reads the file is fetched whole, and nothing is resumed.
+func (uc *DownloadOutputsUseCase) fetch(ctx context.Context, source, part string, size int64) error {
+	out, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = out.Close() }()
+
+	ranges, ok := uc.files.(ports.RangeReader)
+	offset, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if !ok || offset > size {
+		offset = 0
+	}
+	if err := out.Truncate(offset); err != nil {
+		return err
+	}
+	if _, err := out.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	if !ok {
+		data, err := uc.files.ReadBytes(ctx, source)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	}
+	for offset < size {
+		chunk, err := ranges.ReadRange(ctx, source, offset, min(downloadChunk, size-offset))
+		if err != nil {
+			return err
+		}
+		if len(chunk) == 0 {
+			return fmt.Errorf("%s ended at %d of %d bytes", source, offset, size)
+		}
+		if _, err := out.Write(chunk); err != nil {
+			return err
+		}
+		offset += int64(len(chunk))
+	}
+	return out.Close()
+}
+
+func (uc *DownloadOutputsUseCase) step(format string, args ...any) {
+	if uc.progress == nil {
+		return
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.progress.Step(format, args...)
+}
+
+func (uc *DownloadOutputsUseCase) doneReporting() {
+	if uc.progress != nil {
+		uc.progress.Done()
+	}
+}
+
+// localOutputPath returns where an output is written, relative to the
+// download directory: its path under the workflow root, or for a file
+// elsewhere its bucket (or host) and path.
+func localOutputPath(source, workflowRoot string) (string, error) {
+	root := strings.TrimSuffix(workflowRoot, "/") + "/"
+	rel := strings.TrimPrefix(source, root)
+	if workflowRoot == "" || rel == source {
+		rel = source
+		if u, err := url.Parse(source); err == nil && u.Scheme != "" {
+			rel = u.Host + "/" + u.Path
+		}
+	}
+	rel = strings.TrimPrefix(path.Clean("/"+rel), "/")
+	if rel == "" || !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return "", fmt.Errorf("no local path for %s", source)
+	}
+	return rel, nil
+}
+
+// hashLocalFile computes a local file's digests in the encodings storage
+// reports them in.
+func hashLocalFile(name string) (ports.FileDigests, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return ports.FileDigests{}, err
+	}
+	defer func() { _ = f.Close() }()
+
+	sum := md5.New() //nolint:gosec // matching storage checksums, not a security primitive
+	crc := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	if _, err := io.Copy(io.MultiWriter(sum, crc), f); err != nil {
+		return ports.FileDigests{}, err
+	}
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], crc.Sum32())
+	return ports.FileDigests{
+		MD5:    hex.EncodeToString(sum.Sum(nil)),
+		CRC32C: base64.StdEncoding.EncodeToString(b[:]),
+	}, nil
+}
+
+// digestsMatch compares a download with what the storage reported, by MD5
+// when it has one and CRC32C otherwise. With neither there is nothing to
+// compare, and the size check made by the caller has to do.
+func digestsMatch(remote, local ports.FileDigests) bool {
+	switch {
+	case remote.MD5 != "":
+		return strings.EqualFold(remote.MD5, local.MD5)
+	case remote.CRC32C != "":
+		return remote.CRC32C == local.CRC32C
+	default:
+		return true
+	}
+}
@@ -0,0 +1,172 @@
+package workflow
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // test fixture checksums
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// digestFiles is a rangeFiles whose storage reports MD5s, which can be set
+// wrong to simulate corruption in transit.
+type digestFiles struct {
+	*rangeFiles
+	md5      map[string]string
+	rangeLen int64 // bytes returned by ranged reads
+}
+
+func (f *digestFiles) GetContentDigests(_ context.Context, path string) (ports.FileDigests, error) {
+	if sum, ok := f.md5[path]; ok {
+		return ports.FileDigests{MD5: sum}, nil
+	}
+	data, ok := f.files[path]
+	if !ok {
+		return ports.FileDigests{}, ports.ErrFileNotFound
+	}
+	sum := md5.Sum(data) //nolint:gosec // test fixture checksums
+	return ports.FileDigests{MD5: hex.EncodeToString(sum[:])}, nil
+}
+
+func (f *digestFiles) ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error) {
+	data, err := f.rangeFiles.ReadRange(ctx, path, offset, length)
+	f.rangeLen += int64(len(data))
+	return data, err
+}
+
+func TestDownloadOutputsUseCase_Execute(t *testing.T) {
+	const root = "gs://b/wf/run-1"
+	files := &digestFiles{rangeFiles: &rangeFiles{files: map[string][]byte{
+		root + "/call-Align/shard-0/execution/out.bam": []byte("bam 0"),
+		root + "/call-Align/shard-1/execution/out.bam": []byte("bam 1"),
+		root + "/call-Report/execution/report.html":    []byte("<html>"),
+		"gs://refs/hg38.fa":                            []byte(">chr1\nACGT\n"),
+		root + "/call-Bad/execution/bad.txt":           []byte("corrupted"),
+	}}, md5: map[string]string{root + "/call-Bad/execution/bad.txt": "00000000000000000000000000000000"}}
+	repo := &mockWorkflowRepository{getMetadataFunc: func(context.Context, string) (*workflow.Workflow, error) {
+		return &workflow.Workflow{
+			ID: "run-1", Name: "wf", WorkflowRoot: root + "/",
+			Outputs: map[string]any{
+				"wf.bams":   []any{root + "/call-Align/shard-0/execution/out.bam", root + "/call-Align/shard-1/execution/out.bam"},
+				"wf.report": map[string]any{"html": root + "/call-Report/execution/report.html", "title": "QC"},
+				"wf.ref":    "gs://refs/hg38.fa",
+				"wf.bad":    root + "/call-Bad/execution/bad.txt",
+				"wf.count":  42,
+			},
+		}, nil
+	}}
+	uc := NewDownloadOutputsUseCase(repo, files, nil)
+	dir := t.TempDir()
+	// One download at a time keeps the fake's byte count race-free.
+	input := DownloadOutputsInput{WorkflowID: "run-1", Dir: dir, Parallelism: 1}
+	ctx := context.Background()
+
+	// A download interrupted earlier is resumed, not started over.
+	bam0 := filepath.Join(dir, "call-Align", "shard-0", "execution", "out.bam")
+	if err := os.MkdirAll(filepath.Dir(bam0), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bam0+partialSuffix, []byte("bam"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := uc.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if got, _ := os.ReadFile(bam0); string(got) != "bam 0" {
+		t.Errorf("resumed download = %q, want %q", got, "bam 0")
+	}
+	if want := int64(len(" 0") + len("bam 1") + len("<html>") + len(">chr1\nACGT\n") + len("corrupted")); files.rangeLen != want {
+		t.Errorf("read %d bytes, want %d: the resumed file should only fetch its rest", files.rangeLen, want)
+	}
+
+	// A file whose checksum does not match fails, and is not left behind.
+	if out.Failed != 1 {
+		t.Errorf("Failed = %d, want 1", out.Failed)
+	}
+	for _, f := range out.Manifest.Files {
+		want := DownloadVerified
+		if strings.HasSuffix(f.Source, "bad.txt") {
+			want = DownloadFailed
+		}
+		if f.Status != want {
+			t.Errorf("%s: status %s (%s), want %s", f.Source, f.Status, f.Error, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "call-Bad", "execution", "bad.txt")); !os.IsNotExist(err) {
+		t.Error("a file failing verification was kept")
+	}
+
+	// The manifest maps output keys to local paths; files outside the
+	// workflow root are placed under their bucket.
+	data, err := os.ReadFile(out.ManifestPath)
+	if err != nil {
+		t.Fatalf("manifest not written: %v", err)
+	}
+	var manifest DownloadManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	wantOutputs := map[string][]string{
+		"wf.bams":   {"call-Align/shard-0/execution/out.bam", "call-Align/shard-1/execution/out.bam"},
+		"wf.report": {"call-Report/execution/report.html"},
+		"wf.ref":    {"refs/hg38.fa"},
+		"wf.bad":    {"call-Bad/execution/bad.txt"},
+	}
+	for key, want := range wantOutputs {
+		if got := manifest.Outputs[key]; strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("manifest[%s] = %v, want %v", key, got, want)
+		}
+	}
+	if _, ok := manifest.Outputs["wf.count"]; ok {
+		t.Error("manifest lists an output holding no file")
+	}
+
+	// Downloading again skips what is already there and intact.
+	files.rangeLen = 0
+	out, err = uc.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	for _, f := range out.Manifest.Files {
+		if f.Status != DownloadSkipped && f.Status != DownloadFailed {
+			t.Errorf("%s downloaded again (%s)", f.Source, f.Status)
+		}
+	}
+	if files.rangeLen != int64(len("corrupted")) {
+		t.Errorf("second run read %d bytes, want only the failed file again", files.rangeLen)
+	}
+}
+
+func TestLocalOutputPath(t *testing.T) {
+	tests := []struct {
+		source, root, want string
+		wantErr            bool
+	}{
+		{"gs://b/run/call-A/out.txt", "gs://b/run", "call-A/out.txt", false},
+		{"gs://b/run/call-A/out.txt", "gs://b/run/", "call-A/out.txt", false},
+		{"gs://refs/hg38.fa", "gs://b/run", "refs/hg38.fa", false},
+		{"s3://bucket/x/y.vcf", "", "bucket/x/y.vcf", false},
+		{"https://host.example/data/a.txt?sig=1", "", "host.example/data/a.txt", false},
+		{"/cromwell-executions/wf/run/call-A/out.txt", "/cromwell-executions/wf/run", "call-A/out.txt", false},
+		{"/data/ref.fa", "/cromwell-executions/wf/run", "data/ref.fa", false},
+		{"gs://b/run/../../etc/passwd", "", "etc/passwd", false},
+		{"gs://b/run", "gs://b/run", "b/run", false},
+		{"gs://", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			got, err := localOutputPath(tt.source, tt.root)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("localOutputPath(%q, %q) = %q, %v, want %q", tt.source, tt.root, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// OutputCheck is the outcome of verifying one output file.
+type OutputCheck string
+
+const (
+	// OutputIntact is a file matching its baseline record.
+	OutputIntact OutputCheck = "intact"
+	// OutputPresent is a file that exists but has no baseline record to be
+	// compared with.
+	OutputPresent OutputCheck = "present"
+	// OutputChanged is a file whose size or digest differs from its
+	// baseline record.
+	OutputChanged OutputCheck = "changed"
+	// OutputMissing is a file that no longer exists.
+	OutputMissing OutputCheck = "missing"
+	// OutputUnreadable is a file whose storage could not be asked about it.
+	OutputUnreadable OutputCheck = "error"
+)
+
+// VerifiedOutput is one output file and what its verification found.
+type VerifiedOutput struct {
+	// Key is the output the file belongs to; the first one, in key order,
+	// when several outputs list it.
+	Key  string
+	Path string
+	Size int64
+	// Digests are what the storage reported for the file.
+	Digests ports.FileDigests
+	Status  OutputCheck
+	// Detail explains a changed or unreadable file.
+	Detail string
+}
+
+// WorkflowOutputsCheck is the verification of one workflow's outputs.
+type WorkflowOutputsCheck struct {
+	WorkflowID   string
+	WorkflowName string
+	Files        []VerifiedOutput
+	// Err is set when the workflow's outputs could not be listed.
+	Err error
+}
+
+// Problems counts the files that are missing, changed or unreadable.
+func (c WorkflowOutputsCheck) Problems() int {
+	n := 0
+	for _, f := range c.Files {
+		switch f.Status {
+		case OutputMissing, OutputChanged, OutputUnreadable:
+			n++
+		}
+	}
+	return n
+}
+
+// VerifyOutputsUseCase checks that finished workflows' outputs still exist
+// and are what they were, without downloading them.
+type VerifyOutputsUseCase struct {
+	reader   ports.WorkflowMetadataReader
+	querier  ports.WorkflowQuerier
+	files    ports.FileProvider
+	baseline ports.OutputBaseline
+	progress ports.ProgressReporter
+	mu       sync.Mutex // serializes progress reports from concurrent checks
+}
+
+// NewVerifyOutputsUseCase creates a new verify outputs use case. baseline and
+// progress may be nil; without a baseline files can only be checked for
+// existence.
+func NewVerifyOutputsUseCase(reader ports.WorkflowMetadataReader, querier ports.WorkflowQuerier, files ports.FileProvider, baseline ports.OutputBaseline, progress ports.ProgressReporter) *VerifyOutputsUseCase {
+	return &VerifyOutputsUseCase{reader: reader, querier: querier, files: files, baseline: baseline, progress: progress}
+}
+
+// VerifyOutputsInput represents the input for verifying outputs.
+type VerifyOutputsInput struct {
+	// WorkflowIDs are the workflows to verify. When empty, the workflows
+	// matching Query are verified instead.
+	WorkflowIDs []string
+	// Query selects workflows when no IDs are given. With no status filter
+	// it selects succeeded workflows, the ones whose outputs are final.
+	Query workflow.QueryFilter
+	// Record adds the files without a baseline record to the baseline, so
+	// later verifications can detect changes to them.
+	Record bool
+}
+
+// VerifyOutputsOutput represents the result of verifying outputs.
+type VerifyOutputsOutput struct {
+	Workflows []WorkflowOutputsCheck
+	// Recorded counts the files added to the baseline.
+	Recorded int
+}
+
+// Execute checks every file in each workflow's outputs, reading only object
+// metadata. A file with a baseline record is compared with it by size and
+// digest; a file without one can only be found present. A workflow whose
+// metadata cannot be read is reported and does not stop the others.
+func (uc *VerifyOutputsUseCase) Execute(ctx context.Context, input VerifyOutputsInput) (*VerifyOutputsOutput, error) {
+	if input.Record && uc.baseline == nil {
+		return nil, application.NewInputValidationError("record", "no baseline is available")
+	}
+
+	ids := input.WorkflowIDs
+	if len(ids) == 0 {
+		filter := input.Query
+		if len(filter.Status) == 0 {
+			filter.Status = []workflow.Status{workflow.StatusSucceeded}
+		}
+		result, err := uc.querier.Query(ctx, filter)
+		if err != nil {
+			return nil, application.NewUseCaseError("verify-outputs", "failed to query workflows", err)
+		}
+		for _, wf := range result.Workflows {
+			ids = append(ids, wf.ID)
+		}
+	}
+
+	defer uc.doneReporting()
+	out := &VerifyOutputsOutput{}
+	for _, id := range ids {
+		check := uc.verifyWorkflow(ctx, id)
+		if input.Record {
+			out.Recorded += uc.record(check)
+		}
+		out.Workflows = append(out.Workflows, check)
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+	}
+
+	if out.Recorded > 0 {
+		if err := uc.baseline.Save(); err != nil {
+			return out, application.NewUseCaseError("verify-outputs", "failed to save baseline", err)
+		}
+	}
+	return out, nil
+}
+
+// verifyWorkflow lists a workflow's output files and checks them concurrently.
+func (uc *VerifyOutputsUseCase) verifyWorkflow(ctx context.Context, id string) WorkflowOutputsCheck {
+	check := WorkflowOutputsCheck{WorkflowID: id}
+	wf, err := uc.reader.GetMetadata(ctx, id)
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	check.WorkflowName = wf.Name
+
+	keys := make([]string, 0, len(wf.Outputs))
+	for key := range wf.Outputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		for _, p := range workflow.ExtractFilePaths(wf.Outputs[key]) {
+			if path := p.String(); !seen[path] {
+				seen[path] = true
+				check.Files = append(check.Files, VerifiedOutput{Key: key, Path: path})
+			}
+		}
+	}
+
+	var done atomic.Int32
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(digestPrefetch)
+	for i := range check.Files {
+		group.Go(func() error {
+			uc.verifyFile(groupCtx, &check.Files[i])
+			uc.step("Checked %d/%d outputs of %s", done.Add(1), len(check.Files), id)
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return check
+}
+
+// verifyFile fills in what the storage says about one file.
+func (uc *VerifyOutputsUseCase) verifyFile(ctx context.Context, file *VerifiedOutput) {
+	size, err := uc.files.GetSize(ctx, file.Path)
+	if err != nil {
+		file.Status, file.Detail = fileError(err)
+		return
+	}
+	digests, err := uc.files.GetContentDigests(ctx, file.Path)
+	if err != nil && !errors.Is(err, ports.ErrHashUnavailable) {
+		file.Status, file.Detail = fileError(err)
+		return
+	}
+	file.Size, file.Digests = size, digests
+
+	var record ports.OutputRecord
+	var ok bool
+	if uc.baseline != nil {
+		record, ok = uc.baseline.Get(file.Path)
+	}
+	if !ok {
+		file.Status = OutputPresent
+		return
+	}
+	file.Status, file.Detail = compareWithRecord(size, digests, record)
+}
+
+// record adds a workflow's present files to the baseline and returns how
+// many it added. Changed files keep their record, so they go on being
+// reported until the baseline entry is removed.
+func (uc *VerifyOutputsUseCase) record(check WorkflowOutputsCheck) int {
+	n := 0
+	now := time.Now()
+	for _, f := range check.Files {
+		if f.Status == OutputPresent {
+			uc.baseline.Set(f.Path, ports.OutputRecord{Size: f.Size, Digests: f.Digests, RecordedAt: now})
+			n++
+		}
+	}
+	return n
+}
+
+func (uc *VerifyOutputsUseCase) step(format string, args ...any) {
+	if uc.progress == nil {
+		return
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.progress.Step(format, args...)
+}
+
+func (uc *VerifyOutputsUseCase) doneReporting() {
+	if uc.progress != nil {
+		uc.progress.Done()
+	}
+}
+
+// fileError classifies a failed storage lookup.
+func fileError(err error) (OutputCheck, string) {
+	if errors.Is(err, ports.ErrFileNotFound) {
+		return OutputMissing, ""
+	}
+	return OutputUnreadable, err.Error()
+}
+
+// compareWithRecord compares a file with its baseline record, by size and
+// then by whichever digest both sides have. A digest only one side has is
+// not a difference: storage may stop reporting one, as when an object is
+// rewritten as a composite.
+func compareWithRecord(size int64, digests ports.FileDigests, record ports.OutputRecord) (OutputCheck, string) {
+	recorded := record.RecordedAt.Format(time.DateOnly)
+	if size != record.Size {
+		return OutputChanged, fmt.Sprintf("size %d, was %d on %s", size, record.Size, recorded)
+	}
+	if digests.MD5 != "" && record.Digests.MD5 != "" && !strings.EqualFold(digests.MD5, record.Digests.MD5) {
+		return OutputChanged, fmt.Sprintf("md5 differs from the one recorded on %s", recorded)
+	}
+	if digests.CRC32C != "" && record.Digests.CRC32C != "" && digests.CRC32C != record.Digests.CRC32C {
+		return OutputChanged, fmt.Sprintf("crc32c differs from the one recorded on %s", recorded)
+	}
+	return OutputIntact, ""
+}
@@ -0,0 +1,145 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// memoryBaseline is an OutputBaseline kept in a map.
+type memoryBaseline struct {
+	records map[string]ports.OutputRecord
+	saves   int
+}
+
+func (b *memoryBaseline) Load() error { return nil }
+func (b *memoryBaseline) Save() error { b.saves++; return nil }
+
+func (b *memoryBaseline) Get(path string) (ports.OutputRecord, bool) {
+	r, ok := b.records[path]
+	return r, ok
+}
+
+func (b *memoryBaseline) Set(path string, record ports.OutputRecord) {
+	b.records[path] = record
+}
+
+func TestVerifyOutputsUseCase_Execute(t *testing.T) {
+	const root = "gs://b/wf/run-1"
+	files := &digestFiles{rangeFiles: &rangeFiles{files: map[string][]byte{
+		root + "/call-A/shard-0/out.vcf": []byte("vcf 0"),
+		root + "/call-A/shard-1/out.vcf": []byte("vcf 1"),
+		root + "/call-B/report.html":     []byte("<html>"),
+	}}}
+	repo := &mockWorkflowRepository{
+		getMetadataFunc: func(_ context.Context, id string) (*workflow.Workflow, error) {
+			if id != "run-1" {
+				return nil, errors.New("workflow not found")
+			}
+			return &workflow.Workflow{ID: id, Name: "wf", Outputs: map[string]any{
+				"wf.vcfs":   []any{root + "/call-A/shard-0/out.vcf", root + "/call-A/shard-1/out.vcf"},
+				"wf.report": root + "/call-B/report.html",
+				"wf.again":  root + "/call-B/report.html",
+				"wf.count":  3,
+			}}, nil
+		},
+		queryFunc: func(_ context.Context, filter workflow.QueryFilter) (*workflow.QueryResult, error) {
+			if len(filter.Status) != 1 || filter.Status[0] != workflow.StatusSucceeded {
+				t.Errorf("query status = %v, want only succeeded workflows", filter.Status)
+			}
+			return &workflow.QueryResult{Workflows: []workflow.Workflow{{ID: "run-1"}, {ID: "run-2"}}}, nil
+		},
+	}
+	baseline := &memoryBaseline{records: make(map[string]ports.OutputRecord)}
+	uc := NewVerifyOutputsUseCase(repo, repo, files, baseline, nil)
+	ctx := context.Background()
+
+	statuses := func(out *VerifyOutputsOutput) map[string]OutputCheck {
+		got := make(map[string]OutputCheck)
+		for _, f := range out.Workflows[0].Files {
+			got[f.Path] = f.Status
+		}
+		return got
+	}
+
+	// Without a baseline the files can only be found present, and
+	// recording them makes the next check compare against them.
+	out, err := uc.Execute(ctx, VerifyOutputsInput{WorkflowIDs: []string{"run-1"}, Record: true})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if n := len(out.Workflows[0].Files); n != 3 {
+		t.Fatalf("checked %d files, want 3 (a file listed by two outputs counts once)", n)
+	}
+	for path, status := range statuses(out) {
+		if status != OutputPresent {
+			t.Errorf("%s: %s, want %s", path, status, OutputPresent)
+		}
+	}
+	if out.Recorded != 3 || baseline.saves != 1 {
+		t.Errorf("recorded %d files in %d saves, want 3 in 1", out.Recorded, baseline.saves)
+	}
+
+	// A lifecycle rule deletes one file and another is overwritten.
+	delete(files.files, root+"/call-A/shard-1/out.vcf")
+	files.files[root+"/call-B/report.html"] = []byte("<HTML>")
+
+	out, err = uc.Execute(ctx, VerifyOutputsInput{Record: true})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	want := map[string]OutputCheck{
+		root + "/call-A/shard-0/out.vcf": OutputIntact,
+		root + "/call-A/shard-1/out.vcf": OutputMissing,
+		root + "/call-B/report.html":     OutputChanged,
+	}
+	for path, status := range statuses(out) {
+		if status != want[path] {
+			t.Errorf("%s: %s, want %s", path, status, want[path])
+		}
+	}
+	if p := out.Workflows[0].Problems(); p != 2 {
+		t.Errorf("Problems() = %d, want 2", p)
+	}
+	if out.Recorded != 0 {
+		t.Errorf("recorded %d files, want a changed file to keep its old record", out.Recorded)
+	}
+
+	// A workflow selected by the query that cannot be read is reported
+	// without stopping the others.
+	if len(out.Workflows) != 2 || out.Workflows[1].Err == nil {
+		t.Errorf("workflows = %+v, want run-2 reported with an error", out.Workflows)
+	}
+}
+
+func TestCompareWithRecord(t *testing.T) {
+	record := ports.OutputRecord{
+		Size:       10,
+		Digests:    ports.FileDigests{MD5: "abc", CRC32C: "AAAA"},
+		RecordedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		name    string
+		size    int64
+		digests ports.FileDigests
+		want    OutputCheck
+	}{
+		{"same", 10, ports.FileDigests{MD5: "ABC", CRC32C: "AAAA"}, OutputIntact},
+		{"size differs", 11, ports.FileDigests{MD5: "abc"}, OutputChanged},
+		{"md5 differs", 10, ports.FileDigests{MD5: "abd"}, OutputChanged},
+		{"crc32c differs", 10, ports.FileDigests{CRC32C: "BBBB"}, OutputChanged},
+		{"no digest reported", 10, ports.FileDigests{}, OutputIntact},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, detail := compareWithRecord(tt.size, tt.digests, record)
+			if got != tt.want {
+				t.Errorf("compareWithRecord() = %s (%s), want %s", got, detail, tt.want)
+			}
+		})
+	}
+}
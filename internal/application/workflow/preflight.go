@@ -7,11 +7,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
 	"golang.org/x/sync/errgroup"
 
 	"github.com/lmtani/pumbaa/internal/application"
 	"github.com/lmtani/pumbaa/internal/application/ports"
+	workflow2 "github.com/lmtani/pumbaa/internal/domain/workflow"
 	"github.com/lmtani/pumbaa/pkg/wdl"
 )
 
@@ -48,6 +52,14 @@ type PreflightCheck struct {
 type PreflightReport struct {
 	WorkflowName string
 	Checks       []PreflightCheck
+	// Resources is the runtime each call will request, evaluated from the
+	// inputs. Empty when the WDL could not be parsed.
+	Resources []wdl.CallRuntime
+	// Footprint is the predicted compute of the run, or nil when the runtimes
+	// could not be evaluated.
+	Footprint *workflow2.Footprint
+	// Inputs is the inputs JSON that was checked, after merging every source.
+	Inputs []byte
 }
 
 // HasErrors reports whether anything would make the run fail.
@@ -90,6 +102,7 @@ func (e *PreflightFailedError) Error() string {
 type PreflightUseCase struct {
 	fileProvider ports.FileProvider
 	health       ports.HealthChecker
+	quota        workflow2.ResourceQuota
 }
 
 // NewPreflightUseCase creates a new preflight use case. health may be nil,
@@ -98,10 +111,16 @@ func NewPreflightUseCase(fp ports.FileProvider, health ports.HealthChecker) *Pre
 	return &PreflightUseCase{fileProvider: fp, health: health}
 }
 
+// SetQuota sets the compute a run may hold at once. The footprint check warns
+// when the predicted peak goes over it; with no quota it only reports.
+func (uc *PreflightUseCase) SetQuota(q workflow2.ResourceQuota) {
+	uc.quota = q
+}
+
 // PreflightInput is the input for a preflight run.
 type PreflightInput struct {
 	WorkflowFile string
-	InputsFile   string
+	Inputs       InputSources
 	// SkipServer skips the Cromwell health check (submit does this: it is
 	// about to contact the server anyway).
 	SkipServer bool
@@ -110,6 +129,14 @@ type PreflightInput struct {
 	// DependenciesFile is an optional imports zip; its contents are checked
 	// against the workflow's imports.
 	DependenciesFile string
+	// SchemaFile is an optional JSON Schema, as written by "wdl schema", the
+	// inputs are validated against too. It still applies when the WDL cannot
+	// be parsed locally.
+	SchemaFile string
+	// Remote configures how http(s) imports are fetched, so calls into
+	// remotely imported tasks are checked too. Offline resolves them from
+	// the import cache only.
+	Remote wdl.RemoteImportOptions
 }
 
 // Execute runs every check and returns the full report. It does not stop at
@@ -124,14 +151,6 @@ func (uc *PreflightUseCase) Execute(ctx context.Context, input PreflightInput) (
 		return nil, application.NewUseCaseError("preflight", "failed to read workflow file", err)
 	}
 
-	var inputsData []byte
-	if input.InputsFile != "" {
-		inputsData, err = uc.fileProvider.ReadBytes(ctx, input.InputsFile)
-		if err != nil {
-			return nil, application.NewUseCaseError("preflight", "failed to read inputs file", err)
-		}
-	}
-
 	var depsData []byte
 	if input.DependenciesFile != "" {
 		depsData, err = uc.fileProvider.ReadBytes(ctx, input.DependenciesFile)
@@ -140,21 +159,139 @@ func (uc *PreflightUseCase) Execute(ctx context.Context, input PreflightInput) (
 		}
 	}
 
-	return uc.check(ctx, source, inputsData, depsData, input.SkipServer, input.SkipPaths), nil
+	imports := resolveImports(source, depsData, input.Remote)
+	inputsData, err := assembleInputs(ctx, uc.fileProvider, input.Inputs, source, imports.sources)
+	if err != nil {
+		return nil, application.NewUseCaseError("preflight", "failed to read inputs file", err)
+	}
+
+	var schemaData []byte
+	if input.SchemaFile != "" {
+		schemaData, err = uc.fileProvider.ReadBytes(ctx, input.SchemaFile)
+		if err != nil {
+			return nil, application.NewUseCaseError("preflight", "failed to read schema file", err)
+		}
+	}
+
+	report := uc.check(ctx, source, inputsData, imports, input.SkipServer, input.SkipPaths)
+	report.Inputs = inputsData
+	if schemaData != nil {
+		report.Checks = insertAfter(report.Checks, "Inputs", schemaCheck(schemaData, inputsData))
+	}
+	return report, nil
+}
+
+// importSources are what a submission's imports resolve against.
+type importSources struct {
+	// deps is the dependencies zip as submitted; nil without one.
+	deps []byte
+	// sources holds the zip's files and the fetched remote imports.
+	sources wdl.SourceSet
+	// remote reports the fetched remote imports; nil when there are none.
+	remote *PreflightCheck
+}
+
+// resolveImports gathers the sources of a workflow's imports: the files in
+// the dependencies zip, and the http(s) imports of the workflow and of those
+// files, fetched through the import cache.
+func resolveImports(source, depsData []byte, remote wdl.RemoteImportOptions) importSources {
+	imports := importSources{deps: depsData}
+	sources, report := wdl.WithRemoteImports(source, zipSources(depsData), remote)
+	imports.sources = sources
+	if len(report.Fetched) == 0 && len(report.Findings) == 0 {
+		return imports
+	}
+
+	check := PreflightCheck{Name: "Remote imports", Status: CheckOK}
+	check.Detail = fmt.Sprintf("%d file(s) fetched", len(report.Fetched))
+	for _, f := range report.Findings {
+		check.Items = append(check.Items, PreflightItem{Severity: string(f.Severity), Message: f.Message})
+	}
+	if len(check.Items) > 0 {
+		// Cromwell fetches remote imports itself, so this only means the
+		// calls into them could not be checked here.
+		check.Status = CheckWarning
+		check.Detail = fmt.Sprintf("%d file(s) fetched, %d could not be", len(report.Fetched), len(check.Items))
+	}
+	imports.remote = &check
+	return imports
+}
+
+// zipSources reads an imports zip for the checks that resolve imported
+// definitions. A zip that cannot be read is reported by the dependencies
+// check, so here it only means nothing is resolved.
+func zipSources(depsData []byte) wdl.SourceSet {
+	if len(depsData) == 0 {
+		return nil
+	}
+	sources, _ := wdl.SourcesFromZip(depsData)
+	return sources
+}
+
+// schemaCheck validates the inputs against a JSON Schema.
+func schemaCheck(schemaData, inputsData []byte) PreflightCheck {
+	check := PreflightCheck{Name: "Inputs schema"}
+	schema, err := wdl.ParseJSONSchema(schemaData)
+	if err != nil {
+		check.Status = CheckFailed
+		check.Detail = "schema could not be read"
+		check.Items = []PreflightItem{{Severity: string(wdl.SeverityError), Message: err.Error()}}
+		return check
+	}
+	for _, f := range schema.Validate(inputsData) {
+		check.Items = append(check.Items, PreflightItem{
+			Severity: string(f.Severity),
+			Subject:  f.Input,
+			Message:  f.Message,
+		})
+	}
+	if len(check.Items) > 0 {
+		check.Status = CheckFailed
+		check.Detail = "inputs do not match the schema"
+		return check
+	}
+	check.Status = CheckOK
+	check.Detail = "inputs match the schema"
+	return check
+}
+
+// insertAfter places a check right after the named one, or last when that
+// check is absent.
+func insertAfter(checks []PreflightCheck, name string, c PreflightCheck) []PreflightCheck {
+	for i, existing := range checks {
+		if existing.Name == name {
+			return append(checks[:i+1], append([]PreflightCheck{c}, checks[i+1:]...)...)
+		}
+	}
+	return append(checks, c)
 }
 
 // check runs the checklist over already-read sources, so callers that hold
 // the bytes (submit) do not read them twice.
-func (uc *PreflightUseCase) check(ctx context.Context, source, inputsData, depsData []byte, skipServer, skipPaths bool) *PreflightReport {
+func (uc *PreflightUseCase) check(ctx context.Context, source, inputsData []byte, imports importSources, skipServer, skipPaths bool) *PreflightReport {
 	report := &PreflightReport{}
 	report.Checks = append(report.Checks, uc.checkServer(ctx, skipServer))
 
-	inputsReport := wdl.CheckInputs(source, inputsData)
+	// Imported tasks and subworkflows let call-level inputs be checked too.
+	inputsReport := wdl.CheckInputsWithSources(source, inputsData, imports.sources)
 	report.WorkflowName = inputsReport.WorkflowName
 	report.Checks = append(report.Checks, syntaxCheck(inputsReport), inputsCheck(inputsReport))
 
 	report.Checks = append(report.Checks, uc.checkPaths(ctx, inputsReport.Files, skipPaths))
-	report.Checks = append(report.Checks, dependenciesCheck(source, depsData))
+	report.Checks = append(report.Checks, dependenciesCheck(source, imports.deps))
+	if imports.remote != nil {
+		report.Checks = append(report.Checks, *imports.remote)
+	}
+
+	resources, plan := uc.checkResources(ctx, source, inputsData, imports.sources, inputsReport.Parsed, skipPaths)
+	report.Checks = append(report.Checks, resources)
+	if plan != nil {
+		report.Resources = plan.Calls
+	}
+
+	footprint, predicted := uc.checkFootprint(source, imports.sources, plan)
+	report.Checks = append(report.Checks, footprint)
+	report.Footprint = predicted
 
 	return report
 }
@@ -304,6 +441,120 @@ func (uc *PreflightUseCase) checkPaths(ctx context.Context, files []wdl.FileRef,
 	return check
 }
 
+// checkResources evaluates the runtime section of every call against the
+// inputs, so the CPU, memory and disk each task will ask for are visible
+// before they are paid for. Attributes that depend on another call's outputs
+// are only known once the run is under way, which is expected rather than a
+// problem; the check therefore never fails.
+//
+// size() is answered from storage, unless path checks were skipped: the user
+// asked not to touch storage, so those attributes stay unevaluated.
+func (uc *PreflightUseCase) checkResources(ctx context.Context, source, inputsData []byte, sources wdl.SourceSet, parsed, skipPaths bool) (PreflightCheck, *wdl.RuntimePlan) {
+	check := PreflightCheck{Name: "Resources"}
+	if !parsed {
+		check.Status = CheckSkipped
+		check.Detail = "WDL could not be parsed"
+		return check, nil
+	}
+
+	var files wdl.FileSizer
+	if !skipPaths {
+		files = uc.fileProvider
+	}
+
+	plan, err := wdl.EvaluateRuntimes(ctx, source, sources, inputsData, files)
+	if err != nil {
+		check.Status = CheckSkipped
+		check.Detail = "could not evaluate: " + err.Error()
+		return check, nil
+	}
+
+	deferred := 0
+	for _, c := range plan.Calls {
+		deferred += len(c.Unevaluated)
+	}
+	check.Status = CheckOK
+	switch {
+	case len(plan.Calls) == 0:
+		check.Detail = "no calls"
+	case deferred > 0:
+		check.Detail = fmt.Sprintf("%d call(s) evaluated; %d attribute(s) known only at run time", len(plan.Calls), deferred)
+	default:
+		check.Detail = fmt.Sprintf("%d call(s) evaluated", len(plan.Calls))
+	}
+	return check, plan
+}
+
+// checkFootprint predicts how many VMs, CPUs and how much memory the run will
+// hold at its peak, and warns when that goes over the configured quota:
+// regional quotas are counted concurrently, and a run that exhausts one
+// stalls or dies part-way through.
+func (uc *PreflightUseCase) checkFootprint(source []byte, sources wdl.SourceSet, plan *wdl.RuntimePlan) (PreflightCheck, *workflow2.Footprint) {
+	check := PreflightCheck{Name: "Footprint"}
+	if plan == nil {
+		check.Status = CheckSkipped
+		check.Detail = "runtimes could not be evaluated"
+		return check, nil
+	}
+
+	// The graph only adds the ordering between calls; without it every call
+	// counts as concurrent, which overstates the peak but never hides it.
+	var dependsOn map[string][]string
+	if graph, err := wdl.BuildCallGraphWithSources(source, sources); err == nil {
+		dependsOn = graph.Dependencies()
+	}
+
+	var demands []workflow2.CallDemand
+	for _, c := range plan.Calls {
+		if c.Skipped {
+			continue
+		}
+		demands = append(demands, workflow2.CallDemand{
+			Name:      c.Call,
+			DependsOn: dependsOn[c.Call],
+			Instances: c.Shards,
+			CPU:       parseCPU(c.Attributes["cpu"]),
+			Memory:    workflow2.Memory(c.Attributes["memory"]),
+		})
+	}
+	f := workflow2.PredictFootprint(demands)
+
+	bound := ""
+	if len(f.UnknownInstances) > 0 {
+		bound = "at least "
+	}
+	check.Detail = fmt.Sprintf("peak %s%d VM(s), %d CPU(s), %.0f GB; %d VM(s) in total",
+		bound, f.PeakVMs, f.PeakCPUs, f.PeakMemoryGB, f.TotalVMs)
+
+	breaches := f.Exceeds(uc.quota)
+	if len(breaches) == 0 {
+		check.Status = CheckOK
+		return check, &f
+	}
+	check.Status = CheckWarning
+	for _, b := range breaches {
+		check.Items = append(check.Items, PreflightItem{
+			Severity: string(wdl.SeverityWarning),
+			Subject:  b.Resource,
+			Message: fmt.Sprintf("peak of %.0f exceeds the quota of %.0f (stage running %s)",
+				b.Peak, b.Limit, strings.Join(f.PeakCalls, ", ")),
+		})
+	}
+	return check, &f
+}
+
+// parseCPU reads a cpu attribute, which the engine accepts as an Int or a
+// Float. Anything else is 0, which the footprint treats as the engine default.
+func parseCPU(v string) int {
+	if n, err := strconv.Atoi(v); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return int(math.Ceil(f))
+	}
+	return 0
+}
+
 func hasSeverity(items []PreflightItem, s wdl.Severity) bool {
 	for _, i := range items {
 		if i.Severity == string(s) {
@@ -6,11 +6,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/lmtani/pumbaa/internal/application/ports"
 	domain "github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/pkg/wdl"
 )
 
 // mockHealthChecker is a test double for ports.HealthChecker.
@@ -68,7 +73,7 @@ func TestPreflightAllGreen(t *testing.T) {
 	health := &mockHealthChecker{status: &domain.HealthStatus{OK: true}}
 	uc := NewPreflightUseCase(fp, health)
 
-	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", InputsFile: "inputs.json"})
+	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}})
 	if err != nil {
 		t.Fatalf("Execute() error = %v", err)
 	}
@@ -100,7 +105,7 @@ func TestPreflightReportsEveryProblemAtOnce(t *testing.T) {
 		})
 	uc := NewPreflightUseCase(fp, &mockHealthChecker{status: &domain.HealthStatus{OK: true}})
 
-	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", InputsFile: "inputs.json"})
+	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}})
 	if err != nil {
 		t.Fatalf("Execute() error = %v", err)
 	}
@@ -134,7 +139,7 @@ func TestPreflightUnverifiablePathIsAWarning(t *testing.T) {
 		})
 	uc := NewPreflightUseCase(fp, &mockHealthChecker{status: &domain.HealthStatus{OK: true}})
 
-	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", InputsFile: "inputs.json"})
+	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}})
 	if err != nil {
 		t.Fatalf("Execute() error = %v", err)
 	}
@@ -155,7 +160,7 @@ func TestPreflightUnreachableServerFails(t *testing.T) {
 	fp := preflightFiles(preflightWDL, `{"Align.reads": "gs://b/r.fastq", "Align.sample": "NA12878"}`, nil)
 	uc := NewPreflightUseCase(fp, &mockHealthChecker{err: errors.New("connection refused")})
 
-	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", InputsFile: "inputs.json"})
+	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}})
 	if err != nil {
 		t.Fatalf("Execute() error = %v", err)
 	}
@@ -176,7 +181,7 @@ func TestPreflightDegradedServerWarns(t *testing.T) {
 		status: &domain.HealthStatus{OK: false, Degraded: true, UnhealthySystems: []string{"PAPI"}},
 	})
 
-	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", InputsFile: "inputs.json"})
+	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}})
 	if err != nil {
 		t.Fatalf("Execute() error = %v", err)
 	}
@@ -198,7 +203,7 @@ func TestPreflightSkipFlags(t *testing.T) {
 
 	report, err := uc.Execute(context.Background(), PreflightInput{
 		WorkflowFile: "align.wdl",
-		InputsFile:   "inputs.json",
+		Inputs:       InputSources{Files: []string{"inputs.json"}},
 		SkipServer:   true,
 		SkipPaths:    true,
 	})
@@ -225,7 +230,7 @@ func TestPreflightWithoutHealthCheckerSkipsServer(t *testing.T) {
 		func(ctx context.Context, path string) (int64, error) { return 1, nil })
 	uc := NewPreflightUseCase(fp, nil)
 
-	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", InputsFile: "inputs.json"})
+	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}})
 	if err != nil {
 		t.Fatalf("Execute() error = %v", err)
 	}
@@ -238,7 +243,7 @@ func TestPreflightUnparseableWDLDoesNotBlock(t *testing.T) {
 	fp := preflightFiles("this is not WDL {{{", `{"whatever": 1}`, nil)
 	uc := NewPreflightUseCase(fp, nil)
 
-	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", InputsFile: "inputs.json"})
+	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}})
 	if err != nil {
 		t.Fatalf("Execute() error = %v", err)
 	}
@@ -339,7 +344,7 @@ func TestPreflightChecksDependencies(t *testing.T) {
 
 		report, err := uc.Execute(context.Background(), PreflightInput{
 			WorkflowFile:     "pipe.wdl",
-			InputsFile:       "inputs.json",
+			Inputs:           InputSources{Files: []string{"inputs.json"}},
 			DependenciesFile: "deps.zip",
 		})
 		if err != nil {
@@ -384,7 +389,7 @@ func TestPreflightChecksDependencies(t *testing.T) {
 			func(ctx context.Context, path string) (int64, error) { return 1, nil })
 		uc := NewPreflightUseCase(fp, nil)
 
-		report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", InputsFile: "inputs.json"})
+		report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}})
 		if err != nil {
 			t.Fatalf("Execute() error = %v", err)
 		}
@@ -393,3 +398,289 @@ func TestPreflightChecksDependencies(t *testing.T) {
 		}
 	})
 }
+
+func TestPreflightEvaluatesRequestedResources(t *testing.T) {
+	const wdlSrc = `version 1.0
+
+workflow Align {
+    input {
+        File reads
+        Int mem_gb = 16
+    }
+    call BwaMem { input: reads = reads, mem_gb = mem_gb }
+}
+
+task BwaMem {
+    input {
+        File reads
+        Int mem_gb
+    }
+    command <<< bwa mem ~{reads} >>>
+    runtime {
+        cpu: 8
+        memory: "~{mem_gb} GB"
+        disks: "local-disk ~{ceil(size(reads, "GB") * 3) + 10} SSD"
+    }
+}
+`
+	fp := preflightFiles(wdlSrc, `{"Align.reads": "gs://b/r.fastq"}`,
+		func(ctx context.Context, path string) (int64, error) { return 5_000_000_000, nil })
+	uc := NewPreflightUseCase(fp, nil)
+
+	report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if c := checkByName(t, report, "Resources"); c.Status != CheckOK {
+		t.Errorf("Resources check = %s (%s), want ok", c.Status, c.Detail)
+	}
+	if len(report.Resources) != 1 {
+		t.Fatalf("Resources = %+v, want one call", report.Resources)
+	}
+	got := report.Resources[0].Attributes
+	want := map[string]string{"cpu": "8", "memory": "16 GB", "disks": "local-disk 25 SSD"}
+	for attr, v := range want {
+		if got[attr] != v {
+			t.Errorf("%s = %q, want %q", attr, got[attr], v)
+		}
+	}
+}
+
+func TestPreflightFootprintWarnsOverQuota(t *testing.T) {
+	const wdlSrc = `version 1.0
+
+workflow Joint {
+    input {
+        Array[String] samples
+    }
+    scatter (s in samples) {
+        call Genotype { input: sample = s }
+    }
+    call Merge { input: vcfs = Genotype.vcf }
+}
+
+task Genotype {
+    input { String sample }
+    command <<< call ~{sample} >>>
+    runtime {
+        cpu: 16
+        memory: "64 GB"
+    }
+    output { File vcf = "out.vcf" }
+}
+
+task Merge {
+    input { Array[File] vcfs }
+    command <<< merge >>>
+    runtime {
+        cpu: 4
+        memory: "16 GB"
+    }
+}
+`
+	inputs := `{"Joint.samples": ["a", "b", "c", "d", "e", "f", "g", "h", "i", "j"]}`
+
+	tests := []struct {
+		name      string
+		quota     domain.ResourceQuota
+		status    CheckStatus
+		breaching []string
+	}{
+		{"no quota only reports", domain.ResourceQuota{}, CheckOK, nil},
+		{"fits", domain.ResourceQuota{CPUs: 200, MemoryGB: 1000}, CheckOK, nil},
+		{"over the CPU quota", domain.ResourceQuota{CPUs: 96, MemoryGB: 1000}, CheckWarning, []string{"CPUs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewPreflightUseCase(preflightFiles(wdlSrc, inputs, nil), nil)
+			uc.SetQuota(tt.quota)
+
+			report, err := uc.Execute(context.Background(), PreflightInput{WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}, SkipPaths: true})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if report.Footprint == nil || report.Footprint.PeakCPUs != 160 || report.Footprint.TotalVMs != 11 {
+				t.Fatalf("Footprint = %+v, want a peak of 160 CPUs over 11 VMs", report.Footprint)
+			}
+
+			c := checkByName(t, report, "Footprint")
+			if c.Status != tt.status {
+				t.Errorf("Footprint check = %s (%s), want %s", c.Status, c.Detail, tt.status)
+			}
+			var got []string
+			for _, item := range c.Items {
+				got = append(got, item.Subject)
+			}
+			if !reflect.DeepEqual(got, tt.breaching) {
+				t.Errorf("breaches = %v, want %v", got, tt.breaching)
+			}
+			if report.HasErrors() {
+				t.Error("a quota breach is a warning, never a blocking error")
+			}
+		})
+	}
+}
+
+func TestPreflightValidatesAgainstSchema(t *testing.T) {
+	result, err := wdl.InputsSchema([]byte(preflightWDL), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := result.Schema.MarshalIndent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		inputs string
+		status CheckStatus
+		items  []string
+	}{
+		{name: "matches", inputs: `{"Align.reads": "gs://b/r.fq", "Align.sample": "NA12878"}`, status: CheckOK},
+		{
+			name:   "violations",
+			inputs: `{"Align.reads": "gs://b/r.fq", "Align.threads": 2.5}`,
+			status: CheckFailed,
+			items:  []string{"Align.sample: is required but missing", "Align.threads: is a number, but integer is expected"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := preflightFiles(preflightWDL, tt.inputs, nil)
+			read := fp.readBytesFunc
+			fp.readBytesFunc = func(ctx context.Context, path string) ([]byte, error) {
+				if path == "schema.json" {
+					return schema, nil
+				}
+				return read(ctx, path)
+			}
+			uc := NewPreflightUseCase(fp, nil)
+
+			report, err := uc.Execute(context.Background(), PreflightInput{
+				WorkflowFile: "align.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}, SchemaFile: "schema.json", SkipPaths: true,
+			})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			c := checkByName(t, report, "Inputs schema")
+			if c.Status != tt.status {
+				t.Errorf("Inputs schema check = %s (%s), want %s", c.Status, c.Detail, tt.status)
+			}
+			var got []string
+			for _, item := range c.Items {
+				got = append(got, item.Subject+": "+item.Message)
+			}
+			if !reflect.DeepEqual(got, tt.items) {
+				t.Errorf("items = %v, want %v", got, tt.items)
+			}
+			if report.Checks[3].Name != "Inputs schema" {
+				t.Errorf("the schema check should follow the Inputs check, got %s", report.Checks[3].Name)
+			}
+		})
+	}
+}
+
+func TestPreflightResolvesRemoteImports(t *testing.T) {
+	// The remote task imports a sibling by a relative path, which must be
+	// fetched from beside it.
+	remote := map[string]string{
+		"/org/tasks/align.wdl": `version 1.0
+
+import "lib/sort.wdl" as sort
+
+task BwaMem {
+    input {
+        File reads
+    }
+    command <<< bwa mem ~{reads} >>>
+    runtime {
+        cpu: 8
+        memory: "32 GB"
+    }
+}
+`,
+		"/org/tasks/lib/sort.wdl": `version 1.0
+
+task Sort {
+    command <<< sort >>>
+}
+`,
+	}
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		content, ok := remote[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	wdlSrc := `version 1.0
+
+import "` + server.URL + `/org/tasks/align.wdl" as align
+
+workflow Align {
+    input {
+        File reads
+    }
+    call align.BwaMem { input: reads = reads }
+}
+`
+	fp := preflightFiles(wdlSrc, `{"Align.reads": "gs://b/r.fastq"}`,
+		func(ctx context.Context, path string) (int64, error) { return 42, nil })
+	uc := NewPreflightUseCase(fp, nil)
+	cache := t.TempDir()
+	input := PreflightInput{
+		WorkflowFile: "align.wdl",
+		Inputs:       InputSources{Files: []string{"inputs.json"}},
+		Remote:       wdl.RemoteImportOptions{CacheDir: cache},
+	}
+
+	report, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if c := checkByName(t, report, "Remote imports"); c.Status != CheckOK || c.Detail != "2 file(s) fetched" {
+		t.Errorf("Remote imports check = %s (%s), want ok with 2 files", c.Status, c.Detail)
+	}
+	if len(report.Resources) != 1 || report.Resources[0].Unresolved {
+		t.Fatalf("Resources = %+v, want the remote task resolved", report.Resources)
+	}
+	if got := report.Resources[0].Attributes["memory"]; got != "32 GB" {
+		t.Errorf("memory = %q, want %q", got, "32 GB")
+	}
+	if report.Footprint == nil || report.Footprint.PeakCPUs != 8 {
+		t.Errorf("Footprint = %+v, want the remote task's 8 CPUs", report.Footprint)
+	}
+
+	// Offline, the cached copies are used and the server is not asked.
+	server.Close()
+	before := requests.Load()
+	input.Remote.Offline = true
+	report, err = uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("offline Execute() error = %v", err)
+	}
+	if requests.Load() != before || len(report.Resources) != 1 || report.Resources[0].Unresolved {
+		t.Errorf("offline run made %d request(s), resources %+v; want the cache used", requests.Load()-before, report.Resources)
+	}
+
+	// Offline with an empty cache, the call cannot be checked but does not
+	// block the run: Cromwell fetches the import itself.
+	input.Remote.CacheDir = t.TempDir()
+	report, err = uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("offline Execute() with an empty cache error = %v", err)
+	}
+	if c := checkByName(t, report, "Remote imports"); c.Status != CheckWarning {
+		t.Errorf("Remote imports check = %s, want a warning on a cache miss", c.Status)
+	}
+	if report.HasErrors() {
+		t.Errorf("an unfetchable remote import must not block the run: %+v", report.Checks)
+	}
+}
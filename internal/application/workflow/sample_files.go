@@ -0,0 +1,184 @@
+// sample_files.go turns a glob over a storage location into samples: it lists
+// the matching data files through the storage port and groups them by the
+// sample name found in each file name.
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/pkg/wdl"
+)
+
+// DefaultSamplePattern names a sample after the start of a file name, before
+// the Illumina sample number, lane, read and chunk markers and the
+// extensions: "NA12878_S1_L001_R1_001.fastq.gz" and "NA12878_2.fq" are both
+// sample NA12878.
+const DefaultSamplePattern = `^(?P<sample>.+?)(?:_S\d+)?(?:_L\d{3})?(?:[._][Rr]?[12])?(?:_\d{3})?(?:\.[A-Za-z0-9]+)*$`
+
+// expandGlob lists the files matching pattern, sorted. "*", "?" and "[...]"
+// match within one path segment, as in path.Match, and "{a,b}" expands to
+// each alternative; wildcards may appear in any segment, directories
+// included.
+func expandGlob(ctx context.Context, lister ports.FileLister, pattern string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	for _, alt := range expandBraces(pattern) {
+		matches, err := globOne(ctx, lister, alt)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				files = append(files, m)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files match %s", pattern)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// globOne expands a brace-free pattern, listing one directory level per
+// segment from the first one holding a wildcard.
+func globOne(ctx context.Context, lister ports.FileLister, pattern string) ([]string, error) {
+	scheme := ""
+	if i := strings.Index(pattern, "://"); i >= 0 {
+		scheme, pattern = pattern[:i+3], pattern[i+3:]
+	}
+	segments := strings.Split(pattern, "/")
+	first := -1
+	for i, seg := range segments {
+		if strings.ContainsAny(seg, "*?[") {
+			first = i
+			break
+		}
+	}
+	// A literal path is taken as written, the way a shell passes on a
+	// brace expansion.
+	if first < 0 {
+		return []string{scheme + pattern}, nil
+	}
+	// The bucket of a cloud path is never a wildcard.
+	if scheme != "" && first == 0 {
+		return nil, fmt.Errorf("the bucket in %s%s cannot be a wildcard", scheme, pattern)
+	}
+
+	dir := scheme + strings.Join(segments[:first], "/")
+	switch {
+	case first == 1 && segments[0] == "":
+		dir = "/"
+	case dir == "":
+		dir = "."
+	}
+	dirs := []string{dir}
+	for i := first; i < len(segments); i++ {
+		last := i == len(segments)-1
+		var next []string
+		for _, d := range dirs {
+			entries, err := lister.List(ctx, d)
+			if err != nil {
+				return nil, fmt.Errorf("listing %s: %w", d, err)
+			}
+			for _, e := range entries {
+				if e.IsDir == last {
+					continue
+				}
+				matched, err := path.Match(segments[i], path.Base(e.Path))
+				if err != nil {
+					return nil, fmt.Errorf("invalid pattern %q: %w", segments[i], err)
+				}
+				if matched {
+					next = append(next, e.Path)
+				}
+			}
+		}
+		dirs = next
+	}
+	return dirs, nil
+}
+
+// expandBraces expands the first "{a,b}" group of a pattern, recursively, so
+// "x_R{1,2}.fq" becomes "x_R1.fq" and "x_R2.fq". A brace without its match is
+// kept literally.
+func expandBraces(pattern string) []string {
+	open := strings.Index(pattern, "{")
+	if open < 0 {
+		return []string{pattern}
+	}
+	depth, end := 0, -1
+	var commas []int
+	for i := open; i < len(pattern) && end < 0; i++ {
+		switch pattern[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				end = i
+			}
+		case ',':
+			if depth == 1 {
+				commas = append(commas, i)
+			}
+		}
+	}
+	if end < 0 {
+		return []string{pattern}
+	}
+
+	var alternatives []string
+	start := open + 1
+	for _, c := range append(commas, end) {
+		alternatives = append(alternatives, pattern[start:c])
+		start = c + 1
+	}
+	var out []string
+	for _, alt := range alternatives {
+		out = append(out, expandBraces(pattern[:open]+alt+pattern[end+1:])...)
+	}
+	return out
+}
+
+// groupSamples groups files by the sample their name matches in re: the
+// "sample" capture group, or else the first group, or else the whole match.
+// Samples keep the order of their first file; files that do not match are
+// returned apart.
+func groupSamples(files []string, re *regexp.Regexp) (samples []wdl.SampleFiles, unmatched []string) {
+	group := re.SubexpIndex("sample")
+	if group < 0 && re.NumSubexp() > 0 {
+		group = 1
+	}
+
+	index := make(map[string]int)
+	for _, f := range files {
+		m := re.FindStringSubmatch(path.Base(f))
+		name := ""
+		if m != nil {
+			name = m[0]
+			if group > 0 {
+				name = m[group]
+			}
+		}
+		if name == "" {
+			unmatched = append(unmatched, f)
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(samples)
+			index[name] = i
+			samples = append(samples, wdl.SampleFiles{Name: name})
+		}
+		samples[i].Files = append(samples[i].Files, f)
+	}
+	return samples, unmatched
+}
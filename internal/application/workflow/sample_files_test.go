@@ -0,0 +1,244 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// fakeTree serves listings of an in-memory object store holding files.
+func fakeTree(files ...string) func(ctx context.Context, prefix string) ([]ports.FileEntry, error) {
+	return func(_ context.Context, prefix string) ([]ports.FileEntry, error) {
+		prefix = strings.TrimSuffix(prefix, "/") + "/"
+		seen := make(map[string]bool)
+		var entries []ports.FileEntry
+		for _, f := range files {
+			rest, ok := strings.CutPrefix(f, prefix)
+			if !ok {
+				continue
+			}
+			name, _, isDir := strings.Cut(rest, "/")
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			entries = append(entries, ports.FileEntry{Path: prefix + name, IsDir: isDir})
+		}
+		if len(entries) == 0 {
+			return nil, ports.ErrFileNotFound
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+		return entries, nil
+	}
+}
+
+func TestExpandBraces(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"a.fq", []string{"a.fq"}},
+		{"*_R{1,2}.fq", []string{"*_R1.fq", "*_R2.fq"}},
+		{"{x,y}/{1,2}", []string{"x/1", "x/2", "y/1", "y/2"}},
+		{"a{b,{c,d}}e", []string{"abe", "ace", "ade"}},
+		{"a{b", []string{"a{b"}},
+	}
+	for _, tt := range tests {
+		if got := expandBraces(tt.pattern); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expandBraces(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestExpandGlob(t *testing.T) {
+	lister := &mockFileProvider{listFunc: fakeTree(
+		"gs://b/run42/S1_R1.fq.gz",
+		"gs://b/run42/S1_R2.fq.gz",
+		"gs://b/run42/S1.md5",
+		"gs://b/run42/S2_R1.fq.gz",
+		"gs://b/run42/S2_R2.fq.gz",
+		"gs://b/run43/lane1/S3_R1.fq.gz",
+	)}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "wildcard and braces in the file name",
+			pattern: "gs://b/run42/*_R{1,2}.fq.gz",
+			want:    []string{"gs://b/run42/S1_R1.fq.gz", "gs://b/run42/S1_R2.fq.gz", "gs://b/run42/S2_R1.fq.gz", "gs://b/run42/S2_R2.fq.gz"},
+		},
+		{
+			name:    "wildcard in a directory",
+			pattern: "gs://b/run*/*/*.fq.gz",
+			want:    []string{"gs://b/run43/lane1/S3_R1.fq.gz"},
+		},
+		{
+			name:    "nothing matches",
+			pattern: "gs://b/run42/*.bam",
+			wantErr: "no files match",
+		},
+		{
+			name:    "missing directory",
+			pattern: "gs://b/run99/*.fq.gz",
+			wantErr: "listing gs://b/run99",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandGlob(context.Background(), lister, tt.pattern)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expandGlob() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandGlob() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandGlob() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupSamples(t *testing.T) {
+	files := []string{
+		"gs://b/NA12878_S1_L001_R1_001.fastq.gz",
+		"gs://b/NA12878_S1_L001_R2_001.fastq.gz",
+		"gs://b/NA12879_1.fq",
+		"gs://b/NA12879_2.fq",
+		"gs://b/tumor.bam",
+	}
+
+	t.Run("default pattern", func(t *testing.T) {
+		samples, unmatched := groupSamples(files, regexp.MustCompile(DefaultSamplePattern))
+		var names []string
+		for _, s := range samples {
+			names = append(names, s.Name+":"+strings.Join(mapBase(s.Files), ","))
+		}
+		want := []string{
+			"NA12878:NA12878_S1_L001_R1_001.fastq.gz,NA12878_S1_L001_R2_001.fastq.gz",
+			"NA12879:NA12879_1.fq,NA12879_2.fq",
+			"tumor:tumor.bam",
+		}
+		if !reflect.DeepEqual(names, want) || len(unmatched) != 0 {
+			t.Errorf("samples = %v, unmatched = %v; want %v", names, unmatched, want)
+		}
+	})
+
+	t.Run("custom pattern leaves the rest apart", func(t *testing.T) {
+		samples, unmatched := groupSamples(files, regexp.MustCompile(`^(NA\d+)_`))
+		if len(samples) != 2 || samples[0].Name != "NA12878" || samples[1].Name != "NA12879" {
+			t.Errorf("samples = %+v", samples)
+		}
+		if !reflect.DeepEqual(unmatched, []string{"gs://b/tumor.bam"}) {
+			t.Errorf("unmatched = %v", unmatched)
+		}
+	})
+}
+
+func mapBase(files []string) []string {
+	out := make([]string, len(files))
+	for i, f := range files {
+		out[i] = path.Base(f)
+	}
+	return out
+}
+
+func TestScaffoldInputsUseCaseFromFiles(t *testing.T) {
+	const wdlSrc = `version 1.0
+
+workflow Align {
+    input {
+        String sample
+        File reads_r1
+        File reads_r2
+        Array[String] samples
+    }
+}
+`
+	fp := &mockFileProvider{
+		readBytesFunc: func(ctx context.Context, path string) ([]byte, error) {
+			if path != "align.wdl" {
+				return nil, errors.New("unexpected path: " + path)
+			}
+			return []byte(wdlSrc), nil
+		},
+		listFunc: fakeTree("gs://b/run/S1_R1.fq", "gs://b/run/S1_R2.fq", "gs://b/run/S2_R1.fq", "gs://b/run/S2_R2.fq"),
+	}
+	uc := NewScaffoldInputsUseCase(fp, fp, nil)
+
+	t.Run("one file per sample", func(t *testing.T) {
+		out, err := uc.Execute(context.Background(), ScaffoldInputsInput{
+			WorkflowFile: "align.wdl",
+			FromFiles:    "gs://b/run/*_R{1,2}.fq",
+			PerSample:    true,
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if len(out.Samples) != 2 || out.Template != nil {
+			t.Fatalf("got %d samples and template %q, want 2 samples and no batch template", len(out.Samples), out.Template)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(out.Samples[1].Template, &got); err != nil {
+			t.Fatalf("sample template is not valid JSON: %v", err)
+		}
+		want := map[string]any{
+			"Align.sample":   "S2",
+			"Align.reads_r1": "gs://b/run/S2_R1.fq",
+			"Align.reads_r2": "gs://b/run/S2_R2.fq",
+			"Align.samples":  "<FILL: Array[String]>",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("sample template = %v, want %v", got, want)
+		}
+		if !reflect.DeepEqual(out.Filled, []string{"Align.sample", "Align.reads_r1", "Align.reads_r2"}) {
+			t.Errorf("Filled = %v", out.Filled)
+		}
+	})
+
+	t.Run("one file for the batch", func(t *testing.T) {
+		out, err := uc.Execute(context.Background(), ScaffoldInputsInput{
+			WorkflowFile: "align.wdl",
+			FromFiles:    "gs://b/run/*.fq",
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(out.Template, &got); err != nil {
+			t.Fatalf("template is not valid JSON: %v", err)
+		}
+		if !reflect.DeepEqual(got["Align.samples"], []any{"S1", "S2"}) {
+			t.Errorf("Align.samples = %v, want the sample names", got["Align.samples"])
+		}
+		if !reflect.DeepEqual(out.Filled, []string{"Align.samples"}) {
+			t.Errorf("Filled = %v", out.Filled)
+		}
+	})
+
+	t.Run("invalid sample pattern", func(t *testing.T) {
+		_, err := uc.Execute(context.Background(), ScaffoldInputsInput{
+			WorkflowFile:  "align.wdl",
+			FromFiles:     "gs://b/run/*.fq",
+			SamplePattern: "(",
+		})
+		if err == nil || !strings.Contains(err.Error(), "not a valid regular expression") {
+			t.Errorf("Execute() error = %v, want an invalid pattern error", err)
+		}
+	})
+}
@@ -4,6 +4,8 @@ package workflow
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 
 	"github.com/lmtani/pumbaa/internal/application"
 	"github.com/lmtani/pumbaa/internal/application/ports"
@@ -13,18 +15,41 @@ import (
 // ScaffoldInputsUseCase builds an inputs JSON template for a workflow.
 type ScaffoldInputsUseCase struct {
 	fileProvider ports.FileProvider
+	lister       ports.FileLister
+	reader       ports.WorkflowMetadataReader
 }
 
-// NewScaffoldInputsUseCase creates a new scaffold use case.
-func NewScaffoldInputsUseCase(fp ports.FileProvider) *ScaffoldInputsUseCase {
-	return &ScaffoldInputsUseCase{fileProvider: fp}
+// NewScaffoldInputsUseCase creates a new scaffold use case. The lister finds
+// the data files of FromFiles, and the reader the inputs of a FromWorkflow
+// run.
+func NewScaffoldInputsUseCase(fp ports.FileProvider, lister ports.FileLister, reader ports.WorkflowMetadataReader) *ScaffoldInputsUseCase {
+	return &ScaffoldInputsUseCase{fileProvider: fp, lister: lister, reader: reader}
 }
 
 // ScaffoldInputsInput is the input for scaffolding.
 type ScaffoldInputsInput struct {
 	WorkflowFile string
+	// DependenciesFile is an imports zip. Without it, imports are resolved
+	// from WDL files sitting next to the workflow.
+	DependenciesFile string
 	// IncludeOptional adds the optional inputs, rendered with their defaults.
 	IncludeOptional bool
+	// IncludeCalls adds the inputs calls leave unbound, as
+	// "Workflow.call.input" keys.
+	IncludeCalls bool
+	// FromFiles is a glob over data files ("gs://bucket/run42/*_R{1,2}.fq.gz")
+	// that fills the inputs able to take them.
+	FromFiles string
+	// SamplePattern groups the files into samples: a regular expression
+	// over the file name whose "sample" group (or first group) names the
+	// sample. Empty means DefaultSamplePattern.
+	SamplePattern string
+	// PerSample renders one template per sample instead of one for the
+	// whole batch.
+	PerSample bool
+	// FromWorkflow is the ID of a previous run whose inputs the template
+	// starts from, reconciled against the current WDL.
+	FromWorkflow string
 }
 
 // ScaffoldInputsOutput carries the template and the declarations behind it.
@@ -32,6 +57,41 @@ type ScaffoldInputsOutput struct {
 	WorkflowName string
 	Template     []byte
 	Inputs       []wdl.InputSpec
+	// UnresolvedCalls lists calls whose inputs could not be listed because
+	// their definition could not be read.
+	UnresolvedCalls []string
+	// Warning explains a degraded template, such as an unreadable imports zip.
+	Warning string
+
+	// Samples are the samples FromFiles found; with PerSample each carries
+	// its own template, and Template is empty.
+	Samples []SampleTemplate
+	// Filled names the inputs given a value: by the FromFiles files, or
+	// carried over from the FromWorkflow run.
+	Filled []string
+	// Unmatched lists the files the sample pattern did not match, left out.
+	Unmatched []string
+
+	// Reconciled is set with FromWorkflow: what was kept, moved, dropped
+	// and added on the way from the previous run's inputs.
+	Reconciled *Reconciled
+}
+
+// Reconciled describes a template started from a previous run.
+type Reconciled struct {
+	WorkflowID string
+	// PreviousName is the run's workflow name when it differs from the
+	// current WDL's.
+	PreviousName string
+	Kept         []string
+	Changes      []wdl.InputChange
+}
+
+// SampleTemplate is one sample of a FromFiles scaffold.
+type SampleTemplate struct {
+	wdl.SampleFiles
+	// Template is the sample's own inputs file, with PerSample.
+	Template []byte
 }
 
 // Execute reads the WDL and renders its inputs template.
@@ -39,20 +99,147 @@ func (uc *ScaffoldInputsUseCase) Execute(ctx context.Context, input ScaffoldInpu
 	if input.WorkflowFile == "" {
 		return nil, application.NewInputValidationError("workflowFile", "is required")
 	}
+	if input.FromWorkflow != "" && input.FromFiles != "" {
+		return nil, application.NewInputValidationError("fromWorkflow", "cannot be combined with fromFiles")
+	}
 
 	source, err := uc.fileProvider.ReadBytes(ctx, input.WorkflowFile)
 	if err != nil {
 		return nil, application.NewUseCaseError("scaffold_inputs", "failed to read workflow file", err)
 	}
 
-	scaffold, err := wdl.ScaffoldInputs(source, wdl.ScaffoldOptions{IncludeOptional: input.IncludeOptional})
+	sources, warning := resolveImportSources(ctx, uc.fileProvider, input.WorkflowFile, input.DependenciesFile)
+	opts := wdl.ScaffoldOptions{
+		IncludeOptional: input.IncludeOptional,
+		IncludeCalls:    input.IncludeCalls,
+		Sources:         sources,
+	}
+	scaffold, err := wdl.ScaffoldInputs(source, opts)
 	if err != nil {
 		return nil, application.NewUseCaseError("scaffold_inputs", "failed to read the workflow's inputs", err)
 	}
 
-	return &ScaffoldInputsOutput{
-		WorkflowName: scaffold.WorkflowName,
-		Template:     scaffold.Template,
-		Inputs:       scaffold.Inputs,
-	}, nil
+	output := &ScaffoldInputsOutput{
+		WorkflowName:    scaffold.WorkflowName,
+		Template:        scaffold.Template,
+		Inputs:          scaffold.Inputs,
+		UnresolvedCalls: scaffold.UnresolvedCalls,
+		Warning:         warning,
+	}
+	switch {
+	case input.FromFiles != "":
+		if err := uc.fillFromFiles(ctx, input, source, opts, output); err != nil {
+			return nil, err
+		}
+	case input.FromWorkflow != "":
+		if err := uc.reconcile(ctx, input.FromWorkflow, source, opts, output); err != nil {
+			return nil, err
+		}
+	}
+	return output, nil
+}
+
+// reconcile starts the template from the inputs a previous run was submitted
+// with.
+func (uc *ScaffoldInputsUseCase) reconcile(ctx context.Context, workflowID string, source []byte, opts wdl.ScaffoldOptions, output *ScaffoldInputsOutput) error {
+	wf, err := uc.reader.GetMetadata(ctx, workflowID)
+	if err != nil {
+		return application.NewUseCaseError("scaffold_inputs", "failed to get the inputs of workflow "+workflowID, err)
+	}
+	previous := []byte(wf.SubmittedInputs)
+	if len(previous) == 0 {
+		// Cromwell records nothing for a run submitted without inputs.
+		previous = []byte("{}")
+	}
+
+	r, err := wdl.ReconcileInputs(source, previous, opts)
+	if err != nil {
+		return application.NewUseCaseError("scaffold_inputs", "failed to reconcile the inputs of workflow "+workflowID, err)
+	}
+	output.Template = r.Template
+	output.Inputs = r.Inputs
+	output.Filled = append(output.Filled, r.Kept...)
+	for _, change := range r.Changes {
+		if change.Kind == wdl.ChangeRenamed || change.Kind == wdl.ChangeUnchecked {
+			output.Filled = append(output.Filled, change.Input)
+		}
+	}
+	output.Reconciled = &Reconciled{
+		WorkflowID:   wf.ID,
+		PreviousName: r.PreviousWorkflow,
+		Kept:         r.Kept,
+		Changes:      r.Changes,
+	}
+	return nil
+}
+
+// fillFromFiles lists the FromFiles data, groups it into samples and renders
+// the template(s) with the inputs it fills.
+func (uc *ScaffoldInputsUseCase) fillFromFiles(ctx context.Context, input ScaffoldInputsInput, source []byte, opts wdl.ScaffoldOptions, output *ScaffoldInputsOutput) error {
+	pattern := input.SamplePattern
+	if pattern == "" {
+		pattern = DefaultSamplePattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return application.NewInputValidationError("samplePattern", fmt.Sprintf("is not a valid regular expression: %v", err))
+	}
+
+	files, err := expandGlob(ctx, uc.lister, input.FromFiles)
+	if err != nil {
+		return application.NewUseCaseError("scaffold_inputs", "failed to list the data files", err)
+	}
+	samples, unmatched := groupSamples(files, re)
+	output.Unmatched = unmatched
+	if len(samples) == 0 {
+		return application.NewUseCaseError("scaffold_inputs", "no sample found",
+			fmt.Errorf("none of the %d file(s) matching %s matches the sample pattern %s", len(files), input.FromFiles, pattern))
+	}
+
+	filled := make(map[string]bool)
+	render := func(values map[string]any) ([]byte, error) {
+		for name := range values {
+			filled[name] = true
+		}
+		opts.Values = values
+		scaffold, err := wdl.ScaffoldInputs(source, opts)
+		if err != nil {
+			return nil, err
+		}
+		return scaffold.Template, nil
+	}
+
+	if input.PerSample {
+		output.Template = nil
+		for _, sample := range samples {
+			values, err := wdl.SampleInputs(source, opts.Sources, sample)
+			if err != nil {
+				return application.NewUseCaseError("scaffold_inputs", "failed to fill the inputs of sample "+sample.Name, err)
+			}
+			template, err := render(values)
+			if err != nil {
+				return application.NewUseCaseError("scaffold_inputs", "failed to render the inputs of sample "+sample.Name, err)
+			}
+			output.Samples = append(output.Samples, SampleTemplate{SampleFiles: sample, Template: template})
+		}
+	} else {
+		values, err := wdl.BatchInputs(source, opts.Sources, samples)
+		if err != nil {
+			return application.NewUseCaseError("scaffold_inputs", "failed to fill the inputs", err)
+		}
+		if output.Template, err = render(values); err != nil {
+			return application.NewUseCaseError("scaffold_inputs", "failed to render the inputs", err)
+		}
+		for _, sample := range samples {
+			output.Samples = append(output.Samples, SampleTemplate{SampleFiles: sample})
+		}
+	}
+
+	// In declaration order, as the template lists them.
+	for _, spec := range output.Inputs {
+		if filled[spec.Name] {
+			output.Filled = append(output.Filled, spec.Name)
+		}
+	}
+	return nil
 }
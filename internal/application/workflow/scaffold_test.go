@@ -4,7 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"reflect"
 	"testing"
+
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/pkg/wdl"
 )
 
 func TestScaffoldInputsUseCase(t *testing.T) {
@@ -16,7 +20,7 @@ func TestScaffoldInputsUseCase(t *testing.T) {
 			return []byte(preflightWDL), nil
 		},
 	}
-	uc := NewScaffoldInputsUseCase(fp)
+	uc := NewScaffoldInputsUseCase(fp, fp, nil)
 
 	out, err := uc.Execute(context.Background(), ScaffoldInputsInput{WorkflowFile: "align.wdl"})
 	if err != nil {
@@ -47,7 +51,7 @@ func TestScaffoldInputsUseCaseIncludeOptional(t *testing.T) {
 			return []byte(preflightWDL), nil
 		},
 	}
-	uc := NewScaffoldInputsUseCase(fp)
+	uc := NewScaffoldInputsUseCase(fp, fp, nil)
 
 	out, err := uc.Execute(context.Background(), ScaffoldInputsInput{WorkflowFile: "align.wdl", IncludeOptional: true})
 	if err != nil {
@@ -68,7 +72,7 @@ func TestScaffoldInputsUseCaseIncludeOptional(t *testing.T) {
 
 func TestScaffoldInputsUseCaseErrors(t *testing.T) {
 	t.Run("workflow file is required", func(t *testing.T) {
-		uc := NewScaffoldInputsUseCase(&mockFileProvider{})
+		uc := NewScaffoldInputsUseCase(&mockFileProvider{}, nil, nil)
 		if _, err := uc.Execute(context.Background(), ScaffoldInputsInput{}); err == nil {
 			t.Error("expected an error without a workflow file")
 		}
@@ -80,7 +84,7 @@ func TestScaffoldInputsUseCaseErrors(t *testing.T) {
 				return nil, errors.New("no such file")
 			},
 		}
-		uc := NewScaffoldInputsUseCase(fp)
+		uc := NewScaffoldInputsUseCase(fp, fp, nil)
 		if _, err := uc.Execute(context.Background(), ScaffoldInputsInput{WorkflowFile: "nope.wdl"}); err == nil {
 			t.Error("expected an error for an unreadable workflow file")
 		}
@@ -92,9 +96,51 @@ func TestScaffoldInputsUseCaseErrors(t *testing.T) {
 				return []byte("version 1.0\n\ntask alone {\n  command <<< echo hi >>>\n}\n"), nil
 			},
 		}
-		uc := NewScaffoldInputsUseCase(fp)
+		uc := NewScaffoldInputsUseCase(fp, fp, nil)
 		if _, err := uc.Execute(context.Background(), ScaffoldInputsInput{WorkflowFile: "tasks.wdl"}); err == nil {
 			t.Error("scaffolding a task-only WDL should explain there is nothing to scaffold")
 		}
 	})
 }
+
+func TestScaffoldInputsUseCaseFromWorkflow(t *testing.T) {
+	fp := &mockFileProvider{
+		readBytesFunc: func(ctx context.Context, path string) ([]byte, error) {
+			return []byte(preflightWDL), nil
+		},
+	}
+	repo := &mockWorkflowRepository{
+		getMetadataFunc: func(ctx context.Context, workflowID string) (*workflow.Workflow, error) {
+			if workflowID != "wf-1" {
+				return nil, errors.New("workflow not found")
+			}
+			return &workflow.Workflow{
+				ID:              "wf-1",
+				SubmittedInputs: `{"Align.reads": "gs://b/r.fq", "Align.samples": "S1", "Align.threads": 8}`,
+			}, nil
+		},
+	}
+	uc := NewScaffoldInputsUseCase(fp, nil, repo)
+
+	out, err := uc.Execute(context.Background(), ScaffoldInputsInput{WorkflowFile: "align.wdl", FromWorkflow: "wf-1"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var template map[string]any
+	if err := json.Unmarshal(out.Template, &template); err != nil {
+		t.Fatalf("template is not valid JSON: %v", err)
+	}
+	want := map[string]any{"Align.reads": "gs://b/r.fq", "Align.sample": "S1", "Align.threads": float64(8)}
+	if !reflect.DeepEqual(template, want) {
+		t.Errorf("template = %v, want %v", template, want)
+	}
+	if out.Reconciled == nil || len(out.Reconciled.Kept) != 2 || len(out.Reconciled.Changes) != 1 ||
+		out.Reconciled.Changes[0].Kind != wdl.ChangeRenamed {
+		t.Errorf("Reconciled = %+v, want two kept and samples renamed to sample", out.Reconciled)
+	}
+
+	if _, err := uc.Execute(context.Background(), ScaffoldInputsInput{WorkflowFile: "align.wdl", FromWorkflow: "nope"}); err == nil {
+		t.Error("expected an error for an unknown workflow")
+	}
+}
@@ -0,0 +1,62 @@
+// schema.go describes a workflow's inputs file as a JSON Schema, so web forms
+// and editors can validate it with the same rules preflight applies.
+package workflow
+
+import (
+	"context"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/pkg/wdl"
+)
+
+// SchemaUseCase generates inputs schemas.
+type SchemaUseCase struct {
+	files ports.FileProvider
+}
+
+// NewSchemaUseCase creates a new schema use case.
+func NewSchemaUseCase(files ports.FileProvider) *SchemaUseCase {
+	return &SchemaUseCase{files: files}
+}
+
+// SchemaInput is the input for generating a schema.
+type SchemaInput struct {
+	WorkflowFile string
+	// DependenciesFile is an imports zip. Without it, imports are resolved
+	// from WDL files sitting next to the workflow.
+	DependenciesFile string
+}
+
+// SchemaOutput is the generated schema.
+type SchemaOutput struct {
+	Rendered []byte
+	// Unresolved lists calls whose inputs are missing from the schema
+	// because their definition could not be read.
+	Unresolved []string
+	// Warning explains a degraded schema, such as an unreadable imports zip.
+	Warning string
+}
+
+// Execute generates the schema.
+func (uc *SchemaUseCase) Execute(ctx context.Context, input SchemaInput) (*SchemaOutput, error) {
+	if input.WorkflowFile == "" {
+		return nil, application.NewInputValidationError("workflowFile", "is required")
+	}
+
+	source, err := uc.files.ReadBytes(ctx, input.WorkflowFile)
+	if err != nil {
+		return nil, application.NewUseCaseError("schema", "failed to read workflow file", err)
+	}
+	deps, warning := resolveImportSources(ctx, uc.files, input.WorkflowFile, input.DependenciesFile)
+
+	result, err := wdl.InputsSchema(source, deps)
+	if err != nil {
+		return nil, application.NewUseCaseError("schema", "failed to parse workflow", err)
+	}
+	rendered, err := result.Schema.MarshalIndent()
+	if err != nil {
+		return nil, application.NewUseCaseError("schema", "failed to render schema", err)
+	}
+	return &SchemaOutput{Rendered: rendered, Unresolved: result.UnresolvedCalls, Warning: warning}, nil
+}
@@ -0,0 +1,253 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// compressionExtensions are extensions kept together with the one before
+// them, so out.vcf.gz and out.fastq.gz are told apart.
+var compressionExtensions = map[string]bool{
+	".gz": true, ".bgz": true, ".bz2": true, ".xz": true, ".zst": true,
+}
+
+// StorageGroup sums the stored files that share a task or an extension.
+type StorageGroup struct {
+	// Name is the task or extension; empty for files outside every call
+	// root, or without an extension.
+	Name        string
+	Files       int
+	Bytes       int64
+	MonthlyCost float64
+}
+
+// WorkflowStorage is the storage a workflow's files occupy.
+type WorkflowStorage struct {
+	WorkflowID   string
+	WorkflowName string
+	WorkflowRoot string
+	// Files, Bytes and MonthlyCost sum every file under the workflow root.
+	Files       int
+	Bytes       int64
+	MonthlyCost float64
+	// Tasks and Extensions break the totals down, largest first.
+	Tasks      []StorageGroup
+	Extensions []StorageGroup
+	// ExternalFiles and ExternalBytes count final outputs stored outside
+	// the workflow root, such as call-cache hits copied by reference. They
+	// belong to other runs as well, so they are not in the totals.
+	ExternalFiles int
+	ExternalBytes int64
+	// Err is set when the workflow's storage could not be measured.
+	Err error
+}
+
+// StorageUsageUseCase measures what finished and running workflows leave
+// in storage and what keeping it costs a month. It needs a file provider
+// that can list; with one that cannot, every workflow reports an error.
+type StorageUsageUseCase struct {
+	reader   ports.WorkflowMetadataReader
+	querier  ports.WorkflowQuerier
+	files    ports.FileProvider
+	lister   ports.FileLister
+	prices   workflow.StoragePrices
+	progress ports.ProgressReporter
+	mu       sync.Mutex // serializes progress reports from concurrent listings
+}
+
+// NewStorageUsageUseCase creates a new storage usage use case. progress may
+// be nil.
+func NewStorageUsageUseCase(reader ports.WorkflowMetadataReader, querier ports.WorkflowQuerier, fp ports.FileProvider, prices workflow.StoragePrices, progress ports.ProgressReporter) *StorageUsageUseCase {
+	uc := &StorageUsageUseCase{reader: reader, querier: querier, files: fp, prices: prices, progress: progress}
+	uc.lister, _ = fp.(ports.FileLister)
+	return uc
+}
+
+// StorageUsageInput represents the input for a storage report.
+type StorageUsageInput struct {
+	// WorkflowIDs are the workflows to measure. When empty, the workflows
+	// matching Query are measured instead.
+	WorkflowIDs []string
+	Query       workflow.QueryFilter
+}
+
+// StorageUsageOutput represents the result of a storage report.
+type StorageUsageOutput struct {
+	Workflows []WorkflowStorage
+	// Prices is the table the costs were estimated with.
+	Prices workflow.StoragePrices
+}
+
+// Execute measures each workflow in turn. A workflow that cannot be
+// measured is reported and does not stop the others.
+func (uc *StorageUsageUseCase) Execute(ctx context.Context, input StorageUsageInput) (*StorageUsageOutput, error) {
+	ids := input.WorkflowIDs
+	if len(ids) == 0 {
+		result, err := uc.querier.Query(ctx, input.Query)
+		if err != nil {
+			return nil, application.NewUseCaseError("storage-report", "failed to query workflows", err)
+		}
+		for _, wf := range result.Workflows {
+			ids = append(ids, wf.ID)
+		}
+	}
+
+	defer uc.doneReporting()
+	out := &StorageUsageOutput{Prices: uc.prices}
+	for _, id := range ids {
+		out.Workflows = append(out.Workflows, uc.Measure(ctx, id))
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+	}
+	return out, nil
+}
+
+// Measure lists every file under a workflow's root and sums the sizes by
+// task and by extension. Final outputs outside the root are sized one by
+// one. A workflow without a root, or whose root was never written, stores
+// nothing.
+func (uc *StorageUsageUseCase) Measure(ctx context.Context, workflowID string) WorkflowStorage {
+	usage := WorkflowStorage{WorkflowID: workflowID}
+	if uc.lister == nil {
+		usage.Err = errors.New("storage cannot list files")
+		return usage
+	}
+	wf, err := uc.reader.GetMetadata(ctx, workflowID)
+	if err != nil {
+		usage.Err = err
+		return usage
+	}
+	usage.WorkflowName = wf.Name
+	if wf.WorkflowRoot == "" {
+		return usage
+	}
+
+	root := strings.TrimSuffix(wf.WorkflowRoot, "/")
+	usage.WorkflowRoot = root
+	files, err := listTree(ctx, uc.lister, root, func(n int) {
+		uc.step("Listed %d files under %s", n, root)
+	})
+	if err != nil && !errors.Is(err, ports.ErrFileNotFound) {
+		usage.Err = err
+		return usage
+	}
+
+	callRoots := callRootTasks(wf)
+	byTask := make(map[string]*StorageGroup)
+	byExt := make(map[string]*StorageGroup)
+	for _, f := range files {
+		cost := uc.prices.MonthlyCost(f.Path, f.Size)
+		usage.Files++
+		usage.Bytes += f.Size
+		usage.MonthlyCost += cost
+		addToGroup(byTask, taskOf(f.Path, callRoots), f.Size, cost)
+		addToGroup(byExt, fileExtension(f.Path), f.Size, cost)
+	}
+	usage.Tasks = sortedGroups(byTask)
+	usage.Extensions = sortedGroups(byExt)
+
+	usage.ExternalFiles, usage.ExternalBytes = uc.sizeExternalOutputs(ctx, wf, root)
+	return usage
+}
+
+// sizeExternalOutputs sums the final outputs stored outside root. Outputs
+// that no longer exist, or whose size cannot be read, are not counted.
+func (uc *StorageUsageUseCase) sizeExternalOutputs(ctx context.Context, wf *workflow.Workflow, root string) (int, int64) {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, value := range wf.Outputs {
+		for _, p := range workflow.ExtractFilePaths(value) {
+			if path := p.String(); !seen[path] && !strings.HasPrefix(path, root+"/") {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	var files, bytes atomic.Int64
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(listTreeParallelism)
+	for _, p := range paths {
+		group.Go(func() error {
+			if size, err := uc.files.GetSize(groupCtx, p); err == nil {
+				files.Add(1)
+				bytes.Add(size)
+			}
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return int(files.Load()), bytes.Load()
+}
+
+func addToGroup(groups map[string]*StorageGroup, name string, size int64, cost float64) {
+	g := groups[name]
+	if g == nil {
+		g = &StorageGroup{Name: name}
+		groups[name] = g
+	}
+	g.Files++
+	g.Bytes += size
+	g.MonthlyCost += cost
+}
+
+// sortedGroups returns the groups largest first, then by name.
+func sortedGroups(groups map[string]*StorageGroup) []StorageGroup {
+	sorted := make([]StorageGroup, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, *g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Bytes != sorted[j].Bytes {
+			return sorted[i].Bytes > sorted[j].Bytes
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// fileExtension returns the lower-cased extension of a file name, keeping a
+// compression suffix together with the extension before it (".vcf.gz").
+// Files such as stdout or rc have none.
+func fileExtension(p string) string {
+	base := path.Base(p)
+	ext := strings.ToLower(path.Ext(base))
+	if ext == "" || ext == strings.ToLower(base) {
+		// No extension, or a dotfile such as .bashrc.
+		return ""
+	}
+	if compressionExtensions[ext] {
+		stem := strings.TrimSuffix(base, path.Ext(base))
+		if inner := strings.ToLower(path.Ext(stem)); inner != "" && inner != strings.ToLower(stem) {
+			return inner + ext
+		}
+	}
+	return ext
+}
+
+func (uc *StorageUsageUseCase) step(format string, args ...any) {
+	if uc.progress == nil {
+		return
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.progress.Step(format, args...)
+}
+
+func (uc *StorageUsageUseCase) doneReporting() {
+	if uc.progress != nil {
+		uc.progress.Done()
+	}
+}
@@ -0,0 +1,138 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+func TestStorageUsageUseCase_Execute(t *testing.T) {
+	const root = "gs://b/wf/run-1"
+	const gib = 1 << 30
+	files := &treeFiles{sizes: map[string]int64{
+		root + "/call-Align/shard-0/execution/out.bam":  4 * gib,
+		root + "/call-Align/shard-0/execution/stderr":   10,
+		root + "/call-Align/shard-1/execution/out.bam":  4 * gib,
+		root + "/call-Call/execution/calls.vcf.gz":      gib,
+		root + "/call-Call/execution/calls.vcf.gz.tbi":  20,
+		root + "/call-Sub/Sub/sub-1/call-X/execution/x": 30,
+		root + "/workflow.log":                          40,
+	}}
+	files.getSizeFunc = func(_ context.Context, path string) (int64, error) {
+		if path == "gs://b/wf/run-0/call-Ref/execution/ref.fa" {
+			return 500, nil
+		}
+		return 0, ports.ErrFileNotFound
+	}
+	repo := &mockWorkflowRepository{
+		getMetadataFunc: func(_ context.Context, id string) (*workflow.Workflow, error) {
+			switch id {
+			case "run-1":
+				return &workflow.Workflow{
+					ID: id, Name: "wf", WorkflowRoot: root + "/",
+					Outputs: map[string]any{
+						"wf.vcf":     root + "/call-Call/execution/calls.vcf.gz",
+						"wf.ref":     "gs://b/wf/run-0/call-Ref/execution/ref.fa",
+						"wf.deleted": "gs://b/wf/run-0/call-Gone/execution/gone.txt",
+					},
+					Calls: map[string][]workflow.Call{
+						"wf.Align": {
+							{ShardIndex: 0, CallRoot: root + "/call-Align/shard-0"},
+							{ShardIndex: 1, CallRoot: root + "/call-Align/shard-1"},
+						},
+						"wf.Call": {{ShardIndex: -1, CallRoot: root + "/call-Call"}},
+						"wf.Sub":  {{ShardIndex: -1, CallRoot: root + "/call-Sub"}},
+					},
+				}, nil
+			case "run-2":
+				// Failed before writing anything.
+				return &workflow.Workflow{ID: id, Name: "wf", WorkflowRoot: "gs://b/wf/run-2"}, nil
+			}
+			return nil, errors.New("workflow not found")
+		},
+		queryFunc: func(context.Context, workflow.QueryFilter) (*workflow.QueryResult, error) {
+			return &workflow.QueryResult{Workflows: []workflow.Workflow{{ID: "run-1"}, {ID: "run-2"}, {ID: "run-3"}}}, nil
+		},
+	}
+	uc := NewStorageUsageUseCase(repo, repo, files, workflow.StoragePrices{workflow.StorageGCS: 0.02}, nil)
+
+	out, err := uc.Execute(context.Background(), StorageUsageInput{Query: workflow.QueryFilter{Name: "wf"}})
+	if err != nil {
+		t.Fatalf("Execute() unexpected error: %v", err)
+	}
+	if len(out.Workflows) != 3 {
+		t.Fatalf("measured %d workflows, want 3", len(out.Workflows))
+	}
+
+	usage := out.Workflows[0]
+	if usage.Err != nil {
+		t.Fatalf("run-1: unexpected error: %v", usage.Err)
+	}
+	wantBytes := int64(9*gib + 100)
+	if usage.Files != 7 || usage.Bytes != wantBytes {
+		t.Errorf("run-1: %d files (%d bytes), want 7 (%d bytes)", usage.Files, usage.Bytes, wantBytes)
+	}
+	if want := float64(wantBytes) / gib * 0.02; math.Abs(usage.MonthlyCost-want) > 1e-9 {
+		t.Errorf("run-1: monthly cost = %v, want %v", usage.MonthlyCost, want)
+	}
+	wantTasks := []StorageGroup{
+		{Name: "wf.Align", Files: 3, Bytes: 8*gib + 10},
+		{Name: "wf.Call", Files: 2, Bytes: gib + 20},
+		{Name: "", Files: 1, Bytes: 40},
+		{Name: "wf.Sub", Files: 1, Bytes: 30},
+	}
+	assertGroups(t, "Tasks", usage.Tasks, wantTasks)
+	wantExtensions := []StorageGroup{
+		{Name: ".bam", Files: 2, Bytes: 8 * gib},
+		{Name: ".vcf.gz", Files: 1, Bytes: gib},
+		{Name: "", Files: 2, Bytes: 40},
+		{Name: ".log", Files: 1, Bytes: 40},
+		{Name: ".tbi", Files: 1, Bytes: 20},
+	}
+	assertGroups(t, "Extensions", usage.Extensions, wantExtensions)
+	if usage.ExternalFiles != 1 || usage.ExternalBytes != 500 {
+		t.Errorf("run-1: external %d files (%d bytes), want 1 (500 bytes)", usage.ExternalFiles, usage.ExternalBytes)
+	}
+
+	if empty := out.Workflows[1]; empty.Err != nil || empty.Files != 0 {
+		t.Errorf("run-2 = %+v, want an empty root measured as nothing stored", empty)
+	}
+	if out.Workflows[2].Err == nil {
+		t.Error("run-3: want the metadata error reported")
+	}
+}
+
+func assertGroups(t *testing.T, name string, got, want []StorageGroup) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s = %+v, want %+v", name, got, want)
+	}
+	for i := range want {
+		if got[i].Name != want[i].Name || got[i].Files != want[i].Files || got[i].Bytes != want[i].Bytes {
+			t.Errorf("%s[%d] = %+v, want %+v", name, i, got[i], want[i])
+		}
+	}
+}
+
+func TestFileExtension(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"gs://b/run/call-A/execution/out.bam", ".bam"},
+		{"gs://b/run/call-A/execution/OUT.BAM", ".bam"},
+		{"gs://b/run/call-A/execution/calls.vcf.gz", ".vcf.gz"},
+		{"gs://b/run/call-A/execution/reads.gz", ".gz"},
+		{"gs://b/run/call-A/execution/stdout", ""},
+		{"/data/run/call-A/execution/.bashrc", ""},
+	}
+	for _, tt := range tests {
+		if got := fileExtension(tt.path); got != tt.want {
+			t.Errorf("fileExtension(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
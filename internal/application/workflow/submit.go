@@ -6,6 +6,7 @@ import (
 	"github.com/lmtani/pumbaa/internal/application"
 	"github.com/lmtani/pumbaa/internal/application/ports"
 	workflow2 "github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/pkg/wdl"
 )
 
 // SubmitUseCase handles workflow submission.
@@ -24,13 +25,17 @@ func NewSubmitUseCase(submitter ports.WorkflowSubmitter, fileProvider ports.File
 // SubmitInput represents the input for workflow submission.
 type SubmitInput struct {
 	WorkflowFile     string
-	InputsFile       string
+	Inputs           InputSources
 	OptionsFile      string
 	DependenciesFile string
 	Labels           map[string]string
 	// SkipPreflight submits without checking the workflow and its inputs
 	// first.
 	SkipPreflight bool
+	// Remote configures how preflight fetches http(s) imports, as for
+	// PreflightInput, so a submission checks the same import contents a
+	// preflight of it would.
+	Remote wdl.RemoteImportOptions
 }
 
 // SubmitOutput represents the output of workflow submission.
@@ -41,6 +46,9 @@ type SubmitOutput struct {
 	// caller can confirm they happened (and surface any warnings). Nil when
 	// preflight was skipped.
 	Preflight *PreflightReport
+	// Inputs is the inputs JSON that was submitted, after merging every
+	// source.
+	Inputs []byte
 }
 
 // Execute submits a workflow to Cromwell.
@@ -56,14 +64,7 @@ func (uc *SubmitUseCase) Execute(ctx context.Context, input SubmitInput) (*Submi
 	}
 
 	// Read optional files
-	var inputsData, optionsData, depsData []byte
-
-	if input.InputsFile != "" {
-		inputsData, err = uc.fileProvider.ReadBytes(ctx, input.InputsFile)
-		if err != nil {
-			return nil, application.NewUseCaseError("submit", "failed to read inputs file", err)
-		}
-	}
+	var optionsData, depsData []byte
 
 	if input.OptionsFile != "" {
 		optionsData, err = uc.fileProvider.ReadBytes(ctx, input.OptionsFile)
@@ -79,11 +80,22 @@ func (uc *SubmitUseCase) Execute(ctx context.Context, input SubmitInput) (*Submi
 		}
 	}
 
+	// Remote imports are only fetched for the preflight that checks them.
+	imports := importSources{deps: depsData, sources: zipSources(depsData)}
+	if uc.preflight != nil && !input.SkipPreflight {
+		imports = resolveImports(workflowSource, depsData, input.Remote)
+	}
+	inputsData, err := assembleInputs(ctx, uc.fileProvider, input.Inputs, workflowSource, imports.sources)
+	if err != nil {
+		return nil, application.NewUseCaseError("submit", "failed to read inputs file", err)
+	}
+
 	// Catch what Cromwell would only tell us minutes (and dollars) later.
 	// The server check is skipped: submitting is about to contact it anyway.
 	var report *PreflightReport
 	if uc.preflight != nil && !input.SkipPreflight {
-		report = uc.preflight.check(ctx, workflowSource, inputsData, depsData, true, false)
+		report = uc.preflight.check(ctx, workflowSource, inputsData, imports, true, false)
+		report.Inputs = inputsData
 		if report.HasErrors() {
 			return nil, &PreflightFailedError{Report: report}
 		}
@@ -106,5 +118,6 @@ func (uc *SubmitUseCase) Execute(ctx context.Context, input SubmitInput) (*Submi
 		WorkflowID: resp.ID,
 		Status:     string(resp.Status),
 		Preflight:  report,
+		Inputs:     inputsData,
 	}, nil
 }
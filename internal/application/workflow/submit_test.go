@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
 	"github.com/lmtani/pumbaa/internal/application"
 	"github.com/lmtani/pumbaa/internal/application/ports"
 	"github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/pkg/wdl"
 )
 
 // mockWorkflowRepository and mockFileProvider are defined in testutil_test.go
@@ -58,7 +62,7 @@ func TestSubmitUseCase_Execute(t *testing.T) {
 
 	input := SubmitInput{
 		WorkflowFile:     "test.wdl",
-		InputsFile:       "inputs.json",
+		Inputs:           InputSources{Files: []string{"inputs.json"}},
 		OptionsFile:      "options.json",
 		DependenciesFile: "deps.zip",
 		Labels:           map[string]string{"team": "bio"},
@@ -131,7 +135,7 @@ func TestSubmitUseCase_Execute_OptionalFileReadErrors(t *testing.T) {
 	}{
 		{
 			name:          "inputs file",
-			input:         SubmitInput{WorkflowFile: "test.wdl", InputsFile: "inputs.json"},
+			input:         SubmitInput{WorkflowFile: "test.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}},
 			errorPath:     "inputs.json",
 			expectMessage: "failed to read inputs file",
 		},
@@ -234,7 +238,7 @@ workflow Hello {
 
 	input := SubmitInput{
 		WorkflowFile: "hello.wdl",
-		InputsFile:   "inputs.json",
+		Inputs:       InputSources{Files: []string{"inputs.json"}},
 	}
 	_, err := uc.Execute(context.Background(), input)
 	if err == nil {
@@ -291,7 +295,7 @@ workflow Hello {
 
 	input := SubmitInput{
 		WorkflowFile: "hello.wdl",
-		InputsFile:   "inputs.json",
+		Inputs:       InputSources{Files: []string{"inputs.json"}},
 	}
 	output, err := uc.Execute(context.Background(), input)
 	if err != nil {
@@ -341,7 +345,7 @@ workflow Hello {
 	}
 	uc := NewSubmitUseCase(repo, fp, NewPreflightUseCase(fp, nil))
 
-	_, err := uc.Execute(context.Background(), SubmitInput{WorkflowFile: "hello.wdl", InputsFile: "inputs.json"})
+	_, err := uc.Execute(context.Background(), SubmitInput{WorkflowFile: "hello.wdl", Inputs: InputSources{Files: []string{"inputs.json"}}})
 
 	var preflightErr *PreflightFailedError
 	if !errors.As(err, &preflightErr) {
@@ -381,7 +385,7 @@ workflow Hello {
 
 	output, err := uc.Execute(context.Background(), SubmitInput{
 		WorkflowFile:  "hello.wdl",
-		InputsFile:    "inputs.json",
+		Inputs:        InputSources{Files: []string{"inputs.json"}},
 		SkipPreflight: true,
 	})
 	if err != nil {
@@ -394,3 +398,62 @@ workflow Hello {
 		t.Errorf("--skip-preflight should leave no report, got %+v", output.Preflight)
 	}
 }
+
+func TestSubmitUseCase_Execute_RemoteImportsUseTheImportCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`version 1.0
+
+task BwaMem {
+    command <<< bwa mem >>>
+    runtime {
+        memory: "32 GB"
+    }
+}
+`))
+	}))
+	defer server.Close()
+
+	wdlSrc := `version 1.0
+
+import "` + server.URL + `/tasks/align.wdl" as align
+
+workflow Align {
+    call align.BwaMem
+}
+`
+	repo := &mockWorkflowRepository{
+		submitFunc: func(ctx context.Context, req workflow.SubmitRequest) (*workflow.SubmitResponse, error) {
+			return &workflow.SubmitResponse{ID: "wf-1", Status: workflow.StatusSubmitted}, nil
+		},
+	}
+	fp := preflightFiles(wdlSrc, `{}`, nil)
+	uc := NewSubmitUseCase(repo, fp, NewPreflightUseCase(fp, nil))
+	input := SubmitInput{
+		WorkflowFile: "align.wdl",
+		Inputs:       InputSources{Files: []string{"inputs.json"}},
+		Remote:       wdl.RemoteImportOptions{CacheDir: t.TempDir()},
+	}
+
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("online submit made %d request(s), want 1", requests.Load())
+	}
+
+	// Offline, the preflight reads the same cached copy a preflight would.
+	server.Close()
+	input.Remote.Offline = true
+	output, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("offline Execute() error = %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("offline submit made %d more request(s), want the cache used", requests.Load()-1)
+	}
+	if res := output.Preflight.Resources; len(res) != 1 || res[0].Attributes["memory"] != "32 GB" {
+		t.Errorf("Resources = %+v, want the cached remote task", res)
+	}
+}
@@ -69,6 +69,7 @@ type mockFileProvider struct {
 	readBytesFunc  func(ctx context.Context, path string) ([]byte, error)
 	getSizeFunc    func(ctx context.Context, path string) (int64, error)
 	getDigestsFunc func(ctx context.Context, path string) (ports.FileDigests, error)
+	listFunc       func(ctx context.Context, prefix string) ([]ports.FileEntry, error)
 }
 
 func (m *mockFileProvider) Read(ctx context.Context, path string) (string, error) {
@@ -99,6 +100,13 @@ func (m *mockFileProvider) GetContentDigests(ctx context.Context, path string) (
 	return ports.FileDigests{}, nil
 }
 
+func (m *mockFileProvider) List(ctx context.Context, prefix string) ([]ports.FileEntry, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, prefix)
+	}
+	return nil, nil
+}
+
 // =============================================================================
 // Mock TaskMetricsWriter
 // =============================================================================
@@ -0,0 +1,477 @@
+// wdl_diff.go compares two versions of a workflow by what they do rather than
+// how they are written: the calls, the tasks behind them, and which calls a run
+// of the new version can no longer take from the call cache of the old one.
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	domain "github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/pkg/wdl"
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// fingerprintRuntime lists the runtime attributes Cromwell folds into the
+// call-cache fingerprint. Changing any other attribute (cpu, memory, disks)
+// changes where a task runs, not whether its cached result is reused.
+var fingerprintRuntime = map[string]bool{
+	"docker":               true,
+	"continueOnReturnCode": true,
+	"failOnStderr":         true,
+}
+
+// WDLDiffUseCase compares two versions of a workflow.
+type WDLDiffUseCase struct {
+	files     ports.FileProvider
+	revisions ports.RevisionReader
+}
+
+// NewWDLDiffUseCase creates the use case. revisions may be nil, in which case
+// only versions on disk can be compared.
+func NewWDLDiffUseCase(files ports.FileProvider, revisions ports.RevisionReader) *WDLDiffUseCase {
+	return &WDLDiffUseCase{files: files, revisions: revisions}
+}
+
+// WDLVersion locates one version of a workflow.
+type WDLVersion struct {
+	WorkflowFile string
+	// DependenciesFile is an imports zip. Without it, imports come from the
+	// bundle zip beside the workflow (<name>.zip, as `pumbaa bundle` writes
+	// it), or else from the WDL files sitting next to the workflow.
+	DependenciesFile string
+	// Revision reads the workflow, and the WDL files next to it, as they were
+	// at a git revision instead of from disk.
+	Revision string
+}
+
+// Label names the version the way it was given.
+func (v WDLVersion) Label() string {
+	if v.Revision != "" {
+		return v.Revision + ":" + v.WorkflowFile
+	}
+	return v.WorkflowFile
+}
+
+// WDLDiffInput names the two versions to compare.
+type WDLDiffInput struct {
+	Old WDLVersion
+	New WDLVersion
+}
+
+// wdlSide is one version, parsed.
+type wdlSide struct {
+	doc   *ast.Document
+	graph *wdl.CallGraph
+	specs map[string]wdl.TaskSpec
+}
+
+// Execute compares the two versions.
+func (uc *WDLDiffUseCase) Execute(ctx context.Context, input WDLDiffInput) (*domain.WDLDiff, error) {
+	if input.Old.WorkflowFile == "" {
+		return nil, application.NewInputValidationError("old", "is required")
+	}
+	if input.New.WorkflowFile == "" {
+		return nil, application.NewInputValidationError("new", "is required")
+	}
+
+	diff := &domain.WDLDiff{Old: input.Old.Label(), New: input.New.Label()}
+	old, err := uc.load(ctx, input.Old, diff)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := uc.load(ctx, input.New, diff)
+	if err != nil {
+		return nil, err
+	}
+
+	diff.WorkflowOld = old.graph.Workflow
+	diff.WorkflowNew = cur.graph.Workflow
+	if old.doc.Workflow != nil && cur.doc.Workflow != nil {
+		diff.Inputs = compareDeclarations(old.doc.Workflow.Inputs, cur.doc.Workflow.Inputs)
+		diff.Outputs = compareDeclarations(old.doc.Workflow.Outputs, cur.doc.Workflow.Outputs)
+	}
+	changedDefaults := changedInputDefaults(old.graph.InputDefaults, cur.graph.InputDefaults)
+
+	assessments := make(map[string]domain.CallAssessment)
+	for _, name := range unionNames(old.graph, cur.graph) {
+		before, after := old.graph.Nodes[name], cur.graph.Nodes[name]
+		switch {
+		case before == nil:
+			diff.Calls = append(diff.Calls, domain.CallChange{Call: name, Kind: domain.ChangeAdded, TaskNew: after.Task})
+			assessments[name] = domain.CallAssessment{Reasons: []string{"new call"}}
+			continue
+		case after == nil:
+			diff.Calls = append(diff.Calls, domain.CallChange{Call: name, Kind: domain.ChangeRemoved, TaskOld: before.Task})
+			continue
+		}
+
+		change := compareCall(before, after, old.specs, cur.specs)
+		for _, b := range after.Bindings {
+			if input := defaultedInput(b, changedDefaults); input != "" {
+				change.CacheReasons = append(change.CacheReasons, fmt.Sprintf("default of workflow input %s changed", input))
+			}
+		}
+		if before.Unresolved || after.Unresolved {
+			assessments[name] = domain.CallAssessment{Unknown: "task definition not readable"}
+		} else if len(change.CacheReasons) > 0 {
+			assessments[name] = domain.CallAssessment{Reasons: change.CacheReasons}
+		}
+		if change.Kind == domain.ChangeModified {
+			diff.Calls = append(diff.Calls, change)
+		}
+	}
+	diff.ApplyCachePredictions(domain.PredictCacheReuse(cur.graph.Dependencies(), assessments))
+	return diff, nil
+}
+
+// load reads and parses one version, recording why it may be incomplete.
+func (uc *WDLDiffUseCase) load(ctx context.Context, v WDLVersion, diff *domain.WDLDiff) (*wdlSide, error) {
+	var (
+		source  []byte
+		deps    wdl.SourceSet
+		warning string
+		err     error
+	)
+	if v.Revision != "" {
+		source, deps, warning, err = uc.readRevision(ctx, v)
+	} else {
+		source, deps, warning, err = uc.readFiles(ctx, v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if warning != "" {
+		diff.Warnings = append(diff.Warnings, fmt.Sprintf("%s: %s", v.Label(), warning))
+	}
+
+	doc, err := wdl.ParseBytes(source)
+	if err != nil {
+		return nil, application.NewUseCaseError("wdl diff", "failed to parse "+v.Label(), err)
+	}
+	graph := wdl.CallGraphFromDocument(doc, deps)
+	specs := map[string]wdl.TaskSpec{}
+	if s, err := wdl.TaskSpecsWithSources(source, deps); err == nil {
+		specs = s
+	}
+	if names := unresolvedCalls(graph); len(names) > 0 {
+		diff.Warnings = append(diff.Warnings, fmt.Sprintf("%s: definition not readable for %s", v.Label(), strings.Join(names, ", ")))
+	}
+	return &wdlSide{doc: doc, graph: graph, specs: specs}, nil
+}
+
+// readFiles reads a version from disk.
+func (uc *WDLDiffUseCase) readFiles(ctx context.Context, v WDLVersion) ([]byte, wdl.SourceSet, string, error) {
+	source, err := uc.files.ReadBytes(ctx, v.WorkflowFile)
+	if err != nil {
+		return nil, nil, "", application.NewUseCaseError("wdl diff", "failed to read "+v.WorkflowFile, err)
+	}
+	if v.DependenciesFile == "" {
+		// A bundle's main WDL imports files that only exist inside its zip.
+		bundleZip := strings.TrimSuffix(v.WorkflowFile, filepath.Ext(v.WorkflowFile)) + ".zip"
+		if data, err := uc.files.ReadBytes(ctx, bundleZip); err == nil {
+			if deps, err := wdl.SourcesFromZip(data); err == nil {
+				return source, deps, "", nil
+			}
+		}
+	}
+	deps, warning := resolveImportSources(ctx, uc.files, v.WorkflowFile, v.DependenciesFile)
+	return source, deps, warning, nil
+}
+
+// readRevision reads a version from git: the workflow, and every WDL file
+// under its directory, at the revision.
+func (uc *WDLDiffUseCase) readRevision(ctx context.Context, v WDLVersion) ([]byte, wdl.SourceSet, string, error) {
+	if uc.revisions == nil {
+		return nil, nil, "", application.NewInputValidationError("revision", "reading from git is not available")
+	}
+	source, err := uc.revisions.ReadFile(ctx, v.Revision, v.WorkflowFile)
+	if err != nil {
+		return nil, nil, "", application.NewUseCaseError("wdl diff", "failed to read "+v.Label(), err)
+	}
+	if v.DependenciesFile != "" {
+		deps, warning := resolveImportSources(ctx, uc.files, v.WorkflowFile, v.DependenciesFile)
+		return source, deps, warning, nil
+	}
+
+	dir := filepath.Dir(v.WorkflowFile)
+	names, err := uc.revisions.ListFiles(ctx, v.Revision, dir)
+	if err != nil {
+		return source, nil, fmt.Sprintf("could not list the WDL files beside the workflow: %v", err), nil
+	}
+	deps := make(wdl.SourceSet)
+	var unreadable []string
+	for _, name := range names {
+		if !strings.EqualFold(path.Ext(name), ".wdl") {
+			continue
+		}
+		content, err := uc.revisions.ReadFile(ctx, v.Revision, filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			unreadable = append(unreadable, name)
+			continue
+		}
+		deps.Add(name, content)
+	}
+	if len(unreadable) > 0 {
+		return source, deps, "could not read " + strings.Join(unreadable, ", "), nil
+	}
+	return source, deps, "", nil
+}
+
+// compareCall compares a call present in both versions. Its Kind is
+// ChangeUnchanged when nothing differs.
+func compareCall(before, after *wdl.CallNode, oldSpecs, newSpecs map[string]wdl.TaskSpec) domain.CallChange {
+	change := domain.CallChange{Call: after.Name, TaskOld: before.Task, TaskNew: after.Task}
+	change.Bindings = compareBindings(before.Bindings, after.Bindings)
+	for _, b := range change.Bindings {
+		change.CacheReasons = append(change.CacheReasons, fmt.Sprintf("input %s %s", b.Key, bindingVerb(b.Kind)))
+	}
+
+	oldSpec, oldOK := oldSpecs[before.Task]
+	newSpec, newOK := newSpecs[after.Task]
+	if !before.Unresolved && !after.Unresolved && oldOK && newOK {
+		compareTaskSpecs(&change, oldSpec, newSpec, after.Bindings)
+	}
+
+	if change.TaskOld != change.TaskNew || len(change.Bindings) > 0 || change.CommandChanged() ||
+		len(change.Runtime) > 0 || len(change.Inputs) > 0 || len(change.Outputs) > 0 {
+		change.Kind = domain.ChangeModified
+	}
+	return change
+}
+
+// compareTaskSpecs fills in what changed in the task a call runs, and which of
+// those changes alter its cache fingerprint.
+func compareTaskSpecs(change *domain.CallChange, old, cur wdl.TaskSpec, bindings map[string]wdl.ResolvedBinding) {
+	oldHash, _ := old.CommandHash()
+	newHash, _ := cur.CommandHash()
+	if oldHash != newHash {
+		change.CommandHashOld, change.CommandHashNew = oldHash, newHash
+		change.CacheReasons = append(change.CacheReasons, "command template changed")
+	}
+
+	change.Runtime = compareStringMaps(old.RuntimeExpressions, cur.RuntimeExpressions)
+	for _, kd := range change.Runtime {
+		if fingerprintRuntime[kd.Key] && kd.Key != "docker" {
+			change.CacheReasons = append(change.CacheReasons, fmt.Sprintf("runtime attribute %s changed", kd.Key))
+		}
+	}
+	// docker is compared by the image it resolves to, which an input default
+	// may supply while the runtime section reads the same.
+	oldImage, oldOK := old.DockerValue()
+	newImage, newOK := cur.DockerValue()
+	switch {
+	case oldOK && newOK && oldImage != newImage:
+		change.Runtime = upsertKeyDiff(change.Runtime, domain.KeyDiff{Key: "docker", Kind: domain.ChangeModified, ValueA: oldImage, ValueB: newImage})
+		change.CacheReasons = append(change.CacheReasons, fmt.Sprintf("docker image changed (%s → %s)", oldImage, newImage))
+	case old.RuntimeExpressions["docker"] != cur.RuntimeExpressions["docker"] && !(oldOK && newOK):
+		change.CacheReasons = append(change.CacheReasons, "runtime attribute docker changed")
+	}
+
+	change.Inputs = compareSignature(old.InputTypes, cur.InputTypes, old.InputDefaults, cur.InputDefaults)
+	for _, sc := range change.Inputs {
+		if sc.Kind == domain.ChangeModified && old.InputTypes[sc.Name] == cur.InputTypes[sc.Name] {
+			// Only the default changed, which matters only if the call leaves
+			// the input to it.
+			if _, bound := bindings[sc.Name]; !bound {
+				change.CacheReasons = append(change.CacheReasons, fmt.Sprintf("default of input %s changed", sc.Name))
+			}
+			continue
+		}
+		change.CacheReasons = append(change.CacheReasons, fmt.Sprintf("input %s %s", sc.Name, sc.Kind))
+	}
+
+	change.Outputs = compareSignature(old.OutputTypes, cur.OutputTypes, old.OutputExpressions, cur.OutputExpressions)
+	if len(change.Outputs) > 0 {
+		change.CacheReasons = append(change.CacheReasons, "outputs changed")
+	}
+}
+
+// compareSignature compares declarations given as name → type and name →
+// value, rendering each side as written ("Int threads = 4").
+func compareSignature(oldTypes, newTypes, oldValues, newValues map[string]string) []domain.SignatureChange {
+	render := func(types, values map[string]string, name string) string {
+		text := types[name] + " " + name
+		if v, ok := values[name]; ok {
+			text += " = " + v
+		}
+		return text
+	}
+	var out []domain.SignatureChange
+	for _, name := range unionKeys(oldTypes, newTypes) {
+		_, inOld := oldTypes[name]
+		_, inNew := newTypes[name]
+		sc := domain.SignatureChange{Name: name}
+		switch {
+		case !inOld:
+			sc.Kind, sc.New = domain.ChangeAdded, render(newTypes, newValues, name)
+		case !inNew:
+			sc.Kind, sc.Old = domain.ChangeRemoved, render(oldTypes, oldValues, name)
+		default:
+			sc.Old, sc.New = render(oldTypes, oldValues, name), render(newTypes, newValues, name)
+			if sc.Old == sc.New {
+				continue
+			}
+			sc.Kind = domain.ChangeModified
+		}
+		out = append(out, sc)
+	}
+	return out
+}
+
+// compareDeclarations compares a workflow's input or output sections.
+func compareDeclarations(old, cur []*ast.Declaration) []domain.SignatureChange {
+	types := func(decls []*ast.Declaration) (map[string]string, map[string]string) {
+		t, v := make(map[string]string), make(map[string]string)
+		for _, d := range decls {
+			if d == nil || d.Type == nil {
+				continue
+			}
+			t[d.Name] = d.Type.String()
+			if d.Expression != nil {
+				v[d.Name] = wdl.ExpressionText(d.Expression)
+			}
+		}
+		return t, v
+	}
+	oldTypes, oldValues := types(old)
+	newTypes, newValues := types(cur)
+	return compareSignature(oldTypes, newTypes, oldValues, newValues)
+}
+
+// compareStringMaps reports the keys whose value differs.
+func compareStringMaps(old, cur map[string]string) []domain.KeyDiff {
+	var out []domain.KeyDiff
+	for _, key := range unionKeys(old, cur) {
+		a, inOld := old[key]
+		b, inNew := cur[key]
+		switch {
+		case !inOld:
+			out = append(out, domain.KeyDiff{Key: key, Kind: domain.ChangeAdded, ValueB: b})
+		case !inNew:
+			out = append(out, domain.KeyDiff{Key: key, Kind: domain.ChangeRemoved, ValueA: a})
+		case a != b:
+			out = append(out, domain.KeyDiff{Key: key, Kind: domain.ChangeModified, ValueA: a, ValueB: b})
+		}
+	}
+	return out
+}
+
+// compareBindings reports the call inputs whose value now comes from
+// elsewhere, each side rendered as the leaves it is built from.
+func compareBindings(old, cur map[string]wdl.ResolvedBinding) []domain.KeyDiff {
+	render := func(bindings map[string]wdl.ResolvedBinding) map[string]string {
+		out := make(map[string]string, len(bindings))
+		for name, b := range bindings {
+			out[name] = bindingText(b)
+		}
+		return out
+	}
+	return compareStringMaps(render(old), render(cur))
+}
+
+// bindingText renders a binding's leaves, sorted, so two bindings built from
+// the same leaves read the same.
+func bindingText(b wdl.ResolvedBinding) string {
+	parts := make([]string, 0, len(b.Sources))
+	for _, s := range b.Sources {
+		var text string
+		switch s.Kind {
+		case wdl.SourceLiteral:
+			text = s.Literal
+		case wdl.SourceCall:
+			text = s.Name + "." + s.Member
+		case wdl.SourceElement:
+			text = "element of " + s.Name
+		case wdl.SourceIndex:
+			text = "scatter index"
+		default:
+			text = s.Name
+			if s.Scope != "" {
+				text = s.Scope + "." + text
+			}
+		}
+		if s.Field != "" {
+			text += "." + s.Field
+		}
+		parts = append(parts, text)
+	}
+	sort.Strings(parts)
+	text := strings.Join(parts, ", ")
+	if !b.Complete {
+		text += " (+ unresolved)"
+	}
+	return text
+}
+
+func bindingVerb(kind domain.ChangeKind) string {
+	switch kind {
+	case domain.ChangeAdded:
+		return "now set"
+	case domain.ChangeRemoved:
+		return "no longer set"
+	default:
+		return "rebound"
+	}
+}
+
+// changedInputDefaults lists the workflow inputs whose static default differs.
+func changedInputDefaults(old, cur map[string]string) map[string]bool {
+	out := make(map[string]bool)
+	for _, kd := range compareStringMaps(old, cur) {
+		out[kd.Key] = true
+	}
+	return out
+}
+
+// defaultedInput returns a top-level workflow input, among those whose default
+// changed, that the binding reads; "" when it reads none.
+func defaultedInput(b wdl.ResolvedBinding, changed map[string]bool) string {
+	for _, s := range b.Sources {
+		if s.Kind == wdl.SourceInput && s.Scope == "" && changed[s.Name] {
+			return s.Name
+		}
+	}
+	return ""
+}
+
+// upsertKeyDiff replaces the diff with the same key, or appends it.
+func upsertKeyDiff(diffs []domain.KeyDiff, kd domain.KeyDiff) []domain.KeyDiff {
+	for i := range diffs {
+		if diffs[i].Key == kd.Key {
+			diffs[i] = kd
+			return diffs
+		}
+	}
+	return append(diffs, kd)
+}
+
+// unionNames lists the call paths of both graphs, sorted.
+func unionNames(a, b *wdl.CallGraph) []string {
+	seen := make(map[string]bool)
+	for _, n := range a.Names() {
+		seen[n] = true
+	}
+	for _, n := range b.Names() {
+		seen[n] = true
+	}
+	return sortedStringKeys(seen)
+}
+
+// unionKeys lists the keys of both maps, sorted.
+func unionKeys(a, b map[string]string) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	return sortedStringKeys(seen)
+}
@@ -0,0 +1,266 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	domain "github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+const diffOldWDL = `version 1.0
+
+workflow Pipe {
+    input {
+        File reads
+    }
+    call Align { input: reads = reads }
+    call Sort { input: bam = Align.bam }
+    call Index { input: bam = Sort.sorted }
+    call QC { input: reads = reads }
+    call Count { input: bam = Sort.sorted }
+    call Old
+    output { File bai = Index.bai }
+}
+
+task Align {
+    input { File reads }
+    command <<< bwa mem ~{reads} > out.bam >>>
+    output { File bam = "out.bam" }
+}
+
+task Sort {
+    input { File bam }
+    command <<< samtools sort ~{bam} > sorted.bam >>>
+    output { File sorted = "sorted.bam" }
+}
+
+task Index {
+    input { File bam }
+    command <<< samtools index ~{bam} >>>
+    output { File bai = "out.bai" }
+}
+
+task QC {
+    input { File reads }
+    command <<< fastqc ~{reads} >>>
+    runtime { cpu: 2 }
+}
+
+task Count {
+    input {
+        File bam
+        String docker = "samtools:1.16"
+    }
+    command <<< samtools view -c ~{bam} >>>
+    runtime { docker: docker }
+}
+
+task Old {
+    command <<< true >>>
+}
+`
+
+const diffNewWDL = `version 1.0
+
+workflow Pipe {
+    input {
+        File reads
+        String sample
+    }
+    call Align { input: reads = reads }
+    call Sort { input: bam = Align.bam }
+    call Index { input: bam = Sort.sorted }
+    call QC { input: reads = reads }
+    call Count { input: bam = Sort.sorted }
+    call Report { input: name = sample }
+    output { File bai = Index.bai }
+}
+
+task Align {
+    input { File reads }
+    command <<< bwa mem -t 4 ~{reads} > out.bam >>>
+    output { File bam = "out.bam" }
+}
+
+task Sort {
+    input { File bam }
+    command <<< samtools sort ~{bam} > sorted.bam >>>
+    output { File sorted = "sorted.bam" }
+}
+
+task Index {
+    input { File bam }
+    command <<< samtools index ~{bam} >>>
+    output { File bai = "out.bai" }
+}
+
+task QC {
+    input { File reads }
+    command <<< fastqc ~{reads} >>>
+    runtime { cpu: 8 }
+}
+
+task Count {
+    input {
+        File bam
+        String docker = "samtools:1.17"
+    }
+    command <<< samtools view -c ~{bam} >>>
+    runtime { docker: docker }
+}
+
+task Report {
+    input { String name }
+    command <<< echo ~{name} >>>
+}
+`
+
+// fakeRevisions serves files keyed by "rev:path".
+type fakeRevisions map[string]string
+
+func (f fakeRevisions) ReadFile(ctx context.Context, rev, path string) ([]byte, error) {
+	if content, ok := f[rev+":"+path]; ok {
+		return []byte(content), nil
+	}
+	return nil, os.ErrNotExist
+}
+
+func (f fakeRevisions) ListFiles(ctx context.Context, rev, dir string) ([]string, error) {
+	return []string{"main.wdl", "lib/tasks.wdl", "README.md"}, nil
+}
+
+func callChange(d *domain.WDLDiff, name string) (domain.CallChange, bool) {
+	for _, c := range d.Calls {
+		if c.Call == name {
+			return c, true
+		}
+	}
+	return domain.CallChange{}, false
+}
+
+func lossNames(losses []domain.CacheLoss) []string {
+	var out []string
+	for _, l := range losses {
+		out = append(out, l.Call)
+	}
+	return out
+}
+
+func TestWDLDiffUseCase(t *testing.T) {
+	files := &mockFileProvider{
+		readBytesFunc: func(ctx context.Context, path string) ([]byte, error) {
+			switch path {
+			case "/nonexistent/old.wdl":
+				return []byte(diffOldWDL), nil
+			case "/nonexistent/new.wdl":
+				return []byte(diffNewWDL), nil
+			}
+			return nil, os.ErrNotExist
+		},
+	}
+	uc := NewWDLDiffUseCase(files, nil)
+
+	d, err := uc.Execute(context.Background(), WDLDiffInput{
+		Old: WDLVersion{WorkflowFile: "/nonexistent/old.wdl"},
+		New: WDLVersion{WorkflowFile: "/nonexistent/new.wdl"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+
+	if want := []domain.SignatureChange{{Name: "sample", Kind: domain.ChangeAdded, New: "String sample"}}; !reflect.DeepEqual(d.Inputs, want) {
+		t.Errorf("Inputs = %+v, want %+v", d.Inputs, want)
+	}
+
+	var calls []string
+	for _, c := range d.Calls {
+		calls = append(calls, c.Call+" "+c.Kind.String())
+	}
+	wantCalls := []string{"Align modified", "Count modified", "Old removed", "QC modified", "Report added"}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("Calls = %v, want %v", calls, wantCalls)
+	}
+
+	align, _ := callChange(d, "Align")
+	if !align.CommandChanged() || align.CommandHashOld == "" || align.CommandHashNew == "" {
+		t.Errorf("Align command hashes = %q → %q, want a change", align.CommandHashOld, align.CommandHashNew)
+	}
+	count, _ := callChange(d, "Count")
+	wantDocker := []domain.KeyDiff{{Key: "docker", Kind: domain.ChangeModified, ValueA: "samtools:1.16", ValueB: "samtools:1.17"}}
+	if !reflect.DeepEqual(count.Runtime, wantDocker) {
+		t.Errorf("Count runtime = %+v, want the resolved images", count.Runtime)
+	}
+	qc, _ := callChange(d, "QC")
+	if len(qc.Runtime) != 1 || qc.Runtime[0].Key != "cpu" || len(qc.CacheReasons) != 0 {
+		t.Errorf("QC = %+v, want a cpu change that keeps the cache", qc)
+	}
+
+	if got, want := lossNames(d.Reruns), []string{"Align", "Count", "Report"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reruns = %v, want %v", got, want)
+	}
+	// Count sits below Sort too, but it is blamed on its own change.
+	if got, want := lossNames(d.Downstream), []string{"Index", "Sort"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Downstream = %v, want %v", got, want)
+	}
+	for _, l := range d.Downstream {
+		if l.Cause != "Align" {
+			t.Errorf("%s cause = %q, want Align", l.Call, l.Cause)
+		}
+	}
+}
+
+func TestWDLDiffUseCaseRevisions(t *testing.T) {
+	const main = `version 1.0
+import "lib/tasks.wdl" as lib
+workflow W {
+    call lib.Align
+}
+`
+	task := func(image string) string {
+		return `version 1.0
+task Align {
+    command <<< bwa mem >>>
+    runtime { docker: "` + image + `" }
+}
+`
+	}
+	revisions := fakeRevisions{
+		"v1:wf/main.wdl":      main,
+		"v1:wf/lib/tasks.wdl": task("bwa:0.7.15"),
+		"v2:wf/main.wdl":      main,
+		"v2:wf/lib/tasks.wdl": task("bwa:0.7.17"),
+	}
+	uc := NewWDLDiffUseCase(&mockFileProvider{}, revisions)
+
+	d, err := uc.Execute(context.Background(), WDLDiffInput{
+		Old: WDLVersion{WorkflowFile: "wf/main.wdl", Revision: "v1"},
+		New: WDLVersion{WorkflowFile: "wf/main.wdl", Revision: "v2"},
+	})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if len(d.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want the imports resolved at each revision", d.Warnings)
+	}
+	if d.Old != "v1:wf/main.wdl" {
+		t.Errorf("Old = %q", d.Old)
+	}
+	align, ok := callChange(d, "Align")
+	if !ok || len(align.Runtime) != 1 || align.Runtime[0].ValueB != "bwa:0.7.17" {
+		t.Errorf("Align = %+v, want the docker change", align)
+	}
+
+	// Without a revision reader, a revision cannot be read.
+	_, err = NewWDLDiffUseCase(&mockFileProvider{}, nil).Execute(context.Background(), WDLDiffInput{
+		Old: WDLVersion{WorkflowFile: "wf/main.wdl", Revision: "v1"},
+		New: WDLVersion{WorkflowFile: "wf/main.wdl"},
+	})
+	var valErr *application.InputValidationError
+	if !errors.As(err, &valErr) {
+		t.Errorf("error = %v, want an input validation error", err)
+	}
+}
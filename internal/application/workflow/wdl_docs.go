@@ -0,0 +1,580 @@
+// wdl_docs.go generates reference documentation for every workflow, task and
+// struct of the WDL index, so pipelines published to other labs are documented
+// from their own meta and parameter_meta instead of by hand.
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lmtani/pumbaa/internal/application"
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/wdlindex"
+	"github.com/lmtani/pumbaa/pkg/wdl"
+)
+
+// DocsFormat is the format documentation pages are written in.
+type DocsFormat string
+
+const (
+	DocsMarkdown DocsFormat = "markdown"
+	DocsHTML     DocsFormat = "html"
+)
+
+// DocsFormats lists the supported formats.
+var DocsFormats = []DocsFormat{DocsMarkdown, DocsHTML}
+
+// WDLDocsUseCase generates documentation from the WDL index.
+type WDLDocsUseCase struct {
+	files ports.FileProvider
+	html  ports.DocsHTMLRenderer
+}
+
+// NewWDLDocsUseCase creates a new documentation use case. files reads the
+// WDL sources the call graphs are drawn from.
+func NewWDLDocsUseCase(files ports.FileProvider, html ports.DocsHTMLRenderer) *WDLDocsUseCase {
+	return &WDLDocsUseCase{files: files, html: html}
+}
+
+// WDLDocsInput is the input for generating documentation.
+type WDLDocsInput struct {
+	Index  *wdlindex.Index
+	Format DocsFormat
+	// Title heads the index page; it defaults to "WDL Documentation".
+	Title string
+	// NoGraphs leaves the call graph out of workflow pages.
+	NoGraphs bool
+	// Mermaid makes HTML pages load Mermaid from a CDN when viewed, to draw
+	// the call graphs; without it they show the graph source.
+	Mermaid bool
+}
+
+// DocPage is one generated page.
+type DocPage struct {
+	// Path is relative to the output directory, with forward slashes.
+	Path    string
+	Content []byte
+}
+
+// WDLDocsOutput is the generated documentation.
+type WDLDocsOutput struct {
+	// Pages are index first, then workflows, tasks and structs by name.
+	Pages     []DocPage
+	Workflows int
+	Tasks     int
+	Structs   int
+	// Warnings lists what could not be documented fully, such as a call graph
+	// whose sources could not be read.
+	Warnings []string
+}
+
+// Execute generates one page per workflow, task and struct defined in the
+// index, plus an index page linking them. Pages cross-link: a workflow to the
+// tasks and subworkflows it calls, a task to the workflows calling it, and a
+// type to the struct it names.
+func (uc *WDLDocsUseCase) Execute(ctx context.Context, input WDLDocsInput) (*WDLDocsOutput, error) {
+	if input.Index == nil {
+		return nil, application.NewInputValidationError("index", "is required")
+	}
+	format := input.Format
+	if format == "" {
+		format = DocsMarkdown
+	}
+	if format != DocsMarkdown && format != DocsHTML {
+		return nil, application.NewInputValidationError("format", fmt.Sprintf("unsupported format %q (use markdown or html)", format))
+	}
+	if format == DocsHTML && uc.html == nil {
+		return nil, application.NewInputValidationError("format", "HTML rendering is not available")
+	}
+	title := input.Title
+	if title == "" {
+		title = "WDL Documentation"
+	}
+
+	site := newDocsSite(input.Index, format)
+	out := &WDLDocsOutput{Workflows: len(site.workflows), Tasks: len(site.tasks), Structs: len(site.structs)}
+
+	var pages []docsMarkdown
+	pages = append(pages, docsMarkdown{"index", title, site.indexPage(title)})
+	for _, wf := range site.workflows {
+		graph := ""
+		if !input.NoGraphs {
+			var warning string
+			graph, warning = uc.callGraph(ctx, input.Index, wf.Source)
+			if warning != "" {
+				out.Warnings = append(out.Warnings, fmt.Sprintf("workflow %s: %s", wf.Name, warning))
+			}
+		}
+		pages = append(pages, docsMarkdown{site.pages[wf], wf.Name, site.workflowPage(wf, graph)})
+	}
+	for _, t := range site.tasks {
+		pages = append(pages, docsMarkdown{site.pages[t], t.Name, site.taskPage(t)})
+	}
+	for _, s := range site.structs {
+		pages = append(pages, docsMarkdown{site.pages[s], s.Name, site.structPage(s)})
+	}
+
+	for _, p := range pages {
+		page := DocPage{Path: p.path + site.ext, Content: []byte(p.markdown)}
+		if format == DocsHTML {
+			home, err := filepath.Rel(path.Dir(p.path), "index")
+			if err != nil {
+				home = "index"
+			}
+			rendered, err := uc.html.RenderHTML(ports.DocsPageData{
+				Title:     p.title,
+				IndexLink: filepath.ToSlash(home) + site.ext,
+				Markdown:  page.Content,
+				Mermaid:   input.Mermaid,
+			})
+			if err != nil {
+				return nil, application.NewUseCaseError("docs", "failed to render "+page.Path, err)
+			}
+			page.Content = rendered
+		}
+		out.Pages = append(out.Pages, page)
+	}
+	return out, nil
+}
+
+// docsMarkdown is a page before it is converted to its output format.
+type docsMarkdown struct {
+	path, title, markdown string
+}
+
+// callGraph draws a workflow's call graph as Mermaid, reading the workflow
+// and every local file it imports, transitively.
+func (uc *WDLDocsUseCase) callGraph(ctx context.Context, idx *wdlindex.Index, source string) (string, string) {
+	main, err := uc.files.ReadBytes(ctx, source)
+	if err != nil {
+		return "", fmt.Sprintf("call graph left out: %v", err)
+	}
+	sources := wdl.SourceSet{}
+	seen := map[string]bool{source: true}
+	queue := []string{source}
+	for len(queue) > 0 {
+		entry := idx.Files[queue[0]]
+		queue = queue[1:]
+		if entry == nil {
+			continue
+		}
+		for _, imp := range entry.Imports {
+			if imp.Path == "" || seen[imp.Path] {
+				continue
+			}
+			seen[imp.Path] = true
+			data, err := uc.files.ReadBytes(ctx, imp.Path)
+			if err != nil {
+				continue // Its calls are drawn as opaque boxes.
+			}
+			sources.Add(imp.Path, data)
+			queue = append(queue, imp.Path)
+		}
+	}
+
+	graph, err := wdl.BuildCallGraphWithSources(main, sources)
+	if err != nil {
+		return "", fmt.Sprintf("call graph left out: %v", err)
+	}
+	rendered, err := wdl.RenderCallGraph(graph, wdl.GraphMermaid, nil)
+	if err != nil {
+		return "", fmt.Sprintf("call graph left out: %v", err)
+	}
+	return string(rendered), ""
+}
+
+// docsSite knows every documented definition and the page it is on, so pages
+// can link to each other.
+type docsSite struct {
+	idx       *wdlindex.Index
+	ext       string
+	workflows []*wdlindex.IndexedWorkflow
+	tasks     []*wdlindex.IndexedTask
+	structs   []*wdlindex.IndexedStruct
+	// pages maps each definition to its page path, without extension.
+	pages map[any]string
+	// structPages maps a struct name to the page of its first definition.
+	structPages map[string]string
+	// callers lists the workflows calling each task or workflow.
+	callers map[any][]*wdlindex.IndexedWorkflow
+}
+
+func newDocsSite(idx *wdlindex.Index, format DocsFormat) *docsSite {
+	s := &docsSite{
+		idx:         idx,
+		ext:         ".md",
+		pages:       make(map[any]string),
+		structPages: make(map[string]string),
+		callers:     make(map[any][]*wdlindex.IndexedWorkflow),
+	}
+	if format == DocsHTML {
+		s.ext = ".html"
+	}
+
+	paths := make([]string, 0, len(idx.Files))
+	for p := range idx.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	// Pages are named after the definition; a name defined in several files
+	// gets a numbered page for each later definition, in path order.
+	used := make(map[string]bool)
+	page := func(dir, name string) string {
+		base := dir + "/" + docsSlug(name)
+		p := base
+		for n := 2; used[p]; n++ {
+			p = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[p] = true
+		return p
+	}
+	for _, p := range paths {
+		entry := idx.Files[p]
+		if wf := entry.Workflow; wf != nil {
+			s.workflows = append(s.workflows, wf)
+			s.pages[wf] = page("workflows", wf.Name)
+		}
+		for _, t := range entry.Tasks {
+			s.tasks = append(s.tasks, t)
+			s.pages[t] = page("tasks", t.Name)
+		}
+		for _, st := range entry.Structs {
+			s.structs = append(s.structs, st)
+			s.pages[st] = page("structs", st.Name)
+			if _, ok := s.structPages[st.Name]; !ok {
+				s.structPages[st.Name] = s.pages[st]
+			}
+		}
+	}
+
+	for _, wf := range s.workflows {
+		for _, target := range uniqueStrings(wf.Calls) {
+			task, sub := idx.ResolveCall(wf.Source, target)
+			if task != nil {
+				s.callers[task] = append(s.callers[task], wf)
+			} else if sub != nil {
+				s.callers[sub] = append(s.callers[sub], wf)
+			}
+		}
+	}
+
+	sort.SliceStable(s.workflows, func(i, j int) bool { return s.workflows[i].Name < s.workflows[j].Name })
+	sort.SliceStable(s.tasks, func(i, j int) bool { return s.tasks[i].Name < s.tasks[j].Name })
+	sort.SliceStable(s.structs, func(i, j int) bool { return s.structs[i].Name < s.structs[j].Name })
+	return s
+}
+
+// link is a relative link from the page at from to the page of def.
+func (s *docsSite) link(from string, def any) string {
+	to, ok := s.pages[def]
+	if !ok {
+		return ""
+	}
+	rel, err := filepath.Rel(path.Dir(from), to)
+	if err != nil {
+		return to + s.ext
+	}
+	return filepath.ToSlash(rel) + s.ext
+}
+
+// sourceLabel shows a source path relative to the root it was found under.
+func (s *docsSite) sourceLabel(source string) string {
+	entry := s.idx.Files[source]
+	if entry == nil || entry.Root == "" {
+		return source
+	}
+	rel, err := filepath.Rel(entry.Root, source)
+	if err != nil {
+		return source
+	}
+	return filepath.Join(filepath.Base(entry.Root), rel)
+}
+
+func (s *docsSite) indexPage(title string) string {
+	var b strings.Builder
+	from := "index"
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	roots := make([]string, len(s.idx.Roots))
+	for i, r := range s.idx.Roots {
+		roots[i] = "`" + filepath.Base(r) + "`"
+	}
+	fmt.Fprintf(&b, "Generated from the WDL files under %s.\n", strings.Join(roots, ", "))
+
+	if len(s.workflows) > 0 {
+		b.WriteString("\n## Workflows\n\n| Workflow | Description | Source |\n|---|---|---|\n")
+		for _, wf := range s.workflows {
+			fmt.Fprintf(&b, "| [%s](%s) | %s | `%s` |\n", wf.Name, s.link(from, wf), docsCell(wf.Description), s.sourceLabel(wf.Source))
+		}
+	}
+	if len(s.tasks) > 0 {
+		b.WriteString("\n## Tasks\n\n| Task | Description | Source |\n|---|---|---|\n")
+		for _, t := range s.tasks {
+			fmt.Fprintf(&b, "| [%s](%s) | %s | `%s` |\n", t.Name, s.link(from, t), docsCell(t.Description), s.sourceLabel(t.Source))
+		}
+	}
+	if len(s.structs) > 0 {
+		b.WriteString("\n## Structs\n\n| Struct | Members | Source |\n|---|---|---|\n")
+		for _, st := range s.structs {
+			fmt.Fprintf(&b, "| [%s](%s) | %d | `%s` |\n", st.Name, s.link(from, st), len(st.Members), s.sourceLabel(st.Source))
+		}
+	}
+	if errs := s.idx.Errors(); len(errs) > 0 {
+		b.WriteString("\n## Files That Could Not Be Parsed\n\nThese files are not documented.\n\n| File | Error |\n|---|---|\n")
+		for _, e := range errs {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", s.sourceLabel(e.Path), docsCell(e.Message))
+		}
+	}
+	return b.String()
+}
+
+func (s *docsSite) workflowPage(wf *wdlindex.IndexedWorkflow, graph string) string {
+	from := s.pages[wf]
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Workflow `%s`\n\n", wf.Name)
+	s.writeHeader(&b, wf.Description, wf.Source, wf.Meta)
+	s.writeDeclarations(&b, from, "Inputs", wf.Inputs, true)
+	s.writeDeclarations(&b, from, "Outputs", wf.Outputs, false)
+
+	if targets := uniqueStrings(wf.Calls); len(targets) > 0 {
+		b.WriteString("\n## Calls\n\n")
+		for _, target := range targets {
+			task, sub := s.idx.ResolveCall(wf.Source, target)
+			switch {
+			case task != nil:
+				fmt.Fprintf(&b, "- [`%s`](%s) (task)%s\n", target, s.link(from, task), docsSummary(task.Description))
+			case sub != nil:
+				fmt.Fprintf(&b, "- [`%s`](%s) (workflow)%s\n", target, s.link(from, sub), docsSummary(sub.Description))
+			default:
+				fmt.Fprintf(&b, "- `%s` (not indexed)\n", target)
+			}
+		}
+	}
+	if graph != "" {
+		b.WriteString("\n## Call Graph\n\n" + docsFence("mermaid", strings.TrimRight(graph, "\n")))
+	}
+	s.writeCallers(&b, from, s.callers[wf])
+	return b.String()
+}
+
+func (s *docsSite) taskPage(t *wdlindex.IndexedTask) string {
+	from := s.pages[t]
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Task `%s`\n\n", t.Name)
+	s.writeHeader(&b, t.Description, t.Source, t.Meta)
+	s.writeDeclarations(&b, from, "Inputs", t.Inputs, true)
+	s.writeDeclarations(&b, from, "Outputs", t.Outputs, false)
+
+	if len(t.Runtime) > 0 {
+		b.WriteString("\n## Runtime\n\n| Attribute | Value |\n|---|---|\n")
+		for _, key := range sortedStringKeys(t.Runtime) {
+			fmt.Fprintf(&b, "| `%s` | `%s` |\n", key, docsCell(t.Runtime[key]))
+		}
+	}
+	if command := strings.Trim(dedent(t.Command), "\n"); command != "" {
+		b.WriteString("\n## Command\n\n" + docsFence("bash", command))
+	}
+	s.writeCallers(&b, from, s.callers[t])
+	return b.String()
+}
+
+func (s *docsSite) structPage(st *wdlindex.IndexedStruct) string {
+	from := s.pages[st]
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Struct `%s`\n\n", st.Name)
+	s.writeHeader(&b, "", st.Source, nil)
+	if len(st.Members) > 0 {
+		b.WriteString("\n## Members\n\n| Name | Type |\n|---|---|\n")
+		for _, m := range st.Members {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", m.Name, s.typeCell(from, m.Type))
+		}
+	}
+	return b.String()
+}
+
+// writeHeader writes a definition's description, source and other meta.
+func (s *docsSite) writeHeader(b *strings.Builder, description, source string, meta map[string]string) {
+	if description != "" {
+		fmt.Fprintf(b, "%s\n\n", description)
+	}
+	fmt.Fprintf(b, "**Source:** `%s`\n", s.sourceLabel(source))
+	var keys []string
+	for _, key := range sortedStringKeys(meta) {
+		if key != "description" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		b.WriteString("\n| Meta | Value |\n|---|---|\n")
+		for _, key := range keys {
+			fmt.Fprintf(b, "| %s | %s |\n", key, docsCell(meta[key]))
+		}
+	}
+}
+
+// writeDeclarations writes an inputs or outputs table.
+func (s *docsSite) writeDeclarations(b *strings.Builder, from, heading string, decls []wdlindex.Declaration, inputs bool) {
+	if len(decls) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", heading)
+	if inputs {
+		b.WriteString("| Name | Type | Required | Default | Description |\n|---|---|:---:|---|---|\n")
+	} else {
+		b.WriteString("| Name | Type | Description |\n|---|---|---|\n")
+	}
+	for _, d := range decls {
+		if !inputs {
+			fmt.Fprintf(b, "| `%s` | %s | %s |\n", d.Name, s.typeCell(from, d.Type), docsCell(d.Description))
+			continue
+		}
+		required, def := "", ""
+		if d.Required() {
+			required = "yes"
+		}
+		if d.Default != "" {
+			def = "`" + docsCell(d.Default) + "`"
+		}
+		fmt.Fprintf(b, "| `%s` | %s | %s | %s | %s |\n", d.Name, s.typeCell(from, d.Type), required, def, docsCell(d.Description))
+	}
+}
+
+// writeCallers lists the workflows calling a definition.
+func (s *docsSite) writeCallers(b *strings.Builder, from string, callers []*wdlindex.IndexedWorkflow) {
+	if len(callers) == 0 {
+		return
+	}
+	b.WriteString("\n## Called By\n\n")
+	for _, wf := range callers {
+		fmt.Fprintf(b, "- [`%s`](%s)\n", wf.Name, s.link(from, wf))
+	}
+}
+
+// typeIdentifier matches the names in a WDL type, such as "Array[Reads]?".
+var typeIdentifier = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// typeCell renders a type, linking the structs it names.
+func (s *docsSite) typeCell(from, typ string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range typeIdentifier.FindAllStringIndex(typ, -1) {
+		name := typ[loc[0]:loc[1]]
+		page, ok := s.structPages[name]
+		if !ok {
+			continue
+		}
+		b.WriteString(docsCode(typ[last:loc[0]]))
+		rel, err := filepath.Rel(path.Dir(from), page)
+		if err != nil {
+			rel = page
+		}
+		fmt.Fprintf(&b, "[`%s`](%s)", name, filepath.ToSlash(rel)+s.ext)
+		last = loc[1]
+	}
+	b.WriteString(docsCode(typ[last:]))
+	return b.String()
+}
+
+// docsCode wraps text in a code span, leaving empty text empty.
+func docsCode(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + text + "`"
+}
+
+// docsFence puts text in a fenced code block whose fence is longer than any
+// run of backticks in the text, so a command quoting ``` cannot close it.
+func docsFence(lang, text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + lang + "\n" + text + "\n" + fence + "\n"
+}
+
+// docsCell makes text safe for a Markdown table cell.
+func docsCell(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// docsSummary is the first sentence of a description, as a list suffix.
+func docsSummary(description string) string {
+	description = docsCell(description)
+	if description == "" {
+		return ""
+	}
+	if i := strings.Index(description, ". "); i >= 0 {
+		description = description[:i+1]
+	}
+	return " — " + description
+}
+
+// docsSlug turns a definition name into a file name.
+func docsSlug(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// dedent removes the indentation common to every non-blank line.
+func dedent(text string) string {
+	lines := strings.Split(text, "\n")
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if common < 0 || indent < common {
+			common = indent
+		}
+	}
+	if common <= 0 {
+		return text
+	}
+	for i, line := range lines {
+		if len(line) >= common {
+			lines[i] = line[common:]
+		} else {
+			lines[i] = strings.TrimLeft(line, " \t")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func sortedStringKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
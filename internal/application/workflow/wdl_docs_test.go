@@ -0,0 +1,193 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/wdlindex"
+)
+
+const docsMainWDL = `version 1.0
+
+import "lib.wdl" as lib
+
+workflow Main {
+    input {
+        Array[Reads] batch
+    }
+    scatter (r in batch) {
+        call lib.Trim { input: reads = r }
+    }
+}
+`
+
+const docsLibWDL = `version 1.0
+
+struct Reads {
+    File r1
+}
+
+task Trim {
+    input {
+        Reads reads
+    }
+    command <<< trim >>>
+}
+`
+
+func docsFixture() *wdlindex.Index {
+	idx := wdlindex.NewIndex("/repo")
+	idx.Files["/repo/main.wdl"] = &wdlindex.FileEntry{
+		Path:    "/repo/main.wdl",
+		Root:    "/repo",
+		Imports: []wdlindex.ImportRef{{URI: "lib.wdl", Alias: "lib", Path: "/repo/lib.wdl"}, {URI: "https://x/remote.wdl"}},
+		Workflow: &wdlindex.IndexedWorkflow{
+			Name:        "Main",
+			Source:      "/repo/main.wdl",
+			Description: "Trims a batch. Then stops.",
+			Meta:        map[string]string{"description": "Trims a batch. Then stops.", "author": "Lab | Team"},
+			Inputs:      []wdlindex.Declaration{{Name: "batch", Type: "Array[Reads]", Description: "The reads"}},
+			Calls:       []string{"lib.Trim", "remote.Gone"},
+		},
+	}
+	idx.Files["/repo/lib.wdl"] = &wdlindex.FileEntry{
+		Path: "/repo/lib.wdl",
+		Root: "/repo",
+		Tasks: []*wdlindex.IndexedTask{{
+			Name:        "Trim",
+			Source:      "/repo/lib.wdl",
+			Description: "Trims reads",
+			Inputs:      []wdlindex.Declaration{{Name: "reads", Type: "Reads"}, {Name: "threads", Type: "Int", Default: "4"}},
+			Command:     "\n    trim \\\n      --fast\n",
+			Runtime:     map[string]string{"docker": "trimmer:1.0", "cpu": "threads"},
+		}},
+		Structs: []*wdlindex.IndexedStruct{{Name: "Reads", Source: "/repo/lib.wdl", Members: []wdlindex.Declaration{{Name: "r1", Type: "File"}}}},
+	}
+	idx.Files["/repo/broken.wdl"] = &wdlindex.FileEntry{Path: "/repo/broken.wdl", Root: "/repo", Error: "syntax error"}
+	idx.Link()
+	return idx
+}
+
+type fakeDocsRenderer struct{ pages []ports.DocsPageData }
+
+func (r *fakeDocsRenderer) RenderHTML(page ports.DocsPageData) ([]byte, error) {
+	r.pages = append(r.pages, page)
+	return []byte("<html>" + page.Title + "</html>"), nil
+}
+
+func TestWDLDocsUseCase(t *testing.T) {
+	fp := &mockFileProvider{
+		readBytesFunc: func(ctx context.Context, path string) ([]byte, error) {
+			switch path {
+			case "/repo/main.wdl":
+				return []byte(docsMainWDL), nil
+			case "/repo/lib.wdl":
+				return []byte(docsLibWDL), nil
+			}
+			return nil, errors.New("unexpected path: " + path)
+		},
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		out, err := NewWDLDocsUseCase(fp, nil).Execute(context.Background(), WDLDocsInput{Index: docsFixture()})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		pages := make(map[string]string)
+		var paths []string
+		for _, p := range out.Pages {
+			pages[p.Path] = string(p.Content)
+			paths = append(paths, p.Path)
+		}
+		if got := strings.Join(paths, " "); got != "index.md workflows/Main.md tasks/Trim.md structs/Reads.md" {
+			t.Fatalf("pages = %s", got)
+		}
+
+		checks := map[string][]string{
+			"index.md": {
+				"[Main](workflows/Main.md) | Trims a batch. Then stops. | `repo/main.wdl`",
+				"| `repo/broken.wdl` | syntax error |",
+			},
+			"workflows/Main.md": {
+				"| author | Lab \\| Team |",
+				"| `batch` | `Array[`[`Reads`](../structs/Reads.md)`]` | yes |  | The reads |",
+				"- [`lib.Trim`](../tasks/Trim.md) (task) — Trims reads",
+				"- `remote.Gone` (not indexed)",
+				"```mermaid\nflowchart TD",
+			},
+			"tasks/Trim.md": {
+				"| `threads` | `Int` |  | `4` |  |",
+				"| `cpu` | `threads` |",
+				"```bash\ntrim \\\n  --fast\n```",
+				"- [`Main`](../workflows/Main.md)",
+			},
+			"structs/Reads.md": {"| `r1` | `File` |"},
+		}
+		for path, wants := range checks {
+			for _, want := range wants {
+				if !strings.Contains(pages[path], want) {
+					t.Errorf("%s does not contain %q:\n%s", path, want, pages[path])
+				}
+			}
+		}
+		if strings.Contains(pages["workflows/Main.md"], "| description |") {
+			t.Error("the description is repeated in the meta table")
+		}
+		if out.Workflows != 1 || out.Tasks != 1 || out.Structs != 1 || len(out.Warnings) != 0 {
+			t.Errorf("counts = %d/%d/%d, warnings = %v", out.Workflows, out.Tasks, out.Structs, out.Warnings)
+		}
+	})
+
+	t.Run("html links and graph warning", func(t *testing.T) {
+		renderer := &fakeDocsRenderer{}
+		failing := &mockFileProvider{readBytesFunc: func(context.Context, string) ([]byte, error) {
+			return nil, errors.New("gone")
+		}}
+		out, err := NewWDLDocsUseCase(failing, renderer).Execute(context.Background(), WDLDocsInput{Index: docsFixture(), Format: DocsHTML})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if out.Pages[1].Path != "workflows/Main.html" || string(out.Pages[1].Content) != "<html>Main</html>" {
+			t.Errorf("page = %s %s", out.Pages[1].Path, out.Pages[1].Content)
+		}
+		if renderer.pages[1].IndexLink != "../index.html" || !strings.Contains(string(renderer.pages[1].Markdown), "(../tasks/Trim.html)") {
+			t.Errorf("rendered page = %+v", renderer.pages[1])
+		}
+		if renderer.pages[1].Mermaid {
+			t.Error("Mermaid is loaded without being asked for")
+		}
+		if len(out.Warnings) != 1 || !strings.Contains(out.Warnings[0], "workflow Main: call graph left out") {
+			t.Errorf("Warnings = %v", out.Warnings)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := NewWDLDocsUseCase(fp, nil).Execute(context.Background(), WDLDocsInput{Index: docsFixture(), Format: "pdf"})
+		if err == nil || !strings.Contains(err.Error(), "unsupported format") {
+			t.Errorf("Execute() error = %v", err)
+		}
+	})
+}
+
+func TestDocsFence(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"no backticks", "echo hi", "```bash\necho hi\n```\n"},
+		{"short run", "echo `date`", "```bash\necho `date`\n```\n"},
+		{"fence in text", "cat <<EOF\n```\nEOF", "````bash\ncat <<EOF\n```\nEOF\n````\n"},
+		{"longest run wins", "`` ````` `", "``````bash\n`` ````` `\n``````\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := docsFence("bash", tt.text); got != tt.want {
+				t.Errorf("docsFence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -38,8 +40,45 @@ type Config struct {
 	ClientID          string `yaml:"client_id" env:"PUMBAA_CLIENT_ID"`
 
 	// WDL Context configuration
-	WDLDirectory string // Directory containing WDL workflows for chat context
+	WDLDirectory string // Directories containing WDL workflows for chat context, separated like PATH
 	WDLIndexPath string // Path to cached WDL index JSON file
+
+	// Compute quota the preflight footprint check warns against; 0 is no limit
+	QuotaCPUs     int
+	QuotaMemoryGB float64
+	QuotaVMs      int
+
+	// Storage prices per GiB-month by service, e.g. "gs=0.026,s3=0.023";
+	// services not named keep their default price
+	StoragePrices string
+
+	// S3 access; empty fields fall back to the AWS environment
+	S3Endpoint        string // S3-compatible endpoint, e.g. a local MinIO
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+
+	// Azure Blob access; with no key or SAS token only public containers are read
+	AzureStorageAccount string
+	AzureStorageKey     string
+	AzureSASToken       string
+	AzureBlobEndpoint   string // Blob endpoint of an emulator, e.g. a local Azurite
+
+	// DRS resolution of drs:// inputs
+	DRSServer string // GA4GH DRS server, required for compact identifiers
+	DRSToken  string // bearer token for the DRS server
+}
+
+// WDLRoots returns the WDL directories to index: WDLDirectory split as a
+// path list (":"-separated, ";" on Windows), skipping empty elements.
+func (c *Config) WDLRoots() []string {
+	var roots []string
+	for _, dir := range filepath.SplitList(c.WDLDirectory) {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			roots = append(roots, dir)
+		}
+	}
+	return roots
 }
 
 // Load loads configuration from file and environment variables.
@@ -181,6 +220,33 @@ func Load() *Config {
 		telemetryKey = fileCfg.TelemetryKey
 	}
 
+	// Quota config: env > file
+	quotaCPUs := envInt("PUMBAA_QUOTA_CPUS", fileCfg.QuotaCPUs)
+	quotaMemoryGB := fileCfg.QuotaMemoryGB
+	if v, err := strconv.ParseFloat(os.Getenv("PUMBAA_QUOTA_MEMORY_GB"), 64); err == nil {
+		quotaMemoryGB = v
+	}
+	quotaVMs := envInt("PUMBAA_QUOTA_VMS", fileCfg.QuotaVMs)
+
+	// Storage price config: env > file
+	storagePrices := envString("PUMBAA_STORAGE_PRICES", fileCfg.StoragePrices)
+
+	// S3 config: env > file
+	s3Endpoint := envString("PUMBAA_S3_ENDPOINT", fileCfg.S3Endpoint)
+	s3Region := envString("PUMBAA_S3_REGION", fileCfg.S3Region)
+	s3AccessKeyID := envString("PUMBAA_S3_ACCESS_KEY_ID", fileCfg.S3AccessKeyID)
+	s3SecretAccessKey := envString("PUMBAA_S3_SECRET_ACCESS_KEY", fileCfg.S3SecretAccessKey)
+
+	// Azure config: env > file, with the variable names of the Azure CLI
+	azureStorageAccount := envString("AZURE_STORAGE_ACCOUNT", fileCfg.AzureStorageAccount)
+	azureStorageKey := envString("AZURE_STORAGE_KEY", fileCfg.AzureStorageKey)
+	azureSASToken := envString("AZURE_STORAGE_SAS_TOKEN", fileCfg.AzureSASToken)
+	azureBlobEndpoint := envString("PUMBAA_AZURE_BLOB_ENDPOINT", fileCfg.AzureBlobEndpoint)
+
+	// DRS config: env > file
+	drsServer := envString("PUMBAA_DRS_SERVER", fileCfg.DRSServer)
+	drsToken := envString("PUMBAA_DRS_TOKEN", fileCfg.DRSToken)
+
 	return &Config{
 		CromwellHost:      host,
 		CromwellTimeout:   30 * time.Second,
@@ -199,5 +265,39 @@ func Load() *Config {
 		TelemetryEndpoint: telemetryEndpoint,
 		TelemetryKey:      telemetryKey,
 		ClientID:          clientID,
+		QuotaCPUs:         quotaCPUs,
+		QuotaMemoryGB:     quotaMemoryGB,
+		QuotaVMs:          quotaVMs,
+		StoragePrices:     storagePrices,
+		S3Endpoint:        s3Endpoint,
+		S3Region:          s3Region,
+		S3AccessKeyID:     s3AccessKeyID,
+		S3SecretAccessKey: s3SecretAccessKey,
+
+		AzureStorageAccount: azureStorageAccount,
+		AzureStorageKey:     azureStorageKey,
+		AzureSASToken:       azureSASToken,
+		AzureBlobEndpoint:   azureBlobEndpoint,
+
+		DRSServer: drsServer,
+		DRSToken:  drsToken,
+	}
+}
+
+// envString reads a setting from the environment, falling back when the
+// variable is unset.
+func envString(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// envInt reads a whole number from the environment, falling back when the
+// variable is unset or not a number.
+func envInt(name string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(name)); err == nil {
+		return v
 	}
+	return fallback
 }
@@ -26,6 +26,19 @@ func clearEnvVars(t *testing.T) func() {
 		"PUMBAA_TELEMETRY_ENDPOINT",
 		"PUMBAA_TELEMETRY_KEY",
 		"PUMBAA_CLIENT_ID",
+		"PUMBAA_QUOTA_CPUS",
+		"PUMBAA_QUOTA_MEMORY_GB",
+		"PUMBAA_QUOTA_VMS",
+		"PUMBAA_S3_ENDPOINT",
+		"PUMBAA_S3_REGION",
+		"PUMBAA_S3_ACCESS_KEY_ID",
+		"PUMBAA_S3_SECRET_ACCESS_KEY",
+		"AZURE_STORAGE_ACCOUNT",
+		"AZURE_STORAGE_KEY",
+		"AZURE_STORAGE_SAS_TOKEN",
+		"PUMBAA_AZURE_BLOB_ENDPOINT",
+		"PUMBAA_DRS_SERVER",
+		"PUMBAA_DRS_TOKEN",
 	}
 
 	oldValues := make(map[string]string)
@@ -127,6 +140,34 @@ func TestLoad_TelemetryEnvOverride(t *testing.T) {
 	}
 }
 
+func TestLoad_QuotaEnvOverride(t *testing.T) {
+	cleanup := clearEnvVars(t)
+	defer cleanup()
+
+	_ = os.Setenv("PUMBAA_QUOTA_CPUS", "240")
+	_ = os.Setenv("PUMBAA_QUOTA_MEMORY_GB", "960")
+
+	cfg := Load()
+
+	if cfg.QuotaCPUs != 240 || cfg.QuotaMemoryGB != 960 || cfg.QuotaVMs != 0 {
+		t.Errorf("expected quota 240 CPUs / 960 GB / no VM limit, got %d / %v / %d", cfg.QuotaCPUs, cfg.QuotaMemoryGB, cfg.QuotaVMs)
+	}
+}
+
+func TestLoad_S3EnvOverride(t *testing.T) {
+	cleanup := clearEnvVars(t)
+	defer cleanup()
+
+	_ = os.Setenv("PUMBAA_S3_ENDPOINT", "http://localhost:9000")
+	_ = os.Setenv("PUMBAA_S3_REGION", "eu-west-1")
+
+	cfg := Load()
+
+	if cfg.S3Endpoint != "http://localhost:9000" || cfg.S3Region != "eu-west-1" || cfg.S3AccessKeyID != "" {
+		t.Errorf("expected S3 endpoint and region from env, got %q / %q / %q", cfg.S3Endpoint, cfg.S3Region, cfg.S3AccessKeyID)
+	}
+}
+
 func TestLoad_ClientIDGeneration(t *testing.T) {
 	cleanup := clearEnvVars(t)
 	defer cleanup()
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
 )
 
 // FileConfig represents the structure of the config file.
@@ -37,6 +40,30 @@ type FileConfig struct {
 
 	// WDL Context
 	WDLDirectory string `yaml:"wdl_directory,omitempty"`
+
+	// Compute quota checked at preflight
+	QuotaCPUs     int     `yaml:"quota_cpus,omitempty"`
+	QuotaMemoryGB float64 `yaml:"quota_memory_gb,omitempty"`
+	QuotaVMs      int     `yaml:"quota_vms,omitempty"`
+
+	// Storage prices for the storage report
+	StoragePrices string `yaml:"storage_prices,omitempty"`
+
+	// S3
+	S3Endpoint        string `yaml:"s3_endpoint,omitempty"`
+	S3Region          string `yaml:"s3_region,omitempty"`
+	S3AccessKeyID     string `yaml:"s3_access_key_id,omitempty"`
+	S3SecretAccessKey string `yaml:"s3_secret_access_key,omitempty"`
+
+	// Azure Blob
+	AzureStorageAccount string `yaml:"azure_storage_account,omitempty"`
+	AzureStorageKey     string `yaml:"azure_storage_key,omitempty"`
+	AzureSASToken       string `yaml:"azure_sas_token,omitempty"`
+	AzureBlobEndpoint   string `yaml:"azure_blob_endpoint,omitempty"`
+
+	// DRS
+	DRSServer string `yaml:"drs_server,omitempty"`
+	DRSToken  string `yaml:"drs_token,omitempty"`
 }
 
 // DefaultConfigPath returns the default path for the config file.
@@ -128,6 +155,34 @@ func (c *FileConfig) GetValue(key string) (string, bool) {
 		return fmt.Sprintf("%v", *c.TelemetryEnabled), true
 	case "client_id":
 		return c.ClientID, c.ClientID != ""
+	case "quota_cpus":
+		return strconv.Itoa(c.QuotaCPUs), c.QuotaCPUs != 0
+	case "quota_memory_gb":
+		return strconv.FormatFloat(c.QuotaMemoryGB, 'f', -1, 64), c.QuotaMemoryGB != 0
+	case "quota_vms":
+		return strconv.Itoa(c.QuotaVMs), c.QuotaVMs != 0
+	case "storage_prices":
+		return c.StoragePrices, c.StoragePrices != ""
+	case "s3_endpoint":
+		return c.S3Endpoint, c.S3Endpoint != ""
+	case "s3_region":
+		return c.S3Region, c.S3Region != ""
+	case "s3_access_key_id":
+		return c.S3AccessKeyID, c.S3AccessKeyID != ""
+	case "s3_secret_access_key":
+		return c.S3SecretAccessKey, c.S3SecretAccessKey != ""
+	case "azure_storage_account":
+		return c.AzureStorageAccount, c.AzureStorageAccount != ""
+	case "azure_storage_key":
+		return c.AzureStorageKey, c.AzureStorageKey != ""
+	case "azure_sas_token":
+		return c.AzureSASToken, c.AzureSASToken != ""
+	case "azure_blob_endpoint":
+		return c.AzureBlobEndpoint, c.AzureBlobEndpoint != ""
+	case "drs_server":
+		return c.DRSServer, c.DRSServer != ""
+	case "drs_token":
+		return c.DRSToken, c.DRSToken != ""
 	default:
 		return "", false
 	}
@@ -162,6 +217,49 @@ func (c *FileConfig) SetValue(key, value string) error {
 	case "telemetry_enabled":
 		val := value == "true"
 		c.TelemetryEnabled = &val
+	case "quota_cpus":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid quota_cpus: %s (must be a whole number, 0 for no limit)", value)
+		}
+		c.QuotaCPUs = n
+	case "quota_memory_gb":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid quota_memory_gb: %s (must be a number of GB, 0 for no limit)", value)
+		}
+		c.QuotaMemoryGB = n
+	case "quota_vms":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid quota_vms: %s (must be a whole number, 0 for no limit)", value)
+		}
+		c.QuotaVMs = n
+	case "storage_prices":
+		if _, err := workflow.ParseStoragePrices(value); err != nil {
+			return err
+		}
+		c.StoragePrices = value
+	case "s3_endpoint":
+		c.S3Endpoint = value
+	case "s3_region":
+		c.S3Region = value
+	case "s3_access_key_id":
+		c.S3AccessKeyID = value
+	case "s3_secret_access_key":
+		c.S3SecretAccessKey = value
+	case "azure_storage_account":
+		c.AzureStorageAccount = value
+	case "azure_storage_key":
+		c.AzureStorageKey = value
+	case "azure_sas_token":
+		c.AzureSASToken = value
+	case "azure_blob_endpoint":
+		c.AzureBlobEndpoint = value
+	case "drs_server":
+		c.DRSServer = value
+	case "drs_token":
+		c.DRSToken = value
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
@@ -183,5 +281,19 @@ func AllKeys() []string {
 		"wdl_directory",
 		"telemetry_enabled",
 		"client_id",
+		"quota_cpus",
+		"quota_memory_gb",
+		"quota_vms",
+		"storage_prices",
+		"s3_endpoint",
+		"s3_region",
+		"s3_access_key_id",
+		"s3_secret_access_key",
+		"azure_storage_account",
+		"azure_storage_key",
+		"azure_sas_token",
+		"azure_blob_endpoint",
+		"drs_server",
+		"drs_token",
 	}
 }
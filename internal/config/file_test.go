@@ -151,6 +151,15 @@ func TestFileConfig_SetValue(t *testing.T) {
 		{"cromwell_host", "http://test:8000", false},
 		{"ollama_host", "http://ollama:11434", false},
 		{"telemetry_enabled", "true", false},
+		{"quota_cpus", "240", false},
+		{"quota_cpus", "lots", true}, // Not a number
+		{"quota_memory_gb", "960.5", false},
+		{"quota_vms", "-1", true}, // Negative
+		{"storage_prices", "gs=0.026,s3=0.0125", false},
+		{"storage_prices", "gs=free", true}, // Not a price
+		{"s3_endpoint", "http://localhost:9000", false},
+		{"azure_storage_account", "myaccount", false},
+		{"drs_server", "https://drs.example.org", false},
 		{"unknown_key", "value", true}, // Unknown key
 	}
 
@@ -167,6 +176,9 @@ func TestFileConfig_SetValue(t *testing.T) {
 	if cfg.CromwellHost != "http://test:8000" {
 		t.Errorf("expected cromwell_host=http://test:8000, got %s", cfg.CromwellHost)
 	}
+	if cfg.QuotaCPUs != 240 || cfg.QuotaMemoryGB != 960.5 {
+		t.Errorf("expected quota 240 CPUs / 960.5 GB, got %d / %v", cfg.QuotaCPUs, cfg.QuotaMemoryGB)
+	}
 }
 
 func TestAllKeys(t *testing.T) {
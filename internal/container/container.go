@@ -11,11 +11,13 @@ import (
 	"github.com/lmtani/pumbaa/internal/application/ports"
 	"github.com/lmtani/pumbaa/internal/application/workflow"
 	"github.com/lmtani/pumbaa/internal/config"
+	domainworkflow "github.com/lmtani/pumbaa/internal/domain/workflow"
 	"github.com/lmtani/pumbaa/internal/infrastructure/agents/llm"
 	"github.com/lmtani/pumbaa/internal/infrastructure/agents/tools"
 	wdltools "github.com/lmtani/pumbaa/internal/infrastructure/agents/tools/wdl"
 	"github.com/lmtani/pumbaa/internal/infrastructure/cloudlogging"
 	"github.com/lmtani/pumbaa/internal/infrastructure/cromwell"
+	"github.com/lmtani/pumbaa/internal/infrastructure/git"
 	"github.com/lmtani/pumbaa/internal/infrastructure/metrics"
 	"github.com/lmtani/pumbaa/internal/infrastructure/recommendation"
 	"github.com/lmtani/pumbaa/internal/infrastructure/session"
@@ -52,12 +54,22 @@ type Container struct {
 	AbortUseCase                 *workflow.AbortUseCase
 	QueryUseCase                 *workflow.QueryUseCase
 	OutputsUseCase               *workflow.OutputsUseCase
+	DownloadOutputsUseCase       *workflow.DownloadOutputsUseCase
+	VerifyOutputsUseCase         *workflow.VerifyOutputsUseCase
+	CleanupUseCase               *workflow.CleanupUseCase
+	StorageUsageUseCase          *workflow.StorageUsageUseCase
+	FollowLogsUseCase            *workflow.FollowLogsUseCase
 	InputsUseCase                *workflow.InputsUseCase
 	MonitoringUseCase            *workflow.MonitoringUseCase
 	ResourceReportUseCase        *workflow.ResourceReportUseCase
 	BatchLogsUseCase             *workflow.GetBatchLogsUseCase
 	BundleUseCase                *bundle.BundleUseCase
+	BundleVerifyUseCase          *bundle.VerifyUseCase
 	ResourceVisualizationUseCase *workflow.ResourceVisualizationUseCase
+	GraphUseCase                 *workflow.GraphUseCase
+	SchemaUseCase                *workflow.SchemaUseCase
+	WDLDocsUseCase               *workflow.WDLDocsUseCase
+	WDLDiffUseCase               *workflow.WDLDiffUseCase
 
 	// Handlers
 	SubmitHandler         *handler.SubmitHandler
@@ -69,6 +81,10 @@ type Container struct {
 	AbortHandler          *handler.AbortHandler
 	QueryHandler          *handler.QueryHandler
 	OutputsHandler        *handler.OutputsHandler
+	LogsHandler           *handler.LogsHandler
+	VerifyOutputsHandler  *handler.VerifyOutputsHandler
+	CleanupHandler        *handler.CleanupHandler
+	StorageReportHandler  *handler.StorageReportHandler
 	InputsHandler         *handler.InputsHandler
 	ResourceReportHandler *handler.ResourceReportHandler
 	BundleHandler         *handler.BundleHandler
@@ -77,6 +93,12 @@ type Container struct {
 	ChatHandler           *handler.ChatHandler
 	ConfigHandler         *handler.ConfigHandler
 	AnalyzeHandler        *handler.AnalyzeHandler
+	GraphHandler          *handler.GraphHandler
+	SchemaHandler         *handler.SchemaHandler
+	WDLIndexHandler       *handler.WDLIndexHandler
+	WDLSearchHandler      *handler.WDLSearchHandler
+	WDLDocsHandler        *handler.WDLDocsHandler
+	WDLDiffHandler        *handler.WDLDiffHandler
 }
 
 // New creates a new dependency injection container.
@@ -95,7 +117,7 @@ func New(cfg *config.Config, appVersion string) *Container {
 	})
 
 	// Initialize FileProvider for file system access
-	fileProvider := storage.NewFileProvider()
+	fileProvider := storage.NewFileProvider(storageConfig(cfg))
 	metricsWriter := metrics.NewTSVWriter()
 	fileSizeCache := storage.NewFileSizeCache()
 
@@ -117,19 +139,30 @@ func New(cfg *config.Config, appVersion string) *Container {
 
 	// Initialize use cases
 	c.PreflightUseCase = workflow.NewPreflightUseCase(fileProvider, c.CromwellClient)
+	c.PreflightUseCase.SetQuota(domainworkflow.ResourceQuota{CPUs: cfg.QuotaCPUs, MemoryGB: cfg.QuotaMemoryGB, VMs: cfg.QuotaVMs})
 	c.CacheForecastUseCase = workflow.NewCacheForecastUseCase(c.CromwellClient, c.CromwellClient, c.CromwellClient, fileProvider, presenter.NewProgress())
-	c.ScaffoldInputsUseCase = workflow.NewScaffoldInputsUseCase(fileProvider)
+	c.ScaffoldInputsUseCase = workflow.NewScaffoldInputsUseCase(fileProvider, fileProvider, c.CromwellClient)
 	c.SubmitUseCase = workflow.NewSubmitUseCase(c.CromwellClient, fileProvider, c.PreflightUseCase)
 	c.MetadataUseCase = workflow.NewMetadataUseCase(c.CromwellClient)
 	c.CompareUseCase = workflow.NewCompareUseCase(c.CromwellClient)
 	c.AbortUseCase = workflow.NewAbortUseCase(c.CromwellClient)
 	c.QueryUseCase = workflow.NewQueryUseCase(c.CromwellClient)
 	c.OutputsUseCase = workflow.NewOutputsUseCase(c.CromwellClient)
+	c.DownloadOutputsUseCase = workflow.NewDownloadOutputsUseCase(c.CromwellClient, fileProvider, presenter.NewProgress())
+	c.VerifyOutputsUseCase = workflow.NewVerifyOutputsUseCase(c.CromwellClient, c.CromwellClient, fileProvider, storage.NewOutputBaseline(), presenter.NewProgress())
+	c.CleanupUseCase = workflow.NewCleanupUseCase(c.CromwellClient, fileProvider, presenter.NewProgress())
+	c.StorageUsageUseCase = workflow.NewStorageUsageUseCase(c.CromwellClient, c.CromwellClient, fileProvider, storagePrices(cfg), presenter.NewProgress())
+	c.FollowLogsUseCase = workflow.NewFollowLogsUseCase(c.CromwellClient, fileProvider)
 	c.InputsUseCase = workflow.NewInputsUseCase(c.CromwellClient)
 	c.MonitoringUseCase = workflow.NewMonitoringUseCase(fileProvider)
 	c.ResourceReportUseCase = workflow.NewResourceReportUseCase(c.CromwellClient, fileProvider, metricsWriter, fileSizeCache)
 	c.BatchLogsUseCase = workflow.NewGetBatchLogsUseCase(c.CloudLoggingRepo)
 	c.BundleUseCase = bundle.New()
+	c.BundleVerifyUseCase = bundle.NewVerify(fileProvider, c.CromwellClient)
+	c.GraphUseCase = workflow.NewGraphUseCase(fileProvider, c.CromwellClient)
+	c.SchemaUseCase = workflow.NewSchemaUseCase(fileProvider)
+	c.WDLDocsUseCase = workflow.NewWDLDocsUseCase(fileProvider, templates.NewDocsRenderer())
+	c.WDLDiffUseCase = workflow.NewWDLDiffUseCase(fileProvider, git.NewRevisionReader())
 
 	// Initialize metrics reader for TSV files
 	metricsReader := metrics.NewTSVReader()
@@ -137,9 +170,9 @@ func New(cfg *config.Config, appVersion string) *Container {
 	// Initialize LLM-based recommendation generator if LLM is configured
 	// The container creates the tools and passes them to the generator
 	var wdlTools = tools.GetWDLOnlyTools(nil)
-	if cfg.WDLDirectory != "" {
+	if len(cfg.WDLRoots()) > 0 {
 		// Try to initialize WDL indexer for better recommendations
-		indexer, err := wdlindexer.NewIndexer(cfg.WDLDirectory, cfg.WDLIndexPath, false)
+		indexer, err := wdlindexer.NewIndexer(cfg.WDLRoots(), cfg.WDLIndexPath, false)
 		if err == nil {
 			wdlTools = tools.GetWDLOnlyTools(indexer)
 		}
@@ -159,15 +192,27 @@ func New(cfg *config.Config, appVersion string) *Container {
 	c.DiffHandler = handler.NewDiffHandler(c.CompareUseCase, c.Presenter)
 	c.AbortHandler = handler.NewAbortHandler(c.AbortUseCase, c.Presenter)
 	c.QueryHandler = handler.NewQueryHandler(c.QueryUseCase, c.Presenter)
-	c.OutputsHandler = handler.NewOutputsHandler(c.OutputsUseCase, c.Presenter)
+	c.OutputsHandler = handler.NewOutputsHandler(c.OutputsUseCase, c.DownloadOutputsUseCase, c.Presenter)
+	c.LogsHandler = handler.NewLogsHandler(c.FollowLogsUseCase, c.Presenter)
+	c.VerifyOutputsHandler = handler.NewVerifyOutputsHandler(c.VerifyOutputsUseCase, c.Presenter)
+	c.CleanupHandler = handler.NewCleanupHandler(c.CleanupUseCase, c.Presenter)
+	c.StorageReportHandler = handler.NewStorageReportHandler(c.StorageUsageUseCase, c.Presenter)
 	c.InputsHandler = handler.NewInputsHandler(c.InputsUseCase, c.Presenter)
 	c.ResourceReportHandler = handler.NewResourceReportHandler(c.ResourceReportUseCase, c.Presenter)
-	c.BundleHandler = handler.NewBundleHandler(c.BundleUseCase, c.Presenter)
+	c.BundleHandler = handler.NewBundleHandler(c.BundleUseCase, c.BundleVerifyUseCase, c.Presenter)
 	c.DebugHandler = handler.NewDebugHandler(c.CromwellClient, c.TelemetryService, c.MonitoringUseCase, fileProvider, c.BatchLogsUseCase, c.ChatDependencies)
-	c.DashboardHandler = handler.NewDashboardHandler(c.CromwellClient, c.TelemetryService, c.MonitoringUseCase, fileProvider, c.BatchLogsUseCase, c.CompareUseCase, version.NewGitHubChecker(githubRepo), appVersion, c.ChatDependencies)
+	// The dashboard measures storage in the background, so it reports no progress.
+	dashboardStorageUC := workflow.NewStorageUsageUseCase(c.CromwellClient, c.CromwellClient, fileProvider, storagePrices(cfg), nil)
+	c.DashboardHandler = handler.NewDashboardHandler(c.CromwellClient, c.TelemetryService, c.MonitoringUseCase, fileProvider, c.BatchLogsUseCase, c.CompareUseCase, dashboardStorageUC, version.NewGitHubChecker(githubRepo), appVersion, c.ChatDependencies)
 	c.ChatHandler = handler.NewChatHandler(c.Config, c.TelemetryService, c.ChatDependencies, c.SessionStore)
 	c.ConfigHandler = handler.NewConfigHandler()
 	c.AnalyzeHandler = handler.NewAnalyzeHandler(c.ResourceVisualizationUseCase, c.Presenter)
+	c.GraphHandler = handler.NewGraphHandler(c.GraphUseCase, c.Presenter)
+	c.SchemaHandler = handler.NewSchemaHandler(c.SchemaUseCase, c.Presenter)
+	c.WDLIndexHandler = handler.NewWDLIndexHandler(c.WDLIndex, c.Presenter)
+	c.WDLSearchHandler = handler.NewWDLSearchHandler(c.WDLIndex, c.Presenter)
+	c.WDLDocsHandler = handler.NewWDLDocsHandler(c.WDLIndex, c.WDLDocsUseCase, c.Presenter)
+	c.WDLDiffHandler = handler.NewWDLDiffHandler(c.WDLDiffUseCase, c.Presenter)
 
 	return c
 }
@@ -183,20 +228,41 @@ func (c *Container) SessionStore() (ports.ChatSessionStore, error) {
 	return svc, nil
 }
 
+// WDLIndex opens the WDL index over wdlDir, or over the configured WDL
+// directories when wdlDir is empty, bringing the cache at cfg.WDLIndexPath up
+// to date.
+func (c *Container) WDLIndex(wdlDir string, rebuild bool) (ports.WDLRepository, error) {
+	cfg := *c.Config
+	if wdlDir != "" {
+		cfg.WDLDirectory = wdlDir
+	}
+	roots := cfg.WDLRoots()
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no WDL directory configured: set PUMBAA_WDL_DIR or wdl_directory in the config file, or pass --wdl-dir")
+	}
+	return wdlindexer.NewIndexer(roots, c.Config.WDLIndexPath, rebuild)
+}
+
 // initWDLRepository builds the WDL index repository from config. Returns nil
 // (WDL actions disabled) when no directory is configured or indexing fails.
 // The index is cached at cfg.WDLIndexPath, so subsequent startups are instant.
 func (c *Container) initWDLRepository(forceRebuild bool) wdltools.Repository {
-	if c.Config.WDLDirectory == "" {
+	if len(c.Config.WDLRoots()) == 0 {
 		return nil
 	}
-	indexer, err := wdlindexer.NewIndexer(c.Config.WDLDirectory, c.Config.WDLIndexPath, forceRebuild)
+	indexer, err := wdlindexer.NewIndexer(c.Config.WDLRoots(), c.Config.WDLIndexPath, forceRebuild)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: WDL tools disabled - indexing failed: %v\n", err)
 		return nil
 	}
 	if idx, err := indexer.List(); err == nil {
-		fmt.Printf("WDL index: %d tasks, %d workflows\n", len(idx.Tasks), len(idx.Workflows))
+		fmt.Printf("WDL index: %d tasks, %d workflows, %d structs\n", len(idx.Tasks), len(idx.Workflows), len(idx.Structs))
+		if errs := idx.Errors(); len(errs) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d WDL file(s) could not be parsed (see 'pumbaa wdl index'):\n", len(errs))
+			for _, e := range errs {
+				fmt.Fprintf(os.Stderr, "  %s: %s\n", e.Path, e.Message)
+			}
+		}
 	}
 	return indexer
 }
@@ -224,7 +290,40 @@ func (c *Container) ChatDependencies(rebuildWDLIndex bool, extraTools ...tool.To
 		Repo:         c.CromwellClient,
 		Fetcher:      c.CromwellClient,
 		WDLRepo:      c.initWDLRepository(rebuildWDLIndex),
-		FileProvider: storage.NewFileProvider(),
+		FileProvider: storage.NewFileProvider(storageConfig(c.Config)),
 	}, extraTools...)
 	return &tui.ChatDependencies{LLM: llmModel, Tools: agentTools, SessionSvc: svc}, nil
 }
+
+// storageConfig maps the application configuration onto the storage backends'.
+func storageConfig(cfg *config.Config) storage.Config {
+	return storage.Config{
+		S3: storage.S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		},
+		Azure: storage.AzureConfig{
+			AccountName: cfg.AzureStorageAccount,
+			AccountKey:  cfg.AzureStorageKey,
+			SASToken:    cfg.AzureSASToken,
+			Endpoint:    cfg.AzureBlobEndpoint,
+		},
+		DRS: storage.DRSConfig{
+			Server: cfg.DRSServer,
+			Token:  cfg.DRSToken,
+		},
+	}
+}
+
+// storagePrices reads the storage price table of the storage report. The
+// config file is validated when written, so a bad table can only come from
+// the environment; it falls back to the default prices.
+func storagePrices(cfg *config.Config) domainworkflow.StoragePrices {
+	prices, err := domainworkflow.ParseStoragePrices(cfg.StoragePrices)
+	if err != nil {
+		return domainworkflow.DefaultStoragePrices()
+	}
+	return prices
+}
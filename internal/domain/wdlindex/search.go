@@ -0,0 +1,418 @@
+package wdlindex
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Kinds of definition a search hit can be.
+const (
+	KindTask     = "task"
+	KindWorkflow = "workflow"
+	KindStruct   = "struct"
+)
+
+// Searchable fields and how much a match in each counts: a match in a name
+// says more about what a definition does than one in its command.
+const (
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldDocker      = "docker"
+	FieldCommand     = "command"
+	FieldCalls       = "calls"
+	FieldMembers     = "members"
+)
+
+var fieldWeights = map[string]float64{
+	FieldName:        3,
+	FieldDescription: 2,
+	FieldDocker:      2,
+	FieldCalls:       1.5,
+	FieldMembers:     1.5,
+	FieldCommand:     1,
+}
+
+// BM25 parameters: k1 caps how much repeating a term helps, b how much long
+// documents are penalized.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+	// prefixFactor discounts a query term that only starts a document term
+	// ("samtool" in "samtools").
+	prefixFactor = 0.5
+	// minPrefix is the shortest query term matched as a prefix.
+	minPrefix = 3
+	// snippetWidth is the longest snippet, in runes, around the first match.
+	snippetWidth = 120
+)
+
+// SearchOptions narrows a search.
+type SearchOptions struct {
+	// Kind keeps only hits of one kind (KindTask, KindWorkflow, KindStruct);
+	// empty keeps all.
+	Kind string
+	// Limit caps the number of hits; 0 returns all.
+	Limit int
+}
+
+// SearchHit is one definition matching a query, best first.
+type SearchHit struct {
+	Kind     string
+	Name     string
+	Source   string
+	Score    float64
+	Snippets []Snippet
+}
+
+// Snippet is an excerpt of a field where query terms matched.
+type Snippet struct {
+	Field string
+	Text  string
+	// Highlights are the byte ranges of Text that matched, in order.
+	Highlights []Span
+}
+
+// Span is a byte range [Start, End).
+type Span struct {
+	Start, End int
+}
+
+// Marked returns the snippet text with each highlight wrapped in open and
+// close, e.g. "**" and "**" for Markdown.
+func (s Snippet) Marked(open, close string) string {
+	var b strings.Builder
+	last := 0
+	for _, h := range s.Highlights {
+		b.WriteString(s.Text[last:h.Start])
+		b.WriteString(open)
+		b.WriteString(s.Text[h.Start:h.End])
+		b.WriteString(close)
+		last = h.End
+	}
+	b.WriteString(s.Text[last:])
+	return b.String()
+}
+
+// SearchIndex ranks the definitions of an Index against free-text queries
+// with BM25 over their names, descriptions, docker images, commands, calls
+// and struct members. Build it once per Index with NewSearchIndex.
+type SearchIndex struct {
+	docs []*searchDoc
+	// df counts the documents containing each term.
+	df        map[string]int
+	avgLength float64
+}
+
+// searchDoc is one definition, tokenized.
+type searchDoc struct {
+	kind, name, source string
+	fields             map[string]string
+	// tf is the weighted frequency of each term across fields.
+	tf     map[string]float64
+	length float64
+}
+
+// NewSearchIndex tokenizes every task, workflow and struct of idx.
+func NewSearchIndex(idx *Index) *SearchIndex {
+	s := &SearchIndex{df: make(map[string]int)}
+	for _, t := range idx.Tasks {
+		s.add(KindTask, t.Name, t.Source, map[string]string{
+			FieldName:        t.Name,
+			FieldDescription: t.Description,
+			FieldDocker:      dockerImage(t.Runtime),
+			FieldCommand:     t.Command,
+		})
+	}
+	for _, wf := range idx.Workflows {
+		s.add(KindWorkflow, wf.Name, wf.Source, map[string]string{
+			FieldName:        wf.Name,
+			FieldDescription: wf.Description,
+			FieldCalls:       strings.Join(wf.Calls, " "),
+		})
+	}
+	for _, st := range idx.Structs {
+		members := make([]string, len(st.Members))
+		for i, m := range st.Members {
+			members[i] = m.Name
+		}
+		s.add(KindStruct, st.Name, st.Source, map[string]string{
+			FieldName:    st.Name,
+			FieldMembers: strings.Join(members, " "),
+		})
+	}
+
+	total := 0.0
+	for _, d := range s.docs {
+		total += d.length
+	}
+	if len(s.docs) > 0 {
+		s.avgLength = total / float64(len(s.docs))
+	}
+	return s
+}
+
+func (s *SearchIndex) add(kind, name, source string, fields map[string]string) {
+	d := &searchDoc{kind: kind, name: name, source: source, fields: fields, tf: make(map[string]float64)}
+	for field, text := range fields {
+		weight := fieldWeights[field]
+		for _, tok := range tokenize(text) {
+			d.tf[tok.term] += weight
+			d.length += weight
+		}
+	}
+	for term := range d.tf {
+		s.df[term]++
+	}
+	s.docs = append(s.docs, d)
+}
+
+// dockerImage is a task's container image, from either runtime key.
+func dockerImage(runtime map[string]string) string {
+	if image := runtime["docker"]; image != "" {
+		return image
+	}
+	return runtime["container"]
+}
+
+// Search returns the definitions matching query, best first. Every query term
+// counts; a definition needs to match at least one. Ties are broken by name.
+func (s *SearchIndex) Search(query string, opts SearchOptions) []SearchHit {
+	terms := queryTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	// Each query term matches document terms exactly, or as a prefix at a
+	// discount.
+	expansions := make(map[string]map[string]float64, len(terms))
+	for _, q := range terms {
+		matches := map[string]float64{q: 1}
+		if len(q) >= minPrefix {
+			for term := range s.df {
+				if term != q && strings.HasPrefix(term, q) {
+					matches[term] = prefixFactor
+				}
+			}
+		}
+		expansions[q] = matches
+	}
+
+	n := float64(len(s.docs))
+	var hits []SearchHit
+	for _, d := range s.docs {
+		if opts.Kind != "" && d.kind != opts.Kind {
+			continue
+		}
+		score := 0.0
+		matched := make(map[string]bool)
+		for _, q := range terms {
+			for term, factor := range expansions[q] {
+				tf := d.tf[term]
+				if tf == 0 {
+					continue
+				}
+				matched[term] = true
+				df := float64(s.df[term])
+				idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+				norm := 1 - bm25B + bm25B*d.length/s.avgLength
+				score += factor * idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		hits = append(hits, SearchHit{
+			Kind:     d.kind,
+			Name:     d.name,
+			Source:   d.source,
+			Score:    score,
+			Snippets: d.snippets(matched),
+		})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Name != hits[j].Name {
+			return hits[i].Name < hits[j].Name
+		}
+		return hits[i].Source < hits[j].Source
+	})
+	if opts.Limit > 0 && len(hits) > opts.Limit {
+		hits = hits[:opts.Limit]
+	}
+	return hits
+}
+
+// snippetOrder is the order snippets are listed in, most telling first.
+var snippetOrder = []string{FieldName, FieldDescription, FieldDocker, FieldCalls, FieldMembers, FieldCommand}
+
+// snippets excerpts each field where a matched term occurs.
+func (d *searchDoc) snippets(matched map[string]bool) []Snippet {
+	var out []Snippet
+	for _, field := range snippetOrder {
+		text, ok := d.fields[field]
+		if !ok || text == "" {
+			continue
+		}
+		if snip, ok := excerpt(field, text, matched); ok {
+			out = append(out, snip)
+		}
+	}
+	return out
+}
+
+// excerpt cuts the line of text holding the first matched term, trimmed to
+// snippetWidth around it, and highlights every matched term in it.
+func excerpt(field, text string, matched map[string]bool) (Snippet, bool) {
+	for _, line := range strings.Split(text, "\n") {
+		var spans []Span
+		for _, tok := range tokenize(line) {
+			if matched[tok.term] {
+				spans = append(spans, Span{tok.start, tok.end})
+			}
+		}
+		if len(spans) == 0 {
+			continue
+		}
+		spans = mergeSpans(spans)
+
+		start, end := 0, len(line)
+		if len([]rune(line)) > snippetWidth {
+			start = clampToRune(line, spans[0].Start-snippetWidth/3)
+			end = clampToRune(line, start+snippetWidth)
+		}
+		// Drop the indentation of command lines.
+		for start < end && (line[start] == ' ' || line[start] == '\t') {
+			start++
+		}
+
+		snip := Snippet{Field: field}
+		prefix, suffix := "", ""
+		if start > 0 && strings.TrimSpace(line[:start]) != "" {
+			prefix = "…"
+		}
+		if end < len(line) {
+			suffix = "…"
+		}
+		snip.Text = prefix + line[start:end] + suffix
+		for _, sp := range spans {
+			if sp.Start < start || sp.End > end {
+				continue
+			}
+			shift := len(prefix) - start
+			snip.Highlights = append(snip.Highlights, Span{sp.Start + shift, sp.End + shift})
+		}
+		return snip, true
+	}
+	return Snippet{}, false
+}
+
+// mergeSpans joins overlapping spans, such as a word and its camelCase parts.
+func mergeSpans(spans []Span) []Span {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	merged := spans[:1]
+	for _, sp := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if sp.Start < last.End {
+			if sp.End > last.End {
+				last.End = sp.End
+			}
+			continue
+		}
+		merged = append(merged, sp)
+	}
+	return merged
+}
+
+// clampToRune keeps a byte offset inside s and on a rune boundary.
+func clampToRune(s string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	if i >= len(s) {
+		return len(s)
+	}
+	for i > 0 && !isRuneStart(s[i]) {
+		i--
+	}
+	return i
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// token is a term and where it was found.
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into lowercase terms: runs of letters and digits, and
+// also the parts of a camelCase run, so "BwaMem" is "bwamem", "bwa" and
+// "mem". snake_case, paths and image tags split on their punctuation.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := text[start:end]
+		tokens = append(tokens, token{strings.ToLower(word), start, end})
+		if parts := camelParts(word); len(parts) > 1 {
+			offset := start
+			for _, p := range parts {
+				tokens = append(tokens, token{strings.ToLower(p), offset, offset + len(p)})
+				offset += len(p)
+			}
+		}
+		start = -1
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(text))
+	return tokens
+}
+
+// camelParts splits "HaplotypeCaller" into "Haplotype" and "Caller", and
+// "GATKHaplotype" into "GATK" and "Haplotype".
+func camelParts(word string) []string {
+	runes := []rune(word)
+	var parts []string
+	last := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		boundary := unicode.IsLower(prev) && unicode.IsUpper(cur) ||
+			unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if boundary {
+			parts = append(parts, string(runes[last:i]))
+			last = i
+		}
+	}
+	return append(parts, string(runes[last:]))
+}
+
+// queryTerms are the distinct terms of a query.
+func queryTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, tok := range tokenize(query) {
+		if !seen[tok.term] {
+			seen[tok.term] = true
+			terms = append(terms, tok.term)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,131 @@
+package wdlindex
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func searchFixture() *Index {
+	idx := NewIndex("/w")
+	idx.Files["/w/align.wdl"] = &FileEntry{
+		Path: "/w/align.wdl",
+		Tasks: []*IndexedTask{
+			{
+				Name:        "BwaMem",
+				Source:      "/w/align.wdl",
+				Description: "Align reads to a reference with bwa mem",
+				Command:     "set -e\n    bwa mem -t 8 ref.fa reads.fq | samtools sort -o out.bam",
+				Runtime:     map[string]string{"docker": "quay.io/biocontainers/bwa:0.7.17"},
+			},
+			{
+				Name:    "SortBam",
+				Source:  "/w/align.wdl",
+				Command: "samtools sort -o sorted.bam in.bam",
+				Runtime: map[string]string{"docker": "quay.io/biocontainers/samtools:1.17"},
+			},
+		},
+		Workflow: &IndexedWorkflow{Name: "Alignment", Source: "/w/align.wdl", Calls: []string{"BwaMem", "SortBam"}},
+		Structs:  []*IndexedStruct{{Name: "ReadGroup", Source: "/w/align.wdl", Members: []Declaration{{Name: "sample"}, {Name: "library"}}}},
+	}
+	idx.Files["/w/qc.wdl"] = &FileEntry{
+		Path: "/w/qc.wdl",
+		Tasks: []*IndexedTask{
+			{Name: "FastQC", Source: "/w/qc.wdl", Command: "fastqc reads.fq", Description: "Quality report of the reads"},
+		},
+	}
+	idx.Link()
+	return idx
+}
+
+func TestSearchRanking(t *testing.T) {
+	s := NewSearchIndex(searchFixture())
+
+	tests := []struct {
+		name  string
+		query string
+		opts  SearchOptions
+		want  []string
+	}{
+		{"name match ranks first", "samtools sort", SearchOptions{Kind: KindTask}, []string{"SortBam", "BwaMem"}},
+		{"camelCase parts are terms", "bwa", SearchOptions{Kind: KindTask}, []string{"BwaMem"}},
+		{"docker image", "biocontainers samtools", SearchOptions{Limit: 1}, []string{"SortBam"}},
+		{"prefix match", "fastq", SearchOptions{}, []string{"FastQC"}},
+		{"workflow calls", "bwamem", SearchOptions{Kind: KindWorkflow}, []string{"Alignment"}},
+		{"struct members", "library", SearchOptions{}, []string{"ReadGroup"}},
+		{"no match", "haplotypecaller", SearchOptions{}, nil},
+		{"case-insensitive", "FASTQC", SearchOptions{}, []string{"FastQC"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, h := range s.Search(tt.query, tt.opts) {
+				got = append(got, h.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchSnippets(t *testing.T) {
+	hits := NewSearchIndex(searchFixture()).Search("samtools", SearchOptions{Kind: KindTask})
+	if len(hits) == 0 {
+		t.Fatal("no hits")
+	}
+
+	var fields []string
+	marked := make(map[string]string)
+	for _, sn := range hits[0].Snippets {
+		fields = append(fields, sn.Field)
+		marked[sn.Field] = sn.Marked("[", "]")
+	}
+	if !reflect.DeepEqual(fields, []string{FieldDocker, FieldCommand}) {
+		t.Fatalf("snippet fields = %v", fields)
+	}
+	if want := "quay.io/biocontainers/[samtools]:1.17"; marked[FieldDocker] != want {
+		t.Errorf("docker snippet = %q, want %q", marked[FieldDocker], want)
+	}
+	if want := "[samtools] sort -o sorted.bam in.bam"; marked[FieldCommand] != want {
+		t.Errorf("command snippet = %q, want %q", marked[FieldCommand], want)
+	}
+
+	// The command line of BwaMem is found past its first line, unindented.
+	for _, h := range hits {
+		if h.Name != "BwaMem" {
+			continue
+		}
+		got := h.Snippets[len(h.Snippets)-1].Marked("[", "]")
+		if !strings.HasPrefix(got, "bwa mem") || !strings.Contains(got, "[samtools] sort") {
+			t.Errorf("BwaMem command snippet = %q", got)
+		}
+	}
+}
+
+func TestSearchSnippetWindow(t *testing.T) {
+	long := strings.Repeat("filler ", 40) + "needle " + strings.Repeat("tail ", 40)
+	idx := NewIndex()
+	idx.Files["/a.wdl"] = &FileEntry{Path: "/a.wdl", Tasks: []*IndexedTask{{Name: "T", Command: long}}}
+	idx.Link()
+
+	hits := NewSearchIndex(idx).Search("needle", SearchOptions{})
+	if len(hits) != 1 || len(hits[0].Snippets) != 1 {
+		t.Fatalf("hits = %+v", hits)
+	}
+	got := hits[0].Snippets[0].Marked("[", "]")
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") || !strings.Contains(got, "[needle]") {
+		t.Errorf("snippet = %q, want a window around the match", got)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	var got []string
+	for _, tok := range tokenize("GATKHaplotypeCaller run_qc.py") {
+		got = append(got, tok.term)
+	}
+	want := []string{"gatkhaplotypecaller", "gatk", "haplotype", "caller", "run", "qc", "py"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenize() = %v, want %v", got, want)
+	}
+}
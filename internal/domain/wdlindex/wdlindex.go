@@ -1,36 +1,104 @@
 // Package wdlindex provides domain models for WDL workflow indexing.
 package wdlindex
 
-import "time"
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IndexVersion is the current cache format. A cache written in another
+// format is discarded and rebuilt.
+const IndexVersion = 3
 
 // Index holds the complete WDL index (also used for JSON serialization).
+//
+// Files is what is persisted: one entry per WDL file, with what it defines.
+// Tasks, Workflows and Structs are lookups by name derived from it by Link;
+// when several files define the same name, the first file in path order wins.
 type Index struct {
-	Version   int                         `json:"version"`
-	Directory string                      `json:"directory"`
-	IndexedAt time.Time                   `json:"indexed_at"`
-	Tasks     map[string]*IndexedTask     `json:"tasks"`
-	Workflows map[string]*IndexedWorkflow `json:"workflows"`
+	Version   int                   `json:"version"`
+	Roots     []string              `json:"roots"`
+	IndexedAt time.Time             `json:"indexed_at"`
+	Files     map[string]*FileEntry `json:"files"`
+
+	Tasks     map[string]*IndexedTask     `json:"-"`
+	Workflows map[string]*IndexedWorkflow `json:"-"`
+	Structs   map[string]*IndexedStruct   `json:"-"`
+}
+
+// FileEntry is one indexed WDL file. ModTime, Size and SHA256 tell whether the
+// file changed since it was parsed, so unchanged files are not parsed again.
+type FileEntry struct {
+	Path string `json:"path"`
+	// Root is the indexed directory the file was found under; empty for a
+	// file outside every root, indexed because a WDL imports it.
+	Root    string      `json:"root,omitempty"`
+	ModTime time.Time   `json:"mod_time"`
+	Size    int64       `json:"size"`
+	SHA256  string      `json:"sha256"`
+	Imports []ImportRef `json:"imports,omitempty"`
+	// Error is why the file could not be parsed; it then defines nothing.
+	Error string `json:"error,omitempty"`
+
+	Tasks    []*IndexedTask   `json:"tasks,omitempty"`
+	Workflow *IndexedWorkflow `json:"workflow,omitempty"`
+	Structs  []*IndexedStruct `json:"structs,omitempty"`
+}
+
+// ImportRef is an import statement of an indexed file.
+type ImportRef struct {
+	URI   string `json:"uri"`
+	Alias string `json:"alias,omitempty"`
+	// Path is the local file the URI resolves to; empty for a remote import
+	// or one whose file does not exist.
+	Path string `json:"path,omitempty"`
+}
+
+// Namespace is the name calls use for the import: its alias, or else the file
+// name without its .wdl extension.
+func (r ImportRef) Namespace() string {
+	if r.Alias != "" {
+		return r.Alias
+	}
+	return strings.TrimSuffix(path.Base(r.URI), ".wdl")
 }
 
 // IndexedTask represents a task in the index.
 type IndexedTask struct {
-	Name        string            `json:"name"`
-	Source      string            `json:"source"`
-	Inputs      []Declaration     `json:"inputs"`
-	Outputs     []Declaration     `json:"outputs"`
-	Command     string            `json:"command"`
+	Name    string        `json:"name"`
+	Source  string        `json:"source"`
+	Inputs  []Declaration `json:"inputs"`
+	Outputs []Declaration `json:"outputs"`
+	Command string        `json:"command"`
+	// Runtime holds each attribute as written: a literal's value, or the
+	// expression text ("docker_image", "ceil(size(bam, "GB")) + 10").
 	Runtime     map[string]string `json:"runtime,omitempty"`
 	Description string            `json:"description,omitempty"`
+	// Meta is the meta section; values that are not strings are JSON.
+	Meta map[string]string `json:"meta,omitempty"`
 }
 
 // IndexedWorkflow represents a workflow in the index.
 type IndexedWorkflow struct {
-	Name        string        `json:"name"`
-	Source      string        `json:"source"`
-	Inputs      []Declaration `json:"inputs"`
-	Outputs     []Declaration `json:"outputs"`
-	Calls       []string      `json:"calls"`
-	Description string        `json:"description,omitempty"`
+	Name    string        `json:"name"`
+	Source  string        `json:"source"`
+	Inputs  []Declaration `json:"inputs"`
+	Outputs []Declaration `json:"outputs"`
+	// Calls are the call targets ("lib.Align"): top-level calls first, then
+	// those inside scatter and conditional blocks.
+	Calls       []string `json:"calls"`
+	Description string   `json:"description,omitempty"`
+	// Meta is the meta section; values that are not strings are JSON.
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// IndexedStruct represents a struct definition in the index.
+type IndexedStruct struct {
+	Name    string        `json:"name"`
+	Source  string        `json:"source"`
+	Members []Declaration `json:"members"`
 }
 
 // Declaration represents a WDL input/output declaration.
@@ -38,15 +106,128 @@ type Declaration struct {
 	Name     string `json:"name"`
 	Type     string `json:"type"`
 	Optional bool   `json:"optional"`
+	// Default is the default expression as written; empty when there is none.
+	Default string `json:"default,omitempty"`
+	// Description comes from parameter_meta.
+	Description string `json:"description,omitempty"`
+}
+
+// Required reports whether an input must be set: it is neither optional nor
+// defaulted.
+func (d Declaration) Required() bool {
+	return !d.Optional && d.Default == ""
+}
+
+// FileError is a WDL file that could not be parsed.
+type FileError struct {
+	Path    string
+	Message string
 }
 
-// NewIndex creates a new empty Index.
-func NewIndex(directory string) *Index {
+// UpdateStats says how much work refreshing the index took.
+type UpdateStats struct {
+	// Parsed counts the new and changed files parsed.
+	Parsed int
+	// Reused counts the unchanged files taken from the cache.
+	Reused int
+	// Removed counts the files dropped because they are gone.
+	Removed int
+}
+
+// NewIndex creates a new empty Index over the given root directories.
+func NewIndex(roots ...string) *Index {
 	return &Index{
-		Version:   1,
-		Directory: directory,
+		Version:   IndexVersion,
+		Roots:     roots,
 		IndexedAt: time.Now(),
+		Files:     make(map[string]*FileEntry),
 		Tasks:     make(map[string]*IndexedTask),
 		Workflows: make(map[string]*IndexedWorkflow),
+		Structs:   make(map[string]*IndexedStruct),
+	}
+}
+
+// Link rebuilds the name lookups from Files. Call it after changing Files.
+func (idx *Index) Link() {
+	idx.Tasks = make(map[string]*IndexedTask)
+	idx.Workflows = make(map[string]*IndexedWorkflow)
+	idx.Structs = make(map[string]*IndexedStruct)
+	for _, path := range idx.paths() {
+		entry := idx.Files[path]
+		for _, t := range entry.Tasks {
+			if _, exists := idx.Tasks[t.Name]; !exists {
+				idx.Tasks[t.Name] = t
+			}
+		}
+		if wf := entry.Workflow; wf != nil {
+			if _, exists := idx.Workflows[wf.Name]; !exists {
+				idx.Workflows[wf.Name] = wf
+			}
+		}
+		for _, s := range entry.Structs {
+			if _, exists := idx.Structs[s.Name]; !exists {
+				idx.Structs[s.Name] = s
+			}
+		}
+	}
+}
+
+// ResolveCall finds the definition a call target names, from the file the
+// call is in: "Align" is a task of that file, "lib.Align" a task or workflow
+// of the file imported as lib. A workflow target is the imported file's
+// workflow. Both results are nil when the target cannot be resolved, such as
+// a remote import.
+func (idx *Index) ResolveCall(source, target string) (*IndexedTask, *IndexedWorkflow) {
+	entry := idx.Files[source]
+	if entry == nil {
+		return nil, nil
+	}
+	namespace, name, qualified := strings.Cut(target, ".")
+	if !qualified {
+		for _, t := range entry.Tasks {
+			if t.Name == target {
+				return t, nil
+			}
+		}
+		return nil, nil
+	}
+	for _, imp := range entry.Imports {
+		if imp.Namespace() != namespace || imp.Path == "" {
+			continue
+		}
+		imported := idx.Files[imp.Path]
+		if imported == nil {
+			return nil, nil
+		}
+		for _, t := range imported.Tasks {
+			if t.Name == name {
+				return t, nil
+			}
+		}
+		if wf := imported.Workflow; wf != nil && wf.Name == name {
+			return nil, wf
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
+
+// Errors lists the files that could not be parsed, by path.
+func (idx *Index) Errors() []FileError {
+	var errs []FileError
+	for _, path := range idx.paths() {
+		if msg := idx.Files[path].Error; msg != "" {
+			errs = append(errs, FileError{Path: path, Message: msg})
+		}
+	}
+	return errs
+}
+
+func (idx *Index) paths() []string {
+	paths := make([]string, 0, len(idx.Files))
+	for path := range idx.Files {
+		paths = append(paths, path)
 	}
+	sort.Strings(paths)
+	return paths
 }
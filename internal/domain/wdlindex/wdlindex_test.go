@@ -1,19 +1,24 @@
 package wdlindex
 
 import (
+	"reflect"
 	"testing"
 )
 
 func TestNewIndex(t *testing.T) {
-	dir := "/tmp/test"
-	idx := NewIndex(dir)
+	roots := []string{"/tmp/tasks", "/tmp/pipelines"}
+	idx := NewIndex(roots...)
 
-	if idx.Directory != dir {
-		t.Errorf("got %s, want %s", idx.Directory, dir)
+	if !reflect.DeepEqual(idx.Roots, roots) {
+		t.Errorf("got %v, want %v", idx.Roots, roots)
 	}
 
-	if idx.Version != 1 {
-		t.Errorf("got %d, want 1", idx.Version)
+	if idx.Version != IndexVersion {
+		t.Errorf("got %d, want %d", idx.Version, IndexVersion)
+	}
+
+	if idx.Files == nil {
+		t.Error("Files map should be initialized")
 	}
 
 	if idx.Tasks == nil {
@@ -28,3 +33,31 @@ func TestNewIndex(t *testing.T) {
 		t.Error("IndexedAt should be set")
 	}
 }
+
+func TestIndexLink(t *testing.T) {
+	idx := NewIndex("/w")
+	idx.Files["/w/b.wdl"] = &FileEntry{
+		Path:     "/w/b.wdl",
+		Tasks:    []*IndexedTask{{Name: "Align", Source: "/w/b.wdl"}},
+		Workflow: &IndexedWorkflow{Name: "Main", Source: "/w/b.wdl"},
+		Structs:  []*IndexedStruct{{Name: "Sample", Source: "/w/b.wdl"}},
+	}
+	idx.Files["/w/a.wdl"] = &FileEntry{
+		Path:  "/w/a.wdl",
+		Tasks: []*IndexedTask{{Name: "Align", Source: "/w/a.wdl"}},
+	}
+	idx.Files["/w/broken.wdl"] = &FileEntry{Path: "/w/broken.wdl", Error: "syntax error at 3:1"}
+
+	idx.Link()
+
+	if got := idx.Tasks["Align"].Source; got != "/w/a.wdl" {
+		t.Errorf("Align comes from %s, want the first file in path order", got)
+	}
+	if idx.Workflows["Main"] == nil || idx.Structs["Sample"] == nil {
+		t.Errorf("workflow or struct missing from the lookups: %v %v", idx.Workflows, idx.Structs)
+	}
+	want := []FileError{{Path: "/w/broken.wdl", Message: "syntax error at 3:1"}}
+	if got := idx.Errors(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Errors() = %v, want %v", got, want)
+	}
+}
@@ -115,6 +115,8 @@ type CacheForecast struct {
 	// forecast with warnings is still shown — Cromwell is the authority and
 	// the user is told what was assumed.
 	Warnings []string
+	// Inputs is the inputs JSON of the pending submission, as forecast.
+	Inputs []byte
 }
 
 // Counts tallies the forecast by fate, for the headline summary.
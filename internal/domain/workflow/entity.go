@@ -51,6 +51,7 @@ type Workflow struct {
 	SubmittedWorkflow       string
 	SubmittedInputs         string
 	SubmittedOptions        string
+	SubmittedImports        map[string]string // Dependency path in the zip -> content
 	WorkflowLanguage        string
 	WorkflowLanguageVersion string
 }
@@ -173,6 +174,20 @@ func (w *Workflow) IsTerminal() bool {
 	}
 }
 
+// IsTerminal returns true if the call attempt has finished. Call statuses are
+// Cromwell's execution statuses, which differ from workflow statuses: a
+// finished call is "Done", and an attempt that will be retried ends as
+// "RetryableFailure" or "Preempted".
+func (c Call) IsTerminal() bool {
+	switch string(c.Status) {
+	case "Done", "Bypassed", "RetryableFailure", "Preempted",
+		string(StatusSucceeded), string(StatusFailed), string(StatusAborted):
+		return true
+	default:
+		return false
+	}
+}
+
 // Duration returns the duration of the workflow execution.
 func (w *Workflow) Duration() time.Duration {
 	if w.Start.IsZero() {
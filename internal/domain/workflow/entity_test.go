@@ -31,6 +31,31 @@ func TestWorkflow_IsTerminal(t *testing.T) {
 	}
 }
 
+func TestCall_IsTerminal(t *testing.T) {
+	tests := []struct {
+		status Status
+		want   bool
+	}{
+		{"Done", true},
+		{StatusFailed, true},
+		{StatusAborted, true},
+		{"RetryableFailure", true},
+		{"Preempted", true},
+		{StatusRunning, false},
+		{"QueuedInCromwell", false},
+		{"Starting", false},
+		{"WaitingForReturnCode", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := (Call{Status: tt.status}).IsTerminal(); got != tt.want {
+				t.Errorf("Call.IsTerminal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestWorkflow_Duration(t *testing.T) {
 	now := time.Now()
 	oneHourAgo := now.Add(-1 * time.Hour)
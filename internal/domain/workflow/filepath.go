@@ -6,7 +6,8 @@ import (
 )
 
 // FilePath is a Value Object representing a file path.
-// It encapsulates validation and categorization of file paths (GCS, S3, local).
+// It encapsulates validation and categorization of file paths (GCS, S3, HTTP,
+// DRS, local).
 type FilePath string
 
 // IsGCS returns true if the path is a Google Cloud Storage path.
@@ -19,6 +20,18 @@ func (p FilePath) IsS3() bool {
 	return strings.HasPrefix(string(p), "s3://")
 }
 
+// IsHTTP returns true if the path is an http:// or https:// URL, which
+// includes Azure Blob URLs.
+func (p FilePath) IsHTTP() bool {
+	s := string(p)
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// IsDRS returns true if the path is a GA4GH DRS URI.
+func (p FilePath) IsDRS() bool {
+	return strings.HasPrefix(string(p), "drs://")
+}
+
 // IsLocal returns true if the path is a local absolute path with a file extension.
 func (p FilePath) IsLocal() bool {
 	s := string(p)
@@ -27,7 +40,7 @@ func (p FilePath) IsLocal() bool {
 
 // IsValid returns true if the path is a recognized file path format.
 func (p FilePath) IsValid() bool {
-	return p.IsGCS() || p.IsS3() || p.IsLocal()
+	return p.IsGCS() || p.IsS3() || p.IsHTTP() || p.IsDRS() || p.IsLocal()
 }
 
 // String returns the original path string.
@@ -71,6 +71,9 @@ func TestFilePath_IsValid(t *testing.T) {
 	}{
 		{"gs://bucket/file.txt", true},
 		{"s3://bucket/file.txt", true},
+		{"https://account.blob.core.windows.net/inputs/file.txt", true},
+		{"http://example.com/ref.fa", true},
+		{"drs://dg.4503:4b9a0c1e", true},
 		{"/path/to/file.txt", true},
 		{"sample_name", false},
 		{"echo hello", false},
@@ -0,0 +1,175 @@
+// footprint.go predicts how much compute a run will hold at once, before it
+// starts, from what each call requests and which calls wait on which. Cloud
+// quotas are regional and counted concurrently, so the peak matters more than
+// the total: a run that fits in total can still die at its widest scatter.
+package workflow
+
+import "sort"
+
+// Engine defaults for a call whose runtime does not say, or says something
+// that cannot be known before the run. They match Cromwell's defaults on the
+// Google backends.
+const (
+	DefaultCallCPU           = 1
+	DefaultCallMemory Memory = "2 GB"
+)
+
+// CallDemand is what one call asks for, per instance.
+type CallDemand struct {
+	Name string
+	// DependsOn lists the calls whose outputs this one consumes. Names that
+	// are not in the set being predicted are ignored.
+	DependsOn []string
+	// Instances is how many times the call runs; 0 means unknown.
+	Instances int
+	// CPU and Memory are per instance. Zero values fall back to the engine
+	// defaults.
+	CPU    int
+	Memory Memory
+}
+
+// Footprint is a Value Object with the predicted compute of a run.
+//
+// The peak is estimated by grouping calls into stages — a call's stage is one
+// past the latest stage it depends on — and assuming every instance in a
+// stage runs at once. That is what the engine does when the quota allows it;
+// it overstates the peak only when one stage's calls finish well before
+// another's start.
+type Footprint struct {
+	TotalVMs      int
+	TotalCPUs     int
+	TotalMemoryGB float64
+
+	PeakVMs      int
+	PeakCPUs     int
+	PeakMemoryGB float64
+	// PeakCalls are the calls in the stage that makes up the peak, sorted.
+	PeakCalls []string
+
+	// UnknownInstances lists calls whose instance count is only known once
+	// the run is under way. They are left out of every figure, so the
+	// prediction is a lower bound when it is not empty.
+	UnknownInstances []string
+	// AssumedDefaults lists calls whose CPU or memory fell back to the engine
+	// defaults.
+	AssumedDefaults []string
+}
+
+// PredictFootprint works out the total and peak compute of a set of calls.
+func PredictFootprint(calls []CallDemand) Footprint {
+	var f Footprint
+	byName := make(map[string]CallDemand, len(calls))
+	for _, c := range calls {
+		byName[c.Name] = c
+	}
+
+	// stage is memoised; visiting guards against a dependency cycle, which a
+	// valid workflow never has but a partially-resolved graph might.
+	stages := make(map[string]int, len(calls))
+	visiting := make(map[string]bool)
+	var stage func(name string) int
+	stage = func(name string) int {
+		if s, ok := stages[name]; ok {
+			return s
+		}
+		if visiting[name] {
+			return 0
+		}
+		visiting[name] = true
+		s := 0
+		for _, dep := range byName[name].DependsOn {
+			if _, ok := byName[dep]; ok {
+				s = max(s, stage(dep)+1)
+			}
+		}
+		visiting[name] = false
+		stages[name] = s
+		return s
+	}
+
+	type load struct {
+		vms   int
+		cpus  int
+		memGB float64
+		calls []string
+	}
+	loads := make(map[int]*load)
+	for _, c := range calls {
+		if c.Instances <= 0 {
+			f.UnknownInstances = append(f.UnknownInstances, c.Name)
+			continue
+		}
+		cpu, mem := c.CPU, c.Memory.ToGB()
+		if cpu <= 0 || mem <= 0 {
+			f.AssumedDefaults = append(f.AssumedDefaults, c.Name)
+		}
+		if cpu <= 0 {
+			cpu = DefaultCallCPU
+		}
+		if mem <= 0 {
+			mem = DefaultCallMemory.ToGB()
+		}
+
+		f.TotalVMs += c.Instances
+		f.TotalCPUs += c.Instances * cpu
+		f.TotalMemoryGB += float64(c.Instances) * mem
+
+		s := stage(c.Name)
+		if loads[s] == nil {
+			loads[s] = &load{}
+		}
+		l := loads[s]
+		l.vms += c.Instances
+		l.cpus += c.Instances * cpu
+		l.memGB += float64(c.Instances) * mem
+		l.calls = append(l.calls, c.Name)
+	}
+
+	for _, l := range loads {
+		// CPU quota is the one that runs out first, so it decides the peak;
+		// VMs break a tie.
+		if l.cpus > f.PeakCPUs || (l.cpus == f.PeakCPUs && l.vms > f.PeakVMs) {
+			f.PeakVMs, f.PeakCPUs, f.PeakMemoryGB = l.vms, l.cpus, l.memGB
+			f.PeakCalls = l.calls
+		}
+	}
+	sort.Strings(f.PeakCalls)
+	sort.Strings(f.UnknownInstances)
+	sort.Strings(f.AssumedDefaults)
+	return f
+}
+
+// ResourceQuota is the compute a run may hold at once. A zero field means no
+// limit on that resource.
+type ResourceQuota struct {
+	CPUs     int
+	MemoryGB float64
+	VMs      int
+}
+
+// IsSet reports whether any limit is configured.
+func (q ResourceQuota) IsSet() bool {
+	return q.CPUs > 0 || q.MemoryGB > 0 || q.VMs > 0
+}
+
+// QuotaBreach is one resource whose predicted peak exceeds its quota.
+type QuotaBreach struct {
+	Resource string // "CPUs", "memory (GB)" or "VMs"
+	Peak     float64
+	Limit    float64
+}
+
+// Exceeds returns the resources whose peak goes over the quota.
+func (f Footprint) Exceeds(q ResourceQuota) []QuotaBreach {
+	var out []QuotaBreach
+	if q.CPUs > 0 && f.PeakCPUs > q.CPUs {
+		out = append(out, QuotaBreach{Resource: "CPUs", Peak: float64(f.PeakCPUs), Limit: float64(q.CPUs)})
+	}
+	if q.MemoryGB > 0 && f.PeakMemoryGB > q.MemoryGB {
+		out = append(out, QuotaBreach{Resource: "memory (GB)", Peak: f.PeakMemoryGB, Limit: q.MemoryGB})
+	}
+	if q.VMs > 0 && f.PeakVMs > q.VMs {
+		out = append(out, QuotaBreach{Resource: "VMs", Peak: float64(f.PeakVMs), Limit: float64(q.VMs)})
+	}
+	return out
+}
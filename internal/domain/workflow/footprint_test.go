@@ -0,0 +1,72 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPredictFootprint(t *testing.T) {
+	// Prepare feeds a 40-way scatter; Merge waits for every shard. The
+	// scatter's stage is the peak even though Prepare asks for more per VM.
+	calls := []CallDemand{
+		{Name: "Prepare", Instances: 1, CPU: 16, Memory: "64 GB"},
+		{Name: "Align", DependsOn: []string{"Prepare"}, Instances: 40, CPU: 8, Memory: "32 GB"},
+		{Name: "QC", DependsOn: []string{"Prepare"}, Instances: 1, Memory: "4 GB"},
+		{Name: "Merge", DependsOn: []string{"Align", "QC"}, Instances: 1, CPU: 4, Memory: "16 GB"},
+		{Name: "Gather", DependsOn: []string{"Missing"}, Instances: 0, CPU: 2},
+	}
+
+	f := PredictFootprint(calls)
+
+	if f.PeakVMs != 41 || f.PeakCPUs != 321 || f.PeakMemoryGB != 1284 {
+		t.Errorf("peak = %d VMs, %d CPUs, %.0f GB; want 41, 321, 1284", f.PeakVMs, f.PeakCPUs, f.PeakMemoryGB)
+	}
+	if want := []string{"Align", "QC"}; !reflect.DeepEqual(f.PeakCalls, want) {
+		t.Errorf("PeakCalls = %v, want %v", f.PeakCalls, want)
+	}
+	if f.TotalVMs != 43 || f.TotalCPUs != 341 {
+		t.Errorf("total = %d VMs, %d CPUs; want 43, 341", f.TotalVMs, f.TotalCPUs)
+	}
+	if want := []string{"Gather"}; !reflect.DeepEqual(f.UnknownInstances, want) {
+		t.Errorf("UnknownInstances = %v, want %v", f.UnknownInstances, want)
+	}
+	if want := []string{"QC"}; !reflect.DeepEqual(f.AssumedDefaults, want) {
+		t.Errorf("AssumedDefaults = %v, want %v", f.AssumedDefaults, want)
+	}
+}
+
+func TestPredictFootprint_CycleDoesNotHang(t *testing.T) {
+	f := PredictFootprint([]CallDemand{
+		{Name: "A", DependsOn: []string{"B"}, Instances: 1, CPU: 1, Memory: "1 GB"},
+		{Name: "B", DependsOn: []string{"A"}, Instances: 1, CPU: 1, Memory: "1 GB"},
+	})
+	if f.TotalVMs != 2 {
+		t.Errorf("TotalVMs = %d, want 2", f.TotalVMs)
+	}
+}
+
+func TestFootprint_Exceeds(t *testing.T) {
+	f := Footprint{PeakVMs: 41, PeakCPUs: 321, PeakMemoryGB: 1284}
+
+	tests := []struct {
+		name  string
+		quota ResourceQuota
+		want  []string
+	}{
+		{"no quota", ResourceQuota{}, nil},
+		{"fits", ResourceQuota{CPUs: 500, MemoryGB: 2000, VMs: 100}, nil},
+		{"cpu only", ResourceQuota{CPUs: 240}, []string{"CPUs"}},
+		{"everything", ResourceQuota{CPUs: 24, MemoryGB: 100, VMs: 8}, []string{"CPUs", "memory (GB)", "VMs"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, b := range f.Exceeds(tt.quota) {
+				got = append(got, b.Resource)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Exceeds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
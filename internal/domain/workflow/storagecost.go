@@ -0,0 +1,101 @@
+// storagecost.go prices the files a workflow leaves in storage. Prices are
+// per GiB-month and depend only on the storage service, so an estimate is the
+// stored size times the price of the service the file lives in.
+package workflow
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Storage services a file path can be priced under.
+const (
+	StorageGCS   = "gs"
+	StorageS3    = "s3"
+	StorageAzure = "az"
+	StorageLocal = "local"
+)
+
+// bytesPerGiB is the unit storage prices are quoted in.
+const bytesPerGiB = 1 << 30
+
+// StoragePrices is a Value Object with the monthly price, in dollars per
+// GiB, of each storage service. A service missing from the table is free.
+type StoragePrices map[string]float64
+
+// DefaultStoragePrices returns the list prices of standard-class storage in
+// a single US region, which is what most pipelines write to.
+func DefaultStoragePrices() StoragePrices {
+	return StoragePrices{
+		StorageGCS:   0.020,
+		StorageS3:    0.023,
+		StorageAzure: 0.018,
+		StorageLocal: 0,
+	}
+}
+
+// ParseStoragePrices reads a price table written as comma-separated
+// service=price pairs, e.g. "gs=0.026,s3=0.0125". Services not named keep
+// their default price.
+func ParseStoragePrices(spec string) (StoragePrices, error) {
+	prices := DefaultStoragePrices()
+	for _, pair := range strings.Split(spec, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		service, value, ok := strings.Cut(pair, "=")
+		service = strings.ToLower(strings.TrimSpace(service))
+		if !ok || service == "" {
+			return nil, fmt.Errorf("invalid storage price %q (want service=price)", pair)
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || price < 0 {
+			return nil, fmt.Errorf("invalid storage price for %s: %q (want dollars per GiB-month)", service, value)
+		}
+		prices[service] = price
+	}
+	return prices, nil
+}
+
+// String writes the table back in the form ParseStoragePrices reads.
+func (p StoragePrices) String() string {
+	services := make([]string, 0, len(p))
+	for s := range p {
+		services = append(services, s)
+	}
+	sort.Strings(services)
+	pairs := make([]string, len(services))
+	for i, s := range services {
+		pairs[i] = s + "=" + strconv.FormatFloat(p[s], 'f', -1, 64)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// MonthlyCost estimates the dollars a month that keeping bytes at path costs.
+func (p StoragePrices) MonthlyCost(path string, bytes int64) float64 {
+	return float64(bytes) / bytesPerGiB * p[StorageService(path)]
+}
+
+// StorageService returns the service a path is stored in: a bucket scheme,
+// an Azure Blob URL, or the local filesystem for anything else.
+func StorageService(path string) string {
+	switch {
+	case strings.HasPrefix(path, "gs://"):
+		return StorageGCS
+	case strings.HasPrefix(path, "s3://"):
+		return StorageS3
+	case strings.HasPrefix(path, "az://"):
+		return StorageAzure
+	case strings.HasPrefix(path, "https://"):
+		if u, err := url.Parse(path); err == nil && strings.HasSuffix(u.Hostname(), ".blob.core.windows.net") {
+			return StorageAzure
+		}
+		return ""
+	case strings.HasPrefix(path, "http://"):
+		return ""
+	}
+	return StorageLocal
+}
@@ -0,0 +1,69 @@
+package workflow
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStorageService(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"gs://bucket/wf/run/out.bam", StorageGCS},
+		{"s3://bucket/wf/run/out.bam", StorageS3},
+		{"az://container/wf/run/out.bam", StorageAzure},
+		{"https://acct.blob.core.windows.net/container/out.bam", StorageAzure},
+		{"https://example.com/out.bam", ""},
+		{"/data/cromwell-executions/wf/run/out.bam", StorageLocal},
+	}
+	for _, tt := range tests {
+		if got := StorageService(tt.path); got != tt.want {
+			t.Errorf("StorageService(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseStoragePrices(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    StoragePrices
+		wantErr bool
+	}{
+		{name: "empty keeps defaults", spec: "", want: DefaultStoragePrices()},
+		{
+			name: "overrides merge over defaults",
+			spec: " GS=0.026, local=0.01 ",
+			want: StoragePrices{StorageGCS: 0.026, StorageS3: 0.023, StorageAzure: 0.018, StorageLocal: 0.01},
+		},
+		{name: "missing price", spec: "gs", wantErr: true},
+		{name: "not a number", spec: "gs=cheap", wantErr: true},
+		{name: "negative", spec: "s3=-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStoragePrices(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseStoragePrices(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.String() != tt.want.String() {
+				t.Errorf("ParseStoragePrices(%q) = %s, want %s", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoragePrices_MonthlyCost(t *testing.T) {
+	prices := DefaultStoragePrices()
+	const tib = 1 << 40
+	if got := prices.MonthlyCost("gs://b/x", tib); math.Abs(got-20.48) > 1e-9 {
+		t.Errorf("1 TiB on GCS = %v, want 20.48", got)
+	}
+	if got := prices.MonthlyCost("https://example.com/x", tib); got != 0 {
+		t.Errorf("unpriced service = %v, want 0", got)
+	}
+}
@@ -0,0 +1,102 @@
+package workflow
+
+// WDLDiff is what changed in behaviour between two versions of a workflow,
+// read from their WDL alone: the calls, what each runs, and what the change
+// costs in call-cache hits on the next run.
+type WDLDiff struct {
+	// Old and New name the two versions as they were given.
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	WorkflowOld string `json:"workflowOld"`
+	WorkflowNew string `json:"workflowNew"`
+
+	// Inputs and Outputs are the changes to the workflow's own signature.
+	Inputs  []SignatureChange `json:"inputs,omitempty"`
+	Outputs []SignatureChange `json:"outputs,omitempty"`
+	// Calls lists the calls added, removed or modified, by path.
+	Calls []CallChange `json:"calls,omitempty"`
+
+	// Reruns are the calls of the new version that lose their cache hit on
+	// their own account; Downstream those that lose it only because a call
+	// upstream reruns; Undetermined those whose definition could not be read
+	// on one side.
+	Reruns       []CacheLoss `json:"reruns,omitempty"`
+	Downstream   []CacheLoss `json:"downstream,omitempty"`
+	Undetermined []CacheLoss `json:"undetermined,omitempty"`
+
+	// Warnings carries every reason the diff may be incomplete, such as an
+	// import that could not be resolved.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// HasDifferences reports whether anything changed at all.
+func (d *WDLDiff) HasDifferences() bool {
+	return d.WorkflowOld != d.WorkflowNew || len(d.Inputs) > 0 || len(d.Outputs) > 0 || len(d.Calls) > 0
+}
+
+// SignatureChange is one input or output whose declaration differs. Old and
+// New are the declarations as written ("File? bam", "Int threads = 4").
+type SignatureChange struct {
+	Name string     `json:"name"`
+	Kind ChangeKind `json:"kind"`
+	Old  string     `json:"old,omitempty"`
+	New  string     `json:"new,omitempty"`
+}
+
+// CallChange is how one call differs between the two versions.
+type CallChange struct {
+	Call string     `json:"call"`
+	Kind ChangeKind `json:"kind"`
+	// TaskOld and TaskNew are the tasks the call runs; they differ when the
+	// call was pointed at another task.
+	TaskOld string `json:"taskOld,omitempty"`
+	TaskNew string `json:"taskNew,omitempty"`
+
+	// CommandHashOld and CommandHashNew are the command-template hashes
+	// Cromwell records, set when the command changed.
+	CommandHashOld string `json:"commandHashOld,omitempty"`
+	CommandHashNew string `json:"commandHashNew,omitempty"`
+	// Runtime lists the runtime attributes that changed, as written; docker
+	// is listed with its resolved image when an input default supplies it.
+	Runtime []KeyDiff `json:"runtime,omitempty"`
+	// Inputs and Outputs are the changes to the task's signature.
+	Inputs  []SignatureChange `json:"inputs,omitempty"`
+	Outputs []SignatureChange `json:"outputs,omitempty"`
+	// Bindings lists the call inputs now fed from somewhere else.
+	Bindings []KeyDiff `json:"bindings,omitempty"`
+
+	// CacheReasons are the changes that alter the call's cache fingerprint.
+	// A modified call without any keeps its cache hits.
+	CacheReasons []string `json:"cacheReasons,omitempty"`
+}
+
+// CommandChanged reports whether the command template changed.
+func (c CallChange) CommandChanged() bool {
+	return c.CommandHashOld != c.CommandHashNew
+}
+
+// CacheLoss is a call of the new version that will not be served from the
+// call cache of a run of the old one.
+type CacheLoss struct {
+	Call string `json:"call"`
+	// Reasons are why the call reruns, or why its fate is unknown.
+	Reasons []string `json:"reasons,omitempty"`
+	// Cause is the upstream call responsible, for a downstream rerun.
+	Cause string `json:"cause,omitempty"`
+}
+
+// ApplyCachePredictions files each prediction for the new version under the
+// cache loss it represents. Calls that keep their hits are left out.
+func (d *WDLDiff) ApplyCachePredictions(predictions []CallPrediction) {
+	for _, p := range predictions {
+		loss := CacheLoss{Call: p.Call, Reasons: p.Reasons, Cause: p.Cause}
+		switch p.Fate {
+		case FateRerun, FatePartialReuse:
+			d.Reruns = append(d.Reruns, loss)
+		case FateRerunDownstream:
+			d.Downstream = append(d.Downstream, loss)
+		case FateUnknown:
+			d.Undetermined = append(d.Undetermined, loss)
+		}
+	}
+}
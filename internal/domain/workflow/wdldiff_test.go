@@ -0,0 +1,40 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWDLDiffApplyCachePredictions(t *testing.T) {
+	graph := map[string][]string{
+		"Align":  nil,
+		"Sort":   {"Align"},
+		"Call":   {"Sort"},
+		"QC":     nil,
+		"Report": {"QC"},
+	}
+	assessments := map[string]CallAssessment{
+		"Align": {Reasons: []string{"command template changed"}},
+		"QC":    {Unknown: "task definition not readable"},
+	}
+
+	var d WDLDiff
+	d.ApplyCachePredictions(PredictCacheReuse(graph, assessments))
+
+	if want := []CacheLoss{{Call: "Align", Reasons: []string{"command template changed"}}}; !reflect.DeepEqual(d.Reruns, want) {
+		t.Errorf("Reruns = %+v, want %+v", d.Reruns, want)
+	}
+	var downstream []string
+	for _, l := range d.Downstream {
+		if l.Cause != "Align" {
+			t.Errorf("%s cause = %q, want Align", l.Call, l.Cause)
+		}
+		downstream = append(downstream, l.Call)
+	}
+	if want := []string{"Call", "Sort"}; !reflect.DeepEqual(downstream, want) {
+		t.Errorf("Downstream = %v, want %v", downstream, want)
+	}
+	if len(d.Undetermined) != 2 {
+		t.Errorf("Undetermined = %+v, want QC and Report", d.Undetermined)
+	}
+}
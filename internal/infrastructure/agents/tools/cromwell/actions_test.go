@@ -1,14 +1,18 @@
 package cromwell
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/lmtani/pumbaa/internal/application/ports"
 	"github.com/lmtani/pumbaa/internal/domain/workflow"
 	"github.com/lmtani/pumbaa/internal/infrastructure/agents/tools/types"
 )
@@ -206,7 +210,7 @@ func TestReadLogResolvesTaskAndTails(t *testing.T) {
 			{Stderr: stderrPath, Stdout: "x", Attempt: 2, ShardIndex: -1},
 		},
 	}}
-	h := NewReadLogHandler(repo)
+	h := NewReadLogHandler(repo, nil)
 
 	// Short task name resolves the call; the latest attempt wins.
 	out, err := h.Handle(context.Background(), types.Input{Action: "read_log", WorkflowID: "wf-1", Task: "Align", Lines: 10})
@@ -229,7 +233,7 @@ func TestReadLogUnknownTaskListsAvailable(t *testing.T) {
 	repo := &stubLogsRepo{logs: map[string][]workflow.CallLog{
 		"WF.Align": {{Stderr: "s", Attempt: 1, ShardIndex: -1}},
 	}}
-	h := NewReadLogHandler(repo)
+	h := NewReadLogHandler(repo, nil)
 
 	out, _ := h.Handle(context.Background(), types.Input{Action: "read_log", WorkflowID: "wf-1", Task: "Nope"})
 	if out.Success {
@@ -246,7 +250,7 @@ func TestReadLogDirectPathSkipsResolution(t *testing.T) {
 	if err := os.WriteFile(p, []byte("a\nb\n"), 0o644); err != nil {
 		t.Fatal(err)
 	}
-	h := NewReadLogHandler(nil) // repo unused when path is explicit
+	h := NewReadLogHandler(nil, nil) // repo unused when path is explicit
 
 	out, err := h.Handle(context.Background(), types.Input{Action: "read_log", Path: p})
 	if err != nil || !out.Success {
@@ -267,3 +271,80 @@ func TestTailLines(t *testing.T) {
 		t.Errorf("empty content should be 0 lines, got %q/%d", tail, total)
 	}
 }
+
+// rangedLogs is a FileProvider and RangeReader over in-memory logs that, like
+// storage, refuses whole reads of large files.
+type rangedLogs struct {
+	files  map[string][]byte
+	ranged int64
+}
+
+func (f *rangedLogs) Read(context.Context, string) (string, error) {
+	return "", errors.New("file too large")
+}
+func (f *rangedLogs) ReadBytes(context.Context, string) ([]byte, error) {
+	return nil, errors.New("file too large")
+}
+func (f *rangedLogs) GetSize(_ context.Context, path string) (int64, error) {
+	data, ok := f.files[path]
+	if !ok {
+		return 0, ports.ErrFileNotFound
+	}
+	return int64(len(data)), nil
+}
+func (f *rangedLogs) GetContentDigests(context.Context, string) (ports.FileDigests, error) {
+	return ports.FileDigests{}, ports.ErrHashUnavailable
+}
+func (f *rangedLogs) ReadRange(_ context.Context, path string, offset, length int64) ([]byte, error) {
+	data := f.files[path]
+	offset = min(offset, int64(len(data)))
+	end := min(offset+length, int64(len(data)))
+	f.ranged += end - offset
+	return data[offset:end], nil
+}
+
+func TestReadLogThroughRangedReads(t *testing.T) {
+	var big strings.Builder
+	for i := range 1_000_000 { // ~13 MB
+		fmt.Fprintf(&big, "line %d\n", i)
+	}
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write([]byte("a\nb\nc\n"))
+	_ = zw.Close()
+	fp := &rangedLogs{files: map[string][]byte{
+		"gs://b/stderr":    []byte(big.String()),
+		"gs://b/short":     []byte("only\nthree\nlines\n"),
+		"gs://b/stderr.gz": gz.Bytes(),
+	}}
+	h := NewReadLogHandler(nil, fp)
+
+	tests := []struct {
+		name          string
+		path          string
+		lines         int
+		wantContent   string
+		wantTruncated bool
+		wantTotal     any
+	}{
+		{"tail of a large log", "gs://b/stderr", 2, "line 999998\nline 999999", true, nil},
+		{"whole short log", "gs://b/short", 10, "only\nthree\nlines", false, 3},
+		{"gzipped log", "gs://b/stderr.gz", 2, "b\nc", true, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := h.Handle(context.Background(), types.Input{Action: "read_log", Path: tt.path, Lines: tt.lines})
+			if err != nil || !out.Success {
+				t.Fatalf("Handle failed: err=%v out=%+v", err, out)
+			}
+			data := out.Data.(map[string]any)
+			if data["content"] != tt.wantContent || data["truncated"] != tt.wantTruncated || data["total_lines"] != tt.wantTotal {
+				t.Errorf("got content %q truncated %v total %v, want %q %v %v",
+					data["content"], data["truncated"], data["total_lines"], tt.wantContent, tt.wantTruncated, tt.wantTotal)
+			}
+		})
+	}
+	if fp.ranged > 1024*1024 {
+		t.Errorf("read %d bytes to tail the logs, want only their ends", fp.ranged)
+	}
+}
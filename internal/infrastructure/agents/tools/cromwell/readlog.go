@@ -1,8 +1,12 @@
 package cromwell
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -18,18 +22,29 @@ const (
 	maxLogLines     = 500
 	// maxLocalLogSize caps local log reads, mirroring the GCS limit.
 	maxLocalLogSize = 5 * 1024 * 1024
+	// tailWindow is how much of the end of a log a ranged read starts with;
+	// it grows by tailGrowth until it holds enough lines, up to maxTailWindow.
+	tailWindow    = 256 * 1024
+	tailGrowth    = 4
+	maxTailWindow = 16 * 1024 * 1024
 )
 
 // ReadLogHandler handles the "read_log" action: the tail of a task's stderr
 // or stdout in one call — either from an explicit log path or resolved from
 // workflow_id + task.
+//
+// With a file provider that supports ranged reads, only the end of the log
+// is read, so logs of any size can be tailed. Logs ending in .gz are
+// decompressed as they stream.
 type ReadLogHandler struct {
-	repo ports.WorkflowReader
+	repo         ports.WorkflowReader
+	fileProvider ports.FileProvider
 }
 
-// NewReadLogHandler creates a new ReadLogHandler.
-func NewReadLogHandler(repo ports.WorkflowReader) *ReadLogHandler {
-	return &ReadLogHandler{repo: repo}
+// NewReadLogHandler creates a new ReadLogHandler. fp may be nil, in which
+// case logs are fetched whole from GCS or the local filesystem.
+func NewReadLogHandler(repo ports.WorkflowReader, fp ports.FileProvider) *ReadLogHandler {
+	return &ReadLogHandler{repo: repo, fileProvider: fp}
 }
 
 // Handle implements types.Handler.
@@ -53,12 +68,24 @@ func (h *ReadLogHandler) Handle(ctx context.Context, input types.Input) (types.O
 		path = resolved
 	}
 
+	if rr, ok := h.fileProvider.(ports.RangeReader); ok {
+		return h.readRanged(ctx, rr, path, lines)
+	}
+
 	content, err := fetchLog(ctx, path)
 	if err != nil {
 		return types.NewErrorOutput(action, err.Error()), nil
 	}
-
-	tail, total := tailLines(content, lines)
+	var tail string
+	var total int
+	if strings.HasSuffix(path, ".gz") {
+		tail, total, err = gunzipTail(strings.NewReader(content), lines)
+		if err != nil {
+			return types.NewErrorOutput(action, err.Error()), nil
+		}
+	} else {
+		tail, total = tailLines(content, lines)
+	}
 	return types.NewSuccessOutput(action, map[string]any{
 		"path":        path,
 		"total_lines": total,
@@ -68,6 +95,129 @@ func (h *ReadLogHandler) Handle(ctx context.Context, input types.Input) (types.O
 	}), nil
 }
 
+// readRanged tails a log through ranged reads. The total line count is only
+// known when the whole log was read, so it is left out otherwise.
+func (h *ReadLogHandler) readRanged(ctx context.Context, rr ports.RangeReader, path string, lines int) (types.Output, error) {
+	const action = "read_log"
+
+	size, err := h.fileProvider.GetSize(ctx, path)
+	if err != nil {
+		return types.NewErrorOutput(action, fmt.Sprintf("log not found: %v", err)), nil
+	}
+	log := io.NewSectionReader(rangeReaderAt{ctx: ctx, rr: rr, path: path}, 0, size)
+
+	if strings.HasSuffix(path, ".gz") {
+		tail, total, err := gunzipTail(bufio.NewReaderSize(log, tailWindow), lines)
+		if err != nil {
+			return types.NewErrorOutput(action, err.Error()), nil
+		}
+		return types.NewSuccessOutput(action, map[string]any{
+			"path":        path,
+			"total_lines": total,
+			"shown_lines": min(lines, total),
+			"truncated":   total > lines,
+			"content":     tail,
+		}), nil
+	}
+
+	tail, shown, whole, err := rangedTail(log, size, lines)
+	if err != nil {
+		return types.NewErrorOutput(action, fmt.Sprintf("failed to read log: %v", err)), nil
+	}
+	data := map[string]any{
+		"path":        path,
+		"size_bytes":  size,
+		"shown_lines": shown,
+		"truncated":   !whole,
+		"content":     tail,
+	}
+	if whole {
+		data["total_lines"] = shown
+	}
+	return types.NewSuccessOutput(action, data), nil
+}
+
+// rangedTail returns up to n last lines of a log read from its end, widening
+// the window until it holds n whole lines or reaches maxTailWindow. whole
+// reports whether the window covered the entire log and held at most n lines.
+func rangedTail(log io.ReaderAt, size int64, n int) (string, int, bool, error) {
+	for window := int64(tailWindow); ; window *= tailGrowth {
+		start := max(size-window, 0)
+		data := make([]byte, size-start)
+		if _, err := log.ReadAt(data, start); err != nil && !errors.Is(err, io.EOF) {
+			return "", 0, false, err
+		}
+		trimmed := strings.TrimRight(string(data), "\n")
+		all := strings.Split(trimmed, "\n")
+		if start > 0 {
+			// The first line may be cut by the window.
+			all = all[1:]
+		}
+		if len(all) >= n || start == 0 || window >= maxTailWindow {
+			if trimmed == "" {
+				return "", 0, start == 0, nil
+			}
+			if len(all) > n {
+				return strings.Join(all[len(all)-n:], "\n"), n, false, nil
+			}
+			return strings.Join(all, "\n"), len(all), start == 0, nil
+		}
+	}
+}
+
+// gunzipTail decompresses a .gz log as it streams, keeping only its last n
+// lines, and returns them with the total line count.
+func gunzipTail(compressed io.Reader, n int) (string, int, error) {
+	zr, err := gzip.NewReader(compressed)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to decompress log: %v", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	ring := make([]string, n)
+	total := 0
+	r := bufio.NewReader(zr)
+	for {
+		line, err := r.ReadString('\n')
+		if line != "" {
+			ring[total%n] = strings.TrimSuffix(line, "\n")
+			total++
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to decompress log: %v", err)
+		}
+	}
+
+	shown := min(n, total)
+	tail := make([]string, 0, shown)
+	for i := total - shown; i < total; i++ {
+		tail = append(tail, ring[i%n])
+	}
+	return strings.Join(tail, "\n"), total, nil
+}
+
+// rangeReaderAt adapts a RangeReader to io.ReaderAt for one file.
+type rangeReaderAt struct {
+	ctx  context.Context
+	rr   ports.RangeReader
+	path string
+}
+
+func (r rangeReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	data, err := r.rr.ReadRange(r.ctx, r.path, off, int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	n := copy(p, data)
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
 // resolveLogPath finds the log path for workflow_id + task (+ optional
 // shard/stream) using the workflow's log listing. The latest attempt wins:
 // earlier attempts were preempted or retried.
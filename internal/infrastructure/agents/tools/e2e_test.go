@@ -34,13 +34,13 @@ func TestToolsE2E(t *testing.T) {
 	var wdlRepo *wdlindexer.Indexer
 	if dir := os.Getenv("PUMBAA_WDL_DIR"); dir != "" {
 		var err error
-		wdlRepo, err = wdlindexer.NewIndexer(dir, filepath.Join(t.TempDir(), "index.json"), true)
+		wdlRepo, err = wdlindexer.NewIndexer(filepath.SplitList(dir), filepath.Join(t.TempDir(), "index.json"), true)
 		if err != nil {
 			t.Fatalf("failed to build WDL index for %s: %v", dir, err)
 		}
 	}
 
-	registry := tools.NewDefaultRegistry(tools.Deps{Repo: reader, Fetcher: reader, WDLRepo: wdlRepo, FileProvider: storage.NewFileProvider()})
+	registry := tools.NewDefaultRegistry(tools.Deps{Repo: reader, Fetcher: reader, WDLRepo: wdlRepo, FileProvider: storage.NewFileProvider(storage.Config{})})
 	ctx := context.Background()
 
 	handle := func(t *testing.T, input types.Input) types.Output {
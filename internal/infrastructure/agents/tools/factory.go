@@ -110,9 +110,9 @@ func builtinActions() []actionSpec {
 		},
 		{
 			name:        "read_log",
-			description: "Read the tail of a task's log in one call. Either path (a stderr/stdout path from failures/logs), or workflow_id + task (optional: shard, stream=stderr|stdout, lines<=500).",
+			description: "Read the tail of a task's log in one call, however large; .gz logs are decompressed. Either path (a stderr/stdout path from failures/logs), or workflow_id + task (optional: shard, stream=stderr|stdout, lines<=500).",
 			build: func(deps Deps) types.Handler {
-				return cromwell.NewReadLogHandler(deps.Repo)
+				return cromwell.NewReadLogHandler(deps.Repo, deps.FileProvider)
 			},
 		},
 		{
@@ -155,7 +155,7 @@ func builtinActions() []actionSpec {
 		},
 		{
 			name:        "wdl_search",
-			description: "Search tasks/workflows by name or command content. Required: query.",
+			description: "Ranked full-text search of indexed tasks, workflows and structs by name, description, docker image and command; returns the best matches first with highlighted snippets. Required: query. Optional: page_size (default 10).",
 			requiresWDL: true,
 			build: func(deps Deps) types.Handler {
 				return wdl.NewSearchHandler(deps.WDLRepo)
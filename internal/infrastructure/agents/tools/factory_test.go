@@ -19,11 +19,12 @@ import (
 // stubWDLRepo satisfies wdl.Repository for registry construction in tests.
 type stubWDLRepo struct{}
 
-func (stubWDLRepo) List() (*wdlindex.Index, error)                              { return &wdlindex.Index{}, nil }
-func (stubWDLRepo) SearchTasks(string) ([]*wdlindex.IndexedTask, error)         { return nil, nil }
-func (stubWDLRepo) SearchWorkflows(string) ([]*wdlindex.IndexedWorkflow, error) { return nil, nil }
-func (stubWDLRepo) GetTask(string) (*wdlindex.IndexedTask, error)               { return nil, nil }
-func (stubWDLRepo) GetWorkflow(string) (*wdlindex.IndexedWorkflow, error)       { return nil, nil }
+func (stubWDLRepo) List() (*wdlindex.Index, error) { return &wdlindex.Index{}, nil }
+func (stubWDLRepo) Search(string, wdlindex.SearchOptions) ([]wdlindex.SearchHit, error) {
+	return nil, nil
+}
+func (stubWDLRepo) GetTask(string) (*wdlindex.IndexedTask, error)         { return nil, nil }
+func (stubWDLRepo) GetWorkflow(string) (*wdlindex.IndexedWorkflow, error) { return nil, nil }
 
 func TestNewDefaultRegistryOmitsWDLActionsWithoutRepo(t *testing.T) {
 	r := NewDefaultRegistry(Deps{})
@@ -54,7 +54,7 @@ func GetParametersSchema() map[string]any {
 			},
 			"page_size": map[string]any{
 				"type":        "integer",
-				"description": "Number of results to return for query action (default: 10)",
+				"description": "Number of results to return for query and wdl_search actions (default: 10)",
 			},
 			"task": map[string]any{
 				"type":        "string",
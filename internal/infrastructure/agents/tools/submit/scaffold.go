@@ -47,6 +47,9 @@ func (h *ScaffoldHandler) Handle(_ context.Context, input types.Input) (types.Ou
 		if in.Description != "" {
 			entry["description"] = in.Description
 		}
+		if in.Call != "" {
+			entry["call"] = in.Call
+		}
 		inputs = append(inputs, entry)
 	}
 
@@ -10,8 +10,7 @@ const notConfiguredError = "WDL index not configured. Set PUMBAA_WDL_DIR environ
 // Repository defines the interface for WDL index operations.
 type Repository interface {
 	List() (*wdlindex.Index, error)
-	SearchTasks(query string) ([]*wdlindex.IndexedTask, error)
-	SearchWorkflows(query string) ([]*wdlindex.IndexedWorkflow, error)
+	Search(query string, opts wdlindex.SearchOptions) ([]wdlindex.SearchHit, error)
 	GetTask(name string) (*wdlindex.IndexedTask, error)
 	GetWorkflow(name string) (*wdlindex.IndexedWorkflow, error)
 }
@@ -39,12 +39,28 @@ func (h *ListHandler) Handle(_ context.Context, _ types.Input) (types.Output, er
 		workflows = append(workflows, name)
 	}
 
+	structs := make([]string, 0, len(index.Structs))
+	for name := range index.Structs {
+		structs = append(structs, name)
+	}
+
+	// Files that failed to parse define nothing, so whatever the user is
+	// looking for may be in one of them.
+	parseErrors := make([]map[string]string, 0)
+	for _, e := range index.Errors() {
+		parseErrors = append(parseErrors, map[string]string{"file": e.Path, "error": e.Message})
+	}
+
 	return types.NewSuccessOutput(action, map[string]any{
-		"directory":      index.Directory,
+		"roots":          index.Roots,
 		"indexed_at":     index.IndexedAt,
+		"file_count":     len(index.Files),
 		"task_count":     len(tasks),
 		"workflow_count": len(workflows),
+		"struct_count":   len(structs),
 		"tasks":          tasks,
 		"workflows":      workflows,
+		"structs":        structs,
+		"parse_errors":   parseErrors,
 	}), nil
 }
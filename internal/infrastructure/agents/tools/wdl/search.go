@@ -2,10 +2,15 @@ package wdl
 
 import (
 	"context"
+	"math"
 
+	"github.com/lmtani/pumbaa/internal/domain/wdlindex"
 	"github.com/lmtani/pumbaa/internal/infrastructure/agents/tools/types"
 )
 
+// defaultSearchLimit is how many hits wdl_search returns without page_size.
+const defaultSearchLimit = 10
+
 // SearchHandler handles the "wdl_search" action to search tasks/workflows.
 type SearchHandler struct {
 	repo Repository
@@ -28,37 +33,39 @@ func (h *SearchHandler) Handle(_ context.Context, input types.Input) (types.Outp
 		return types.NewErrorOutput(action, "query is required"), nil
 	}
 
-	tasks, err := h.repo.SearchTasks(input.Query)
-	if err != nil {
-		return types.NewErrorOutput(action, err.Error()), nil
+	limit := input.PageSize
+	if limit <= 0 {
+		limit = defaultSearchLimit
 	}
 
-	workflows, err := h.repo.SearchWorkflows(input.Query)
+	hits, err := h.repo.Search(input.Query, wdlindex.SearchOptions{Limit: limit})
 	if err != nil {
 		return types.NewErrorOutput(action, err.Error()), nil
 	}
 
-	taskResults := make([]map[string]any, 0, len(tasks))
-	for _, t := range tasks {
-		taskResults = append(taskResults, map[string]any{
-			"name":        t.Name,
-			"source":      t.Source,
-			"description": t.Description,
-		})
-	}
-
-	workflowResults := make([]map[string]any, 0, len(workflows))
-	for _, w := range workflows {
-		workflowResults = append(workflowResults, map[string]any{
-			"name":        w.Name,
-			"source":      w.Source,
-			"description": w.Description,
+	results := make([]map[string]any, 0, len(hits))
+	for _, hit := range hits {
+		// Matched terms are marked in Markdown bold, so the model can quote
+		// the snippets as they are.
+		matches := make([]map[string]any, 0, len(hit.Snippets))
+		for _, sn := range hit.Snippets {
+			matches = append(matches, map[string]any{
+				"field": sn.Field,
+				"text":  sn.Marked("**", "**"),
+			})
+		}
+		results = append(results, map[string]any{
+			"type":    hit.Kind,
+			"name":    hit.Name,
+			"source":  hit.Source,
+			"score":   math.Round(hit.Score*100) / 100,
+			"matches": matches,
 		})
 	}
 
 	return types.NewSuccessOutput(action, map[string]any{
-		"query":     input.Query,
-		"tasks":     taskResults,
-		"workflows": workflowResults,
+		"query":   input.Query,
+		"count":   len(results),
+		"results": results,
 	}), nil
 }
@@ -41,6 +41,7 @@ func mapMetadataResponseToWorkflow(m *metadataResponse) *workflow.Workflow {
 		wf.SubmittedWorkflow = m.SubmittedFiles.Workflow
 		wf.SubmittedInputs = m.SubmittedFiles.Inputs
 		wf.SubmittedOptions = m.SubmittedFiles.Options
+		wf.SubmittedImports = m.SubmittedFiles.Imports
 	}
 
 	// Map calls with all detailed fields
@@ -42,6 +42,9 @@ type submittedFiles struct {
 	Workflow string `json:"workflow"`
 	Inputs   string `json:"inputs"`
 	Options  string `json:"options"`
+	// Imports holds the dependencies zip's WDL files, keyed by their path in
+	// the zip.
+	Imports map[string]string `json:"imports"`
 }
 
 // callMetadata represents metadata for a single call.
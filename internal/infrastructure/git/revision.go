@@ -0,0 +1,84 @@
+// Package git reads files from a git repository as they were at a revision,
+// by running the git command line.
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// Compile-time check: RevisionReader implements the revision port.
+var _ ports.RevisionReader = (*RevisionReader)(nil)
+
+// RevisionReader reads files at a revision of the git repository they sit
+// in. Paths are taken relative to the working directory, as git itself does.
+type RevisionReader struct {
+	binary string
+}
+
+// NewRevisionReader creates a reader running the git found on PATH.
+func NewRevisionReader() *RevisionReader {
+	return &RevisionReader{binary: "git"}
+}
+
+// ReadFile returns the content of path at rev.
+func (r *RevisionReader) ReadFile(ctx context.Context, rev, path string) ([]byte, error) {
+	if err := checkRevision(rev); err != nil {
+		return nil, err
+	}
+	// Running git from the file's directory resolves "./name" against it, so
+	// the path need not be relative to the repository root.
+	out, err := r.run(ctx, filepath.Dir(path), "show", rev+":./"+filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", path, rev, err)
+	}
+	return out, nil
+}
+
+// ListFiles lists the files under dir at rev, as paths relative to dir.
+func (r *RevisionReader) ListFiles(ctx context.Context, rev, dir string) ([]string, error) {
+	if err := checkRevision(rev); err != nil {
+		return nil, err
+	}
+	out, err := r.run(ctx, dir, "ls-tree", "-r", "-z", "--name-only", rev)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s at %s: %w", dir, rev, err)
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\x00") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// checkRevision refuses a revision git would parse as an option, such as
+// "--output=<file>", which would have git show write wherever it names. No
+// branch, tag or commit name starts with a dash.
+func checkRevision(rev string) error {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return fmt.Errorf("invalid revision %q", rev)
+	}
+	return nil
+}
+
+func (r *RevisionReader) run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, r.binary, append([]string{"-C", dir}, args...)...) //nolint:gosec // arguments are revisions and paths, never shell text
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s", msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("wf/main.wdl", "v1")
+	write("wf/tasks/align.wdl", "task")
+	write("README.md", "readme")
+	run("add", "-A")
+	run("commit", "-q", "-m", "first")
+	run("tag", "v1")
+	write("wf/main.wdl", "v2")
+	run("commit", "-q", "-am", "second")
+	return dir
+}
+
+func TestRevisionReader(t *testing.T) {
+	dir := gitRepo(t)
+	r := NewRevisionReader()
+	ctx := context.Background()
+
+	for rev, want := range map[string]string{"v1": "v1", "HEAD": "v2"} {
+		got, err := r.ReadFile(ctx, rev, filepath.Join(dir, "wf", "main.wdl"))
+		if err != nil {
+			t.Fatalf("ReadFile(%s) error: %v", rev, err)
+		}
+		if string(got) != want {
+			t.Errorf("ReadFile(%s) = %q, want %q", rev, got, want)
+		}
+	}
+
+	files, err := r.ListFiles(ctx, "v1", filepath.Join(dir, "wf"))
+	if err != nil {
+		t.Fatalf("ListFiles() error: %v", err)
+	}
+	if want := []string{"main.wdl", "tasks/align.wdl"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("ListFiles() = %v, want %v", files, want)
+	}
+
+	if _, err := r.ReadFile(ctx, "no-such-rev", filepath.Join(dir, "wf", "main.wdl")); err == nil {
+		t.Error("ReadFile() of an unknown revision should fail")
+	}
+}
+
+func TestRevisionReader_RejectsOptions(t *testing.T) {
+	dir := gitRepo(t)
+	r := NewRevisionReader()
+	ctx := context.Background()
+	out := filepath.Join(t.TempDir(), "written")
+
+	for _, rev := range []string{"--output=" + out, "-h", ""} {
+		if _, err := r.ReadFile(ctx, rev, filepath.Join(dir, "wf", "main.wdl")); err == nil {
+			t.Errorf("ReadFile(%q) should fail", rev)
+		}
+		if _, err := r.ListFiles(ctx, rev, filepath.Join(dir, "wf")); err == nil {
+			t.Errorf("ListFiles(%q) should fail", rev)
+		}
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("a revision was run as an option: %s was written", out)
+	}
+}
@@ -0,0 +1,478 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// azureBlobHostSuffix is the host suffix of the Azure Blob public cloud;
+// accounts are addressed as https://<account>.blob.core.windows.net.
+const azureBlobHostSuffix = ".blob.core.windows.net"
+
+// azureAPIVersion is the Blob REST API version requests are made with.
+const azureAPIVersion = "2021-08-06"
+
+// AzureConfig configures access to Azure Blob Storage. Without a key or SAS
+// token only public containers, and URLs that carry their own SAS token, can
+// be read.
+type AzureConfig struct {
+	// AccountName is the storage account the key or SAS token belongs to.
+	// Requests to other accounts are sent without credentials.
+	AccountName string
+	// AccountKey is the account's base64 shared key, used to sign requests.
+	AccountKey string
+	// SASToken is a shared access signature query string, with or without
+	// its leading "?". It is used instead of the key when both are set.
+	SASToken string
+	// Endpoint is the Blob endpoint of an emulator, such as a local Azurite
+	// ("http://127.0.0.1:10000/devstoreaccount1"). Paths under it are then
+	// handled too, with the account taken from AccountName.
+	Endpoint string
+}
+
+// AzureBackend implements StorageBackend for Azure Blob Storage through the
+// Blob REST API, so no SDK is involved. Paths are blob URLs:
+// https://<account>.blob.core.windows.net/<container>/<blob>.
+type AzureBackend struct {
+	cfg    AzureConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewAzureBackend creates a new AzureBackend.
+func NewAzureBackend(cfg AzureConfig) *AzureBackend {
+	cfg.SASToken = strings.TrimPrefix(cfg.SASToken, "?")
+	cfg.Endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+	return &AzureBackend{cfg: cfg, client: newHTTPClient(), now: time.Now}
+}
+
+// azureBlob is a blob URL split into the parts requests are built from.
+type azureBlob struct {
+	// base is the account's Blob endpoint, without a trailing slash.
+	base      string
+	account   string
+	container string
+	name      string
+	// query is the SAS token the URL carried, if any.
+	query string
+}
+
+// url returns the blob's URL, with name escaped segment by segment.
+func (b azureBlob) url() string {
+	u := b.base + "/" + b.container
+	if b.name != "" {
+		segments := strings.Split(b.name, "/")
+		for i, s := range segments {
+			segments[i] = url.PathEscape(s)
+		}
+		u += "/" + strings.Join(segments, "/")
+	}
+	return u
+}
+
+// CanHandle returns true for Azure Blob URLs, and for paths under the
+// configured emulator endpoint.
+func (b *AzureBackend) CanHandle(path string) bool {
+	if b.cfg.Endpoint != "" && strings.HasPrefix(path, b.cfg.Endpoint+"/") {
+		return true
+	}
+	u, err := url.Parse(path)
+	return err == nil && u.Scheme == "https" && strings.HasSuffix(u.Hostname(), azureBlobHostSuffix)
+}
+
+// Read reads the content of a blob as a string.
+// Enforces maxFileSize limit to prevent memory issues.
+func (b *AzureBackend) Read(ctx context.Context, path string) (string, error) {
+	size, err := b.GetSize(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	if size > maxFileSize {
+		return "", fmt.Errorf("file too large (%.2f MB > 1 MB limit)", float64(size)/(1024*1024))
+	}
+
+	data, err := b.ReadBytes(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ReadBytes reads the content of a blob as raw bytes.
+// No size limit is enforced, suitable for binary files like ZIP dependencies.
+func (b *AzureBackend) ReadBytes(ctx context.Context, path string) ([]byte, error) {
+	return b.get(ctx, path, "")
+}
+
+// ReadRange reads up to length bytes of a blob starting at offset,
+// downloading only that range.
+func (b *AzureBackend) ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error) {
+	if length <= 0 {
+		return nil, nil
+	}
+	return b.get(ctx, path, fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+}
+
+// get downloads a blob, or the part of it rng selects when not empty.
+func (b *AzureBackend) get(ctx context.Context, path, rng string) ([]byte, error) {
+	blob, err := b.parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.do(ctx, http.MethodGet, blob, nil, rng)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		// The range starts past the end of the blob.
+		return nil, nil
+	}
+	if err := azureStatusError(resp, path); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blob: %w", err)
+	}
+	return data, nil
+}
+
+// GetSize returns the size of a blob without reading its content.
+func (b *AzureBackend) GetSize(ctx context.Context, path string) (int64, error) {
+	resp, err := b.head(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+	return resp.ContentLength, nil
+}
+
+// GetContentDigests returns a blob's MD5, read from its properties so no data
+// is transferred. Azure sets Content-MD5 for blobs uploaded in a single
+// request; blobs assembled from blocks have one only if the uploader supplied
+// it. Azure keeps no CRC32C.
+func (b *AzureBackend) GetContentDigests(ctx context.Context, path string) (ports.FileDigests, error) {
+	resp, err := b.head(ctx, path)
+	if err != nil {
+		return ports.FileDigests{}, err
+	}
+	md5 := contentMD5Hex(resp.Header.Get("Content-MD5"))
+	if md5 == "" {
+		return ports.FileDigests{}, fmt.Errorf("%w: %s", ports.ErrHashUnavailable, path)
+	}
+	return ports.FileDigests{MD5: md5}, nil
+}
+
+// azureListResult is the part of a List Blobs response that is used.
+type azureListResult struct {
+	Blobs struct {
+		Blob []struct {
+			Name       string `xml:"Name"`
+			Properties struct {
+				ContentLength int64 `xml:"Content-Length"`
+			} `xml:"Properties"`
+		} `xml:"Blob"`
+		BlobPrefix []struct {
+			Name string `xml:"Name"`
+		} `xml:"BlobPrefix"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// List returns the blobs and virtual directories directly under a prefix,
+// using "/" as the delimiter so only one level is listed. A container root
+// (".../<container>") is a valid prefix.
+func (b *AzureBackend) List(ctx context.Context, prefix string) ([]ports.FileEntry, error) {
+	blob, err := b.parseLocation(prefix)
+	if err != nil {
+		return nil, err
+	}
+	dir := blob.name
+	if dir != "" && !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+	container := azureBlob{base: blob.base, account: blob.account, container: blob.container, query: blob.query}
+
+	var entries []ports.FileEntry
+	marker := ""
+	for {
+		params := url.Values{
+			"restype":   {"container"},
+			"comp":      {"list"},
+			"prefix":    {dir},
+			"delimiter": {"/"},
+		}
+		if marker != "" {
+			params.Set("marker", marker)
+		}
+		resp, err := b.do(ctx, http.MethodGet, container, params, "")
+		if err != nil {
+			return nil, err
+		}
+		var page azureListResult
+		if err := azureStatusError(resp, prefix); err != nil {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode blob listing: %w", err)
+		}
+
+		// Entries carry the SAS token the prefix came with, so they can be
+		// read, sized or listed with the same access.
+		base, query := container.url()+"/", ""
+		if blob.query != "" {
+			query = "?" + blob.query
+		}
+		for _, p := range page.Blobs.BlobPrefix {
+			entries = append(entries, ports.FileEntry{Path: base + strings.TrimSuffix(p.Name, "/") + query, IsDir: true})
+		}
+		for _, item := range page.Blobs.Blob {
+			if item.Name == dir {
+				// The placeholder blob some tools create for a "folder".
+				continue
+			}
+			entries = append(entries, ports.FileEntry{Path: base + item.Name + query, Size: item.Properties.ContentLength})
+		}
+		if page.NextMarker == "" {
+			break
+		}
+		marker = page.NextMarker
+	}
+	// Blob storage has no empty directories: nothing under a prefix means
+	// the prefix is not there.
+	if len(entries) == 0 && dir != "" {
+		return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, prefix)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// Delete removes a blob. A blob already gone is not an error.
+func (b *AzureBackend) Delete(ctx context.Context, path string) error {
+	blob, err := b.parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.do(ctx, http.MethodDelete, blob, nil, "")
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return azureStatusError(resp, path)
+}
+
+// head fetches a blob's properties.
+func (b *AzureBackend) head(ctx context.Context, path string) (*http.Response, error) {
+	blob, err := b.parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := b.do(ctx, http.MethodHead, blob, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if err := azureStatusError(resp, path); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// do sends a request for a blob or container, authorised with the URL's own
+// SAS token, the configured SAS token or the shared key, in that order. A
+// non-empty rng is sent as the x-ms-range of the request.
+func (b *AzureBackend) do(ctx context.Context, method string, blob azureBlob, params url.Values, rng string) (*http.Response, error) {
+	query := url.Values{}
+	for k, v := range params {
+		query[k] = v
+	}
+	signWithKey := false
+	switch {
+	case blob.query != "":
+		if err := mergeQuery(query, blob.query); err != nil {
+			return nil, err
+		}
+	case blob.account == b.cfg.AccountName && b.cfg.SASToken != "":
+		if err := mergeQuery(query, b.cfg.SASToken); err != nil {
+			return nil, err
+		}
+	case blob.account == b.cfg.AccountName && b.cfg.AccountKey != "":
+		signWithKey = true
+	}
+
+	target := blob.url()
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build blob request: %w", err)
+	}
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	req.Header.Set("x-ms-date", b.now().UTC().Format(http.TimeFormat))
+	if rng != "" {
+		req.Header.Set("x-ms-range", rng)
+	}
+	if signWithKey {
+		if err := b.sign(req, blob.account); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("blob request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// sign adds a Shared Key Authorization header, following the Blob service's
+// string-to-sign: the standard headers, the x-ms- headers and the resource.
+func (b *AzureBackend) sign(req *http.Request, account string) error {
+	key, err := base64.StdEncoding.DecodeString(b.cfg.AccountKey)
+	if err != nil {
+		return fmt.Errorf("invalid Azure storage key: %w", err)
+	}
+
+	var msHeaders []string
+	for name := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-ms-") {
+			msHeaders = append(msHeaders, lower+":"+strings.TrimSpace(req.Header.Get(name)))
+		}
+	}
+	sort.Strings(msHeaders)
+
+	resource := "/" + account + req.URL.EscapedPath()
+	query := req.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := append([]string(nil), query[name]...)
+		sort.Strings(values)
+		resource += "\n" + strings.ToLower(name) + ":" + strings.Join(values, ",")
+	}
+
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		"", // Content-Length, empty for a request without a body
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, superseded by x-ms-date
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	}, "\n") + "\n" + strings.Join(msHeaders, "\n") + "\n" + resource
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stringToSign))
+	req.Header.Set("Authorization", "SharedKey "+account+":"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+// parsePath splits a blob URL, which must name a blob inside a container.
+func (b *AzureBackend) parsePath(path string) (azureBlob, error) {
+	blob, err := b.parseLocation(path)
+	if err != nil {
+		return azureBlob{}, err
+	}
+	if blob.name == "" {
+		return azureBlob{}, fmt.Errorf("invalid Azure Blob path: %s", path)
+	}
+	return blob, nil
+}
+
+// parseLocation splits a blob URL or a container URL.
+func (b *AzureBackend) parseLocation(path string) (azureBlob, error) {
+	rest, query, _ := strings.Cut(path, "?")
+	var blob azureBlob
+	if b.cfg.Endpoint != "" && strings.HasPrefix(rest, b.cfg.Endpoint+"/") {
+		blob.base = b.cfg.Endpoint
+		blob.account = b.cfg.AccountName
+		rest = strings.TrimPrefix(rest, b.cfg.Endpoint+"/")
+	} else {
+		u, err := url.Parse(rest)
+		if err != nil || !strings.HasSuffix(u.Hostname(), azureBlobHostSuffix) {
+			return azureBlob{}, fmt.Errorf("invalid Azure Blob path: %s", path)
+		}
+		blob.base = u.Scheme + "://" + u.Host
+		blob.account = strings.TrimSuffix(u.Hostname(), azureBlobHostSuffix)
+		rest = strings.TrimPrefix(u.Path, "/")
+	}
+	blob.container, blob.name, _ = strings.Cut(rest, "/")
+	if blob.container == "" {
+		return azureBlob{}, fmt.Errorf("invalid Azure Blob path: %s", path)
+	}
+	blob.query = query
+	return blob, nil
+}
+
+// azureStatusError maps an error response to ErrFileNotFound or an error
+// carrying the service's error code.
+func azureStatusError(resp *http.Response, path string) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ports.ErrFileNotFound, path)
+	}
+	code := resp.Header.Get("x-ms-error-code")
+	if code == "" {
+		code = resp.Status
+	}
+	return fmt.Errorf("blob request failed: %s", code)
+}
+
+// mergeQuery adds the parameters of a raw query string to query.
+func mergeQuery(query url.Values, raw string) error {
+	extra, err := url.ParseQuery(raw)
+	if err != nil {
+		return fmt.Errorf("invalid SAS token: %w", err)
+	}
+	for k, v := range extra {
+		query[k] = v
+	}
+	return nil
+}
+
+// contentMD5Hex converts a base64 Content-MD5 header to lowercase hex, or ""
+// when it is absent or malformed.
+func contentMD5Hex(header string) string {
+	sum, err := base64.StdEncoding.DecodeString(header)
+	if err != nil || len(sum) != 16 {
+		return ""
+	}
+	return hex.EncodeToString(sum)
+}
+
+// Ensure AzureBackend implements StorageBackend at compile time.
+var _ ports.StorageBackend = (*AzureBackend)(nil)
@@ -0,0 +1,349 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// testAzureKey is the well-known key of the Azurite emulator's account.
+const testAzureKey = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+// newFakeAzure starts a minimal Blob service stand-in for one container of
+// the devstoreaccount1 account, addressed path-style like Azurite. It serves
+// HEAD, (ranged) GET and DELETE of a blob and List Blobs with a "/" delimiter, and records
+// each request's Authorization header and query.
+func newFakeAzure(t *testing.T, blobs map[string]string, md5s map[string]string, seen *[]*http.Request) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = append(*seen, r)
+		rest := strings.TrimPrefix(r.URL.Path, "/devstoreaccount1/")
+		container, name, _ := strings.Cut(rest, "/")
+		if container != "inputs" {
+			w.Header().Set("x-ms-error-code", "ContainerNotFound")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if name == "" && r.URL.Query().Get("comp") == "list" {
+			prefix := r.URL.Query().Get("prefix")
+			var b strings.Builder
+			b.WriteString(`<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs>`)
+			dirs := map[string]bool{}
+			for key, body := range blobs {
+				if !strings.HasPrefix(key, prefix) {
+					continue
+				}
+				if dir, _, nested := strings.Cut(strings.TrimPrefix(key, prefix), "/"); nested {
+					if !dirs[dir] {
+						dirs[dir] = true
+						fmt.Fprintf(&b, `<BlobPrefix><Name>%s%s/</Name></BlobPrefix>`, prefix, dir)
+					}
+					continue
+				}
+				fmt.Fprintf(&b, `<Blob><Name>%s</Name><Properties><Content-Length>%d</Content-Length></Properties></Blob>`, key, len(body))
+			}
+			b.WriteString(`</Blobs><NextMarker/></EnumerationResults>`)
+			_, _ = fmt.Fprint(w, b.String())
+			return
+		}
+
+		body, ok := blobs[name]
+		if !ok {
+			w.Header().Set("x-ms-error-code", "BlobNotFound")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(blobs, name)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		if md5 := md5s[name]; md5 != "" {
+			w.Header().Set("Content-MD5", md5)
+		}
+		if r.Method == http.MethodGet {
+			if rng := r.Header.Get("x-ms-range"); rng != "" {
+				r.Header.Set("Range", rng)
+			}
+			http.ServeContent(w, r, name, time.Time{}, strings.NewReader(body))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAzureBackend_CanHandle(t *testing.T) {
+	backend := NewAzureBackend(AzureConfig{Endpoint: "http://127.0.0.1:10000/devstoreaccount1"})
+
+	tests := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{"blob URL", "https://account.blob.core.windows.net/inputs/file.txt", true},
+		{"blob URL with SAS", "https://account.blob.core.windows.net/inputs/file.txt?sv=2021&sig=abc", true},
+		{"emulator endpoint", "http://127.0.0.1:10000/devstoreaccount1/inputs/file.txt", true},
+		{"plain blob URL over http", "http://account.blob.core.windows.net/inputs/file.txt", false},
+		{"other https host", "https://example.com/file.txt", false},
+		{"GCS path", "gs://bucket/file.txt", false},
+		{"local path", "/home/user/file.txt", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backend.CanHandle(tt.path); got != tt.expected {
+				t.Errorf("CanHandle(%q) = %v, want %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAzureBackend_ParsePath(t *testing.T) {
+	backend := NewAzureBackend(AzureConfig{})
+
+	tests := []struct {
+		path      string
+		account   string
+		container string
+		name      string
+		wantErr   bool
+	}{
+		{"https://acct.blob.core.windows.net/inputs/run/r1.fq", "acct", "inputs", "run/r1.fq", false},
+		{"https://acct.blob.core.windows.net/inputs", "", "", "", true},
+		{"https://acct.blob.core.windows.net/", "", "", "", true},
+		{"https://example.com/inputs/r1.fq", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			blob, err := backend.parsePath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if blob.account != tt.account || blob.container != tt.container || blob.name != tt.name {
+				t.Errorf("parsePath() = %s/%s/%s, want %s/%s/%s", blob.account, blob.container, blob.name, tt.account, tt.container, tt.name)
+			}
+		})
+	}
+}
+
+func TestAzureBackend_SharedKeySignature(t *testing.T) {
+	backend := NewAzureBackend(AzureConfig{AccountName: "acct", AccountKey: testAzureKey})
+	date := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	req := httptest.NewRequest(http.MethodGet, "https://acct.blob.core.windows.net/inputs?restype=container&comp=list&prefix=run%2F", nil)
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	req.Header.Set("x-ms-date", date.Format(http.TimeFormat))
+	if err := backend.sign(req, "acct"); err != nil {
+		t.Fatalf("sign() unexpected error: %v", err)
+	}
+
+	stringToSign := "GET\n\n\n\n\n\n\n\n\n\n\n\n" +
+		"x-ms-date:Fri, 02 Jan 2026 03:04:05 GMT\nx-ms-version:" + azureAPIVersion + "\n" +
+		"/acct/inputs\ncomp:list\nprefix:run/\nrestype:container"
+	key, _ := base64.StdEncoding.DecodeString(testAzureKey)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stringToSign))
+	want := "SharedKey acct:" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestAzureBackend_ReadSizeAndDigests(t *testing.T) {
+	var seen []*http.Request
+	server := newFakeAzure(t,
+		map[string]string{"run/r1.fq": "hello", "run/big.bam": "data"},
+		map[string]string{"run/r1.fq": "XUFAKrxLKna5cZ2REBfFkg=="},
+		&seen)
+	base := server.URL + "/devstoreaccount1"
+	backend := NewAzureBackend(AzureConfig{Endpoint: base, AccountName: "devstoreaccount1", AccountKey: testAzureKey})
+	ctx := context.Background()
+
+	content, err := backend.Read(ctx, base+"/inputs/run/r1.fq")
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if content != "hello" {
+		t.Errorf("Read() = %q, want %q", content, "hello")
+	}
+	if auth := seen[0].Header.Get("Authorization"); !strings.HasPrefix(auth, "SharedKey devstoreaccount1:") {
+		t.Errorf("request not signed with the shared key: Authorization = %q", auth)
+	}
+
+	digests, err := backend.GetContentDigests(ctx, base+"/inputs/run/r1.fq")
+	if err != nil {
+		t.Fatalf("GetContentDigests() unexpected error: %v", err)
+	}
+	if digests != (ports.FileDigests{MD5: "5d41402abc4b2a76b9719d911017c592"}) {
+		t.Errorf("GetContentDigests() = %+v", digests)
+	}
+	if _, err := backend.GetContentDigests(ctx, base+"/inputs/run/big.bam"); !errors.Is(err, ports.ErrHashUnavailable) {
+		t.Errorf("GetContentDigests() without Content-MD5 error = %v, want ErrHashUnavailable", err)
+	}
+
+	for _, path := range []string{base + "/inputs/run/missing", base + "/other/run/r1.fq"} {
+		if _, err := backend.GetSize(ctx, path); !errors.Is(err, ports.ErrFileNotFound) {
+			t.Errorf("GetSize(%q) error = %v, want ErrFileNotFound", path, err)
+		}
+	}
+}
+
+func TestAzureBackend_ReadRange(t *testing.T) {
+	var seen []*http.Request
+	server := newFakeAzure(t, map[string]string{"run/stderr": "0123456789"}, nil, &seen)
+	base := server.URL + "/devstoreaccount1"
+	backend := NewAzureBackend(AzureConfig{Endpoint: base, AccountName: "devstoreaccount1", AccountKey: testAzureKey})
+	ctx := context.Background()
+
+	data, err := backend.ReadRange(ctx, base+"/inputs/run/stderr", 6, 100)
+	if err != nil {
+		t.Fatalf("ReadRange() unexpected error: %v", err)
+	}
+	if string(data) != "6789" {
+		t.Errorf("ReadRange() = %q, want %q", data, "6789")
+	}
+	if rng := seen[0].Header.Get("x-ms-range"); rng != "bytes=6-105" {
+		t.Errorf("x-ms-range = %q, want %q", rng, "bytes=6-105")
+	}
+	if data, err := backend.ReadRange(ctx, base+"/inputs/run/stderr", 10, 5); err != nil || len(data) != 0 {
+		t.Errorf("ReadRange() past the end = %q, %v, want nothing", data, err)
+	}
+}
+
+func TestAzureBackend_Delete(t *testing.T) {
+	var seen []*http.Request
+	blobs := map[string]string{"run/call-A/tmp.bam": "bam", "run/out.vcf": "vcf"}
+	server := newFakeAzure(t, blobs, nil, &seen)
+	base := server.URL + "/devstoreaccount1"
+	backend := NewAzureBackend(AzureConfig{Endpoint: base, AccountName: "devstoreaccount1", AccountKey: testAzureKey})
+	ctx := context.Background()
+
+	if err := backend.Delete(ctx, base+"/inputs/run/call-A/tmp.bam"); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if _, ok := blobs["run/call-A/tmp.bam"]; ok || len(blobs) != 1 {
+		t.Errorf("blobs after Delete() = %v, want only run/out.vcf", blobs)
+	}
+	if auth := seen[0].Header.Get("Authorization"); !strings.HasPrefix(auth, "SharedKey devstoreaccount1:") {
+		t.Errorf("Authorization = %q, want a shared key signature", auth)
+	}
+	if err := backend.Delete(ctx, base+"/inputs/run/call-A/tmp.bam"); err != nil {
+		t.Errorf("Delete() of a blob already gone = %v, want nil", err)
+	}
+	if err := backend.Delete(ctx, base+"/other/run/out.vcf"); err != nil {
+		t.Errorf("Delete() in a missing container = %v, want nil", err)
+	}
+}
+
+func TestAzureBackend_SASToken(t *testing.T) {
+	var seen []*http.Request
+	server := newFakeAzure(t, map[string]string{"r1.fq": "hello"}, nil, &seen)
+	base := server.URL + "/devstoreaccount1"
+	ctx := context.Background()
+
+	backend := NewAzureBackend(AzureConfig{Endpoint: base, AccountName: "devstoreaccount1", AccountKey: testAzureKey, SASToken: "?sv=2021-08-06&sig=configured"})
+	if _, err := backend.GetSize(ctx, base+"/inputs/r1.fq"); err != nil {
+		t.Fatalf("GetSize() unexpected error: %v", err)
+	}
+	if _, err := backend.GetSize(ctx, base+"/inputs/r1.fq?sv=2021-08-06&sig=inline"); err != nil {
+		t.Fatalf("GetSize() unexpected error: %v", err)
+	}
+
+	for i, want := range []string{"configured", "inline"} {
+		if got := seen[i].URL.Query().Get("sig"); got != want {
+			t.Errorf("request %d sig = %q, want %q", i, got, want)
+		}
+		if auth := seen[i].Header.Get("Authorization"); auth != "" {
+			t.Errorf("request %d with a SAS token was also signed: %q", i, auth)
+		}
+	}
+}
+
+func TestAzureBackend_List(t *testing.T) {
+	var seen []*http.Request
+	server := newFakeAzure(t, map[string]string{
+		"run/stdout":            "hello",
+		"run/call-A/output.txt": "out",
+	}, nil, &seen)
+	base := server.URL + "/devstoreaccount1"
+	backend := NewAzureBackend(AzureConfig{Endpoint: base, AccountName: "devstoreaccount1"})
+	ctx := context.Background()
+
+	entries, err := backend.List(ctx, base+"/inputs/run")
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	want := []ports.FileEntry{
+		{Path: base + "/inputs/run/call-A", IsDir: true},
+		{Path: base + "/inputs/run/stdout", Size: 5},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("List() = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("List()[%d] = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+
+	if _, err := backend.List(ctx, base+"/inputs/nothing-here"); !errors.Is(err, ports.ErrFileNotFound) {
+		t.Errorf("List() of empty prefix error = %v, want ErrFileNotFound", err)
+	}
+}
+
+func TestAzureBackend_ListKeepsSASToken(t *testing.T) {
+	var seen []*http.Request
+	server := newFakeAzure(t, map[string]string{
+		"run/stdout":            "hello",
+		"run/call-A/output.txt": "out",
+	}, nil, &seen)
+	base := server.URL + "/devstoreaccount1"
+	backend := NewAzureBackend(AzureConfig{Endpoint: base, AccountName: "devstoreaccount1"})
+	ctx := context.Background()
+	const sas = "?sv=2021-08-06&sig=inline"
+
+	entries, err := backend.List(ctx, base+"/inputs/run"+sas)
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	want := []ports.FileEntry{
+		{Path: base + "/inputs/run/call-A" + sas, IsDir: true},
+		{Path: base + "/inputs/run/stdout" + sas, Size: 5},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("List() = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("List()[%d] = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+
+	// The entries are usable as they are: each request carries the token.
+	seen = nil
+	if _, err := backend.List(ctx, entries[0].Path); err != nil {
+		t.Errorf("List() of a listed directory unexpected error: %v", err)
+	}
+	if size, err := backend.GetSize(ctx, entries[1].Path); err != nil || size != 5 {
+		t.Errorf("GetSize() of a listed blob = %d, %v, want 5", size, err)
+	}
+	for i, r := range seen {
+		if got := r.URL.Query().Get("sig"); got != "inline" {
+			t.Errorf("request %d sig = %q, want %q", i, got, "inline")
+		}
+	}
+}
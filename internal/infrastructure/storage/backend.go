@@ -1,5 +1,34 @@
 package storage
 
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
 // maxFileSize is the maximum file size for Read operations (1 MB).
 // This limit applies to string-based reads to prevent memory issues.
 const maxFileSize = 1 * 1024 * 1024
+
+// Timeouts of the HTTP clients the REST backends use. They bound each step
+// up to the response headers, so an unreachable or stalled server fails the
+// request instead of hanging it. Reading the body has no deadline: a ranged
+// read of a large file may take long, and is bounded by the caller's
+// context instead.
+const (
+	httpDialTimeout           = 30 * time.Second
+	httpTLSHandshakeTimeout   = 10 * time.Second
+	httpResponseHeaderTimeout = 60 * time.Second
+)
+
+// newHTTPClient returns the client a REST backend sends its requests with.
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   httpDialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = httpTLSHandshakeTimeout
+	transport.ResponseHeaderTimeout = httpResponseHeaderTimeout
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,346 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// drsSignedURLTTL is how long an access URL fetched through an access_id is
+// trusted. Servers hand out signed URLs that expire, usually after an hour or
+// more, without saying when.
+const drsSignedURLTTL = 15 * time.Minute
+
+// DRSConfig configures the GA4GH Data Repository Service server drs:// URIs
+// are resolved through.
+type DRSConfig struct {
+	// Server is the base URL of the DRS server ("https://drs.example.org"),
+	// under which /ga4gh/drs/v1 is served. It is required for compact
+	// identifier URIs (drs://dg.4503:abc); hostname-based URIs
+	// (drs://drs.example.org/abc) use their own host when it is empty.
+	Server string
+	// Token is a bearer token sent to the DRS server, as Terra and AnVIL
+	// require. It is only sent to Server, never to the host a URI names.
+	Token string
+}
+
+// DRSBackend implements StorageBackend for drs:// URIs. It resolves each
+// object through a DRS server to an access URL and hands the operation to
+// the backend that handles that URL, caching resolutions in a DRSCache.
+type DRSBackend struct {
+	cfg      DRSConfig
+	client   *http.Client
+	cache    *DRSCache
+	backends []ports.StorageBackend
+	now      func() time.Time
+	saveWarn sync.Once // the cache failing to save is reported once
+}
+
+// NewDRSBackend creates a DRSBackend delegating to backends, in order. A nil
+// cache keeps resolutions in memory only.
+func NewDRSBackend(cfg DRSConfig, cache *DRSCache, backends ...ports.StorageBackend) *DRSBackend {
+	if cache == nil {
+		cache = NewDRSCacheWithPath("")
+	}
+	cfg.Server = strings.TrimSuffix(cfg.Server, "/")
+	return &DRSBackend{cfg: cfg, client: newHTTPClient(), cache: cache, backends: backends, now: time.Now}
+}
+
+// CanHandle returns true for paths starting with "drs://".
+func (d *DRSBackend) CanHandle(path string) bool {
+	return strings.HasPrefix(path, "drs://")
+}
+
+// Read reads the content of a DRS object as a string.
+// Enforces maxFileSize limit to prevent memory issues.
+func (d *DRSBackend) Read(ctx context.Context, path string) (string, error) {
+	var content string
+	err := d.delegate(ctx, path, func(backend ports.StorageBackend, res DRSResolution) error {
+		var err error
+		content, err = backend.Read(ctx, res.URL)
+		return err
+	})
+	return content, err
+}
+
+// ReadBytes reads the content of a DRS object as raw bytes.
+// No size limit is enforced, suitable for binary files like ZIP dependencies.
+func (d *DRSBackend) ReadBytes(ctx context.Context, path string) ([]byte, error) {
+	var data []byte
+	err := d.delegate(ctx, path, func(backend ports.StorageBackend, res DRSResolution) error {
+		var err error
+		data, err = backend.ReadBytes(ctx, res.URL)
+		return err
+	})
+	return data, err
+}
+
+// ReadRange reads part of a DRS object through its access URL.
+func (d *DRSBackend) ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error) {
+	var data []byte
+	err := d.delegate(ctx, path, func(backend ports.StorageBackend, res DRSResolution) error {
+		var err error
+		data, err = backend.ReadRange(ctx, res.URL, offset, length)
+		return err
+	})
+	return data, err
+}
+
+// GetSize returns the size of a DRS object, as its access URL reports it.
+func (d *DRSBackend) GetSize(ctx context.Context, path string) (int64, error) {
+	var size int64
+	err := d.delegate(ctx, path, func(backend ports.StorageBackend, res DRSResolution) error {
+		var err error
+		size, err = backend.GetSize(ctx, res.URL)
+		return err
+	})
+	return size, err
+}
+
+// GetContentDigests returns a DRS object's digests as its access URL reports
+// them, completed with the checksums the DRS server recorded. A signed HTTPS
+// URL usually reports none, so the server's are then all there is.
+func (d *DRSBackend) GetContentDigests(ctx context.Context, path string) (ports.FileDigests, error) {
+	var digests ports.FileDigests
+	err := d.delegate(ctx, path, func(backend ports.StorageBackend, res DRSResolution) error {
+		var err error
+		digests, err = backend.GetContentDigests(ctx, res.URL)
+		if err != nil && !errors.Is(err, ports.ErrHashUnavailable) {
+			return err
+		}
+		if digests.MD5 == "" {
+			digests.MD5 = res.MD5
+		}
+		if digests.CRC32C == "" {
+			digests.CRC32C = res.CRC32C
+		}
+		if digests == (ports.FileDigests{}) {
+			return fmt.Errorf("%w: %s", ports.ErrHashUnavailable, path)
+		}
+		return nil
+	})
+	return digests, err
+}
+
+// List is not supported: DRS addresses single objects, not directories.
+func (d *DRSBackend) List(_ context.Context, prefix string) ([]ports.FileEntry, error) {
+	return nil, fmt.Errorf("listing is not supported for DRS URIs: %s", prefix)
+}
+
+// Delete is not supported: a DRS object belongs to the server that serves
+// it, not to the workflows that read it.
+func (d *DRSBackend) Delete(_ context.Context, path string) error {
+	return fmt.Errorf("deleting is not supported for DRS URIs: %s", path)
+}
+
+// delegate resolves path and runs op against the backend of its access URL.
+// A cached access URL that fails may have been revoked or have expired
+// early, so the object is resolved afresh and op tried once more.
+func (d *DRSBackend) delegate(ctx context.Context, path string, op func(ports.StorageBackend, DRSResolution) error) error {
+	res, cached := d.cache.Get(path)
+	if !cached {
+		var err error
+		if res, err = d.resolve(ctx, path); err != nil {
+			return err
+		}
+	}
+
+	backend := d.backendFor(res.URL)
+	if backend == nil {
+		return fmt.Errorf("no storage backend found for %s (resolved from %s)", res.URL, path)
+	}
+	err := op(backend, res)
+	if err == nil || !cached || errors.Is(err, ports.ErrHashUnavailable) {
+		return err
+	}
+
+	d.saved(d.cache.Delete(path))
+	if res, err = d.resolve(ctx, path); err != nil {
+		return err
+	}
+	if backend = d.backendFor(res.URL); backend == nil {
+		return fmt.Errorf("no storage backend found for %s (resolved from %s)", res.URL, path)
+	}
+	return op(backend, res)
+}
+
+// drsObject is the part of a DRS object the backend uses.
+type drsObject struct {
+	ID        string `json:"id"`
+	Size      int64  `json:"size"`
+	Checksums []struct {
+		Checksum string `json:"checksum"`
+		Type     string `json:"type"`
+	} `json:"checksums"`
+	AccessMethods []struct {
+		Type      string        `json:"type"`
+		AccessURL *drsAccessURL `json:"access_url"`
+		AccessID  string        `json:"access_id"`
+	} `json:"access_methods"`
+}
+
+// drsAccessURL is where an object can be read from. Headers, when present,
+// must accompany every request, which the delegate backends cannot do.
+type drsAccessURL struct {
+	URL     string   `json:"url"`
+	Headers []string `json:"headers"`
+}
+
+// resolve asks the DRS server for an object and picks the first access
+// method a backend can read: a direct access URL if there is one, otherwise
+// one fetched through an access_id. The result is cached.
+func (d *DRSBackend) resolve(ctx context.Context, path string) (DRSResolution, error) {
+	server, id, err := d.parseURI(path)
+	if err != nil {
+		return DRSResolution{}, err
+	}
+	objectURL := server + "/ga4gh/drs/v1/objects/" + url.PathEscape(id)
+
+	var obj drsObject
+	if err := d.getJSON(ctx, objectURL, path, &obj); err != nil {
+		return DRSResolution{}, err
+	}
+
+	res := DRSResolution{Size: obj.Size}
+	for _, c := range obj.Checksums {
+		switch strings.ToLower(c.Type) {
+		case "md5":
+			res.MD5 = strings.ToLower(c.Checksum)
+		case "crc32c":
+			res.CRC32C = crc32cHexToBase64(c.Checksum)
+		}
+	}
+
+	for _, m := range obj.AccessMethods {
+		if m.AccessURL != nil && len(m.AccessURL.Headers) == 0 && d.backendFor(m.AccessURL.URL) != nil {
+			res.URL = m.AccessURL.URL
+			break
+		}
+	}
+	for _, m := range obj.AccessMethods {
+		if res.URL != "" {
+			break
+		}
+		if m.AccessID == "" {
+			continue
+		}
+		var access drsAccessURL
+		if err := d.getJSON(ctx, objectURL+"/access/"+url.PathEscape(m.AccessID), path, &access); err != nil {
+			return DRSResolution{}, err
+		}
+		if len(access.Headers) == 0 && d.backendFor(access.URL) != nil {
+			res.URL = access.URL
+			res.ExpiresAt = d.now().Add(drsSignedURLTTL).Unix()
+		}
+	}
+	if res.URL == "" {
+		return DRSResolution{}, fmt.Errorf("no readable access method for %s", path)
+	}
+
+	d.saved(d.cache.Set(path, res))
+	return res, nil
+}
+
+// saved reports a failure to save the resolution cache. It does not fail
+// the operation: the resolution is still cached for the rest of the run.
+func (d *DRSBackend) saved(err error) {
+	if err != nil {
+		d.saveWarn.Do(func() {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		})
+	}
+}
+
+// getJSON fetches a DRS endpoint and decodes its response into v.
+func (d *DRSBackend) getJSON(ctx context.Context, endpoint, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("invalid DRS request for %s: %w", path, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	// The token belongs to the configured server: a hostname-based URI from
+	// an inputs file must not be able to send it to a host of its choosing.
+	if d.cfg.Token != "" && d.cfg.Server != "" && strings.HasPrefix(endpoint, d.cfg.Server+"/") {
+		req.Header.Set("Authorization", "Bearer "+d.cfg.Token)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ports.ErrFileNotFound, path)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("failed to resolve %s: DRS server returned %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode DRS response for %s: %w", path, err)
+	}
+	return nil
+}
+
+// parseURI returns the server to ask about a drs:// URI and the object ID.
+// A configured server answers for every URI; otherwise a hostname-based URI
+// (drs://host/id) is sent to its host. A compact identifier
+// (drs://prefix:accession) is passed whole as the ID.
+func (d *DRSBackend) parseURI(path string) (server, id string, err error) {
+	rest := strings.TrimPrefix(path, "drs://")
+	host, object, hostBased := strings.Cut(rest, "/")
+	switch {
+	case hostBased && host != "" && object != "":
+		id, server = object, "https://"+host
+	case !hostBased && strings.Contains(rest, ":"):
+		id = rest
+	default:
+		return "", "", fmt.Errorf("invalid DRS URI: %s", path)
+	}
+
+	if d.cfg.Server != "" {
+		server = d.cfg.Server
+	}
+	if server == "" {
+		return "", "", fmt.Errorf("no DRS server configured to resolve %s", path)
+	}
+	return server, id, nil
+}
+
+// backendFor returns the backend that handles an access URL, or nil.
+func (d *DRSBackend) backendFor(accessURL string) ports.StorageBackend {
+	if accessURL == "" {
+		return nil
+	}
+	for _, backend := range d.backends {
+		if backend.CanHandle(accessURL) {
+			return backend
+		}
+	}
+	return nil
+}
+
+// crc32cHexToBase64 converts a DRS crc32c checksum, hex as the DRS schema
+// specifies, to the base64 form ports.FileDigests uses. Anything else is
+// dropped.
+func crc32cHexToBase64(checksum string) string {
+	sum, err := hex.DecodeString(checksum)
+	if err != nil || len(sum) != 4 {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(sum)
+}
+
+// Ensure DRSBackend implements StorageBackend at compile time.
+var _ ports.StorageBackend = (*DRSBackend)(nil)
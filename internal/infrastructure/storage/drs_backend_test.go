@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// newFakeDRS serves DRS objects by ID, counting the requests it gets. The
+// object "signed" is only readable through an access_id.
+func newFakeDRS(t *testing.T, requests *int, token string) *httptest.Server {
+	t.Helper()
+	objects := map[string]string{
+		"abc": `{"id": "abc", "size": 5,
+			"checksums": [{"type": "md5", "checksum": "5D41402ABC4B2A76B9719D911017C592"}, {"type": "crc32c", "checksum": "9a71bb4c"}],
+			"access_methods": [
+				{"type": "https", "access_url": {"url": "https://signed.example.com/abc", "headers": ["Authorization: Bearer x"]}},
+				{"type": "gs", "access_url": {"url": "mock://bucket/abc"}}
+			]}`,
+		"dg.4503:xyz": `{"id": "dg.4503:xyz", "size": 3, "access_methods": [{"type": "gs", "access_url": {"url": "mock://bucket/xyz"}}]}`,
+		"signed":      `{"id": "signed", "size": 4, "access_methods": [{"type": "https", "access_id": "az-1"}]}`,
+		"unreadable":  `{"id": "unreadable", "access_methods": [{"type": "ftp", "access_url": {"url": "ftp://host/file"}}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/ga4gh/drs/v1/objects/")
+		if id == "signed/access/az-1" {
+			_, _ = fmt.Fprint(w, `{"url": "mock://signed/file?sig=123"}`)
+			return
+		}
+		obj, ok := objects[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"msg": "not found", "status_code": 404}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, obj)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// drsDelegate is a backend for mock:// URLs holding the given contents.
+func drsDelegate(files map[string]string, digests ports.FileDigests) *mockStorageBackend {
+	return &mockStorageBackend{
+		canHandleFunc: func(path string) bool { return strings.HasPrefix(path, "mock://") },
+		readFunc: func(_ context.Context, path string) (string, error) {
+			content, ok := files[path]
+			if !ok {
+				return "", fmt.Errorf("%w: %s", ports.ErrFileNotFound, path)
+			}
+			return content, nil
+		},
+		getSizeFunc: func(_ context.Context, path string) (int64, error) {
+			content, ok := files[path]
+			if !ok {
+				return 0, fmt.Errorf("%w: %s", ports.ErrFileNotFound, path)
+			}
+			return int64(len(content)), nil
+		},
+		getDigestsFunc: func(_ context.Context, path string) (ports.FileDigests, error) {
+			if digests == (ports.FileDigests{}) {
+				return digests, fmt.Errorf("%w: %s", ports.ErrHashUnavailable, path)
+			}
+			return digests, nil
+		},
+	}
+}
+
+func TestDRSBackend_ParseURI(t *testing.T) {
+	tests := []struct {
+		name       string
+		server     string
+		path       string
+		wantServer string
+		wantID     string
+		wantErr    bool
+	}{
+		{"hostname-based", "", "drs://drs.example.org/abc", "https://drs.example.org", "abc", false},
+		{"hostname-based with configured server", "https://resolver.example.org", "drs://drs.example.org/abc", "https://resolver.example.org", "abc", false},
+		{"compact identifier", "https://resolver.example.org", "drs://dg.4503:xyz", "https://resolver.example.org", "dg.4503:xyz", false},
+		{"compact identifier without server", "", "drs://dg.4503:xyz", "", "", true},
+		{"no object", "", "drs://drs.example.org/", "", "", true},
+		{"neither form", "", "drs://abc", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := NewDRSBackend(DRSConfig{Server: tt.server}, nil)
+			server, id, err := backend.parseURI(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseURI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if server != tt.wantServer || id != tt.wantID {
+				t.Errorf("parseURI() = %q, %q, want %q, %q", server, id, tt.wantServer, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestDRSBackend_DelegatesToAccessURL(t *testing.T) {
+	var requests int
+	server := newFakeDRS(t, &requests, "tok")
+	delegate := drsDelegate(map[string]string{"mock://bucket/abc": "hello", "mock://bucket/xyz": "abc"}, ports.FileDigests{})
+	backend := NewDRSBackend(DRSConfig{Server: server.URL, Token: "tok"}, nil, delegate)
+	ctx := context.Background()
+
+	// The first access method needs headers, so the second is used.
+	content, err := backend.Read(ctx, "drs://drs.example.org/abc")
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if content != "hello" {
+		t.Errorf("Read() = %q, want %q", content, "hello")
+	}
+
+	size, err := backend.GetSize(ctx, "drs://drs.example.org/abc")
+	if err != nil {
+		t.Fatalf("GetSize() unexpected error: %v", err)
+	}
+	if size != 5 {
+		t.Errorf("GetSize() = %d, want 5", size)
+	}
+	if requests != 1 {
+		t.Errorf("DRS server got %d requests, want 1 (the resolution is cached)", requests)
+	}
+
+	// The delegate has no digests, so the server's checksums are used.
+	digests, err := backend.GetContentDigests(ctx, "drs://drs.example.org/abc")
+	if err != nil {
+		t.Fatalf("GetContentDigests() unexpected error: %v", err)
+	}
+	want := ports.FileDigests{MD5: "5d41402abc4b2a76b9719d911017c592", CRC32C: "mnG7TA=="}
+	if digests != want {
+		t.Errorf("GetContentDigests() = %+v, want %+v", digests, want)
+	}
+	if _, err := backend.GetContentDigests(ctx, "drs://dg.4503:xyz"); !errors.Is(err, ports.ErrHashUnavailable) {
+		t.Errorf("GetContentDigests() without checksums error = %v, want ErrHashUnavailable", err)
+	}
+}
+
+func TestDRSBackend_ResolutionErrors(t *testing.T) {
+	var requests int
+	server := newFakeDRS(t, &requests, "")
+	backend := NewDRSBackend(DRSConfig{Server: server.URL}, nil, drsDelegate(nil, ports.FileDigests{}))
+	ctx := context.Background()
+
+	if _, err := backend.GetSize(ctx, "drs://drs.example.org/missing"); !errors.Is(err, ports.ErrFileNotFound) {
+		t.Errorf("GetSize() of unknown object error = %v, want ErrFileNotFound", err)
+	}
+	if _, err := backend.GetSize(ctx, "drs://drs.example.org/unreadable"); err == nil || !strings.Contains(err.Error(), "no readable access method") {
+		t.Errorf("GetSize() without a usable access method error = %v", err)
+	}
+	if _, err := NewDRSBackend(DRSConfig{Server: server.URL, Token: "wrong"}, nil).GetSize(ctx, "drs://drs.example.org/abc"); err == nil {
+		t.Error("GetSize() expected an error from a rejected token")
+	}
+}
+
+func TestDRSBackend_TokenOnlyForConfiguredServer(t *testing.T) {
+	var auth []string
+	foreign := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		_, _ = fmt.Fprint(w, `{"id": "abc", "size": 5, "access_methods": [{"type": "gs", "access_url": {"url": "mock://bucket/abc"}}]}`)
+	}))
+	t.Cleanup(foreign.Close)
+
+	// Without a server, a hostname-based URI is resolved against its own
+	// host, which must not receive the token.
+	backend := NewDRSBackend(DRSConfig{Token: "secret"}, nil, drsDelegate(map[string]string{"mock://bucket/abc": "hello"}, ports.FileDigests{}))
+	backend.client = foreign.Client()
+	host := strings.TrimPrefix(foreign.URL, "https://")
+	if _, err := backend.GetSize(context.Background(), "drs://"+host+"/abc"); err != nil {
+		t.Fatalf("GetSize() unexpected error: %v", err)
+	}
+	if len(auth) != 1 || auth[0] != "" {
+		t.Errorf("foreign host got Authorization headers %q, want none", auth)
+	}
+}
+
+func TestDRSBackend_SignedAccessURL(t *testing.T) {
+	var requests int
+	server := newFakeDRS(t, &requests, "")
+	now := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	cache := NewDRSCacheWithPath(filepath.Join(t.TempDir(), "drs.json"))
+	cache.now = func() time.Time { return now }
+	backend := NewDRSBackend(DRSConfig{Server: server.URL}, cache,
+		drsDelegate(map[string]string{"mock://signed/file?sig=123": "data"}, ports.FileDigests{}))
+	backend.now = cache.now
+
+	if size, err := backend.GetSize(context.Background(), "drs://drs.example.org/signed"); err != nil || size != 4 {
+		t.Fatalf("GetSize() = %d, %v, want 4", size, err)
+	}
+	res, ok := cache.Get("drs://drs.example.org/signed")
+	if !ok || res.URL != "mock://signed/file?sig=123" {
+		t.Fatalf("cached resolution = %+v, %v", res, ok)
+	}
+
+	// A signed URL outlives its TTL in neither the cache nor its file.
+	now = now.Add(drsSignedURLTTL)
+	if _, ok := cache.Get("drs://drs.example.org/signed"); ok {
+		t.Error("expired resolution still returned")
+	}
+	reloaded := NewDRSCacheWithPath(cache.path)
+	reloaded.now = cache.now
+	if _, ok := reloaded.Get("drs://drs.example.org/signed"); ok {
+		t.Error("expired resolution still returned after reload")
+	}
+}
+
+func TestDRSBackend_RetriesStaleResolution(t *testing.T) {
+	var requests int
+	server := newFakeDRS(t, &requests, "")
+	cache := NewDRSCacheWithPath("")
+	cache.Set("drs://drs.example.org/abc", DRSResolution{URL: "mock://moved/abc"})
+	backend := NewDRSBackend(DRSConfig{Server: server.URL}, cache,
+		drsDelegate(map[string]string{"mock://bucket/abc": "hello"}, ports.FileDigests{}))
+
+	content, err := backend.Read(context.Background(), "drs://drs.example.org/abc")
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if content != "hello" || requests != 1 {
+		t.Errorf("Read() = %q after %d resolutions, want %q after 1", content, requests, "hello")
+	}
+	if res, _ := cache.Get("drs://drs.example.org/abc"); res.URL != "mock://bucket/abc" {
+		t.Errorf("cache holds %q, want the fresh resolution", res.URL)
+	}
+}
+
+func TestDRSCache_Save(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pumbaa")
+	cache := NewDRSCacheWithPath(filepath.Join(dir, "drs.json"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			uri := fmt.Sprintf("drs://drs.example.org/%d", i)
+			if err := cache.Set(uri, DRSResolution{URL: "https://signed.example.org/" + uri + "?sig=1"}); err != nil {
+				t.Errorf("Set(%s) unexpected error: %v", uri, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	reloaded := NewDRSCacheWithPath(cache.path)
+	for i := 0; i < 20; i++ {
+		if _, ok := reloaded.Get(fmt.Sprintf("drs://drs.example.org/%d", i)); !ok {
+			t.Errorf("resolution %d missing after reload", i)
+		}
+	}
+
+	// Signed URLs are credentials: only the owner may read them.
+	for path, want := range map[string]os.FileMode{cache.path: 0600, dir: 0700} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != want {
+			t.Errorf("mode of %s = %v, want %v", path, info.Mode().Perm(), want)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("cache directory holds %d entries, want only the cache file", len(entries))
+	}
+
+	// A cache that cannot be written reports it, and keeps the resolution.
+	blocked := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocked, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	unwritable := NewDRSCacheWithPath(filepath.Join(blocked, "drs.json"))
+	if err := unwritable.Set("drs://drs.example.org/abc", DRSResolution{URL: "mock://bucket/abc"}); err == nil {
+		t.Error("Set() expected an error saving under a file")
+	}
+	if _, ok := unwritable.Get("drs://drs.example.org/abc"); !ok {
+		t.Error("resolution dropped after a failed save")
+	}
+}
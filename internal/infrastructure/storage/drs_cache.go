@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DRSResolution is what a DRS server said about an object: where to read it
+// and the size and checksums it recorded.
+type DRSResolution struct {
+	// URL is the access URL reads are delegated to (gs://, s3://, https://).
+	URL    string `json:"url"`
+	Size   int64  `json:"size,omitempty"`
+	MD5    string `json:"md5,omitempty"`
+	CRC32C string `json:"crc32c,omitempty"`
+	// ExpiresAt is when a signed access URL stops working, in Unix seconds;
+	// zero for a URL that does not expire.
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+// DRSCache provides thread-safe caching of DRS resolutions with persistent
+// storage, so a drs:// input is resolved once rather than on every run.
+// Like FileSizeCache it loads from disk on first access and saves after
+// modifications. Expired resolutions are treated as absent.
+//
+// Signed access URLs are bearer credentials, so the file is readable by its
+// owner only and is replaced atomically, never rewritten in place.
+type DRSCache struct {
+	mu          sync.RWMutex
+	saveMu      sync.Mutex // serializes writes to path
+	path        string
+	resolutions map[string]DRSResolution
+	loaded      bool
+	dirty       bool
+	now         func() time.Time
+}
+
+// NewDRSCache creates a DRSCache using the default cache path.
+func NewDRSCache() *DRSCache {
+	return NewDRSCacheWithPath(defaultDRSCachePath())
+}
+
+// NewDRSCacheWithPath creates a DRSCache with a custom cache path. An empty
+// path keeps the cache in memory.
+func NewDRSCacheWithPath(path string) *DRSCache {
+	return &DRSCache{
+		path:        path,
+		resolutions: make(map[string]DRSResolution),
+		now:         time.Now,
+	}
+}
+
+// Load hydrates the cache from persistent storage.
+func (c *DRSCache) Load() error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var resolutions map[string]DRSResolution
+	if err := json.Unmarshal(data, &resolutions); err != nil {
+		return err
+	}
+	if resolutions == nil {
+		resolutions = make(map[string]DRSResolution)
+	}
+
+	c.mu.Lock()
+	c.resolutions = resolutions
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Save persists the cache to storage if it has been modified. Expired
+// resolutions are dropped on the way. Saves that overlap are folded into
+// one: a save waiting for another to finish writes nothing if the first
+// already wrote its changes.
+func (c *DRSCache) Save() error {
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+
+	c.mu.Lock()
+	if !c.dirty || c.path == "" {
+		c.dirty = false
+		c.mu.Unlock()
+		return nil
+	}
+	snapshot := make(map[string]DRSResolution, len(c.resolutions))
+	for uri, res := range c.resolutions {
+		if !c.expired(res) {
+			snapshot[uri] = res
+		}
+	}
+	data, err := json.Marshal(snapshot)
+	if err == nil {
+		c.dirty = false
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(c.path, data); err != nil {
+		c.mu.Lock()
+		c.dirty = true
+		c.mu.Unlock()
+		return fmt.Errorf("failed to save DRS cache %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it over path, so readers never see a partial file. The file is created
+// with mode 0600 and its directory, when missing, with 0700.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get returns the cached resolution of a DRS URI, unless it has expired.
+func (c *DRSCache) Get(uri string) (DRSResolution, bool) {
+	c.ensureLoaded()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res, ok := c.resolutions[uri]
+	if !ok || c.expired(res) {
+		return DRSResolution{}, false
+	}
+	return res, true
+}
+
+// Set caches the resolution of a DRS URI and saves the cache. The
+// resolution is cached in memory even when saving fails.
+func (c *DRSCache) Set(uri string, res DRSResolution) error {
+	c.ensureLoaded()
+	c.mu.Lock()
+	c.resolutions[uri] = res
+	c.dirty = true
+	c.mu.Unlock()
+
+	return c.Save()
+}
+
+// Delete forgets the resolution of a DRS URI, such as one whose access URL
+// stopped working, and saves the cache.
+func (c *DRSCache) Delete(uri string) error {
+	c.ensureLoaded()
+	c.mu.Lock()
+	if _, ok := c.resolutions[uri]; ok {
+		delete(c.resolutions, uri)
+		c.dirty = true
+	}
+	c.mu.Unlock()
+
+	return c.Save()
+}
+
+// expired reports whether a resolution's access URL has stopped working.
+// The caller holds the lock.
+func (c *DRSCache) expired(res DRSResolution) bool {
+	return res.ExpiresAt != 0 && c.now().Unix() >= res.ExpiresAt
+}
+
+// ensureLoaded performs lazy loading of the cache from disk.
+func (c *DRSCache) ensureLoaded() {
+	c.mu.Lock()
+	if c.loaded {
+		c.mu.Unlock()
+		return
+	}
+	c.loaded = true
+	c.mu.Unlock()
+
+	_ = c.Load()
+}
+
+func defaultDRSCachePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".pumbaa", "drs_resolutions.json")
+}
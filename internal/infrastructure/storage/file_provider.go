@@ -13,15 +13,30 @@ type FileProvider struct {
 	backends []ports.StorageBackend
 }
 
-// NewFileProvider creates a FileProvider with default backends (GCS and Local).
-// The order matters: backends are checked in order, with Local as the fallback.
-func NewFileProvider() *FileProvider {
-	return &FileProvider{
-		backends: []ports.StorageBackend{
-			NewGCSBackend(),
-			NewLocalBackend(), // Local is the fallback (last)
-		},
+// Config configures the storage backends that need it. The zero value uses
+// each backend's defaults.
+type Config struct {
+	S3    S3Config
+	Azure AzureConfig
+	DRS   DRSConfig
+}
+
+// NewFileProvider creates a FileProvider with default backends (GCS, S3, DRS,
+// Azure Blob, HTTP and Local). The order matters: backends are checked in
+// order, so Azure claims its https:// URLs before HTTP, and Local is the
+// fallback. DRS hands resolved access URLs to the remote backends.
+func NewFileProvider(cfg Config) *FileProvider {
+	remote := []ports.StorageBackend{
+		NewGCSBackend(),
+		NewS3Backend(cfg.S3),
+		NewAzureBackend(cfg.Azure),
+		NewHTTPBackend(),
 	}
+	drs := NewDRSBackend(cfg.DRS, NewDRSCache(), remote...)
+
+	backends := append([]ports.StorageBackend{drs}, remote...)
+	backends = append(backends, NewLocalBackend()) // Local is the fallback (last)
+	return &FileProvider{backends: backends}
 }
 
 // NewFileProviderWithBackends creates a FileProvider with custom backends.
@@ -72,5 +87,40 @@ func (f *FileProvider) GetContentDigests(ctx context.Context, path string) (port
 	return ports.FileDigests{}, fmt.Errorf("no storage backend found for path: %s", path)
 }
 
-// Ensure FileProvider implements the domain interface at compile time.
-var _ ports.FileProvider = (*FileProvider)(nil)
+// List returns a directory's entries by delegating to the appropriate backend.
+func (f *FileProvider) List(ctx context.Context, prefix string) ([]ports.FileEntry, error) {
+	for _, backend := range f.backends {
+		if backend.CanHandle(prefix) {
+			return backend.List(ctx, prefix)
+		}
+	}
+	return nil, fmt.Errorf("no storage backend found for path: %s", prefix)
+}
+
+// ReadRange reads part of a file by delegating to the appropriate backend.
+func (f *FileProvider) ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error) {
+	for _, backend := range f.backends {
+		if backend.CanHandle(path) {
+			return backend.ReadRange(ctx, path, offset, length)
+		}
+	}
+	return nil, fmt.Errorf("no storage backend found for path: %s", path)
+}
+
+// Delete removes a file by delegating to the appropriate backend.
+func (f *FileProvider) Delete(ctx context.Context, path string) error {
+	for _, backend := range f.backends {
+		if backend.CanHandle(path) {
+			return backend.Delete(ctx, path)
+		}
+	}
+	return fmt.Errorf("no storage backend found for path: %s", path)
+}
+
+// Ensure FileProvider implements the domain interfaces at compile time.
+var (
+	_ ports.FileProvider = (*FileProvider)(nil)
+	_ ports.FileLister   = (*FileProvider)(nil)
+	_ ports.RangeReader  = (*FileProvider)(nil)
+	_ ports.FileDeleter  = (*FileProvider)(nil)
+)
@@ -109,10 +109,10 @@ func TestFileProvider_NoBackendFound(t *testing.T) {
 }
 
 func TestNewFileProvider_DefaultBackends(t *testing.T) {
-	fp := NewFileProvider()
+	fp := NewFileProvider(Config{})
 
-	if len(fp.backends) != 2 {
-		t.Errorf("NewFileProvider() should have 2 backends, got %d", len(fp.backends))
+	if len(fp.backends) != 6 {
+		t.Errorf("NewFileProvider() should have 6 backends, got %d", len(fp.backends))
 	}
 }
 
@@ -123,6 +123,9 @@ type mockStorageBackend struct {
 	readBytesFunc      func(ctx context.Context, path string) ([]byte, error)
 	getSizeFunc        func(ctx context.Context, path string) (int64, error)
 	getDigestsFunc func(ctx context.Context, path string) (ports.FileDigests, error)
+	listFunc       func(ctx context.Context, prefix string) ([]ports.FileEntry, error)
+	readRangeFunc  func(ctx context.Context, path string, offset, length int64) ([]byte, error)
+	deleteFunc     func(ctx context.Context, path string) error
 }
 
 func (m *mockStorageBackend) CanHandle(path string) bool {
@@ -160,4 +163,25 @@ func (m *mockStorageBackend) GetContentDigests(ctx context.Context, path string)
 	return ports.FileDigests{}, nil
 }
 
+func (m *mockStorageBackend) List(ctx context.Context, prefix string) ([]ports.FileEntry, error) {
+	if m.listFunc != nil {
+		return m.listFunc(ctx, prefix)
+	}
+	return nil, nil
+}
+
+func (m *mockStorageBackend) ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error) {
+	if m.readRangeFunc != nil {
+		return m.readRangeFunc(ctx, path, offset, length)
+	}
+	return nil, nil
+}
+
+func (m *mockStorageBackend) Delete(ctx context.Context, path string) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, path)
+	}
+	return nil
+}
+
 var _ ports.StorageBackend = (*mockStorageBackend)(nil)
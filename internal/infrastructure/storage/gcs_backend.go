@@ -6,10 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"sort"
 	"strings"
 	"sync"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
 
 	"github.com/lmtani/pumbaa/internal/application/ports"
 )
@@ -165,6 +169,109 @@ func (g *GCSBackend) GetContentDigests(ctx context.Context, path string) (ports.
 	return digests, nil
 }
 
+// List returns the objects and common prefixes directly under a gs:// prefix,
+// using "/" as the delimiter so only one level is listed. A bucket root
+// ("gs://bucket") is a valid prefix.
+func (g *GCSBackend) List(ctx context.Context, prefix string) ([]ports.FileEntry, error) {
+	bucket, dir, _ := strings.Cut(strings.TrimPrefix(prefix, "gs://"), "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("invalid GCS path: %s", prefix)
+	}
+	if dir != "" && !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+
+	client, err := g.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ports.FileEntry
+	it := client.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: dir, Delimiter: "/"})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			if errors.Is(err, storage.ErrBucketNotExist) {
+				return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, prefix)
+			}
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		switch {
+		case attrs.Prefix != "":
+			entries = append(entries, ports.FileEntry{Path: "gs://" + bucket + "/" + strings.TrimSuffix(attrs.Prefix, "/"), IsDir: true})
+		case attrs.Name == dir:
+			// The placeholder object some tools create for a "folder".
+		default:
+			entries = append(entries, ports.FileEntry{Path: "gs://" + bucket + "/" + attrs.Name, Size: attrs.Size})
+		}
+	}
+	// Object stores have no empty directories: nothing under a prefix means
+	// the prefix is not there.
+	if len(entries) == 0 && dir != "" {
+		return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, prefix)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// ReadRange reads up to length bytes of a GCS object starting at offset,
+// downloading only that range.
+func (g *GCSBackend) ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error) {
+	if length <= 0 {
+		return nil, nil
+	}
+	bucket, object, err := g.parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := g.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := client.Bucket(bucket).Object(object).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		var apiErr *googleapi.Error
+		switch {
+		case errors.Is(err, storage.ErrObjectNotExist):
+			return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, path)
+		case errors.As(err, &apiErr) && apiErr.Code == http.StatusRequestedRangeNotSatisfiable:
+			// The range starts past the end of the object.
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open GCS object: %w", err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GCS object: %w", err)
+	}
+	return data, nil
+}
+
+// Delete removes a GCS object. An object already gone is not an error.
+func (g *GCSBackend) Delete(ctx context.Context, path string) error {
+	bucket, object, err := g.parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	client, err := g.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Bucket(bucket).Object(object).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("failed to delete GCS object: %w", err)
+	}
+	return nil
+}
+
 // parsePath extracts bucket and object from a gs:// path.
 func (g *GCSBackend) parsePath(path string) (bucket, object string, err error) {
 	cleanPath := strings.TrimPrefix(path, "gs://")
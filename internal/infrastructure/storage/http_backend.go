@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// HTTPBackend implements StorageBackend for plain http:// and https:// URLs,
+// the way Cromwell localizes HTTP inputs. It sits after the backends that own
+// particular hosts, such as Azure Blob, and is read-only: there is no
+// directory listing over HTTP.
+type HTTPBackend struct {
+	client *http.Client
+}
+
+// NewHTTPBackend creates a new HTTPBackend.
+func NewHTTPBackend() *HTTPBackend {
+	return &HTTPBackend{client: newHTTPClient()}
+}
+
+// CanHandle returns true for paths starting with "http://" or "https://".
+func (h *HTTPBackend) CanHandle(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// Read reads the content of a URL as a string.
+// Enforces maxFileSize limit to prevent memory issues. It asks for one byte
+// more than the limit with a range GET, so a large file is refused after a
+// single request without being downloaded.
+func (h *HTTPBackend) Read(ctx context.Context, path string) (string, error) {
+	resp, err := h.get(ctx, path, "bytes=0-"+strconv.Itoa(maxFileSize))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	size := resp.ContentLength
+	if resp.StatusCode == http.StatusPartialContent {
+		size = contentRangeTotal(resp.Header.Get("Content-Range"))
+	}
+	if size > maxFileSize {
+		return "", fmt.Errorf("file too large (%.2f MB > 1 MB limit)", float64(size)/(1024*1024))
+	}
+
+	// A server that ignores ranges and sends no length is cut off here.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(data) > maxFileSize {
+		return "", fmt.Errorf("file too large (> 1 MB limit)")
+	}
+	return string(data), nil
+}
+
+// ReadBytes reads the content of a URL as raw bytes.
+// No size limit is enforced, suitable for binary files like ZIP dependencies.
+func (h *HTTPBackend) ReadBytes(ctx context.Context, path string) ([]byte, error) {
+	resp, err := h.get(ctx, path, "")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// ReadRange reads up to length bytes of a URL's content starting at offset
+// with a range GET. A server that ignores the range sends the whole body,
+// which is then skipped through to the range.
+func (h *HTTPBackend) ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error) {
+	if length <= 0 {
+		return nil, nil
+	}
+	resp, err := h.get(ctx, path, fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body := io.Reader(resp.Body)
+	if resp.StatusCode == http.StatusOK && offset > 0 {
+		if _, err := io.CopyN(io.Discard, body, offset); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	data, err := io.ReadAll(io.LimitReader(body, length))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// GetSize returns the size of a URL's content from a HEAD request. Servers
+// that answer HEAD without a length are asked for the first byte instead,
+// and report the total in Content-Range.
+func (h *HTTPBackend) GetSize(ctx context.Context, path string) (int64, error) {
+	resp, err := h.head(ctx, path)
+	if err == nil && resp.ContentLength >= 0 {
+		return resp.ContentLength, nil
+	}
+	if errors.Is(err, ports.ErrFileNotFound) {
+		return 0, err
+	}
+
+	resp, err = h.get(ctx, path, "bytes=0-0")
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusPartialContent {
+		if total := contentRangeTotal(resp.Header.Get("Content-Range")); total >= 0 {
+			return total, nil
+		}
+	}
+	if resp.ContentLength >= 0 {
+		return resp.ContentLength, nil
+	}
+	return 0, fmt.Errorf("size of %s is unknown: the server sends no length", path)
+}
+
+// GetContentDigests returns the digests a server advertises in its response
+// headers: Content-MD5, or the x-goog-hash of Google Cloud Storage's public
+// URLs. Most servers advertise neither.
+func (h *HTTPBackend) GetContentDigests(ctx context.Context, path string) (ports.FileDigests, error) {
+	resp, err := h.head(ctx, path)
+	if err != nil {
+		return ports.FileDigests{}, err
+	}
+
+	digests := ports.FileDigests{MD5: contentMD5Hex(resp.Header.Get("Content-MD5"))}
+	for _, value := range resp.Header.Values("x-goog-hash") {
+		for _, part := range strings.Split(value, ",") {
+			name, hash, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch name {
+			case "md5":
+				digests.MD5 = contentMD5Hex(hash)
+			case "crc32c":
+				digests.CRC32C = hash
+			}
+		}
+	}
+	if digests == (ports.FileDigests{}) {
+		return digests, fmt.Errorf("%w: %s", ports.ErrHashUnavailable, path)
+	}
+	return digests, nil
+}
+
+// Delete is not supported: HTTP URLs are read-only.
+func (h *HTTPBackend) Delete(_ context.Context, path string) error {
+	return fmt.Errorf("deleting is not supported for HTTP URLs: %s", path)
+}
+
+// List is not supported: HTTP has no directory listing.
+func (h *HTTPBackend) List(_ context.Context, prefix string) ([]ports.FileEntry, error) {
+	return nil, fmt.Errorf("listing is not supported for HTTP URLs: %s", prefix)
+}
+
+// head sends a HEAD request and checks its status.
+func (h *HTTPBackend) head(ctx context.Context, path string) (*http.Response, error) {
+	resp, err := h.do(ctx, http.MethodHead, path, "")
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+// get sends a GET request, for a byte range when rng is set, and checks its
+// status. The caller closes the body.
+func (h *HTTPBackend) get(ctx context.Context, path, rng string) (*http.Response, error) {
+	return h.do(ctx, http.MethodGet, path, rng)
+}
+
+func (h *HTTPBackend) do(ctx context.Context, method, path, rng string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", path, err)
+	}
+	if rng != "" {
+		req.Header.Set("Range", rng)
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", path, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, path)
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// An empty file has no first byte, and a range past the end has
+		// none either; report it as empty content.
+		_ = resp.Body.Close()
+		resp.Body = http.NoBody
+		resp.ContentLength = 0
+		resp.StatusCode = http.StatusOK
+		return resp, nil
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("request to %s failed: %s", path, resp.Status)
+	}
+	return resp, nil
+}
+
+// contentRangeTotal returns the complete length from a Content-Range header
+// ("bytes 0-0/1234"), or -1 when it is absent or unknown ("*").
+func contentRangeTotal(header string) int64 {
+	_, total, ok := strings.Cut(header, "/")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// Ensure HTTPBackend implements StorageBackend at compile time.
+var _ ports.StorageBackend = (*HTTPBackend)(nil)
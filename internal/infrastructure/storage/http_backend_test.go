@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+func TestHTTPBackend_CanHandle(t *testing.T) {
+	backend := NewHTTPBackend()
+
+	tests := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{"https URL", "https://example.com/ref.fa", true},
+		{"http URL", "http://example.com/ref.fa", true},
+		{"GCS path", "gs://bucket/file.txt", false},
+		{"local path", "/home/user/file.txt", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backend.CanHandle(tt.path); got != tt.expected {
+				t.Errorf("CanHandle(%q) = %v, want %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+// newFakeHTTP serves files with range support through http.ServeContent.
+// When noHead is set it refuses HEAD, as some servers do.
+func newFakeHTTP(t *testing.T, files map[string]string, headers map[string]string, noHead bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if noHead && r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, strings.NewReader(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPBackend_ReadAndSize(t *testing.T) {
+	big := strings.Repeat("x", maxFileSize+10)
+	server := newFakeHTTP(t, map[string]string{"/small.txt": "hello", "/big.txt": big, "/empty.txt": ""}, nil, false)
+	backend := NewHTTPBackend()
+	ctx := context.Background()
+
+	content, err := backend.Read(ctx, server.URL+"/small.txt")
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if content != "hello" {
+		t.Errorf("Read() = %q, want %q", content, "hello")
+	}
+
+	if content, err := backend.Read(ctx, server.URL+"/empty.txt"); err != nil || content != "" {
+		t.Errorf("Read() of empty file = %q, %v", content, err)
+	}
+
+	if _, err := backend.Read(ctx, server.URL+"/big.txt"); err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("Read() of large file error = %v, want 'too large'", err)
+	}
+
+	data, err := backend.ReadBytes(ctx, server.URL+"/big.txt")
+	if err != nil {
+		t.Fatalf("ReadBytes() unexpected error: %v", err)
+	}
+	if len(data) != len(big) {
+		t.Errorf("ReadBytes() returned %d bytes, want %d", len(data), len(big))
+	}
+
+	size, err := backend.GetSize(ctx, server.URL+"/big.txt")
+	if err != nil {
+		t.Fatalf("GetSize() unexpected error: %v", err)
+	}
+	if size != int64(len(big)) {
+		t.Errorf("GetSize() = %d, want %d", size, len(big))
+	}
+
+	if _, err := backend.GetSize(ctx, server.URL+"/missing.txt"); !errors.Is(err, ports.ErrFileNotFound) {
+		t.Errorf("GetSize() of missing file error = %v, want ErrFileNotFound", err)
+	}
+	if _, err := backend.ReadBytes(ctx, server.URL+"/missing.txt"); !errors.Is(err, ports.ErrFileNotFound) {
+		t.Errorf("ReadBytes() of missing file error = %v, want ErrFileNotFound", err)
+	}
+}
+
+func TestHTTPBackend_ReadRange(t *testing.T) {
+	server := newFakeHTTP(t, map[string]string{"/log.txt": "0123456789"}, nil, false)
+	// ignoring answers every request with the whole body, as servers
+	// without range support do.
+	ignoring := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, "0123456789")
+	}))
+	t.Cleanup(ignoring.Close)
+	backend := NewHTTPBackend()
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		url    string
+		offset int64
+		length int64
+		want   string
+	}{
+		{"ranged", server.URL + "/log.txt", 2, 3, "234"},
+		{"across the end", server.URL + "/log.txt", 8, 10, "89"},
+		{"past the end", server.URL + "/log.txt", 20, 10, ""},
+		{"range ignored", ignoring.URL + "/log.txt", 2, 3, "234"},
+		{"range ignored, past the end", ignoring.URL + "/log.txt", 20, 3, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := backend.ReadRange(ctx, tt.url, tt.offset, tt.length)
+			if err != nil {
+				t.Fatalf("ReadRange() unexpected error: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("ReadRange() = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPBackend_GetSizeWithoutHead(t *testing.T) {
+	server := newFakeHTTP(t, map[string]string{"/ref.fa": "ACGTACGT"}, nil, true)
+	backend := NewHTTPBackend()
+
+	size, err := backend.GetSize(context.Background(), server.URL+"/ref.fa")
+	if err != nil {
+		t.Fatalf("GetSize() unexpected error: %v", err)
+	}
+	if size != 8 {
+		t.Errorf("GetSize() = %d, want 8", size)
+	}
+}
+
+func TestHTTPBackend_GetContentDigests(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    ports.FileDigests
+		wantErr error
+	}{
+		{"Content-MD5", map[string]string{"Content-MD5": "XUFAKrxLKna5cZ2REBfFkg=="}, ports.FileDigests{MD5: "5d41402abc4b2a76b9719d911017c592"}, nil},
+		{"x-goog-hash", map[string]string{"x-goog-hash": "crc32c=mnG7TA==,md5=XUFAKrxLKna5cZ2REBfFkg=="}, ports.FileDigests{MD5: "5d41402abc4b2a76b9719d911017c592", CRC32C: "mnG7TA=="}, nil},
+		{"no digest headers", nil, ports.FileDigests{}, ports.ErrHashUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeHTTP(t, map[string]string{"/f": "hello"}, tt.headers, false)
+			got, err := NewHTTPBackend().GetContentDigests(context.Background(), server.URL+"/f")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetContentDigests() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetContentDigests() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetContentDigests() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPBackend_List(t *testing.T) {
+	_, err := NewHTTPBackend().List(context.Background(), "https://example.com/dir")
+	if err == nil {
+		t.Fatal("List() expected an error for an HTTP URL")
+	}
+	if !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("List() error = %v, want 'not supported'", err)
+	}
+}
+
+func TestHTTPBackend_ClientTimeouts(t *testing.T) {
+	clients := map[string]*http.Client{
+		"http":  NewHTTPBackend().client,
+		"azure": NewAzureBackend(AzureConfig{}).client,
+		"drs":   NewDRSBackend(DRSConfig{}, nil).client,
+	}
+	for name, client := range clients {
+		transport, ok := client.Transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("%s: transport is %T, want *http.Transport", name, client.Transport)
+		}
+		if transport.DialContext == nil || transport.TLSHandshakeTimeout == 0 || transport.ResponseHeaderTimeout == 0 {
+			t.Errorf("%s: transport without dial, TLS handshake or response header timeouts", name)
+		}
+		if client.Timeout != 0 {
+			t.Errorf("%s: client Timeout = %v, want none so long ranged reads are bounded by their context", name, client.Timeout)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/lmtani/pumbaa/internal/application/ports"
@@ -95,6 +96,54 @@ func (l *LocalBackend) GetContentDigests(_ context.Context, path string) (ports.
 	}, nil
 }
 
+// List returns the files and subdirectories directly inside a local
+// directory. os.ReadDir already sorts them by name.
+func (l *LocalBackend) List(_ context.Context, prefix string) ([]ports.FileEntry, error) {
+	items, err := os.ReadDir(prefix)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, prefix)
+		}
+		return nil, fmt.Errorf("failed to list directory: %w", err)
+	}
+
+	entries := make([]ports.FileEntry, 0, len(items))
+	for _, item := range items {
+		entry := ports.FileEntry{Path: filepath.Join(prefix, item.Name()), IsDir: item.IsDir()}
+		if !item.IsDir() {
+			info, err := item.Info()
+			if err != nil {
+				// Removed between the listing and the stat.
+				continue
+			}
+			entry.Size = info.Size()
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ReadRange reads up to length bytes of a local file starting at offset.
+func (l *LocalBackend) ReadRange(_ context.Context, path string, offset, length int64) ([]byte, error) {
+	if length <= 0 {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, path)
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	data, err := io.ReadAll(io.NewSectionReader(f, offset, length))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
 // encodeCRC32C renders a Castagnoli checksum the way GCS reports it and
 // Cromwell stores it: base64 of the four bytes, big-endian.
 func encodeCRC32C(sum uint32) string {
@@ -105,3 +154,22 @@ func encodeCRC32C(sum uint32) string {
 
 // Ensure LocalBackend implements StorageBackend at compile time.
 var _ ports.StorageBackend = (*LocalBackend)(nil)
+
+// Delete removes a local file. Directories are refused: cleanup removes
+// files one by one, and a directory never stands for a single output.
+func (l *LocalBackend) Delete(_ context.Context, path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("refusing to delete a directory: %s", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
@@ -115,6 +115,43 @@ func TestLocalBackend_ReadBytes(t *testing.T) {
 	})
 }
 
+func TestLocalBackend_ReadRange(t *testing.T) {
+	backend := NewLocalBackend()
+	ctx := context.Background()
+	tmpFile := filepath.Join(t.TempDir(), "stderr")
+	if err := os.WriteFile(tmpFile, []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		offset int64
+		length int64
+		want   string
+	}{
+		{"start", 0, 4, "0123"},
+		{"middle", 4, 3, "456"},
+		{"across the end", 8, 10, "89"},
+		{"past the end", 20, 10, ""},
+		{"zero length", 2, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := backend.ReadRange(ctx, tmpFile, tt.offset, tt.length)
+			if err != nil {
+				t.Fatalf("ReadRange() unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ReadRange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := backend.ReadRange(ctx, "non-existent-file.txt", 0, 1); !errors.Is(err, ports.ErrFileNotFound) {
+		t.Errorf("ReadRange() of missing file error = %v, want ErrFileNotFound", err)
+	}
+}
+
 // The forecast compares a candidate file's MD5 against the hash Cromwell
 // recorded, so this must be a plain content MD5 in lowercase hex.
 func TestLocalBackendGetContentHash(t *testing.T) {
@@ -144,3 +181,57 @@ func TestLocalBackendGetContentHashMissingFile(t *testing.T) {
 		t.Errorf("GetContentDigests() error = %v, want ErrFileNotFound", err)
 	}
 }
+
+func TestLocalBackendList(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "b.fq"), []byte("ACGT"), 0o600); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "a"), 0o755); err != nil {
+		t.Fatalf("creating dir: %v", err)
+	}
+
+	got, err := NewLocalBackend().List(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	want := []ports.FileEntry{
+		{Path: filepath.Join(dir, "a"), IsDir: true},
+		{Path: filepath.Join(dir, "b.fq"), Size: 4},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("List() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if _, err := NewLocalBackend().List(context.Background(), filepath.Join(dir, "nope")); !errors.Is(err, ports.ErrFileNotFound) {
+		t.Errorf("List() of a missing directory error = %v, want ErrFileNotFound", err)
+	}
+}
+
+func TestLocalBackend_Delete(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tmp.bam")
+	if err := os.WriteFile(path, []byte("bam"), 0o600); err != nil {
+		t.Fatalf("writing file: %v", err)
+	}
+	backend := NewLocalBackend()
+	ctx := context.Background()
+
+	if err := backend.Delete(ctx, path); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file still exists after Delete(): %v", err)
+	}
+	if err := backend.Delete(ctx, path); err != nil {
+		t.Errorf("Delete() of a file already gone = %v, want nil", err)
+	}
+	if err := backend.Delete(ctx, dir); err == nil {
+		t.Error("Delete() of a directory succeeded, want an error")
+	}
+}
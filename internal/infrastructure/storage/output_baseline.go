@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// outputRecord is the on-disk form of a ports.OutputRecord.
+type outputRecord struct {
+	Size       int64     `json:"size"`
+	MD5        string    `json:"md5,omitempty"`
+	CRC32C     string    `json:"crc32c,omitempty"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// OutputBaseline keeps the digests of verified workflow outputs in a JSON
+// file. Unlike FileSizeCache it does not save after every change: a
+// verification records many files at once and saves when it is done.
+type OutputBaseline struct {
+	mu      sync.RWMutex
+	path    string
+	records map[string]outputRecord
+	loaded  bool
+	dirty   bool
+}
+
+// NewOutputBaseline creates an OutputBaseline using the default path.
+func NewOutputBaseline() *OutputBaseline {
+	return NewOutputBaselineWithPath(defaultOutputBaselinePath())
+}
+
+// NewOutputBaselineWithPath creates an OutputBaseline with a custom path. An
+// empty path keeps the baseline in memory.
+func NewOutputBaselineWithPath(path string) *OutputBaseline {
+	return &OutputBaseline{
+		path:    path,
+		records: make(map[string]outputRecord),
+	}
+}
+
+// Load hydrates the baseline from persistent storage.
+func (b *OutputBaseline) Load() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.load()
+}
+
+func (b *OutputBaseline) load() error {
+	b.loaded = true
+	if b.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var records map[string]outputRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return err
+	}
+	if records == nil {
+		records = make(map[string]outputRecord)
+	}
+	b.records = records
+	return nil
+}
+
+// Save persists the baseline to storage if it has been modified.
+func (b *OutputBaseline) Save() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.dirty || b.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(b.records, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(b.path, data, 0644); err != nil {
+		return err
+	}
+	b.dirty = false
+	return nil
+}
+
+// Get returns the record for a file path.
+// Automatically loads the baseline from disk on first access.
+func (b *OutputBaseline) Get(path string) (ports.OutputRecord, bool) {
+	b.ensureLoaded()
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	r, ok := b.records[path]
+	if !ok {
+		return ports.OutputRecord{}, false
+	}
+	return ports.OutputRecord{
+		Size:       r.Size,
+		Digests:    ports.FileDigests{MD5: r.MD5, CRC32C: r.CRC32C},
+		RecordedAt: r.RecordedAt,
+	}, true
+}
+
+// Set records a file path. The change is kept in memory until Save.
+func (b *OutputBaseline) Set(path string, record ports.OutputRecord) {
+	b.ensureLoaded()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.records[path] = outputRecord{
+		Size:       record.Size,
+		MD5:        record.Digests.MD5,
+		CRC32C:     record.Digests.CRC32C,
+		RecordedAt: record.RecordedAt,
+	}
+	b.dirty = true
+}
+
+// ensureLoaded performs lazy loading of the baseline from disk.
+func (b *OutputBaseline) ensureLoaded() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.loaded {
+		_ = b.load()
+	}
+}
+
+func defaultOutputBaselinePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".pumbaa", "output_baseline.json")
+}
+
+// Ensure OutputBaseline implements the port interface at compile time.
+var _ ports.OutputBaseline = (*OutputBaseline)(nil)
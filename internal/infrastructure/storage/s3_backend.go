@@ -0,0 +1,340 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// defaultS3Region is used when neither the configuration nor the AWS
+// environment names a region. S3-compatible stand-ins accept any region.
+const defaultS3Region = "us-east-1"
+
+// S3Config configures access to Amazon S3 or an S3-compatible store.
+// Every field is optional: by default the standard AWS credential chain
+// (environment, shared config and credentials files, instance role) is used.
+type S3Config struct {
+	// Endpoint points at an S3-compatible store instead of AWS, such as a
+	// local MinIO ("http://localhost:9000"). Requests then use path-style
+	// addressing, which such stores expect.
+	Endpoint string
+	// Region is the region of the buckets; it defaults to the AWS
+	// environment's, then to us-east-1.
+	Region string
+	// AccessKeyID and SecretAccessKey, when both set, are used instead of the
+	// credential chain. SessionToken goes with temporary credentials.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3Backend implements StorageBackend for Amazon S3 and S3-compatible stores.
+//
+// Like the GCS backend it builds one client on first use and shares it, so a
+// process that never reads s3:// paths never looks up credentials.
+type S3Backend struct {
+	cfg    S3Config
+	once   sync.Once
+	client *s3.Client
+	err    error
+}
+
+// NewS3Backend creates a new S3Backend.
+func NewS3Backend(cfg S3Config) *S3Backend {
+	return &S3Backend{cfg: cfg}
+}
+
+// clientFor returns the shared client, building it on first use.
+func (b *S3Backend) clientFor(ctx context.Context) (*s3.Client, error) {
+	b.once.Do(func() {
+		var opts []func(*awsconfig.LoadOptions) error
+		if b.cfg.Region != "" {
+			opts = append(opts, awsconfig.WithRegion(b.cfg.Region))
+		}
+		if b.cfg.AccessKeyID != "" && b.cfg.SecretAccessKey != "" {
+			opts = append(opts, awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+				b.cfg.AccessKeyID, b.cfg.SecretAccessKey, b.cfg.SessionToken)))
+		}
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			b.err = err
+			return
+		}
+		if awsCfg.Region == "" {
+			awsCfg.Region = defaultS3Region
+		}
+		b.client = s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+			if b.cfg.Endpoint != "" {
+				o.BaseEndpoint = aws.String(b.cfg.Endpoint)
+				o.UsePathStyle = true
+			}
+		})
+	})
+	if b.err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", b.err)
+	}
+	return b.client, nil
+}
+
+// CanHandle returns true for paths starting with "s3://".
+func (b *S3Backend) CanHandle(path string) bool {
+	return strings.HasPrefix(path, "s3://")
+}
+
+// Read reads the content of an S3 object as a string.
+// Enforces maxFileSize limit to prevent memory issues.
+func (b *S3Backend) Read(ctx context.Context, path string) (string, error) {
+	size, err := b.GetSize(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	if size > maxFileSize {
+		return "", fmt.Errorf("file too large (%.2f MB > 1 MB limit)", float64(size)/(1024*1024))
+	}
+
+	data, err := b.ReadBytes(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ReadBytes reads the content of an S3 object as raw bytes.
+// No size limit is enforced, suitable for binary files like ZIP dependencies.
+func (b *S3Backend) ReadBytes(ctx context.Context, path string) ([]byte, error) {
+	return b.get(ctx, path, "")
+}
+
+// ReadRange reads up to length bytes of an S3 object starting at offset,
+// downloading only that range.
+func (b *S3Backend) ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error) {
+	if length <= 0 {
+		return nil, nil
+	}
+	data, err := b.get(ctx, path, fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "InvalidRange" {
+		// The range starts past the end of the object.
+		return nil, nil
+	}
+	return data, err
+}
+
+// get downloads an object, or the part of it rng selects when not empty.
+func (b *S3Backend) get(ctx context.Context, path, rng string) ([]byte, error) {
+	bucket, key, err := b.parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := b.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}
+	if rng != "" {
+		input.Range = aws.String(rng)
+	}
+	out, err := client.GetObject(ctx, input)
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, path)
+		}
+		return nil, fmt.Errorf("failed to open S3 object: %w", err)
+	}
+	defer func() { _ = out.Body.Close() }()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read S3 object: %w", err)
+	}
+	return data, nil
+}
+
+// GetSize returns the size of an S3 object without reading its content.
+func (b *S3Backend) GetSize(ctx context.Context, path string) (int64, error) {
+	head, err := b.head(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+	return aws.ToInt64(head.ContentLength), nil
+}
+
+// GetContentDigests returns an S3 object's checksums, read from a HEAD request
+// so no data is transferred.
+//
+// The MD5 comes from the ETag, which is the content MD5 only for an object
+// uploaded in a single part and not encrypted with KMS; a multipart ETag
+// ("<hex>-<parts>") is a hash of hashes and is left out. The CRC32C comes from
+// the checksum S3 stores when the uploader asked for one, and is left out
+// when it covers parts rather than the whole object.
+func (b *S3Backend) GetContentDigests(ctx context.Context, path string) (ports.FileDigests, error) {
+	head, err := b.head(ctx, path)
+	if err != nil {
+		return ports.FileDigests{}, err
+	}
+
+	var digests ports.FileDigests
+	etag := strings.Trim(aws.ToString(head.ETag), `"`)
+	if isMD5Hex(etag) && head.ServerSideEncryption != types.ServerSideEncryptionAwsKms &&
+		head.ServerSideEncryption != types.ServerSideEncryptionAwsKmsDsse {
+		digests.MD5 = strings.ToLower(etag)
+	}
+	if crc := aws.ToString(head.ChecksumCRC32C); crc != "" && head.ChecksumType != types.ChecksumTypeComposite {
+		digests.CRC32C = crc
+	}
+	if digests == (ports.FileDigests{}) {
+		return digests, fmt.Errorf("%w: %s", ports.ErrHashUnavailable, path)
+	}
+	return digests, nil
+}
+
+// List returns the objects and common prefixes directly under an s3://
+// prefix, using "/" as the delimiter so only one level is listed. A bucket
+// root ("s3://bucket") is a valid prefix.
+func (b *S3Backend) List(ctx context.Context, prefix string) ([]ports.FileEntry, error) {
+	bucket, dir, _ := strings.Cut(strings.TrimPrefix(prefix, "s3://"), "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("invalid S3 path: %s", prefix)
+	}
+	if dir != "" && !strings.HasSuffix(dir, "/") {
+		dir += "/"
+	}
+
+	client, err := b.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []ports.FileEntry
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucket),
+		Prefix:    aws.String(dir),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			if isS3NotFound(err) {
+				return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, prefix)
+			}
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, p := range page.CommonPrefixes {
+			entries = append(entries, ports.FileEntry{Path: "s3://" + bucket + "/" + strings.TrimSuffix(aws.ToString(p.Prefix), "/"), IsDir: true})
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if key == dir {
+				// The placeholder object some tools create for a "folder".
+				continue
+			}
+			entries = append(entries, ports.FileEntry{Path: "s3://" + bucket + "/" + key, Size: aws.ToInt64(obj.Size)})
+		}
+	}
+	// Object stores have no empty directories: nothing under a prefix means
+	// the prefix is not there.
+	if len(entries) == 0 && dir != "" {
+		return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, prefix)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// Delete removes an S3 object. S3 itself treats deleting a missing key as
+// success.
+func (b *S3Backend) Delete(ctx context.Context, path string) error {
+	bucket, key, err := b.parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	client, err := b.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}); err != nil && !isS3NotFound(err) {
+		return fmt.Errorf("failed to delete S3 object: %w", err)
+	}
+	return nil
+}
+
+// head fetches an object's metadata, asking for its stored checksums.
+func (b *S3Backend) head(ctx context.Context, path string) (*s3.HeadObjectOutput, error) {
+	bucket, key, err := b.parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := b.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(bucket),
+		Key:          aws.String(key),
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ports.ErrFileNotFound, path)
+		}
+		return nil, fmt.Errorf("failed to get object metadata: %w", err)
+	}
+	return head, nil
+}
+
+// parsePath extracts bucket and key from an s3:// path.
+func (b *S3Backend) parsePath(path string) (bucket, key string, err error) {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(path, "s3://"), "/")
+	if !ok || bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid S3 path: %s", path)
+	}
+	return bucket, key, nil
+}
+
+// isS3NotFound reports whether err says the object or bucket does not exist.
+// A HEAD response has no body, so its 404 arrives as a bare NotFound.
+func isS3NotFound(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "NotFound", "NoSuchKey", "NoSuchBucket":
+		return true
+	}
+	return false
+}
+
+// isMD5Hex reports whether s is 32 hex digits.
+func isMD5Hex(s string) bool {
+	if len(s) != 32 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Ensure S3Backend implements StorageBackend at compile time.
+var _ ports.StorageBackend = (*S3Backend)(nil)
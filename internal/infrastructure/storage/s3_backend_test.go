@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+// fakeS3Object is an object served by newFakeS3.
+type fakeS3Object struct {
+	body   string
+	etag   string
+	crc32c string
+}
+
+// newFakeS3 starts a minimal path-style S3 stand-in serving objects of one
+// bucket: HEAD and (ranged) GET of an object, and ListObjectsV2 with a "/"
+// delimiter.
+func newFakeS3(t *testing.T, bucket string, objects map[string]fakeS3Object) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/")
+		name, key, _ := strings.Cut(path, "/")
+		if name != bucket {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `<Error><Code>NoSuchBucket</Code></Error>`)
+			return
+		}
+
+		if key == "" && r.URL.Query().Get("list-type") == "2" {
+			prefix := r.URL.Query().Get("prefix")
+			var b strings.Builder
+			b.WriteString(`<ListBucketResult><Name>` + bucket + `</Name><IsTruncated>false</IsTruncated>`)
+			seen := map[string]bool{}
+			for k, obj := range objects {
+				if !strings.HasPrefix(k, prefix) {
+					continue
+				}
+				if dir, _, nested := strings.Cut(strings.TrimPrefix(k, prefix), "/"); nested {
+					if !seen[dir] {
+						seen[dir] = true
+						b.WriteString(`<CommonPrefixes><Prefix>` + prefix + dir + `/</Prefix></CommonPrefixes>`)
+					}
+					continue
+				}
+				fmt.Fprintf(&b, `<Contents><Key>%s</Key><Size>%d</Size></Contents>`, k, len(obj.body))
+			}
+			b.WriteString(`</ListBucketResult>`)
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = fmt.Fprint(w, b.String())
+			return
+		}
+
+		obj, ok := objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method != http.MethodHead {
+				_, _ = fmt.Fprint(w, `<Error><Code>NoSuchKey</Code></Error>`)
+			}
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(obj.body)))
+		w.Header().Set("ETag", `"`+obj.etag+`"`)
+		// S3 sends no whole-object checksum with part of an object.
+		if obj.crc32c != "" && r.Header.Get("Range") == "" {
+			w.Header().Set("x-amz-checksum-crc32c", obj.crc32c)
+		}
+		if r.Method == http.MethodGet {
+			http.ServeContent(w, r, key, time.Time{}, strings.NewReader(obj.body))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestS3Backend(t *testing.T) *S3Backend {
+	t.Helper()
+	server := newFakeS3(t, "bucket", map[string]fakeS3Object{
+		"run/stdout":            {body: "hello", etag: "5d41402abc4b2a76b9719d911017c592", crc32c: "mnG7TA=="},
+		"run/multipart.bam":     {body: "data", etag: "0123456789abcdef0123456789abcdef-3"},
+		"run/call-A/output.txt": {body: "out", etag: "d4d8cd3f4e1e5a4f3ac5f7e0d8c9f0a1"},
+	})
+	return NewS3Backend(S3Config{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		AccessKeyID:     "test",
+		SecretAccessKey: "test",
+	})
+}
+
+func TestS3Backend_CanHandle(t *testing.T) {
+	backend := NewS3Backend(S3Config{})
+
+	tests := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{"S3 path", "s3://bucket/file.txt", true},
+		{"S3 path with nested object", "s3://bucket/folder/file.txt", true},
+		{"GCS path", "gs://bucket/file.txt", false},
+		{"local path", "/home/user/file.txt", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backend.CanHandle(tt.path); got != tt.expected {
+				t.Errorf("CanHandle(%q) = %v, want %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestS3Backend_ParsePath(t *testing.T) {
+	backend := NewS3Backend(S3Config{})
+
+	tests := []struct {
+		path    string
+		bucket  string
+		key     string
+		wantErr bool
+	}{
+		{"s3://my-bucket/path/to/file.txt", "my-bucket", "path/to/file.txt", false},
+		{"s3://bucket-only", "", "", true},
+		{"s3:///file.txt", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			bucket, key, err := backend.parsePath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if bucket != tt.bucket || key != tt.key {
+				t.Errorf("parsePath() = %q, %q, want %q, %q", bucket, key, tt.bucket, tt.key)
+			}
+		})
+	}
+}
+
+func TestS3Backend_ReadAndSize(t *testing.T) {
+	backend := newTestS3Backend(t)
+	ctx := context.Background()
+
+	size, err := backend.GetSize(ctx, "s3://bucket/run/stdout")
+	if err != nil {
+		t.Fatalf("GetSize() unexpected error: %v", err)
+	}
+	if size != 5 {
+		t.Errorf("GetSize() = %d, want 5", size)
+	}
+
+	content, err := backend.Read(ctx, "s3://bucket/run/stdout")
+	if err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+	if content != "hello" {
+		t.Errorf("Read() = %q, want %q", content, "hello")
+	}
+
+	for _, path := range []string{"s3://bucket/run/missing", "s3://other/run/stdout"} {
+		if _, err := backend.GetSize(ctx, path); !errors.Is(err, ports.ErrFileNotFound) {
+			t.Errorf("GetSize(%q) error = %v, want ErrFileNotFound", path, err)
+		}
+		if _, err := backend.ReadBytes(ctx, path); !errors.Is(err, ports.ErrFileNotFound) {
+			t.Errorf("ReadBytes(%q) error = %v, want ErrFileNotFound", path, err)
+		}
+	}
+}
+
+func TestS3Backend_ReadRange(t *testing.T) {
+	backend := newTestS3Backend(t)
+	ctx := context.Background()
+
+	data, err := backend.ReadRange(ctx, "s3://bucket/run/stdout", 1, 3)
+	if err != nil {
+		t.Fatalf("ReadRange() unexpected error: %v", err)
+	}
+	if string(data) != "ell" {
+		t.Errorf("ReadRange() = %q, want %q", data, "ell")
+	}
+	if data, err := backend.ReadRange(ctx, "s3://bucket/run/stdout", 3, 10); err != nil || string(data) != "lo" {
+		t.Errorf("ReadRange() across the end = %q, %v, want %q", data, err, "lo")
+	}
+	if _, err := backend.ReadRange(ctx, "s3://bucket/run/missing", 0, 10); !errors.Is(err, ports.ErrFileNotFound) {
+		t.Errorf("ReadRange() of missing object error = %v, want ErrFileNotFound", err)
+	}
+}
+
+func TestS3Backend_GetContentDigests(t *testing.T) {
+	backend := newTestS3Backend(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    ports.FileDigests
+		wantErr error
+	}{
+		{"single-part ETag and CRC32C", "s3://bucket/run/stdout", ports.FileDigests{MD5: "5d41402abc4b2a76b9719d911017c592", CRC32C: "mnG7TA=="}, nil},
+		{"single-part ETag only", "s3://bucket/run/call-A/output.txt", ports.FileDigests{MD5: "d4d8cd3f4e1e5a4f3ac5f7e0d8c9f0a1"}, nil},
+		{"multipart ETag", "s3://bucket/run/multipart.bam", ports.FileDigests{}, ports.ErrHashUnavailable},
+		{"missing object", "s3://bucket/run/missing", ports.FileDigests{}, ports.ErrFileNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := backend.GetContentDigests(ctx, tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetContentDigests() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetContentDigests() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetContentDigests() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestS3Backend_List(t *testing.T) {
+	backend := newTestS3Backend(t)
+	ctx := context.Background()
+
+	entries, err := backend.List(ctx, "s3://bucket/run")
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	want := []ports.FileEntry{
+		{Path: "s3://bucket/run/call-A", IsDir: true},
+		{Path: "s3://bucket/run/multipart.bam", Size: 4},
+		{Path: "s3://bucket/run/stdout", Size: 5},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("List() = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("List()[%d] = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+
+	if _, err := backend.List(ctx, "s3://bucket/nothing-here"); !errors.Is(err, ports.ErrFileNotFound) {
+		t.Errorf("List() of empty prefix error = %v, want ErrFileNotFound", err)
+	}
+}
@@ -0,0 +1,59 @@
+package templates
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+)
+
+//go:embed docs.html
+var docsTemplate string
+
+// docsPageData is what the documentation page template is filled with.
+type docsPageData struct {
+	Title   string
+	Home    string
+	Body    template.HTML
+	Mermaid bool
+}
+
+// DocsRenderer converts Markdown documentation pages to HTML with GitHub
+// flavoured Markdown (tables included). It implements ports.DocsHTMLRenderer.
+type DocsRenderer struct {
+	markdown goldmark.Markdown
+	page     *template.Template
+}
+
+// NewDocsRenderer creates a new documentation page renderer.
+func NewDocsRenderer() *DocsRenderer {
+	return &DocsRenderer{
+		markdown: goldmark.New(goldmark.WithExtensions(extension.GFM)),
+		page:     template.Must(template.New("docs").Parse(docsTemplate)),
+	}
+}
+
+func (r *DocsRenderer) RenderHTML(page ports.DocsPageData) ([]byte, error) {
+	var body bytes.Buffer
+	if err := r.markdown.Convert(page.Markdown, &body); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	err := r.page.Execute(&out, docsPageData{
+		Title: page.Title,
+		Home:  page.IndexLink,
+		// The Markdown is generated from the WDL index, and goldmark escapes
+		// raw HTML in it unless told otherwise.
+		Body:    template.HTML(body.String()), //nolint:gosec // see above
+		Mermaid: page.Mermaid,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -2,11 +2,16 @@
 package wdlindexer
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/lmtani/pumbaa/internal/domain/wdlindex"
 	"github.com/lmtani/pumbaa/pkg/wdl"
@@ -17,116 +22,228 @@ import (
 type Indexer struct {
 	index     *wdlindex.Index
 	indexPath string
+	stats     wdlindex.UpdateStats
+
+	// search is built from index on the first search.
+	searchOnce sync.Once
+	search     *wdlindex.SearchIndex
 }
 
-// NewIndexer creates an indexer, loading from cache if available or building a new index.
-func NewIndexer(directory, indexPath string, forceRebuild bool) (*Indexer, error) {
+// NewIndexer creates an indexer over one or more root directories. The cached
+// index is brought up to date incrementally: only new and changed files are
+// parsed, and deleted ones dropped. forceRebuild ignores the cache and parses
+// every file again.
+//
+// Besides the .wdl files under the roots, local files they import are indexed
+// too. A file that fails to parse does not fail the index; its error is kept
+// in the index (see wdlindex.Index.Errors).
+func NewIndexer(roots []string, indexPath string, forceRebuild bool) (*Indexer, error) {
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no WDL directory to index")
+	}
+	absRoots := make([]string, 0, len(roots))
+	for _, root := range roots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve directory %s: %w", root, err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return nil, fmt.Errorf("WDL directory %s: %w", root, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("WDL directory %s is not a directory", root)
+		}
+		absRoots = append(absRoots, abs)
+	}
+
 	indexer := &Indexer{indexPath: indexPath}
 
-	// Try loading from cache if not forcing rebuild
+	var cached *wdlindex.Index
 	if !forceRebuild {
-		cached, err := indexer.loadFromCache()
-		if err == nil && cached != nil && cached.Directory == directory {
-			indexer.index = cached
-			return indexer, nil
+		// A missing, unreadable or outdated cache just means parsing everything.
+		if idx, err := indexer.loadFromCache(); err == nil && idx.Version == wdlindex.IndexVersion && idx.Files != nil {
+			cached = idx
 		}
 	}
 
-	// Build new index
-	if err := indexer.buildIndex(directory); err != nil {
+	changed, err := indexer.update(absRoots, cached)
+	if err != nil {
 		return nil, err
 	}
 
-	// Save to cache
-	if err := indexer.saveToCache(); err != nil {
-		// Non-fatal: log but continue
-		fmt.Fprintf(os.Stderr, "Warning: failed to save WDL index cache: %v\n", err)
+	if changed {
+		if err := indexer.saveToCache(); err != nil {
+			// Non-fatal: log but continue
+			fmt.Fprintf(os.Stderr, "Warning: failed to save WDL index cache: %v\n", err)
+		}
 	}
 
 	return indexer, nil
 }
 
-// buildIndex walks the directory and indexes all WDL files.
-func (i *Indexer) buildIndex(directory string) error {
-	absDir, err := filepath.Abs(directory)
-	if err != nil {
-		return fmt.Errorf("failed to resolve directory: %w", err)
-	}
-
-	i.index = wdlindex.NewIndex(absDir)
-
-	// Track which files have been indexed (to handle imports)
-	indexed := make(map[string]bool)
+// Stats reports how the index was brought up to date.
+func (i *Indexer) Stats() wdlindex.UpdateStats {
+	return i.stats
+}
 
-	// First pass: find all WDL files in directory
-	var wdlFiles []string
-	err = filepath.Walk(absDir, func(path string, info os.FileInfo, err error) error {
+// update builds the index over roots, reusing the entries of cached files that
+// did not change. It reports whether the index differs from the cache.
+func (i *Indexer) update(roots []string, cached *wdlindex.Index) (bool, error) {
+	i.index = wdlindex.NewIndex(roots...)
+	previous := map[string]*wdlindex.FileEntry{}
+	changed := cached == nil || !slices.Equal(cached.Roots, roots)
+	if cached != nil {
+		previous = cached.Files
+		i.index.IndexedAt = cached.IndexedAt
+	}
+
+	// Every file under a root is indexed before any import is followed, so
+	// a file that is both imported and under a root gets that root however
+	// the roots are ordered, and matches its cached entry.
+	type rootFile struct{ path, root string }
+	var files []rootFile
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil // Skip unreadable entries
+			}
+			if !d.IsDir() && strings.HasSuffix(path, ".wdl") {
+				files = append(files, rootFile{path: path, root: root})
+			}
+			return nil
+		})
 		if err != nil {
-			return nil // Skip errors
+			return false, fmt.Errorf("failed to walk directory %s: %w", root, err)
+		}
+	}
+	for _, f := range files {
+		if _, ok := i.index.Files[f.path]; ok {
+			continue // Under two nested roots: the first one wins.
 		}
-		if !info.IsDir() && strings.HasSuffix(path, ".wdl") {
-			wdlFiles = append(wdlFiles, path)
+		entry, entryChanged := i.indexFile(f.path, f.root, previous[f.path])
+		i.index.Files[f.path] = entry
+		if entryChanged {
+			changed = true
+		}
+	}
+	for _, f := range files {
+		if i.followImports(i.index.Files[f.path], previous) {
+			changed = true
 		}
-		return nil
-	})
-	if err != nil {
-		return fmt.Errorf("failed to walk directory: %w", err)
 	}
 
-	// Index each file and follow imports
-	for _, wdlFile := range wdlFiles {
-		if err := i.indexFile(wdlFile, indexed); err != nil {
-			// Log warning but continue
-			fmt.Fprintf(os.Stderr, "Warning: failed to index %s: %v\n", wdlFile, err)
+	for path := range previous {
+		if _, ok := i.index.Files[path]; !ok {
+			i.stats.Removed++
+			changed = true
 		}
 	}
 
-	return nil
+	if changed && cached != nil {
+		i.index.IndexedAt = time.Now()
+	}
+	i.index.Link()
+	return changed, nil
+}
+
+// visit indexes a file reached through an import, outside every root, then
+// the local files it imports, unless it is already indexed. It reports
+// whether the file's entry differs from the cached one.
+func (i *Indexer) visit(path string, previous map[string]*wdlindex.FileEntry) bool {
+	if _, ok := i.index.Files[path]; ok {
+		return false
+	}
+	entry, changed := i.indexFile(path, "", previous[path])
+	i.index.Files[path] = entry
+	if i.followImports(entry, previous) {
+		changed = true
+	}
+	return changed
+}
+
+// followImports resolves the local imports of an indexed file and visits the
+// files they name. It reports whether anything differs from the cache.
+func (i *Indexer) followImports(entry *wdlindex.FileEntry, previous map[string]*wdlindex.FileEntry) bool {
+	changed := false
+	baseDir := filepath.Dir(entry.Path)
+	for n := range entry.Imports {
+		imp := &entry.Imports[n]
+		// Resolved again every time: the imported file may have appeared or
+		// disappeared since the importing file was parsed.
+		resolved := resolveImportPath(imp.URI, baseDir)
+		if resolved != imp.Path {
+			imp.Path = resolved
+			changed = true
+		}
+		if resolved != "" && i.visit(resolved, previous) {
+			changed = true
+		}
+	}
+	return changed
 }
 
-// indexFile parses and indexes a single WDL file, following imports recursively.
-func (i *Indexer) indexFile(filePath string, indexed map[string]bool) error {
-	absPath, err := filepath.Abs(filePath)
+// indexFile returns the entry for one file: the cached one when the file did
+// not change, or a freshly parsed one.
+func (i *Indexer) indexFile(path, root string, prev *wdlindex.FileEntry) (*wdlindex.FileEntry, bool) {
+	entry := &wdlindex.FileEntry{Path: path, Root: root}
+
+	info, err := os.Stat(path)
 	if err != nil {
-		return err
+		entry.Error = err.Error()
+		i.stats.Parsed++
+		return entry, true
 	}
+	entry.ModTime = info.ModTime().UTC()
+	entry.Size = info.Size()
 
-	if indexed[absPath] {
-		return nil // Already indexed
+	if prev != nil && prev.Root == root && prev.Size == entry.Size && prev.ModTime.Equal(entry.ModTime) {
+		i.stats.Reused++
+		return prev, false
 	}
-	indexed[absPath] = true
 
-	doc, err := wdl.Parse(absPath)
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("parse error: %w", err)
+		entry.Error = err.Error()
+		i.stats.Parsed++
+		return entry, true
 	}
+	sum := sha256.Sum256(data)
+	entry.SHA256 = hex.EncodeToString(sum[:])
 
-	// Index tasks
-	for _, task := range doc.Tasks {
-		i.indexTask(task, absPath)
+	// Touched but not edited: keep what was parsed, with the new timestamp.
+	if prev != nil && prev.SHA256 == entry.SHA256 {
+		reused := *prev
+		reused.Root, reused.ModTime, reused.Size = root, entry.ModTime, entry.Size
+		i.stats.Reused++
+		return &reused, true
 	}
 
-	// Index workflow
-	if doc.Workflow != nil {
-		i.indexWorkflow(doc.Workflow, absPath)
+	i.stats.Parsed++
+	doc, err := wdl.ParseBytes(data)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry, true
 	}
 
-	// Follow imports recursively
-	baseDir := filepath.Dir(absPath)
 	for _, imp := range doc.Imports {
-		importPath := i.resolveImportPath(imp.URI, baseDir)
-		if importPath != "" {
-			if err := i.indexFile(importPath, indexed); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to index import %s: %v\n", imp.URI, err)
-			}
-		}
+		entry.Imports = append(entry.Imports, wdlindex.ImportRef{URI: imp.URI, Alias: imp.As})
 	}
-
-	return nil
+	for _, s := range doc.Structs {
+		entry.Structs = append(entry.Structs, indexStruct(s, path))
+	}
+	for _, task := range doc.Tasks {
+		entry.Tasks = append(entry.Tasks, indexTask(task, path))
+	}
+	if doc.Workflow != nil {
+		entry.Workflow = indexWorkflow(doc.Workflow, path)
+	}
+	return entry, true
 }
 
-// resolveImportPath resolves an import URI to an absolute path.
-func (i *Indexer) resolveImportPath(uri, baseDir string) string {
+// resolveImportPath resolves an import URI to an absolute path, or "" for a
+// remote import or a file that does not exist.
+func resolveImportPath(uri, baseDir string) string {
 	// Skip HTTP/HTTPS imports
 	if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
 		return ""
@@ -150,37 +267,76 @@ func (i *Indexer) resolveImportPath(uri, baseDir string) string {
 	return absPath
 }
 
-// indexTask extracts task information and adds to index.
-func (i *Indexer) indexTask(task *ast.Task, source string) {
+// declarations converts AST declarations to index declarations, documented
+// from parameterMeta.
+func declarations(decls []*ast.Declaration, parameterMeta map[string]any) []wdlindex.Declaration {
+	var out []wdlindex.Declaration
+	for _, d := range decls {
+		if d == nil || d.Type == nil {
+			continue
+		}
+		out = append(out, wdlindex.Declaration{
+			Name:        d.Name,
+			Type:        d.Type.String(),
+			Optional:    d.Type.Optional,
+			Default:     wdl.ExpressionText(d.Expression),
+			Description: wdl.ParameterMetaDescription(parameterMeta, d.Name),
+		})
+	}
+	return out
+}
+
+// metaStrings converts a meta section to strings, with values that are not
+// strings as JSON.
+func metaStrings(meta map[string]any) map[string]string {
+	if len(meta) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(meta))
+	for key, value := range meta {
+		if s, ok := value.(string); ok {
+			out[key] = s
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			data = []byte(fmt.Sprintf("%v", value))
+		}
+		out[key] = string(data)
+	}
+	return out
+}
+
+// indexStruct extracts a struct definition.
+func indexStruct(s *ast.Struct, source string) *wdlindex.IndexedStruct {
+	return &wdlindex.IndexedStruct{
+		Name:    s.Name,
+		Source:  source,
+		Members: declarations(s.Members, nil),
+	}
+}
+
+// indexTask extracts task information.
+func indexTask(task *ast.Task, source string) *wdlindex.IndexedTask {
 	indexed := &wdlindex.IndexedTask{
 		Name:    task.Name,
 		Source:  source,
 		Command: task.Command,
+		Inputs:  declarations(task.Inputs, task.ParameterMeta),
+		Outputs: declarations(task.Outputs, task.ParameterMeta),
 		Runtime: make(map[string]string),
+		Meta:    metaStrings(task.Meta),
 	}
 
-	// Extract inputs
-	for _, input := range task.Inputs {
-		indexed.Inputs = append(indexed.Inputs, wdlindex.Declaration{
-			Name:     input.Name,
-			Type:     input.Type.String(),
-			Optional: input.Type.Optional,
-		})
-	}
-
-	// Extract outputs
-	for _, output := range task.Outputs {
-		indexed.Outputs = append(indexed.Outputs, wdlindex.Declaration{
-			Name:     output.Name,
-			Type:     output.Type.String(),
-			Optional: output.Type.Optional,
-		})
-	}
-
-	// Extract runtime as strings
+	// Extract runtime as strings: literal values bare, anything else as the
+	// expression it is computed from.
 	for key, expr := range task.Runtime {
 		if lit, ok := expr.(*ast.Literal); ok {
 			indexed.Runtime[key] = fmt.Sprintf("%v", lit.Value)
+		} else if str, ok := expr.(*ast.StringLiteral); ok {
+			indexed.Runtime[key] = str.Value
+		} else {
+			indexed.Runtime[key] = wdl.ExpressionText(expr)
 		}
 	}
 
@@ -189,45 +345,49 @@ func (i *Indexer) indexTask(task *ast.Task, source string) {
 		indexed.Description = fmt.Sprintf("%v", desc)
 	}
 
-	i.index.Tasks[task.Name] = indexed
+	return indexed
 }
 
-// indexWorkflow extracts workflow information and adds to index.
-func (i *Indexer) indexWorkflow(wf *ast.Workflow, source string) {
+// indexWorkflow extracts workflow information.
+func indexWorkflow(wf *ast.Workflow, source string) *wdlindex.IndexedWorkflow {
 	indexed := &wdlindex.IndexedWorkflow{
-		Name:   wf.Name,
-		Source: source,
-	}
-
-	// Extract inputs
-	for _, input := range wf.Inputs {
-		indexed.Inputs = append(indexed.Inputs, wdlindex.Declaration{
-			Name:     input.Name,
-			Type:     input.Type.String(),
-			Optional: input.Type.Optional,
-		})
-	}
-
-	// Extract outputs
-	for _, output := range wf.Outputs {
-		indexed.Outputs = append(indexed.Outputs, wdlindex.Declaration{
-			Name:     output.Name,
-			Type:     output.Type.String(),
-			Optional: output.Type.Optional,
-		})
+		Name:    wf.Name,
+		Source:  source,
+		Inputs:  declarations(wf.Inputs, wf.ParameterMeta),
+		Outputs: declarations(wf.Outputs, wf.ParameterMeta),
+		Meta:    metaStrings(wf.Meta),
+	}
+
+	// Extract call targets, including calls nested in blocks
+	var walk func(body []ast.WorkflowElement)
+	walk = func(body []ast.WorkflowElement) {
+		for _, el := range body {
+			switch e := el.(type) {
+			case *ast.Call:
+				indexed.Calls = append(indexed.Calls, e.Target)
+			case *ast.Scatter:
+				walk(e.Body)
+			case *ast.Conditional:
+				walk(e.Body)
+			}
+		}
 	}
-
-	// Extract call targets
 	for _, call := range wf.Calls {
 		indexed.Calls = append(indexed.Calls, call.Target)
 	}
+	for _, s := range wf.Scatters {
+		walk(s.Body)
+	}
+	for _, c := range wf.Conditionals {
+		walk(c.Body)
+	}
 
 	// Extract description from meta
 	if desc, ok := wf.Meta["description"]; ok {
 		indexed.Description = fmt.Sprintf("%v", desc)
 	}
 
-	i.index.Workflows[wf.Name] = indexed
+	return indexed
 }
 
 // loadFromCache loads the index from the cache file.
@@ -270,45 +430,12 @@ func (i *Indexer) List() (*wdlindex.Index, error) {
 	return i.index, nil
 }
 
-// SearchTasks finds tasks matching the query (case-insensitive).
-func (i *Indexer) SearchTasks(query string) ([]*wdlindex.IndexedTask, error) {
-	var results []*wdlindex.IndexedTask
-	queryLower := strings.ToLower(query)
-
-	for _, task := range i.index.Tasks {
-		// Match name or command content
-		if strings.Contains(strings.ToLower(task.Name), queryLower) ||
-			strings.Contains(strings.ToLower(task.Command), queryLower) ||
-			strings.Contains(strings.ToLower(task.Description), queryLower) {
-			results = append(results, task)
-		}
-	}
-
-	return results, nil
-}
-
-// SearchWorkflows finds workflows matching the query (case-insensitive).
-func (i *Indexer) SearchWorkflows(query string) ([]*wdlindex.IndexedWorkflow, error) {
-	var results []*wdlindex.IndexedWorkflow
-	queryLower := strings.ToLower(query)
-
-	for _, wf := range i.index.Workflows {
-		// Match name, calls, or description
-		if strings.Contains(strings.ToLower(wf.Name), queryLower) ||
-			strings.Contains(strings.ToLower(wf.Description), queryLower) {
-			results = append(results, wf)
-			continue
-		}
-		// Check calls
-		for _, call := range wf.Calls {
-			if strings.Contains(strings.ToLower(call), queryLower) {
-				results = append(results, wf)
-				break
-			}
-		}
-	}
-
-	return results, nil
+// Search ranks the tasks, workflows and structs matching query, best first.
+func (i *Indexer) Search(query string, opts wdlindex.SearchOptions) ([]wdlindex.SearchHit, error) {
+	i.searchOnce.Do(func() {
+		i.search = wdlindex.NewSearchIndex(i.index)
+	})
+	return i.search.Search(query, opts), nil
 }
 
 // GetTask returns a specific task by name.
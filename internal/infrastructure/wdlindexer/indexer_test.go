@@ -2,7 +2,13 @@ package wdlindexer
 
 import (
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/lmtani/pumbaa/internal/domain/wdlindex"
 )
 
 func TestNewIndexer(t *testing.T) {
@@ -22,7 +28,7 @@ func TestNewIndexer(t *testing.T) {
 	_ = tmpFile.Close()
 
 	// Test indexer creation
-	indexer, err := NewIndexer(testDir, tmpFile.Name(), true)
+	indexer, err := NewIndexer([]string{testDir}, tmpFile.Name(), true)
 	if err != nil {
 		t.Fatalf("Failed to create indexer: %v", err)
 	}
@@ -44,7 +50,7 @@ func TestNewIndexer(t *testing.T) {
 	t.Logf("Indexed %d tasks, %d workflows", len(idx.Tasks), len(idx.Workflows))
 
 	// Test search
-	tasks, err := indexer.SearchTasks("hello")
+	tasks, err := indexer.Search("hello", wdlindex.SearchOptions{Kind: wdlindex.KindTask})
 	if err != nil {
 		t.Fatalf("Failed to search tasks: %v", err)
 	}
@@ -64,7 +70,7 @@ func TestNewIndexer(t *testing.T) {
 	}
 
 	// Test cache loading
-	indexer2, err := NewIndexer(testDir, tmpFile.Name(), false)
+	indexer2, err := NewIndexer([]string{testDir}, tmpFile.Name(), false)
 	if err != nil {
 		t.Fatalf("Failed to load from cache: %v", err)
 	}
@@ -85,18 +91,282 @@ func TestSearchCaseInsensitive(t *testing.T) {
 	defer func() { _ = os.Remove(tmpFile.Name()) }()
 	_ = tmpFile.Close()
 
-	indexer, err := NewIndexer(testDir, tmpFile.Name(), true)
+	indexer, err := NewIndexer([]string{testDir}, tmpFile.Name(), true)
 	if err != nil {
 		t.Fatalf("Failed to create indexer: %v", err)
 	}
 
 	// Search should be case-insensitive
-	tasksLower, _ := indexer.SearchTasks("hello")
-	tasksUpper, _ := indexer.SearchTasks("HELLO")
-	tasksMixed, _ := indexer.SearchTasks("HeLLo")
+	taskOnly := wdlindex.SearchOptions{Kind: wdlindex.KindTask}
+	tasksLower, _ := indexer.Search("hello", taskOnly)
+	tasksUpper, _ := indexer.Search("HELLO", taskOnly)
+	tasksMixed, _ := indexer.Search("HeLLo", taskOnly)
 
 	if len(tasksLower) != len(tasksUpper) || len(tasksUpper) != len(tasksMixed) {
 		t.Errorf("Case-insensitive search failed: lower=%d, upper=%d, mixed=%d",
 			len(tasksLower), len(tasksUpper), len(tasksMixed))
 	}
 }
+
+// writeWDL writes a WDL file under dir, creating parent directories.
+func writeWDL(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const structsWDL = `version 1.0
+
+struct Reads {
+    String name
+    File r1
+    File? r2
+}
+
+task Trim {
+    input {
+        Reads reads
+    }
+    command <<< trim ~{reads.r1} >>>
+}
+`
+
+func TestIndexerIncremental(t *testing.T) {
+	dir := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+	writeWDL(t, dir, "trim.wdl", structsWDL)
+	touched := writeWDL(t, dir, "align.wdl", "version 1.0\n\ntask Align {\n    command <<< bwa >>>\n}\n")
+	gone := writeWDL(t, dir, "old.wdl", "version 1.0\n\ntask Old {\n    command <<< old >>>\n}\n")
+
+	first, err := NewIndexer([]string{dir}, indexPath, false)
+	if err != nil {
+		t.Fatalf("NewIndexer() error = %v", err)
+	}
+	if got, want := first.Stats(), (wdlindex.UpdateStats{Parsed: 3}); got != want {
+		t.Errorf("first build stats = %+v, want %+v", got, want)
+	}
+
+	// Touch one file without editing it, delete one, add a broken one.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(touched, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	writeWDL(t, dir, "broken.wdl", "version 1.0\n\ntask Broken {\n")
+
+	second, err := NewIndexer([]string{dir}, indexPath, false)
+	if err != nil {
+		t.Fatalf("NewIndexer() error = %v", err)
+	}
+	if got, want := second.Stats(), (wdlindex.UpdateStats{Parsed: 1, Reused: 2, Removed: 1}); got != want {
+		t.Errorf("incremental stats = %+v, want %+v", got, want)
+	}
+
+	idx, _ := second.List()
+	if _, err := second.GetTask("Old"); err == nil {
+		t.Error("task of a deleted file is still indexed")
+	}
+	if idx.Structs["Reads"] == nil || len(idx.Structs["Reads"].Members) != 3 {
+		t.Errorf("struct Reads not indexed with its members: %+v", idx.Structs["Reads"])
+	}
+	errs := idx.Errors()
+	if len(errs) != 1 || !strings.HasSuffix(errs[0].Path, "broken.wdl") || errs[0].Message == "" {
+		t.Errorf("Errors() = %+v, want the broken file with a message", errs)
+	}
+
+	// Nothing changed since: every file comes from the cache.
+	third, err := NewIndexer([]string{dir}, indexPath, false)
+	if err != nil {
+		t.Fatalf("NewIndexer() error = %v", err)
+	}
+	if got, want := third.Stats(), (wdlindex.UpdateStats{Reused: 3}); got != want {
+		t.Errorf("unchanged stats = %+v, want %+v", got, want)
+	}
+
+	// A forced rebuild parses everything again.
+	rebuilt, err := NewIndexer([]string{dir}, indexPath, true)
+	if err != nil {
+		t.Fatalf("NewIndexer() error = %v", err)
+	}
+	if got, want := rebuilt.Stats(), (wdlindex.UpdateStats{Parsed: 3}); got != want {
+		t.Errorf("rebuild stats = %+v, want %+v", got, want)
+	}
+}
+
+func TestIndexerMultipleRoots(t *testing.T) {
+	library := t.TempDir()
+	pipelines := t.TempDir()
+	outside := t.TempDir()
+	writeWDL(t, library, "trim.wdl", structsWDL)
+	writeWDL(t, outside, "qc.wdl", "version 1.0\n\ntask QC {\n    command <<< qc >>>\n}\n")
+	main := writeWDL(t, pipelines, "main.wdl", `version 1.0
+
+import "`+filepath.Join(library, "trim.wdl")+`" as trim
+import "`+filepath.Join(outside, "qc.wdl")+`"
+import "https://example.com/remote.wdl"
+
+workflow Main {
+    call trim.Trim
+}
+`)
+
+	indexer, err := NewIndexer([]string{library, pipelines}, filepath.Join(t.TempDir(), "index.json"), false)
+	if err != nil {
+		t.Fatalf("NewIndexer() error = %v", err)
+	}
+	idx, _ := indexer.List()
+
+	if _, err := indexer.GetWorkflow("main"); err != nil {
+		t.Errorf("workflow of the second root not indexed: %v", err)
+	}
+	if _, err := indexer.GetTask("QC"); err != nil {
+		t.Errorf("task of an imported file outside the roots not indexed: %v", err)
+	}
+
+	entry := idx.Files[main]
+	if entry == nil || entry.Root != pipelines {
+		t.Fatalf("entry for main.wdl = %+v, want one under root %s", entry, pipelines)
+	}
+	if len(entry.Imports) != 3 || entry.Imports[0].Alias != "trim" || entry.Imports[0].Path != filepath.Join(library, "trim.wdl") || entry.Imports[2].Path != "" {
+		t.Errorf("imports = %+v", entry.Imports)
+	}
+	if qc := idx.Files[filepath.Join(outside, "qc.wdl")]; qc == nil || qc.Root != "" {
+		t.Errorf("imported file entry = %+v, want no root", qc)
+	}
+	if trim := idx.Files[filepath.Join(library, "trim.wdl")]; trim == nil || trim.Root != library {
+		t.Errorf("library file entry = %+v, want root %s", trim, library)
+	}
+
+	if _, err := NewIndexer([]string{library, filepath.Join(library, "missing")}, filepath.Join(t.TempDir(), "index.json"), false); err == nil {
+		t.Error("NewIndexer() with a missing root should fail")
+	}
+}
+
+// TestIndexerImportedRootFileIsStable covers a file under one root that a
+// file under an earlier root imports: it must keep its root, so an unchanged
+// tree leaves the cache alone.
+func TestIndexerImportedRootFileIsStable(t *testing.T) {
+	library := t.TempDir()
+	pipelines := t.TempDir()
+	writeWDL(t, library, "trim.wdl", structsWDL)
+	writeWDL(t, pipelines, "main.wdl", `version 1.0
+
+import "`+filepath.Join(library, "trim.wdl")+`" as trim
+
+workflow Main {
+    call trim.Trim
+}
+`)
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+	roots := []string{pipelines, library}
+
+	first, err := NewIndexer(roots, indexPath, false)
+	if err != nil {
+		t.Fatalf("NewIndexer() error = %v", err)
+	}
+	idx, _ := first.List()
+	if trim := idx.Files[filepath.Join(library, "trim.wdl")]; trim == nil || trim.Root != library {
+		t.Errorf("imported library file entry = %+v, want root %s", trim, library)
+	}
+	cache, err := os.ReadFile(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := NewIndexer(roots, indexPath, false)
+	if err != nil {
+		t.Fatalf("NewIndexer() error = %v", err)
+	}
+	if got, want := second.Stats(), (wdlindex.UpdateStats{Reused: 2}); got != want {
+		t.Errorf("unchanged stats = %+v, want %+v", got, want)
+	}
+	if again, err := os.ReadFile(indexPath); err != nil || string(again) != string(cache) {
+		t.Error("an unchanged tree rewrote the index cache")
+	}
+}
+
+func TestIndexerDocumentation(t *testing.T) {
+	dir := t.TempDir()
+	writeWDL(t, dir, "main.wdl", `version 1.0
+
+task Count {
+    input {
+        File bam
+        Int threads = 4
+    }
+    command <<< samtools view -c ~{bam} >>>
+    output {
+        Int reads = read_int(stdout())
+    }
+    runtime {
+        docker: "samtools:1.17"
+        cpu: threads
+    }
+    meta {
+        description: "Counts reads"
+        tags: ["qc", "bam"]
+    }
+    parameter_meta {
+        bam: "Aligned reads"
+        threads: { description: "Worker threads" }
+        reads: "Read count"
+    }
+}
+
+workflow Main {
+    input {
+        Array[File] bams
+        Boolean deep = false
+    }
+    scatter (b in bams) {
+        if (deep) {
+            call Count { input: bam = b }
+        }
+    }
+}
+`)
+	indexer, err := NewIndexer([]string{dir}, filepath.Join(t.TempDir(), "index.json"), false)
+	if err != nil {
+		t.Fatalf("NewIndexer() error = %v", err)
+	}
+
+	task, err := indexer.GetTask("Count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantInputs := []wdlindex.Declaration{
+		{Name: "bam", Type: "File", Description: "Aligned reads"},
+		{Name: "threads", Type: "Int", Default: "4", Description: "Worker threads"},
+	}
+	if !reflect.DeepEqual(task.Inputs, wantInputs) {
+		t.Errorf("Inputs = %+v, want %+v", task.Inputs, wantInputs)
+	}
+	if task.Outputs[0].Description != "Read count" {
+		t.Errorf("output description = %q", task.Outputs[0].Description)
+	}
+	if task.Runtime["docker"] != "samtools:1.17" || task.Runtime["cpu"] != "threads" {
+		t.Errorf("Runtime = %v", task.Runtime)
+	}
+	if task.Meta["tags"] != `["qc","bam"]` || task.Description != "Counts reads" {
+		t.Errorf("Meta = %v, Description = %q", task.Meta, task.Description)
+	}
+
+	wf, err := indexer.GetWorkflow("Main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(wf.Calls, []string{"Count"}) {
+		t.Errorf("Calls = %v, want the call nested in scatter and if", wf.Calls)
+	}
+	if got, _ := indexer.index.ResolveCall(wf.Source, "Count"); got != task {
+		t.Errorf("ResolveCall() = %v, want the task", got)
+	}
+}
@@ -4,6 +4,7 @@ package handler
 import (
 	"context"
 	"path/filepath"
+	"strings"
 
 	"github.com/urfave/cli/v2"
 
@@ -15,13 +16,15 @@ import (
 // BundleHandler handles the WDL bundle command.
 type BundleHandler struct {
 	useCase   *bundle.BundleUseCase
+	verify    *bundle.VerifyUseCase
 	presenter *presenter.Presenter
 }
 
 // NewBundleHandler creates a new BundleHandler.
-func NewBundleHandler(uc *bundle.BundleUseCase, p *presenter.Presenter) *BundleHandler {
+func NewBundleHandler(uc *bundle.BundleUseCase, verify *bundle.VerifyUseCase, p *presenter.Presenter) *BundleHandler {
 	return &BundleHandler{
 		useCase:   uc,
+		verify:    verify,
 		presenter: p,
 	}
 }
@@ -32,18 +35,31 @@ func (h *BundleHandler) Command() *cli.Command {
 		Name:    "bundle",
 		Aliases: []string{"b", "pack"},
 		Usage:   "Create a WDL bundle with all dependencies",
+		Description: "Writes the main WDL with rewritten imports, a ZIP of its dependencies and a\n" +
+			"lockfile (<name>.lock.json) with the hash and origin of every bundled file.\n" +
+			"The same sources always produce byte-identical files.",
+		Subcommands: []*cli.Command{h.verifyCommand()},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:     "workflow",
-				Aliases:  []string{"w"},
-				Usage:    "[required] Path to the main WDL workflow file",
-				Required: true,
+				Name:    "workflow",
+				Aliases: []string{"w"},
+				Usage:   "[required] Path to the main WDL workflow file",
+				// Not enforced by the flag parser: it would also demand
+				// these flags of the verify subcommand. The use case
+				// validates them instead.
 			},
 			&cli.StringFlag{
-				Name:     "output",
-				Aliases:  []string{"o"},
-				Usage:    "[required] Output path for the bundle ZIP file",
-				Required: true,
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "[required] Output path for the bundle ZIP file",
+			},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "[optional] Resolve http(s) imports from the import cache only",
+			},
+			&cli.StringFlag{
+				Name:  "import-cache",
+				Usage: "[optional] Directory caching fetched http(s) imports (default: ~/.pumbaa/imports)",
 			},
 		},
 		Action: h.handle,
@@ -56,6 +72,8 @@ func (h *BundleHandler) handle(c *cli.Context) error {
 	input := bundle.Input{
 		MainWorkflowPath: c.String("workflow"),
 		OutputPath:       c.String("output"),
+		Offline:          c.Bool("offline"),
+		ImportCacheDir:   c.String("import-cache"),
 	}
 
 	h.presenter.Info("Analyzing workflow dependencies...")
@@ -73,12 +91,18 @@ func (h *BundleHandler) handle(c *cli.Context) error {
 	if output.DependenciesZipPath != "" {
 		h.presenter.KeyValue("Dependencies ZIP", output.DependenciesZipPath)
 	}
+	h.presenter.KeyValue("Lockfile", output.LockfilePath)
 	h.presenter.KeyValue("Total Files", output.TotalFiles)
 
 	if len(output.Dependencies) > 0 {
 		h.presenter.Newline()
 		h.presenter.Title("Dependencies (included in ZIP)")
 		for _, dep := range output.Dependencies {
+			if strings.Contains(dep, "://") {
+				// Remote imports are shown by where they came from.
+				h.presenter.Print("  • %s\n", dep)
+				continue
+			}
 			h.presenter.Print("  • %s\n", filepath.Base(dep))
 		}
 	} else {
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/bundle"
+	"github.com/lmtani/pumbaa/pkg/wdl"
+)
+
+// verifyCommand returns the bundle verify subcommand.
+func (h *BundleHandler) verifyCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "verify",
+		Usage: "Check a bundle, or what a workflow ran with, against the source tree",
+		Description: "Rebuilds the bundle from the sources in memory and compares every file by\n" +
+			"hash with the given ZIP (--bundle) or with the workflow and imports a Cromwell\n" +
+			"run was submitted with (--run). Exits with an error on any difference.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "workflow",
+				Aliases:  []string{"w"},
+				Usage:    "[required] Main WDL workflow file in the source tree",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "bundle",
+				Aliases: []string{"b"},
+				Usage:   "[optional] Dependencies ZIP to check",
+			},
+			&cli.StringFlag{
+				Name:    "run",
+				Aliases: []string{"r"},
+				Usage:   "[optional] Workflow ID whose submitted files to check",
+			},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "[optional] Resolve http(s) imports from the import cache only",
+			},
+			&cli.StringFlag{
+				Name:  "import-cache",
+				Usage: "[optional] Directory caching fetched http(s) imports (default: ~/.pumbaa/imports)",
+			},
+		},
+		Action: h.handleVerify,
+	}
+}
+
+func (h *BundleHandler) handleVerify(c *cli.Context) error {
+	output, err := h.verify.Execute(context.Background(), bundle.VerifyInput{
+		MainWorkflowPath: c.String("workflow"),
+		BundlePath:       c.String("bundle"),
+		WorkflowID:       c.String("run"),
+		Offline:          c.Bool("offline"),
+		ImportCacheDir:   c.String("import-cache"),
+	})
+	if err != nil {
+		return err
+	}
+
+	result := output.Result
+	if result.OK() {
+		h.presenter.Success("%s matches the sources (%d file(s))", output.Against, result.Checked)
+		return nil
+	}
+
+	h.presenter.Error("%s does not match the sources", output.Against)
+	h.presenter.Newline()
+	for _, d := range result.Differences {
+		switch d.Status {
+		case wdl.BundleFileChanged:
+			h.presenter.Print("  ~ %s differs from %s\n", d.Name, d.Source)
+		case wdl.BundleFileMissing:
+			h.presenter.Print("  - %s is missing (from %s)\n", d.Name, d.Source)
+		case wdl.BundleFileUnexpected:
+			h.presenter.Print("  + %s is not produced by the sources\n", d.Name)
+		}
+	}
+	return fmt.Errorf("%d of %d files differ", len(result.Differences), result.Checked)
+}
@@ -37,18 +37,13 @@ func (h *CacheForecastHandler) Command() *cli.Command {
 			"worth starting.\n\n" +
 			"The prediction is advisory: Cromwell decides. Supported backends are local and\n" +
 			"GCP; anything else is reported as undetermined rather than guessed.",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "workflow",
 				Aliases:  []string{"w"},
 				Usage:    "[required] Path to the WDL workflow file",
 				Required: true,
 			},
-			&cli.StringFlag{
-				Name:    "inputs",
-				Aliases: []string{"i"},
-				Usage:   "[optional] Path to the inputs JSON file",
-			},
 			&cli.StringFlag{
 				Name:    "dependencies",
 				Aliases: []string{"d"},
@@ -63,7 +58,7 @@ func (h *CacheForecastHandler) Command() *cli.Command {
 				Name:  "json",
 				Usage: "[optional] Emit the forecast as JSON",
 			},
-		},
+		}, inputsFlags()...),
 		Action: h.handle,
 	}
 }
@@ -71,7 +66,7 @@ func (h *CacheForecastHandler) Command() *cli.Command {
 func (h *CacheForecastHandler) handle(c *cli.Context) error {
 	forecast, err := h.useCase.Execute(context.Background(), workflow.CacheForecastInput{
 		WorkflowFile:     c.String("workflow"),
-		InputsFile:       c.String("inputs"),
+		Inputs:           inputSources(c),
 		DependenciesFile: c.String("dependencies"),
 		ReferenceID:      c.String("against"),
 	})
@@ -85,8 +80,11 @@ func (h *CacheForecastHandler) handle(c *cli.Context) error {
 	}
 
 	if c.Bool("json") {
+		// Kept off stdout so the forecast stays parseable.
+		showInputs(c, os.Stderr, forecast.Inputs)
 		return json.NewEncoder(os.Stdout).Encode(forecastJSON(forecast))
 	}
+	showInputs(c, os.Stdout, forecast.Inputs)
 	renderCacheForecast(h.presenter, forecast)
 	return nil
 }
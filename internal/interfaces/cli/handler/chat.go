@@ -82,7 +82,7 @@ func (h *ChatHandler) Command() *cli.Command {
 			},
 			&cli.StringFlag{
 				Name:    "wdl-dir",
-				Usage:   "Directory containing WDL workflows for context",
+				Usage:   "Directories containing WDL workflows for context, separated by ':'",
 				EnvVars: []string{"PUMBAA_WDL_DIR"},
 			},
 			&cli.BoolFlag{
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+)
+
+// CleanupHandler handles the workflow cleanup command.
+type CleanupHandler struct {
+	useCase   *workflow.CleanupUseCase
+	presenter *presenter.Presenter
+}
+
+// NewCleanupHandler creates a new CleanupHandler.
+func NewCleanupHandler(uc *workflow.CleanupUseCase, p *presenter.Presenter) *CleanupHandler {
+	return &CleanupHandler{
+		useCase:   uc,
+		presenter: p,
+	}
+}
+
+// Command returns the CLI command for cleaning up intermediate files.
+func (h *CleanupHandler) Command() *cli.Command {
+	return &cli.Command{
+		Name:      "cleanup",
+		Usage:     "Report, and optionally delete, the intermediate files of a successful workflow",
+		ArgsUsage: "<workflow-id>",
+		Description: "Lists every file under the workflow root that is not a final output and\n" +
+			"reports the bytes reclaimable per task. Nothing is deleted unless --dry-run=false\n" +
+			"is given and the deletion is confirmed.",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only report what would be deleted; use --dry-run=false to delete",
+				Value: true,
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "[optional] Confirm the deletion without asking",
+			},
+			&cli.BoolFlag{
+				Name:  "allow-failed",
+				Usage: "[optional] Also clean up a Failed or Aborted workflow, deleting the results of its finished calls and its logs",
+			},
+		},
+		Action: h.handle,
+	}
+}
+
+func (h *CleanupHandler) handle(c *cli.Context) error {
+	if c.NArg() < 1 {
+		h.presenter.Error("Workflow ID is required")
+		return cli.Exit("workflow ID required", 1)
+	}
+
+	ctx := context.Background()
+	plan, err := h.useCase.Plan(ctx, workflow.CleanupInput{
+		WorkflowID:  c.Args().First(),
+		AllowFailed: c.Bool("allow-failed"),
+	})
+	if err != nil {
+		h.presenter.Error("Failed to plan cleanup: %v", err)
+		if errors.Is(err, workflow.ErrCleanupNotSucceeded) {
+			h.presenter.Info("A Failed or Aborted workflow is only cleaned up with --allow-failed.")
+		}
+		return err
+	}
+
+	h.displayPlan(plan)
+	if len(plan.Files) == 0 {
+		h.presenter.Success("Nothing to clean up")
+		return nil
+	}
+	if c.Bool("dry-run") {
+		h.presenter.Info("Dry run: nothing was deleted. Use --dry-run=false to delete these files.")
+		return nil
+	}
+
+	if !c.Bool("yes") {
+		h.presenter.Print("Delete %d file(s), %s, under %s? [y/N] ", len(plan.Files), formatBytes(plan.Bytes), plan.WorkflowRoot)
+		answer, _ := bufio.NewReader(c.App.Reader).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			h.presenter.Info("Cleanup cancelled; nothing was deleted")
+			return nil
+		}
+	}
+
+	result, err := h.useCase.Apply(ctx, plan)
+	if err != nil {
+		h.presenter.Error("Cleanup interrupted: %v", err)
+		return err
+	}
+	if len(result.Failed) > 0 {
+		h.presenter.Newline()
+		for _, f := range result.Failed {
+			h.presenter.Print("  ✗ %s: %v\n", f.Path, f.Err)
+		}
+		h.presenter.Error("Deleted %d file(s) (%s); %d could not be deleted", result.Deleted, formatBytes(result.Bytes), len(result.Failed))
+		return cli.Exit("some files could not be deleted", 1)
+	}
+	h.presenter.Success("Deleted %d file(s), reclaiming %s", result.Deleted, formatBytes(result.Bytes))
+	return nil
+}
+
+func (h *CleanupHandler) displayPlan(plan *workflow.CleanupPlan) {
+	h.presenter.Title("Workflow Cleanup")
+	h.presenter.KeyValue("Workflow ID", plan.WorkflowID)
+	h.presenter.KeyValue("Workflow Name", plan.WorkflowName)
+	h.presenter.KeyValue("Workflow Root", plan.WorkflowRoot)
+	h.presenter.KeyValue("Final outputs kept", fmt.Sprintf("%d file(s), %s", plan.KeptFiles, formatBytes(plan.KeptBytes)))
+	h.presenter.KeyValue("Intermediate files", fmt.Sprintf("%d file(s), %s", len(plan.Files), formatBytes(plan.Bytes)))
+	h.presenter.Newline()
+
+	if len(plan.Tasks) == 0 {
+		return
+	}
+	table := h.presenter.NewTable([]string{"Task", "Files", "Reclaimable"})
+	for _, t := range plan.Tasks {
+		task := stripWorkflowPrefix(t.Task, plan.WorkflowName)
+		if task == "" {
+			task = "(outside call roots)"
+		}
+		_ = table.Append([]string{task, fmt.Sprint(t.Files), formatBytes(t.Bytes)})
+	}
+	_ = table.Render()
+	h.presenter.Newline()
+}
@@ -97,8 +97,8 @@ func (h *ConfigHandler) Get(key string) error {
 	if !found {
 		fmt.Printf("%s: (not set)\n", key)
 	} else {
-		// Mask API keys for security
-		if strings.Contains(key, "api_key") && len(value) > 8 {
+		// Mask secrets for security
+		if isSecretKey(key) && len(value) > 8 {
 			value = value[:4] + "..." + value[len(value)-4:]
 		}
 		fmt.Printf("%s: %s\n", key, value)
@@ -117,8 +117,8 @@ func (h *ConfigHandler) List() error {
 	for _, key := range config.AllKeys() {
 		value, found := cfg.GetValue(key)
 		if found {
-			// Mask API keys for security
-			if strings.Contains(key, "api_key") && len(value) > 8 {
+			// Mask secrets for security
+			if isSecretKey(key) && len(value) > 8 {
 				value = value[:4] + "..." + value[len(value)-4:]
 			}
 			fmt.Printf("  %s: %s\n", key, value)
@@ -126,3 +126,8 @@ func (h *ConfigHandler) List() error {
 	}
 	return nil
 }
+
+// isSecretKey reports whether a config key holds a credential.
+func isSecretKey(key string) bool {
+	return strings.HasSuffix(key, "_key") || strings.HasSuffix(key, "_token") || strings.Contains(key, "secret")
+}
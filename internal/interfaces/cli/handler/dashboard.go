@@ -20,6 +20,7 @@ type DashboardHandler struct {
 	fileProvider  ports.FileProvider
 	batchLogsUC   *workflowapp.GetBatchLogsUseCase
 	compareUC     *workflowapp.CompareUseCase
+	storageUC     *workflowapp.StorageUsageUseCase
 	updateChecker ports.UpdateChecker
 	version       string
 	chatDeps      ChatDepsProvider
@@ -33,6 +34,7 @@ func NewDashboardHandler(
 	fp ports.FileProvider,
 	bluc *workflowapp.GetBatchLogsUseCase,
 	cuc *workflowapp.CompareUseCase,
+	suc *workflowapp.StorageUsageUseCase,
 	updateChecker ports.UpdateChecker,
 	version string,
 	chatDeps ChatDepsProvider,
@@ -44,6 +46,7 @@ func NewDashboardHandler(
 		fileProvider:  fp,
 		batchLogsUC:   bluc,
 		compareUC:     cuc,
+		storageUC:     suc,
 		updateChecker: updateChecker,
 		chatDeps:      chatDeps,
 		version:       version,
@@ -68,6 +71,7 @@ KEY BINDINGS:
   /             Filter by workflow name
   Ctrl+X        Clear all filters
   r             Refresh workflow list
+  S             Measure storage of the workflows on screen
   q             Quit`,
 		Flags: []cli.Flag{
 			&cli.StringSliceFlag{
@@ -115,6 +119,7 @@ func (h *DashboardHandler) createDependencies() *tui.Dependencies {
 		MonitoringUC:   h.monitoringUC,
 		BatchLogsUC:    h.batchLogsUC,
 		CompareUC:      h.compareUC,
+		StorageUC:      h.storageUC,
 		UpdateChecker:  h.updateChecker,
 		CurrentVersion: h.version,
 	}
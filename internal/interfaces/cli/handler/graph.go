@@ -0,0 +1,104 @@
+// Package handler provides CLI command handlers.
+package handler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+	"github.com/lmtani/pumbaa/pkg/wdl"
+)
+
+// GraphHandler handles the wdl graph command.
+type GraphHandler struct {
+	useCase   *workflow.GraphUseCase
+	presenter *presenter.Presenter
+}
+
+// NewGraphHandler creates a new GraphHandler.
+func NewGraphHandler(uc *workflow.GraphUseCase, p *presenter.Presenter) *GraphHandler {
+	return &GraphHandler{useCase: uc, presenter: p}
+}
+
+// Command returns the CLI command for exporting a call graph.
+func (h *GraphHandler) Command() *cli.Command {
+	formats := make([]string, len(wdl.GraphFormats))
+	for i, f := range wdl.GraphFormats {
+		formats[i] = string(f)
+	}
+	return &cli.Command{
+		Name:  "graph",
+		Usage: "Draw a workflow's call graph as DOT, Mermaid or JSON",
+		Description: "Renders every call, the dependencies between them, and the scatters,\n" +
+			"conditionals and subworkflows around them as nested clusters. With --run, each\n" +
+			"call is coloured with its status in that workflow run. Without --output the graph\n" +
+			"goes to stdout, e.g. 'pumbaa wdl graph -w main.wdl | dot -Tsvg > graph.svg'.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "workflow",
+				Aliases:  []string{"w"},
+				Usage:    "[required] Path to the WDL workflow file",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "[optional] Output format: " + strings.Join(formats, ", "),
+				Value:   string(wdl.GraphDOT),
+			},
+			&cli.StringFlag{
+				Name:    "dependencies",
+				Aliases: []string{"d"},
+				Usage:   "[optional] Imports ZIP; without it, imports are read from beside the workflow",
+			},
+			&cli.StringFlag{
+				Name:    "run",
+				Aliases: []string{"r"},
+				Usage:   "[optional] Workflow ID whose call statuses colour the graph",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "[optional] Write the graph to this file instead of stdout",
+			},
+		},
+		Action: h.handle,
+	}
+}
+
+func (h *GraphHandler) handle(c *cli.Context) error {
+	output, err := h.useCase.Execute(context.Background(), workflow.GraphInput{
+		WorkflowFile:     c.String("workflow"),
+		DependenciesFile: c.String("dependencies"),
+		Format:           wdl.GraphFormat(c.String("format")),
+		RunID:            c.String("run"),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Notes go to stderr so they never end up inside a piped graph.
+	if output.Warning != "" {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", output.Warning)
+	}
+	if len(output.Unresolved) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠ drawn without their contents (bundle the imports to see inside): %s\n",
+			strings.Join(output.Unresolved, ", "))
+	}
+
+	outputFile := c.String("output")
+	if outputFile == "" {
+		h.presenter.Print("%s", output.Rendered)
+		return nil
+	}
+	if err := os.WriteFile(outputFile, output.Rendered, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+	h.presenter.Success("Wrote %s", outputFile)
+	return nil
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/workflow"
+)
+
+// repeatable collects every occurrence of a flag as written. Unlike a
+// StringSliceFlag it does not split values on commas, which
+// "--set Wf.lanes=[1,2]" needs.
+type repeatable []string
+
+func (r *repeatable) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+
+func (r *repeatable) String() string {
+	if r == nil {
+		return ""
+	}
+	return strings.Join(*r, " ")
+}
+
+// inputsFlags are the flags that assemble a submission's inputs, shared by
+// submit, preflight and cache-forecast.
+func inputsFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.GenericFlag{
+			Name:    "inputs",
+			Aliases: []string{"i"},
+			Usage:   "[optional] Inputs file, JSON or YAML; repeat to layer overlays, later files win",
+			Value:   &repeatable{},
+		},
+		&cli.GenericFlag{
+			Name:  "set",
+			Usage: "[optional] Set one input, applied after the files: Workflow.input=value (repeatable)",
+			Value: &repeatable{},
+		},
+		&cli.BoolFlag{
+			Name:  "show-inputs",
+			Usage: "[optional] Print the merged inputs JSON",
+		},
+	}
+}
+
+// inputSources reads the inputs flags.
+func inputSources(c *cli.Context) workflow.InputSources {
+	var src workflow.InputSources
+	if files, ok := c.Generic("inputs").(*repeatable); ok {
+		src.Files = *files
+	}
+	if set, ok := c.Generic("set").(*repeatable); ok {
+		src.Set = *set
+	}
+	return src
+}
+
+// showInputs prints the merged inputs when --show-inputs asks for them.
+func showInputs(c *cli.Context, w io.Writer, inputs []byte) {
+	if !c.Bool("show-inputs") {
+		return
+	}
+	if len(inputs) == 0 {
+		fmt.Fprintln(w, "No inputs given.")
+		return
+	}
+	fmt.Fprintln(w, "Merged inputs:")
+	fmt.Fprintln(w, strings.TrimRight(string(inputs), "\n"))
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+)
+
+// LogsHandler handles the workflow logs command.
+type LogsHandler struct {
+	useCase   *workflow.FollowLogsUseCase
+	presenter *presenter.Presenter
+}
+
+// NewLogsHandler creates a new LogsHandler.
+func NewLogsHandler(uc *workflow.FollowLogsUseCase, p *presenter.Presenter) *LogsHandler {
+	return &LogsHandler{
+		useCase:   uc,
+		presenter: p,
+	}
+}
+
+// Command returns the CLI command for reading a task's log.
+func (h *LogsHandler) Command() *cli.Command {
+	return &cli.Command{
+		Name:      "logs",
+		Aliases:   []string{"l"},
+		Usage:     "Print the tail of a task's log, optionally following it while the task runs",
+		ArgsUsage: "<workflow-id> <task>",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "follow",
+				Aliases: []string{"f"},
+				Usage:   "Keep printing what the task writes until the call finishes",
+			},
+			&cli.IntFlag{
+				Name:  "shard",
+				Usage: "[optional] Scatter shard (-1 for a task that is not scattered)",
+				Value: -1,
+			},
+			&cli.IntFlag{
+				Name:  "attempt",
+				Usage: "[optional] Call attempt (default: the latest, following each retry as it starts)",
+			},
+			&cli.StringFlag{
+				Name:  "stream",
+				Usage: "[optional] Log to read: stderr or stdout",
+				Value: "stderr",
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "[optional] How often to poll the log when following",
+				Value: workflow.DefaultFollowInterval,
+			},
+		},
+		Action: h.handle,
+	}
+}
+
+func (h *LogsHandler) handle(c *cli.Context) error {
+	if c.NArg() < 2 {
+		h.presenter.Error("Workflow ID and task are required")
+		return cli.Exit("workflow ID and task required", 1)
+	}
+
+	// Ctrl+C stops following without the error a killed command would show.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	input := workflow.FollowLogsInput{
+		WorkflowID: c.Args().Get(0),
+		Task:       c.Args().Get(1),
+		Shard:      c.Int("shard"),
+		Attempt:    c.Int("attempt"),
+		Stream:     c.String("stream"),
+		Follow:     c.Bool("follow"),
+		Interval:   c.Duration("interval"),
+	}
+
+	// The log goes to stdout, so it can be piped; notices go to stderr.
+	notices := presenter.New(c.App.ErrWriter)
+	output, err := h.useCase.Execute(ctx, input, c.App.Writer)
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		notices.Error("Failed to read log: %v", err)
+		return err
+	}
+	if input.Follow {
+		notices.Info("%s attempt %d finished: %s", output.Call.Name, output.Call.Attempt, output.Call.Status)
+	}
+	return nil
+}
@@ -15,15 +15,17 @@ import (
 
 // OutputsHandler handles the workflow outputs command.
 type OutputsHandler struct {
-	useCase   *workflow.OutputsUseCase
-	presenter *presenter.Presenter
+	useCase         *workflow.OutputsUseCase
+	downloadUseCase *workflow.DownloadOutputsUseCase
+	presenter       *presenter.Presenter
 }
 
 // NewOutputsHandler creates a new OutputsHandler.
-func NewOutputsHandler(uc *workflow.OutputsUseCase, p *presenter.Presenter) *OutputsHandler {
+func NewOutputsHandler(uc *workflow.OutputsUseCase, duc *workflow.DownloadOutputsUseCase, p *presenter.Presenter) *OutputsHandler {
 	return &OutputsHandler{
-		useCase:   uc,
-		presenter: p,
+		useCase:         uc,
+		downloadUseCase: duc,
+		presenter:       p,
 	}
 }
 
@@ -40,6 +42,16 @@ func (h *OutputsHandler) Command() *cli.Command {
 				Aliases: []string{"j"},
 				Usage:   "Output in JSON format",
 			},
+			&cli.StringFlag{
+				Name:    "download",
+				Aliases: []string{"d"},
+				Usage:   "[optional] Download the output files into this directory, verifying their checksums",
+			},
+			&cli.IntFlag{
+				Name:  "parallel",
+				Usage: "[optional] Number of files downloaded at once",
+				Value: workflow.DefaultDownloadParallelism,
+			},
 		},
 		Action: h.handle,
 	}
@@ -54,6 +66,14 @@ func (h *OutputsHandler) handle(c *cli.Context) error {
 	ctx := context.Background()
 	workflowID := c.Args().First()
 
+	if dir := c.String("download"); dir != "" {
+		return h.download(ctx, workflow.DownloadOutputsInput{
+			WorkflowID:  workflowID,
+			Dir:         dir,
+			Parallelism: c.Int("parallel"),
+		})
+	}
+
 	input := workflow.OutputsInput{
 		WorkflowID: workflowID,
 	}
@@ -72,6 +92,49 @@ func (h *OutputsHandler) handle(c *cli.Context) error {
 	return nil
 }
 
+func (h *OutputsHandler) download(ctx context.Context, input workflow.DownloadOutputsInput) error {
+	output, err := h.downloadUseCase.Execute(ctx, input)
+	if err != nil {
+		h.presenter.Error("Failed to download workflow outputs: %v", err)
+		return err
+	}
+
+	h.presenter.Title("Downloaded Outputs")
+	h.presenter.KeyValue("Workflow ID", output.Manifest.WorkflowID)
+	h.presenter.KeyValue("Workflow Name", output.Manifest.WorkflowName)
+	h.presenter.Newline()
+
+	if len(output.Manifest.Files) == 0 {
+		h.presenter.Info("No output files to download")
+		return nil
+	}
+
+	table := h.presenter.NewTable([]string{"File", "Size", "Status"})
+	counts := make(map[workflow.DownloadStatus]int)
+	for _, f := range output.Manifest.Files {
+		counts[f.Status]++
+		status := string(f.Status)
+		if f.Error != "" {
+			status += ": " + f.Error
+		}
+		_ = table.Append([]string{f.Path, formatBytes(f.Size), status})
+	}
+	_ = table.Render()
+	h.presenter.Newline()
+
+	h.presenter.KeyValue("Manifest", output.ManifestPath)
+	if counts[workflow.DownloadUnverified] > 0 {
+		h.presenter.Warning("%d file(s) checked by size only: the storage reported no checksum", counts[workflow.DownloadUnverified])
+	}
+	if output.Failed > 0 {
+		h.presenter.Error("%d of %d file(s) failed; run the command again to retry them", output.Failed, len(output.Manifest.Files))
+		return cli.Exit("some outputs failed to download", 1)
+	}
+	h.presenter.Success("%d file(s) downloaded and verified (%d already present)",
+		len(output.Manifest.Files), counts[workflow.DownloadSkipped])
+	return nil
+}
+
 func (h *OutputsHandler) displayJSON(outputs map[string]any) error {
 	data, err := json.MarshalIndent(outputs, "", "  ")
 	if err != nil {
@@ -3,11 +3,14 @@ package handler
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/lmtani/pumbaa/internal/application/workflow"
 	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+	"github.com/lmtani/pumbaa/pkg/wdl"
 )
 
 // PreflightHandler handles the pre-submission check command.
@@ -29,24 +32,26 @@ func (h *PreflightHandler) Command() *cli.Command {
 		Usage:   "Check a workflow and its inputs before submitting",
 		Description: "Verifies that Cromwell is reachable, the WDL parses, every required input is\n" +
 			"present and well-typed, and the files the inputs point at exist — so a broken\n" +
-			"submission fails in seconds instead of minutes.",
-		Flags: []cli.Flag{
+			"submission fails in seconds instead of minutes. It also evaluates each call's\n" +
+			"runtime section against the inputs to show the CPU, memory and disk it will request,\n" +
+			"and predicts the run's peak footprint, warning when it goes over the quota set with\n" +
+			"'pumbaa config set quota_cpus|quota_memory_gb|quota_vms'.",
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "workflow",
 				Aliases:  []string{"w"},
 				Usage:    "[required] Path to the WDL workflow file",
 				Required: true,
 			},
-			&cli.StringFlag{
-				Name:    "inputs",
-				Aliases: []string{"i"},
-				Usage:   "[optional] Path to the inputs JSON file",
-			},
 			&cli.StringFlag{
 				Name:    "dependencies",
 				Aliases: []string{"d"},
 				Usage:   "[optional] Path to the dependencies ZIP; its imports are checked",
 			},
+			&cli.StringFlag{
+				Name:  "schema",
+				Usage: "[optional] JSON Schema (from 'pumbaa wdl schema') to validate the inputs against too",
+			},
 			&cli.BoolFlag{
 				Name:  "skip-paths",
 				Usage: "[optional] Do not check that input files exist",
@@ -55,7 +60,15 @@ func (h *PreflightHandler) Command() *cli.Command {
 				Name:  "skip-server",
 				Usage: "[optional] Do not check that Cromwell is reachable",
 			},
-		},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "[optional] Resolve http(s) imports from the import cache only",
+			},
+			&cli.StringFlag{
+				Name:  "import-cache",
+				Usage: "[optional] Directory caching fetched http(s) imports (default: ~/.pumbaa/imports)",
+			},
+		}, inputsFlags()...),
 		Action: h.handle,
 	}
 }
@@ -63,15 +76,18 @@ func (h *PreflightHandler) Command() *cli.Command {
 func (h *PreflightHandler) handle(c *cli.Context) error {
 	report, err := h.useCase.Execute(context.Background(), workflow.PreflightInput{
 		WorkflowFile:     c.String("workflow"),
-		InputsFile:       c.String("inputs"),
+		Inputs:           inputSources(c),
 		DependenciesFile: c.String("dependencies"),
+		SchemaFile:       c.String("schema"),
 		SkipPaths:        c.Bool("skip-paths"),
 		SkipServer:       c.Bool("skip-server"),
+		Remote:           wdl.RemoteImportOptions{CacheDir: c.String("import-cache"), Offline: c.Bool("offline")},
 	})
 	if err != nil {
 		return err
 	}
 
+	showInputs(c, os.Stdout, report.Inputs)
 	renderPreflightReport(h.presenter, report)
 
 	if report.HasErrors() {
@@ -97,6 +113,8 @@ func renderPreflightReport(p *presenter.Presenter, r *workflow.PreflightReport)
 		}
 	}
 
+	renderResources(p, r.Resources)
+
 	errCount, warnCount := r.Counts()
 	p.Newline()
 	switch {
@@ -109,6 +127,57 @@ func renderPreflightReport(p *presenter.Presenter, r *workflow.PreflightReport)
 	}
 }
 
+// resourceAttributes are the runtime attributes shown per call: the ones that
+// decide what a task costs and whether it fits.
+var resourceAttributes = []string{"cpu", "memory", "disks"}
+
+// renderResources lists what each call will request, one line per call.
+func renderResources(p *presenter.Presenter, calls []wdl.CallRuntime) {
+	if len(calls) == 0 {
+		return
+	}
+	width := 0
+	for _, c := range calls {
+		width = max(width, len(c.Call))
+	}
+
+	p.Newline()
+	p.Print("  Requested per call\n")
+	for _, c := range calls {
+		p.Print("      %-*s  %s\n", width, c.Call, resourceSummary(c))
+	}
+}
+
+// resourceSummary renders one call's requests, saying plainly when a value
+// only exists once the run is under way.
+func resourceSummary(c wdl.CallRuntime) string {
+	switch {
+	case c.Unresolved:
+		return "task definition not available (bundle the imports to see it)"
+	case c.Skipped:
+		return "will not run with these inputs"
+	}
+	var parts []string
+	for _, attr := range resourceAttributes {
+		if v, ok := c.Attributes[attr]; ok {
+			parts = append(parts, attr+" "+v)
+		} else if _, ok := c.Unevaluated[attr]; ok {
+			parts = append(parts, attr+" known at run time")
+		}
+	}
+	if len(parts) == 0 {
+		return "engine defaults"
+	}
+	summary := strings.Join(parts, " · ")
+	switch {
+	case c.Scattered && c.Shards > 0:
+		summary += fmt.Sprintf(" (per shard, %d shards)", c.Shards)
+	case c.Scattered:
+		summary += " (per shard)"
+	}
+	return summary
+}
+
 // itemText prefixes the message with the input or path it is about.
 func itemText(item workflow.PreflightItem) string {
 	if item.Subject == "" {
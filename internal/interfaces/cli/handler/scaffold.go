@@ -2,10 +2,14 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/urfave/cli/v2"
 
@@ -33,7 +37,16 @@ func (h *ScaffoldHandler) Command() *cli.Command {
 		Usage:   "Generate an inputs JSON template from a WDL",
 		Description: "Reads the workflow's own declarations and writes an inputs file to fill in:\n" +
 			"required inputs first, each with its type and documentation. Without --output\n" +
-			"the template goes to stdout, so it can be redirected to a file.",
+			"the template goes to stdout, so it can be redirected to a file. Inputs calls\n" +
+			"leave unbound (a task's memory or docker, say) can be set as\n" +
+			"Workflow.call.input; required ones are always listed, --all adds the rest.\n\n" +
+			"--from-files lists data files (local or gs://, with *, ? and {a,b}), groups\n" +
+			"them into samples and fills the inputs that can take them: Array[File],\n" +
+			"arrays of structs or Pairs, one per sample, and sample names. With\n" +
+			"--per-sample, one inputs file per sample is written to the --output directory.\n\n" +
+			"--from starts from the inputs a previous run was submitted with: values of\n" +
+			"inputs still declared are kept, renamed inputs follow their new name, and\n" +
+			"re-typed, dropped and new required inputs are listed for review.",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:     "workflow",
@@ -46,9 +59,30 @@ func (h *ScaffoldHandler) Command() *cli.Command {
 				Aliases: []string{"o"},
 				Usage:   "[optional] Write the template to this file instead of stdout",
 			},
+			&cli.StringFlag{
+				Name:    "dependencies",
+				Aliases: []string{"d"},
+				Usage:   "[optional] Imports ZIP; without it, imports are read from beside the workflow",
+			},
 			&cli.BoolFlag{
 				Name:  "all",
-				Usage: "[optional] Include optional inputs, with their default values",
+				Usage: "[optional] Include optional inputs and overridable call inputs, with their default values",
+			},
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "[optional] ID of a previous run whose inputs the template starts from",
+			},
+			&cli.StringFlag{
+				Name:  "from-files",
+				Usage: "[optional] Glob of data files to fill the inputs with, e.g. 'gs://bucket/run42/*_R{1,2}.fastq.gz'",
+			},
+			&cli.StringFlag{
+				Name:  "sample-pattern",
+				Usage: "[optional] Regular expression over file names whose (?P<sample>...) group names the sample",
+			},
+			&cli.BoolFlag{
+				Name:  "per-sample",
+				Usage: "[optional] With --from-files, write one inputs file per sample into the --output directory",
 			},
 			&cli.BoolFlag{
 				Name:  "force",
@@ -63,14 +97,63 @@ func (h *ScaffoldHandler) handle(c *cli.Context) error {
 	workflowFile := c.String("workflow")
 	outputFile := c.String("output")
 
+	perSample := c.Bool("per-sample")
+	if perSample && c.String("from-files") == "" {
+		return fmt.Errorf("--per-sample needs --from-files")
+	}
+	if perSample && outputFile == "" {
+		return fmt.Errorf("--per-sample writes one file per sample: give their directory with --output")
+	}
+
 	output, err := h.useCase.Execute(context.Background(), workflow.ScaffoldInputsInput{
-		WorkflowFile:    workflowFile,
-		IncludeOptional: c.Bool("all"),
+		WorkflowFile:     workflowFile,
+		DependenciesFile: c.String("dependencies"),
+		IncludeOptional:  c.Bool("all"),
+		IncludeCalls:     c.Bool("all"),
+		FromFiles:        c.String("from-files"),
+		SamplePattern:    c.String("sample-pattern"),
+		PerSample:        perSample,
+		FromWorkflow:     c.String("from"),
 	})
 	if err != nil {
 		return err
 	}
 
+	// Notes go to stderr so they never end up inside a redirected template.
+	if output.Warning != "" {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", output.Warning)
+	}
+	if len(output.UnresolvedCalls) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠ inputs of these calls are not listed, their definition could not be read: %s\n",
+			strings.Join(output.UnresolvedCalls, ", "))
+	}
+
+	if len(output.Samples) > 0 {
+		files := 0
+		for _, sample := range output.Samples {
+			files += len(sample.Files)
+		}
+		if len(output.Filled) == 0 {
+			fmt.Fprintf(os.Stderr, "⚠ found %d sample(s) in %d file(s), but no input of %s can take them\n",
+				len(output.Samples), files, output.WorkflowName)
+		} else {
+			fmt.Fprintf(os.Stderr, "Filled %s from %d sample(s) in %d file(s)\n",
+				strings.Join(output.Filled, ", "), len(output.Samples), files)
+		}
+	}
+	if len(output.Unmatched) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠ %d file(s) match no sample and were left out: %s\n",
+			len(output.Unmatched), strings.Join(output.Unmatched, ", "))
+	}
+
+	if output.Reconciled != nil {
+		renderReconciled(os.Stderr, output.Reconciled)
+	}
+
+	if perSample {
+		return h.writeSamples(workflowFile, outputFile, output, c.Bool("force"))
+	}
+
 	// No destination: the template is the output, so it can be piped.
 	if outputFile == "" {
 		h.presenter.Print("%s", output.Template)
@@ -83,7 +166,63 @@ func (h *ScaffoldHandler) handle(c *cli.Context) error {
 
 	h.presenter.Success("Wrote %s for workflow %s", outputFile, output.WorkflowName)
 	h.renderInputs(output.Inputs)
-	h.renderNextSteps(workflowFile, outputFile, output.Inputs)
+	h.renderNextSteps(workflowFile, outputFile, output.Inputs, output.Filled)
+	return nil
+}
+
+// renderReconciled explains what changed on the way from a previous run's
+// inputs, one line per input that was not carried over as it was.
+func renderReconciled(w io.Writer, r *workflow.Reconciled) {
+	fmt.Fprintf(w, "Started from the inputs of %s: %d kept, %d to review\n", r.WorkflowID, len(r.Kept), len(r.Changes))
+	if r.PreviousName != "" {
+		fmt.Fprintf(w, "  the workflow was named %s; its keys were renamed to match\n", r.PreviousName)
+	}
+	for _, change := range r.Changes {
+		switch change.Kind {
+		case wdl.ChangeRenamed:
+			fmt.Fprintf(w, "  %-9s %s → %s\n", change.Kind, change.Previous, change.Input)
+		case wdl.ChangeAdded:
+			fmt.Fprintf(w, "  %-9s %s %s\n", change.Kind, change.Input, change.Detail)
+		case wdl.ChangeDropped:
+			fmt.Fprintf(w, "  %-9s %s %s (was %s)\n", change.Kind, change.Previous, change.Detail, previousValue(change.Value))
+		default:
+			fmt.Fprintf(w, "  %-9s %s %s (was %s)\n", change.Kind, change.Input, change.Detail, previousValue(change.Value))
+		}
+	}
+}
+
+// previousValue renders a previous run's value compactly, so a dropped or
+// re-typed one can be copied back by hand.
+func previousValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if len(data) > 80 {
+		return string(data[:77]) + "..."
+	}
+	return string(data)
+}
+
+// writeSamples writes each sample's template to <dir>/<sample>.inputs.json.
+func (h *ScaffoldHandler) writeSamples(workflowFile, dir string, output *workflow.ScaffoldInputsOutput, force bool) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	table := h.presenter.NewTable([]string{"SAMPLE", "FILES", "INPUTS FILE"})
+	for _, sample := range output.Samples {
+		path := filepath.Join(dir, sample.Name+".inputs.json")
+		if err := writeTemplate(path, sample.Template, force); err != nil {
+			return err
+		}
+		_ = table.Append([]string{sample.Name, fmt.Sprint(len(sample.Files)), path})
+	}
+
+	h.presenter.Success("Wrote %d inputs file(s) to %s for workflow %s", len(output.Samples), dir, output.WorkflowName)
+	h.presenter.Newline()
+	_ = table.Render()
+	h.renderNextSteps(workflowFile, filepath.Join(dir, "<sample>.inputs.json"), output.Inputs, output.Filled)
 	return nil
 }
 
@@ -111,29 +250,77 @@ func (h *ScaffoldHandler) renderInputs(inputs []wdl.InputSpec) {
 		return
 	}
 
-	h.presenter.Newline()
-	table := h.presenter.NewTable([]string{"INPUT", "TYPE", "REQUIRED", "DEFAULT", "DESCRIPTION"})
+	var workflowInputs, callInputs []wdl.InputSpec
 	for _, in := range inputs {
-		required := "no"
-		if in.Required() {
-			required = "yes"
+		if in.Call == "" {
+			workflowInputs = append(workflowInputs, in)
+		} else {
+			callInputs = append(callInputs, in)
 		}
-		_ = table.Append([]string{in.Name, in.Type, required, in.Default, in.Description})
 	}
-	_ = table.Render()
+
+	if len(workflowInputs) > 0 {
+		h.presenter.Newline()
+		table := h.presenter.NewTable([]string{"INPUT", "TYPE", "REQUIRED", "DEFAULT", "DESCRIPTION"})
+		for _, in := range workflowInputs {
+			_ = table.Append([]string{in.Name, in.Type, yesNo(in.Required()), inputDefault(in), in.Description})
+		}
+		_ = table.Render()
+	}
+
+	if len(callInputs) > 0 {
+		h.presenter.Newline()
+		h.presenter.Title("Call inputs")
+		table := h.presenter.NewTable([]string{"CALL", "INPUT", "TYPE", "REQUIRED", "DEFAULT", "DESCRIPTION"})
+		previous := ""
+		for _, in := range callInputs {
+			// The call is named once, on the first row of its group.
+			call := in.Call
+			if call == previous {
+				call = ""
+			}
+			previous = in.Call
+			_ = table.Append([]string{call, in.Name, in.Type, yesNo(in.Required()), inputDefault(in), in.Description})
+		}
+		_ = table.Render()
+	}
 }
 
-func (h *ScaffoldHandler) renderNextSteps(workflowFile, outputFile string, inputs []wdl.InputSpec) {
+// inputDefault is the DEFAULT column: the literal default, or a note that one
+// is computed by the WDL.
+func inputDefault(in wdl.InputSpec) string {
+	if in.Default == "" && in.HasDefault {
+		return "(computed)"
+	}
+	return in.Default
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func (h *ScaffoldHandler) renderNextSteps(workflowFile, outputFile string, inputs []wdl.InputSpec, filled []string) {
+	done := make(map[string]bool, len(filled))
+	for _, name := range filled {
+		done[name] = true
+	}
 	required := 0
 	for _, in := range inputs {
-		if in.Required() {
+		if in.Required() && !done[in.Name] {
 			required++
 		}
 	}
 
 	h.presenter.Newline()
 	h.presenter.Title("Next steps")
-	h.presenter.Println(fmt.Sprintf("  1. Replace the %d placeholder value(s) in %s", required, outputFile))
+	if required > 0 {
+		h.presenter.Println(fmt.Sprintf("  1. Replace the %d placeholder value(s) in %s", required, outputFile))
+	} else {
+		h.presenter.Println(fmt.Sprintf("  1. Review the values in %s", outputFile))
+	}
 	h.presenter.Println(fmt.Sprintf("  2. pumbaa workflow preflight -w %s -i %s", workflowFile, outputFile))
 	h.presenter.Println(fmt.Sprintf("  3. pumbaa workflow submit -w %s -i %s", workflowFile, outputFile))
 }
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+)
+
+// SchemaHandler handles the wdl schema command.
+type SchemaHandler struct {
+	useCase   *workflow.SchemaUseCase
+	presenter *presenter.Presenter
+}
+
+// NewSchemaHandler creates a new SchemaHandler.
+func NewSchemaHandler(uc *workflow.SchemaUseCase, p *presenter.Presenter) *SchemaHandler {
+	return &SchemaHandler{useCase: uc, presenter: p}
+}
+
+// Command returns the CLI command for generating an inputs schema.
+func (h *SchemaHandler) Command() *cli.Command {
+	return &cli.Command{
+		Name:  "schema",
+		Usage: "Describe a workflow's inputs file as a JSON Schema",
+		Description: "Writes a JSON Schema (draft 2020-12) for the workflow's inputs JSON: every\n" +
+			"input and overridable call input with its type, default and parameter_meta\n" +
+			"description, with structs under $defs. Web forms and editors can validate an\n" +
+			"inputs file with it, and 'pumbaa workflow preflight --schema' applies it too.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "workflow",
+				Aliases:  []string{"w"},
+				Usage:    "[required] Path to the WDL workflow file",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "dependencies",
+				Aliases: []string{"d"},
+				Usage:   "[optional] Imports ZIP; without it, imports are read from beside the workflow",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "[optional] Write the schema to this file instead of stdout",
+			},
+		},
+		Action: h.handle,
+	}
+}
+
+func (h *SchemaHandler) handle(c *cli.Context) error {
+	output, err := h.useCase.Execute(context.Background(), workflow.SchemaInput{
+		WorkflowFile:     c.String("workflow"),
+		DependenciesFile: c.String("dependencies"),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Notes go to stderr so they never end up inside a piped schema.
+	if output.Warning != "" {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", output.Warning)
+	}
+	if len(output.Unresolved) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠ inputs of these calls are not described, so unknown keys are allowed (bundle the imports): %s\n",
+			strings.Join(output.Unresolved, ", "))
+	}
+
+	outputFile := c.String("output")
+	if outputFile == "" {
+		h.presenter.Print("%s", output.Rendered)
+		return nil
+	}
+	if err := os.WriteFile(outputFile, output.Rendered, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputFile, err)
+	}
+	h.presenter.Success("Wrote %s", outputFile)
+	return nil
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/workflow"
+	domainworkflow "github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+)
+
+// StorageReportHandler handles the workflow storage-report command.
+type StorageReportHandler struct {
+	useCase   *workflow.StorageUsageUseCase
+	presenter *presenter.Presenter
+}
+
+// NewStorageReportHandler creates a new StorageReportHandler.
+func NewStorageReportHandler(uc *workflow.StorageUsageUseCase, p *presenter.Presenter) *StorageReportHandler {
+	return &StorageReportHandler{
+		useCase:   uc,
+		presenter: p,
+	}
+}
+
+// Command returns the CLI command for reporting storage usage.
+func (h *StorageReportHandler) Command() *cli.Command {
+	return &cli.Command{
+		Name:      "storage-report",
+		Usage:     "Report the storage workflows occupy and its estimated monthly cost",
+		ArgsUsage: "[workflow-id...]",
+		Description: "Lists every file under each workflow root and sums the sizes by task and\n" +
+			"by file extension. Monthly costs use the storage_prices config key, dollars\n" +
+			"per GiB-month by service (e.g. gs=0.020,s3=0.023). Without IDs, the\n" +
+			"workflows matching --name, --status and --label are measured.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "name",
+				Aliases: []string{"n"},
+				Usage:   "[optional] Measure the workflows with this name",
+			},
+			&cli.StringSliceFlag{
+				Name:    "status",
+				Aliases: []string{"s"},
+				Usage:   "[optional] Measure the workflows with this status (Succeeded, Failed, Aborted, Running)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "label",
+				Usage: "[optional] Measure the workflows with this label (format: key=value)",
+			},
+			&cli.IntFlag{
+				Name:    "limit",
+				Aliases: []string{"l"},
+				Usage:   "[optional] Maximum number of workflows measured from the query",
+				Value:   20,
+			},
+			&cli.BoolFlag{
+				Name:  "by-extension",
+				Usage: "[optional] Also break each workflow down by file extension",
+				Value: true,
+			},
+		},
+		Action: h.handle,
+	}
+}
+
+func (h *StorageReportHandler) handle(c *cli.Context) error {
+	input := workflow.StorageUsageInput{WorkflowIDs: c.Args().Slice()}
+	if len(input.WorkflowIDs) == 0 {
+		if c.String("name") == "" && len(c.StringSlice("status")) == 0 && len(c.StringSlice("label")) == 0 {
+			h.presenter.Error("Workflow IDs, --name, --status or --label are required")
+			return cli.Exit("nothing to measure", 1)
+		}
+		labels := make(map[string]string)
+		for _, l := range c.StringSlice("label") {
+			key, value, _ := strings.Cut(l, "=")
+			labels[key] = value
+		}
+		var statuses []domainworkflow.Status
+		for _, s := range c.StringSlice("status") {
+			statuses = append(statuses, domainworkflow.Status(s))
+		}
+		input.Query = domainworkflow.QueryFilter{
+			Name:     c.String("name"),
+			Status:   statuses,
+			Labels:   labels,
+			PageSize: c.Int("limit"),
+		}
+	}
+
+	output, err := h.useCase.Execute(context.Background(), input)
+	if err != nil {
+		h.presenter.Error("Failed to measure workflow storage: %v", err)
+		return err
+	}
+	if len(output.Workflows) == 0 {
+		h.presenter.Info("No workflows found matching the criteria")
+		return nil
+	}
+
+	var (
+		bytes int64
+		cost  float64
+		fails int
+	)
+	for _, usage := range output.Workflows {
+		h.displayUsage(usage, c.Bool("by-extension"))
+		if usage.Err != nil {
+			fails++
+			continue
+		}
+		bytes += usage.Bytes
+		cost += usage.MonthlyCost
+	}
+
+	if len(output.Workflows) > 1 {
+		h.presenter.Title("Total")
+		h.presenter.KeyValue("Workflows", len(output.Workflows)-fails)
+		h.presenter.KeyValue("Stored", formatBytes(bytes))
+		h.presenter.KeyValue("Monthly cost", formatMonthlyCost(cost))
+		h.presenter.Newline()
+	}
+	h.presenter.Info("Prices per GiB-month: %s", output.Prices)
+	if fails > 0 {
+		return cli.Exit(fmt.Sprintf("%d workflow(s) could not be measured", fails), 1)
+	}
+	return nil
+}
+
+func (h *StorageReportHandler) displayUsage(usage workflow.WorkflowStorage, byExtension bool) {
+	title := usage.WorkflowID
+	if usage.WorkflowName != "" {
+		title = fmt.Sprintf("%s (%s)", usage.WorkflowName, usage.WorkflowID)
+	}
+	h.presenter.Title(title)
+	if usage.Err != nil {
+		h.presenter.Error("Failed to measure storage: %v", usage.Err)
+		h.presenter.Newline()
+		return
+	}
+	if usage.WorkflowRoot == "" {
+		h.presenter.Info("Workflow has no root directory; nothing is stored")
+		h.presenter.Newline()
+		return
+	}
+
+	h.presenter.KeyValue("Workflow Root", usage.WorkflowRoot)
+	h.presenter.KeyValue("Stored", fmt.Sprintf("%d file(s), %s", usage.Files, formatBytes(usage.Bytes)))
+	h.presenter.KeyValue("Monthly cost", formatMonthlyCost(usage.MonthlyCost))
+	if usage.ExternalFiles > 0 {
+		h.presenter.KeyValue("Outputs outside root", fmt.Sprintf("%d file(s), %s (not in the totals)", usage.ExternalFiles, formatBytes(usage.ExternalBytes)))
+	}
+	h.presenter.Newline()
+	if usage.Files == 0 {
+		return
+	}
+
+	h.displayGroups("Task", usage.Tasks, usage.WorkflowName, "(outside call roots)")
+	if byExtension {
+		h.displayGroups("Extension", usage.Extensions, "", "(none)")
+	}
+}
+
+func (h *StorageReportHandler) displayGroups(header string, groups []workflow.StorageGroup, workflowName, unnamed string) {
+	table := h.presenter.NewTable([]string{header, "Files", "Size", "Monthly cost"})
+	for _, g := range groups {
+		name := g.Name
+		if workflowName != "" {
+			name = stripWorkflowPrefix(name, workflowName)
+		}
+		if name == "" {
+			name = unnamed
+		}
+		_ = table.Append([]string{name, fmt.Sprint(g.Files), formatBytes(g.Bytes), formatMonthlyCost(g.MonthlyCost)})
+	}
+	_ = table.Render()
+	h.presenter.Newline()
+}
+
+// formatMonthlyCost shows dollars with cents, or "< $0.01" for a cost that
+// would round to nothing.
+func formatMonthlyCost(cost float64) string {
+	if cost > 0 && cost < 0.005 {
+		return "< $0.01/mo"
+	}
+	return fmt.Sprintf("$%.2f/mo", cost)
+}
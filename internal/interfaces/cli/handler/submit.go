@@ -4,12 +4,14 @@ package handler
 import (
 	"context"
 	"errors"
+	"os"
 	"strings"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/lmtani/pumbaa/internal/application/workflow"
 	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+	"github.com/lmtani/pumbaa/pkg/wdl"
 )
 
 // SubmitHandler handles the workflow submission command.
@@ -32,18 +34,13 @@ func (h *SubmitHandler) Command() *cli.Command {
 		Name:    "submit",
 		Aliases: []string{"s"},
 		Usage:   "Submit a workflow to Cromwell",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "workflow",
 				Aliases:  []string{"w"},
 				Usage:    "[required] Path to the WDL workflow file",
 				Required: true,
 			},
-			&cli.StringFlag{
-				Name:    "inputs",
-				Aliases: []string{"i"},
-				Usage:   "[optional] Path to the inputs JSON file",
-			},
 			&cli.StringFlag{
 				Name:    "options",
 				Aliases: []string{"o"},
@@ -63,7 +60,15 @@ func (h *SubmitHandler) Command() *cli.Command {
 				Name:  "skip-preflight",
 				Usage: "[optional] Submit without checking the workflow and inputs first",
 			},
-		},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "[optional] Resolve http(s) imports from the import cache only",
+			},
+			&cli.StringFlag{
+				Name:  "import-cache",
+				Usage: "[optional] Directory caching fetched http(s) imports (default: ~/.pumbaa/imports)",
+			},
+		}, inputsFlags()...),
 		Action: h.handle,
 	}
 }
@@ -84,11 +89,12 @@ func (h *SubmitHandler) handle(c *cli.Context) error {
 
 	input := workflow.SubmitInput{
 		WorkflowFile:     c.String("workflow"),
-		InputsFile:       c.String("inputs"),
+		Inputs:           inputSources(c),
 		OptionsFile:      c.String("options"),
 		DependenciesFile: c.String("dependencies"),
 		Labels:           labels,
 		SkipPreflight:    c.Bool("skip-preflight"),
+		Remote:           wdl.RemoteImportOptions{CacheDir: c.String("import-cache"), Offline: c.Bool("offline")},
 	}
 
 	output, err := h.useCase.Execute(ctx, input)
@@ -97,6 +103,7 @@ func (h *SubmitHandler) handle(c *cli.Context) error {
 		// single message, so everything can be fixed in one pass.
 		var preflightErr *workflow.PreflightFailedError
 		if errors.As(err, &preflightErr) {
+			showInputs(c, os.Stdout, preflightErr.Report.Inputs)
 			renderPreflightReport(h.presenter, preflightErr.Report)
 			h.presenter.Newline()
 			h.presenter.Info("Nothing was submitted. Fix the problems above, or use --skip-preflight to submit anyway.")
@@ -106,6 +113,8 @@ func (h *SubmitHandler) handle(c *cli.Context) error {
 		return err
 	}
 
+	showInputs(c, os.Stdout, output.Inputs)
+
 	// Confirm preflight ran: silent success would make the feature look like
 	// it never happened, and would swallow non-blocking warnings.
 	reportPreflightBeforeSubmit(h.presenter, output.Preflight, c.Bool("skip-preflight"))
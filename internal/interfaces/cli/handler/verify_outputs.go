@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/workflow"
+	domainworkflow "github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+)
+
+// VerifyOutputsHandler handles the workflow verify-outputs command.
+type VerifyOutputsHandler struct {
+	useCase   *workflow.VerifyOutputsUseCase
+	presenter *presenter.Presenter
+}
+
+// NewVerifyOutputsHandler creates a new VerifyOutputsHandler.
+func NewVerifyOutputsHandler(uc *workflow.VerifyOutputsUseCase, p *presenter.Presenter) *VerifyOutputsHandler {
+	return &VerifyOutputsHandler{
+		useCase:   uc,
+		presenter: p,
+	}
+}
+
+// Command returns the CLI command for verifying workflow outputs.
+func (h *VerifyOutputsHandler) Command() *cli.Command {
+	return &cli.Command{
+		Name:      "verify-outputs",
+		Usage:     "Check that finished workflows' output files still exist and are unchanged",
+		ArgsUsage: "[workflow-id...]",
+		Description: "Checks every output file's existence, size and checksum through storage\n" +
+			"metadata, without downloading. Without IDs, the succeeded workflows matching\n" +
+			"--name and --label are checked. Files recorded with --record are compared\n" +
+			"with that record on later runs. Exits with an error when any file is\n" +
+			"missing or changed.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "name",
+				Aliases: []string{"n"},
+				Usage:   "[optional] Check the succeeded workflows with this name",
+			},
+			&cli.StringSliceFlag{
+				Name:  "label",
+				Usage: "[optional] Check the succeeded workflows with this label (format: key=value)",
+			},
+			&cli.IntFlag{
+				Name:    "limit",
+				Aliases: []string{"l"},
+				Usage:   "[optional] Maximum number of workflows checked from the query",
+				Value:   20,
+			},
+			&cli.BoolFlag{
+				Name:    "record",
+				Aliases: []string{"r"},
+				Usage:   "[optional] Record the digests of files not yet in the baseline (~/.pumbaa/output_baseline.json)",
+			},
+		},
+		Action: h.handle,
+	}
+}
+
+func (h *VerifyOutputsHandler) handle(c *cli.Context) error {
+	input := workflow.VerifyOutputsInput{
+		WorkflowIDs: c.Args().Slice(),
+		Record:      c.Bool("record"),
+	}
+	if len(input.WorkflowIDs) == 0 {
+		if c.String("name") == "" && len(c.StringSlice("label")) == 0 {
+			h.presenter.Error("Workflow IDs, --name or --label are required")
+			return cli.Exit("nothing to verify", 1)
+		}
+		labels := make(map[string]string)
+		for _, l := range c.StringSlice("label") {
+			key, value, _ := strings.Cut(l, "=")
+			labels[key] = value
+		}
+		input.Query = domainworkflow.QueryFilter{
+			Name:     c.String("name"),
+			Labels:   labels,
+			PageSize: c.Int("limit"),
+		}
+	}
+
+	output, err := h.useCase.Execute(context.Background(), input)
+	if err != nil {
+		h.presenter.Error("Failed to verify workflow outputs: %v", err)
+		return err
+	}
+	if len(output.Workflows) == 0 {
+		h.presenter.Info("No workflows found matching the criteria")
+		return nil
+	}
+
+	problems := 0
+	for _, check := range output.Workflows {
+		problems += h.displayCheck(check)
+	}
+
+	if output.Recorded > 0 {
+		h.presenter.Info("Recorded %d file(s) in the baseline", output.Recorded)
+	}
+	if problems > 0 {
+		return cli.Exit(fmt.Sprintf("%d output file(s) missing, changed or unreadable", problems), 1)
+	}
+	h.presenter.Success("All outputs of %d workflow(s) are present", len(output.Workflows))
+	return nil
+}
+
+// displayCheck prints one workflow's verification and returns its number of
+// problems. Only files with a problem are listed.
+func (h *VerifyOutputsHandler) displayCheck(check workflow.WorkflowOutputsCheck) int {
+	title := check.WorkflowID
+	if check.WorkflowName != "" {
+		title = fmt.Sprintf("%s (%s)", check.WorkflowName, check.WorkflowID)
+	}
+	h.presenter.Title(title)
+	if check.Err != nil {
+		h.presenter.Error("Failed to get outputs: %v", check.Err)
+		h.presenter.Newline()
+		return 1
+	}
+
+	counts := make(map[workflow.OutputCheck]int)
+	for _, f := range check.Files {
+		counts[f.Status]++
+	}
+	h.presenter.KeyValue("Files", len(check.Files))
+	h.presenter.KeyValue("Intact", counts[workflow.OutputIntact])
+	h.presenter.KeyValue("Present (no baseline)", counts[workflow.OutputPresent])
+
+	problems := check.Problems()
+	if problems > 0 {
+		h.presenter.Newline()
+		table := h.presenter.NewTable([]string{"Output", "File", "Status", "Detail"})
+		for _, f := range check.Files {
+			switch f.Status {
+			case workflow.OutputIntact, workflow.OutputPresent:
+				continue
+			}
+			_ = table.Append([]string{
+				stripWorkflowPrefix(f.Key, check.WorkflowName),
+				f.Path,
+				string(f.Status),
+				f.Detail,
+			})
+		}
+		_ = table.Render()
+	}
+	h.presenter.Newline()
+	return problems
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/workflow"
+	workflowdomain "github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+)
+
+// WDLDiffHandler handles the wdl diff command.
+type WDLDiffHandler struct {
+	useCase   *workflow.WDLDiffUseCase
+	presenter *presenter.Presenter
+}
+
+// NewWDLDiffHandler creates a new WDLDiffHandler.
+func NewWDLDiffHandler(uc *workflow.WDLDiffUseCase, p *presenter.Presenter) *WDLDiffHandler {
+	return &WDLDiffHandler{useCase: uc, presenter: p}
+}
+
+// Command returns the CLI command for comparing two versions of a workflow.
+func (h *WDLDiffHandler) Command() *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Compare what two versions of a workflow do, and which calls lose their cache hits",
+		ArgsUsage: "<old.wdl> <new.wdl>",
+		Description: "Compares the calls of two versions of a workflow and the tasks behind them:\n" +
+			"added and removed calls, command templates, docker images, runtime attributes,\n" +
+			"input and output signatures and call input wiring. It then traces which calls\n" +
+			"of the new version a run of the old one can no longer serve from the call\n" +
+			"cache, on their own account or because a call upstream reruns.\n\n" +
+			"Either version may be a git revision, written REV:path (v1.2.0:main.wdl).\n" +
+			"A bundle's main WDL is read with the <name>.zip beside it.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "old-dependencies",
+				Usage: "[optional] Imports zip of the old version",
+			},
+			&cli.StringFlag{
+				Name:  "new-dependencies",
+				Usage: "[optional] Imports zip of the new version",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "[optional] Output the diff as JSON",
+			},
+		},
+		Action: h.handle,
+	}
+}
+
+func (h *WDLDiffHandler) handle(c *cli.Context) error {
+	if c.NArg() != 2 {
+		h.presenter.Error("Two versions are required: pumbaa wdl diff <old.wdl> <new.wdl>")
+		return cli.Exit("two versions required", 1)
+	}
+
+	diff, err := h.useCase.Execute(context.Background(), workflow.WDLDiffInput{
+		Old: parseWDLVersion(c.Args().Get(0), c.String("old-dependencies")),
+		New: parseWDLVersion(c.Args().Get(1), c.String("new-dependencies")),
+	})
+	if err != nil {
+		h.presenter.Error("Failed to compare workflows: %v", err)
+		return err
+	}
+
+	if c.Bool("json") {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			h.presenter.Error("Failed to encode diff: %v", err)
+			return err
+		}
+		h.presenter.Println(string(data))
+		return nil
+	}
+	h.display(diff)
+	return nil
+}
+
+// parseWDLVersion reads "REV:path" as a file at a git revision. A path that
+// exists on disk, a URL and a Windows drive letter are taken as files.
+func parseWDLVersion(arg, dependencies string) workflow.WDLVersion {
+	v := workflow.WDLVersion{WorkflowFile: arg, DependenciesFile: dependencies}
+	if _, err := os.Stat(arg); err == nil || strings.Contains(arg, "://") {
+		return v
+	}
+	rev, path, ok := strings.Cut(arg, ":")
+	if !ok || len(rev) < 2 || path == "" {
+		return v
+	}
+	v.Revision, v.WorkflowFile = rev, path
+	return v
+}
+
+func (h *WDLDiffHandler) display(diff *workflowdomain.WDLDiff) {
+	h.presenter.Title("WDL Diff")
+	h.presenter.Print("  %s  %s  %s\n", color.New(color.Bold).Sprint("old:"), diff.Old, color.HiBlackString(labelOrDash(diff.WorkflowOld)))
+	h.presenter.Print("  %s  %s  %s\n", color.New(color.Bold).Sprint("new:"), diff.New, color.HiBlackString(labelOrDash(diff.WorkflowNew)))
+	for _, w := range diff.Warnings {
+		h.presenter.Warning("%s", w)
+	}
+	if diff.WorkflowOld != diff.WorkflowNew {
+		h.presenter.Warning("Workflow names differ — comparing different workflows")
+	}
+	if !diff.HasDifferences() {
+		h.presenter.Newline()
+		h.presenter.Success("No differences found")
+		return
+	}
+
+	h.displaySignature("Workflow inputs", diff.Inputs)
+	h.displaySignature("Workflow outputs", diff.Outputs)
+	h.displayCalls(diff.Calls)
+	h.displayCache(diff)
+}
+
+func (h *WDLDiffHandler) displaySignature(title string, changes []workflowdomain.SignatureChange) {
+	if len(changes) == 0 {
+		return
+	}
+	h.presenter.Newline()
+	h.presenter.Title(fmt.Sprintf("%s (%d changed)", title, len(changes)))
+	for _, line := range signatureLines(changes) {
+		h.presenter.Print("  %s\n", line)
+	}
+}
+
+func (h *WDLDiffHandler) displayCalls(calls []workflowdomain.CallChange) {
+	h.presenter.Newline()
+	if len(calls) == 0 {
+		h.presenter.Title("Calls (no changes)")
+		return
+	}
+	h.presenter.Title(fmt.Sprintf("Calls (%d changed)", len(calls)))
+	for _, c := range calls {
+		switch c.Kind {
+		case workflowdomain.ChangeAdded:
+			h.presenter.Print("  %s %s  %s\n", color.GreenString("+"), c.Call, color.HiBlackString("task "+c.TaskNew))
+		case workflowdomain.ChangeRemoved:
+			h.presenter.Print("  %s %s  %s\n", color.RedString("-"), c.Call, color.HiBlackString("task "+c.TaskOld))
+		default:
+			h.presenter.Print("  %s %s\n", color.YellowString("~"), c.Call)
+			for _, line := range callChangeLines(c) {
+				h.presenter.Print("      %s\n", line)
+			}
+		}
+	}
+}
+
+// callChangeLines describes the specific changes of a modified call.
+func callChangeLines(c workflowdomain.CallChange) []string {
+	var lines []string
+	if c.TaskOld != c.TaskNew {
+		lines = append(lines, fmt.Sprintf("task:     %s → %s", c.TaskOld, c.TaskNew))
+	}
+	if c.CommandChanged() {
+		lines = append(lines, fmt.Sprintf("command:  template changed (%s → %s)",
+			shortHash(c.CommandHashOld), shortHash(c.CommandHashNew)))
+	}
+	for _, kd := range c.Runtime {
+		lines = append(lines, "runtime:  "+keyDiffText(kd))
+	}
+	for _, line := range signatureLines(c.Inputs) {
+		lines = append(lines, "input:    "+line)
+	}
+	for _, line := range signatureLines(c.Outputs) {
+		lines = append(lines, "output:   "+line)
+	}
+	for _, kd := range c.Bindings {
+		lines = append(lines, "wiring:   "+keyDiffText(kd))
+	}
+	if len(c.CacheReasons) == 0 {
+		lines = append(lines, color.GreenString("cache:    fingerprint unchanged"))
+	}
+	return lines
+}
+
+func (h *WDLDiffHandler) displayCache(diff *workflowdomain.WDLDiff) {
+	h.presenter.Newline()
+	lost := len(diff.Reruns) + len(diff.Downstream)
+	if lost == 0 && len(diff.Undetermined) == 0 {
+		h.presenter.Title("Call cache (every call keeps its hits)")
+		return
+	}
+	h.presenter.Title(fmt.Sprintf("Call cache (%d calls lose their hits)", lost))
+	for _, l := range diff.Reruns {
+		h.presenter.Print("  %s %s  %s\n", color.RedString("✗"), l.Call, strings.Join(l.Reasons, "; "))
+	}
+	for _, l := range diff.Downstream {
+		h.presenter.Print("  %s %s  %s\n", color.YellowString("↓"), l.Call, color.HiBlackString("downstream of "+l.Cause))
+	}
+	for _, l := range diff.Undetermined {
+		h.presenter.Print("  %s %s  %s\n", color.HiBlackString("?"), l.Call, color.HiBlackString(strings.Join(l.Reasons, "; ")))
+	}
+}
+
+func signatureLines(changes []workflowdomain.SignatureChange) []string {
+	lines := make([]string, 0, len(changes))
+	for _, sc := range changes {
+		switch sc.Kind {
+		case workflowdomain.ChangeAdded:
+			lines = append(lines, fmt.Sprintf("%s %s", color.GreenString("+"), sc.New))
+		case workflowdomain.ChangeRemoved:
+			lines = append(lines, fmt.Sprintf("%s %s", color.RedString("-"), sc.Old))
+		default:
+			lines = append(lines, fmt.Sprintf("%s %s → %s", color.YellowString("~"), sc.Old, sc.New))
+		}
+	}
+	return lines
+}
+
+func keyDiffText(kd workflowdomain.KeyDiff) string {
+	switch kd.Kind {
+	case workflowdomain.ChangeAdded:
+		return fmt.Sprintf("%s %s: %s", color.GreenString("+"), kd.Key, valuePreview(kd.ValueB))
+	case workflowdomain.ChangeRemoved:
+		return fmt.Sprintf("%s %s: %s", color.RedString("-"), kd.Key, valuePreview(kd.ValueA))
+	default:
+		return fmt.Sprintf("%s %s: %s → %s", color.YellowString("~"), kd.Key, valuePreview(kd.ValueA), valuePreview(kd.ValueB))
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return color.HiBlackString(hash[:8])
+	}
+	return color.HiBlackString(hash)
+}
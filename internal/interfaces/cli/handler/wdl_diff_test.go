@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+
+	"github.com/lmtani/pumbaa/internal/application/workflow"
+	workflowdomain "github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+)
+
+func TestParseWDLVersion(t *testing.T) {
+	existing := filepath.Join(t.TempDir(), "a:b.wdl")
+	if err := os.WriteFile(existing, []byte("version 1.0"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		arg  string
+		want workflow.WDLVersion
+	}{
+		{"main.wdl", workflow.WDLVersion{WorkflowFile: "main.wdl"}},
+		{"v1.2.0:wf/main.wdl", workflow.WDLVersion{WorkflowFile: "wf/main.wdl", Revision: "v1.2.0"}},
+		{"HEAD~1:main.wdl", workflow.WDLVersion{WorkflowFile: "main.wdl", Revision: "HEAD~1"}},
+		{"https://example.com/main.wdl", workflow.WDLVersion{WorkflowFile: "https://example.com/main.wdl"}},
+		{`C:\wf\main.wdl`, workflow.WDLVersion{WorkflowFile: `C:\wf\main.wdl`}},
+		{existing, workflow.WDLVersion{WorkflowFile: existing}},
+	}
+	for _, tt := range tests {
+		if got := parseWDLVersion(tt.arg, ""); got != tt.want {
+			t.Errorf("parseWDLVersion(%q) = %+v, want %+v", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestWDLDiffHandler_Display(t *testing.T) {
+	color.NoColor = true
+
+	var buf bytes.Buffer
+	h := &WDLDiffHandler{presenter: presenter.New(&buf)}
+	h.display(&workflowdomain.WDLDiff{
+		Old: "v1:main.wdl", New: "main.wdl", WorkflowOld: "Pipe", WorkflowNew: "Pipe",
+		Inputs: []workflowdomain.SignatureChange{{Name: "sample", Kind: workflowdomain.ChangeAdded, New: "String sample"}},
+		Calls: []workflowdomain.CallChange{
+			{
+				Call: "Align", Kind: workflowdomain.ChangeModified, TaskOld: "Align", TaskNew: "Align",
+				CommandHashOld: "AAAAAAAA11", CommandHashNew: "BBBBBBBB22",
+				CacheReasons: []string{"command template changed"},
+			},
+			{
+				Call: "QC", Kind: workflowdomain.ChangeModified, TaskOld: "QC", TaskNew: "QC",
+				Runtime: []workflowdomain.KeyDiff{{Key: "cpu", Kind: workflowdomain.ChangeModified, ValueA: "2", ValueB: "8"}},
+			},
+			{Call: "Old", Kind: workflowdomain.ChangeRemoved, TaskOld: "Old"},
+		},
+		Reruns:     []workflowdomain.CacheLoss{{Call: "Align", Reasons: []string{"command template changed"}}},
+		Downstream: []workflowdomain.CacheLoss{{Call: "Sort", Cause: "Align"}},
+	})
+	out := buf.String()
+
+	for _, want := range []string{
+		"+ String sample",
+		"~ Align",
+		"command:  template changed (AAAAAAAA → BBBBBBBB)",
+		"runtime:  ~ cpu: 2 → 8",
+		"cache:    fingerprint unchanged",
+		"- Old",
+		"Call cache (2 calls lose their hits)",
+		"Sort  downstream of Align",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
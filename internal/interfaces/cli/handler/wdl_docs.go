@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+)
+
+// WDLDocsHandler handles the wdl docs command.
+type WDLDocsHandler struct {
+	openIndex WDLIndexProvider
+	useCase   *workflow.WDLDocsUseCase
+	presenter *presenter.Presenter
+}
+
+// NewWDLDocsHandler creates a new WDLDocsHandler.
+func NewWDLDocsHandler(openIndex WDLIndexProvider, uc *workflow.WDLDocsUseCase, p *presenter.Presenter) *WDLDocsHandler {
+	return &WDLDocsHandler{openIndex: openIndex, useCase: uc, presenter: p}
+}
+
+// Command returns the CLI command for generating WDL documentation.
+func (h *WDLDocsHandler) Command() *cli.Command {
+	return &cli.Command{
+		Name:  "docs",
+		Usage: "Generate documentation for the indexed workflows, tasks and structs",
+		Description: "Writes one page per workflow, task and struct of the WDL index, plus an\n" +
+			"index page: descriptions and other meta, inputs and outputs with their\n" +
+			"defaults and parameter_meta, runtime attributes, commands and call graphs.\n" +
+			"Pages link to the tasks a workflow calls, the workflows calling a task, and\n" +
+			"the structs a type names.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "output",
+				Aliases:  []string{"o"},
+				Usage:    "[required] Directory to write the pages to",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Value:   string(workflow.DocsMarkdown),
+				Usage:   fmt.Sprintf("[optional] Page format: %v", workflow.DocsFormats),
+			},
+			&cli.StringFlag{
+				Name:  "title",
+				Usage: "[optional] Title of the index page (default: WDL Documentation)",
+			},
+			&cli.BoolFlag{
+				Name:  "no-graph",
+				Usage: "[optional] Leave call graphs out of workflow pages",
+			},
+			&cli.BoolFlag{
+				Name:  "mermaid",
+				Usage: "[optional] Draw call graphs in HTML pages with Mermaid, loaded from cdn.jsdelivr.net when a page is viewed",
+			},
+			&cli.StringFlag{
+				Name:  "wdl-dir",
+				Usage: "[optional] Directories to document instead of the configured ones, separated by ':'",
+			},
+		},
+		Action: h.handle,
+	}
+}
+
+func (h *WDLDocsHandler) handle(c *cli.Context) error {
+	repo, err := h.openIndex(c.String("wdl-dir"), false)
+	if err != nil {
+		return err
+	}
+	idx, err := repo.List()
+	if err != nil {
+		return err
+	}
+
+	output, err := h.useCase.Execute(context.Background(), workflow.WDLDocsInput{
+		Index:    idx,
+		Format:   workflow.DocsFormat(c.String("format")),
+		Title:    c.String("title"),
+		NoGraphs: c.Bool("no-graph"),
+		Mermaid:  c.Bool("mermaid"),
+	})
+	if err != nil {
+		return err
+	}
+
+	dir := c.String("output")
+	for _, page := range output.Pages {
+		path := filepath.Join(dir, filepath.FromSlash(page.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, page.Content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+
+	for _, w := range output.Warnings {
+		h.presenter.Warning("%s", w)
+	}
+	if errs := idx.Errors(); len(errs) > 0 {
+		h.presenter.Warning("%d file(s) could not be parsed and are not documented (see 'pumbaa wdl index')", len(errs))
+	}
+	h.presenter.Success("Documented %d workflows, %d tasks and %d structs in %s",
+		output.Workflows, output.Tasks, output.Structs, filepath.Join(dir, output.Pages[0].Path))
+	return nil
+}
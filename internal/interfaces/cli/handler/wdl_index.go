@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+)
+
+// WDLIndexProvider opens the WDL index, bringing it up to date. wdlDir
+// overrides the configured directories when not empty, and rebuild parses
+// every file again. Wired to Container.WDLIndex.
+type WDLIndexProvider func(wdlDir string, rebuild bool) (ports.WDLRepository, error)
+
+// WDLIndexHandler handles the wdl index command.
+type WDLIndexHandler struct {
+	openIndex WDLIndexProvider
+	presenter *presenter.Presenter
+}
+
+// NewWDLIndexHandler creates a new WDLIndexHandler.
+func NewWDLIndexHandler(openIndex WDLIndexProvider, p *presenter.Presenter) *WDLIndexHandler {
+	return &WDLIndexHandler{openIndex: openIndex, presenter: p}
+}
+
+// Command returns the CLI command for refreshing the WDL index.
+func (h *WDLIndexHandler) Command() *cli.Command {
+	return &cli.Command{
+		Name:  "index",
+		Usage: "Update the WDL index used by chat and report files that fail to parse",
+		Description: "Indexes the tasks, workflows and structs of every .wdl file under the WDL\n" +
+			"directories (PUMBAA_WDL_DIR or wdl_directory; separate several with ':'),\n" +
+			"plus the local files they import. Only files changed since the last run are\n" +
+			"parsed again. Files that fail to parse are listed with their error.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "wdl-dir",
+				Usage: "[optional] Directories to index instead of the configured ones, separated by ':'",
+			},
+			&cli.BoolFlag{
+				Name:  "rebuild",
+				Usage: "[optional] Ignore the cache and parse every file again",
+			},
+		},
+		Action: h.handle,
+	}
+}
+
+func (h *WDLIndexHandler) handle(c *cli.Context) error {
+	repo, err := h.openIndex(c.String("wdl-dir"), c.Bool("rebuild"))
+	if err != nil {
+		return err
+	}
+	idx, err := repo.List()
+	if err != nil {
+		return err
+	}
+	stats := repo.Stats()
+
+	h.presenter.Title("WDL Index")
+	h.presenter.KeyValue("Roots", strings.Join(idx.Roots, ", "))
+	h.presenter.KeyValue("Files", len(idx.Files))
+	h.presenter.KeyValue("Tasks", len(idx.Tasks))
+	h.presenter.KeyValue("Workflows", len(idx.Workflows))
+	h.presenter.KeyValue("Structs", len(idx.Structs))
+	h.presenter.KeyValue("Updated", fmt.Sprintf("%d parsed, %d unchanged, %d removed", stats.Parsed, stats.Reused, stats.Removed))
+
+	errs := idx.Errors()
+	if len(errs) == 0 {
+		h.presenter.Newline()
+		h.presenter.Success("All files parsed")
+		return nil
+	}
+
+	h.presenter.Newline()
+	h.presenter.Warning("%d file(s) could not be parsed and define nothing in the index:", len(errs))
+	table := h.presenter.NewTable([]string{"File", "Error"})
+	for _, e := range errs {
+		_ = table.Append([]string{e.Path, e.Message})
+	}
+	_ = table.Render()
+	return nil
+}
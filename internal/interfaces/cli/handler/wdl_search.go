@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/lmtani/pumbaa/internal/domain/wdlindex"
+	"github.com/lmtani/pumbaa/internal/interfaces/cli/presenter"
+)
+
+// WDLSearchHandler handles the wdl search command.
+type WDLSearchHandler struct {
+	openIndex WDLIndexProvider
+	presenter *presenter.Presenter
+}
+
+// NewWDLSearchHandler creates a new WDLSearchHandler.
+func NewWDLSearchHandler(openIndex WDLIndexProvider, p *presenter.Presenter) *WDLSearchHandler {
+	return &WDLSearchHandler{openIndex: openIndex, presenter: p}
+}
+
+// Command returns the CLI command for searching the WDL index.
+func (h *WDLSearchHandler) Command() *cli.Command {
+	return &cli.Command{
+		Name:      "search",
+		Usage:     "Search the indexed tasks, workflows and structs, best matches first",
+		ArgsUsage: "<query...>",
+		Description: "Ranks definitions by how well they match the query (BM25) across their\n" +
+			"names, meta descriptions, docker images, commands, workflow calls and struct\n" +
+			"members, and shows where each matched. Names count most. A term also matches\n" +
+			"words it starts (\"samtool\" finds \"samtools\"), and camelCase names split into\n" +
+			"words (\"caller\" finds \"HaplotypeCaller\").",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "kind",
+				Usage: "[optional] Only show one kind: task, workflow or struct",
+			},
+			&cli.IntFlag{
+				Name:    "limit",
+				Aliases: []string{"n"},
+				Value:   10,
+				Usage:   "[optional] Maximum number of results (0 for all)",
+			},
+			&cli.StringFlag{
+				Name:  "wdl-dir",
+				Usage: "[optional] Directories to search instead of the configured ones, separated by ':'",
+			},
+		},
+		Action: h.handle,
+	}
+}
+
+func (h *WDLSearchHandler) handle(c *cli.Context) error {
+	query := strings.Join(c.Args().Slice(), " ")
+	if strings.TrimSpace(query) == "" {
+		h.presenter.Error("A search query is required")
+		return cli.Exit("query required", 1)
+	}
+	kind := strings.ToLower(c.String("kind"))
+	switch kind {
+	case "", wdlindex.KindTask, wdlindex.KindWorkflow, wdlindex.KindStruct:
+	default:
+		return fmt.Errorf("invalid --kind %q: use task, workflow or struct", c.String("kind"))
+	}
+
+	repo, err := h.openIndex(c.String("wdl-dir"), false)
+	if err != nil {
+		return err
+	}
+	hits, err := repo.Search(query, wdlindex.SearchOptions{Kind: kind, Limit: c.Int("limit")})
+	if err != nil {
+		return err
+	}
+
+	if len(hits) == 0 {
+		h.presenter.Info("No definitions match %q", query)
+		return nil
+	}
+
+	highlight := color.New(color.FgYellow, color.Bold).SprintFunc()
+	for n, hit := range hits {
+		if n > 0 {
+			h.presenter.Newline()
+		}
+		h.presenter.Print("%s %s  %s\n",
+			color.New(color.Bold).Sprint(hit.Name),
+			color.CyanString("(%s)", hit.Kind),
+			color.HiBlackString("%s · score %.2f", hit.Source, hit.Score))
+		for _, sn := range hit.Snippets {
+			if sn.Field == wdlindex.FieldName {
+				continue
+			}
+			h.presenter.Print("  %s %s\n", color.HiBlackString("%-11s", sn.Field+":"), highlightSnippet(sn, highlight))
+		}
+	}
+	return nil
+}
+
+// highlightSnippet renders a snippet with its matches passed through mark.
+func highlightSnippet(sn wdlindex.Snippet, mark func(a ...any) string) string {
+	var b strings.Builder
+	last := 0
+	for _, span := range sn.Highlights {
+		b.WriteString(sn.Text[last:span.Start])
+		b.WriteString(mark(sn.Text[span.Start:span.End]))
+		last = span.End
+	}
+	b.WriteString(sn.Text[last:])
+	return b.String()
+}
@@ -28,6 +28,20 @@ func maxInt(a, b int) int {
 	return common.MaxInt(a, b)
 }
 
+// formatBytes formats a byte count as a human-readable size.
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
 // formatDuration formats a duration into a human-readable string (seconds, minutes, or hours).
 func formatDuration(d time.Duration) string {
 	return common.FormatDurationShort(d)
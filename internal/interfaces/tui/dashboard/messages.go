@@ -1,6 +1,7 @@
 package dashboard
 
 import (
+	workflowapp "github.com/lmtani/pumbaa/internal/application/workflow"
 	"github.com/lmtani/pumbaa/internal/domain/workflow"
 )
 
@@ -13,6 +14,11 @@ type diffErrorMsg struct {
 	err error
 }
 
+// storageMeasuredMsg carries the storage report of one workflow.
+type storageMeasuredMsg struct {
+	usage workflowapp.WorkflowStorage
+}
+
 // Messages for dashboard model
 
 type workflowsLoadedMsg struct {
@@ -77,6 +77,11 @@ type Model struct {
 	diffResult      *workflow.RunDiff
 	diffError       string
 
+	// Storage report state: measured workflows by ID, nil while in flight
+	storageUC      *workflowapp.StorageUsageUseCase
+	storage        map[string]*workflowapp.WorkflowStorage
+	storagePending int
+
 	// Debug transition state
 	loadingDebug    bool
 	loadingDebugID  string
@@ -132,9 +137,10 @@ func NewModel() Model {
 
 // NewModelWithRepository creates a new dashboard model with all repository capabilities.
 // The repository satisfies WorkflowQuerier, WorkflowAborter, WorkflowMetadataFetcher,
-// HealthChecker, and LabelManager through interface composition. compareUC may be
-// nil, in which case the compare feature is disabled.
-func NewModelWithRepository(repo ports.WorkflowRepository, compareUC *workflowapp.CompareUseCase, version string, updateChecker ports.UpdateChecker) Model {
+// HealthChecker, and LabelManager through interface composition. compareUC and
+// storageUC may be nil, in which case the compare and storage features are
+// disabled.
+func NewModelWithRepository(repo ports.WorkflowRepository, compareUC *workflowapp.CompareUseCase, storageUC *workflowapp.StorageUsageUseCase, version string, updateChecker ports.UpdateChecker) Model {
 	m := NewModel()
 	m.querier = repo
 	m.aborter = repo
@@ -142,6 +148,7 @@ func NewModelWithRepository(repo ports.WorkflowRepository, compareUC *workflowap
 	m.healthChecker = repo
 	m.labelManager = repo
 	m.compareUC = compareUC
+	m.storageUC = storageUC
 	m.currentVersion = version
 	m.updateChecker = updateChecker
 	m.loading = true
@@ -152,7 +159,7 @@ func NewModelWithRepository(repo ports.WorkflowRepository, compareUC *workflowap
 // dies while the screen is hidden, since spinner ticks are only routed to
 // the focused screen.
 func (m *Model) ResumeCmd() tea.Cmd {
-	if m.loading || m.loadingDebug || m.labelsLoading || m.labelsUpdating {
+	if m.loading || m.loadingDebug || m.labelsLoading || m.labelsUpdating || m.storagePending > 0 {
 		return m.spinner.Tick
 	}
 	return nil
@@ -204,7 +211,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.filterInput.Width = minInt(40, m.width-20)
 
 	case spinner.TickMsg:
-		if m.loading || m.loadingDebug || m.labelsLoading || m.labelsUpdating || m.diffLoading || m.statusMsg != "" {
+		if m.loading || m.loadingDebug || m.labelsLoading || m.labelsUpdating || m.diffLoading || m.storagePending > 0 || m.statusMsg != "" {
 			m.spinner, cmd = m.spinner.Update(msg)
 			cmds = append(cmds, cmd)
 		}
@@ -221,6 +228,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.LastError = msg.err
 		return m, nil
 
+	case storageMeasuredMsg:
+		usage := msg.usage
+		m.storage[usage.WorkflowID] = &usage
+		m.storagePending--
+		if usage.Err != nil {
+			m.LastError = usage.Err
+		}
+		if m.storagePending == 0 {
+			m.setStatusMessage("✓ Storage measured")
+			cmds = append(cmds, getClearStatusCmd())
+		}
+
 	case workflowsLoadedMsg:
 		m.loading = false
 		m.allWorkflows = msg.workflows
@@ -0,0 +1,76 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	workflowapp "github.com/lmtani/pumbaa/internal/application/workflow"
+)
+
+// storageColumnWidth fits a size and a monthly cost, e.g. "1023.9 GB $20.48".
+const storageColumnWidth = 17
+
+// handleStorageKey measures the storage of the workflows on screen that have
+// not been measured yet. Each workflow is listed in its own command, so the
+// STORAGE column fills in as the listings finish.
+func (m *Model) handleStorageKey() tea.Cmd {
+	if m.storageUC == nil {
+		m.setStatusMessage("Storage report not available")
+		return getClearStatusCmd()
+	}
+	if m.storage == nil {
+		m.storage = make(map[string]*workflowapp.WorkflowStorage)
+	}
+
+	var cmds []tea.Cmd
+	end := minInt(m.scrollY+m.getVisibleRows(), len(m.workflows))
+	for _, wf := range m.workflows[m.scrollY:end] {
+		if _, seen := m.storage[wf.ID]; seen {
+			continue
+		}
+		// A nil entry marks a measurement in flight.
+		m.storage[wf.ID] = nil
+		m.storagePending++
+		cmds = append(cmds, m.measureStorage(wf.ID))
+	}
+	if len(cmds) == 0 {
+		if m.storagePending > 0 {
+			m.setStatusMessage("Storage is still being measured")
+		} else {
+			m.setStatusMessage("Storage of the rows on screen is already measured")
+		}
+		return getClearStatusCmd()
+	}
+	m.setStatusMessage(fmt.Sprintf("Measuring storage of %d workflow(s)...", len(cmds)))
+	return tea.Batch(append(cmds, m.spinner.Tick)...)
+}
+
+// measureStorage lists one workflow's files off the UI thread.
+func (m *Model) measureStorage(id string) tea.Cmd {
+	uc := m.storageUC
+	return func() tea.Msg {
+		return storageMeasuredMsg{usage: uc.Measure(context.Background(), id)}
+	}
+}
+
+// storageCell renders a workflow's measured storage for the STORAGE column.
+func (m Model) storageCell(id string) string {
+	usage, seen := m.storage[id]
+	switch {
+	case !seen:
+		return "-"
+	case usage == nil:
+		return "…"
+	case usage.Err != nil:
+		return "error"
+	}
+	return fmt.Sprintf("%s $%.2f", formatBytes(usage.Bytes), usage.MonthlyCost)
+}
+
+// showStorageColumn reports whether any workflow has been measured, or is
+// being measured; until then the table keeps its space for labels.
+func (m Model) showStorageColumn() bool {
+	return len(m.storage) > 0
+}
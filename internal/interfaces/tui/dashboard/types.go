@@ -23,6 +23,7 @@ type KeyMap struct {
 	Help          key.Binding
 	ErrorDetail   key.Binding // Show full text of the last error
 	Compare       key.Binding // Mark base / compare two workflows
+	Storage       key.Binding // Measure the storage of the workflows on screen
 }
 
 // DefaultKeyMap returns the default key bindings for the dashboard.
@@ -81,6 +82,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("c"),
 			key.WithHelp("c", "mark base / compare"),
 		),
+		Storage: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "measure storage"),
+		),
 	}
 }
 
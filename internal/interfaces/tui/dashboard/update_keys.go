@@ -159,6 +159,11 @@ func (m Model) handleMainKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case key.Matches(msg, m.keys.Storage):
+		if cmd := m.handleStorageKey(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
 	case key.Matches(msg, m.keys.Escape):
 		// Nothing to close here; let the app decide (dashboard is the root,
 		// so this triggers the quit confirmation).
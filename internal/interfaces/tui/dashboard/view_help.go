@@ -39,6 +39,7 @@ func (m Model) renderHelpModal() string {
 	content.WriteString(helpLine("L", "Edit labels"))
 	content.WriteString(helpLine("r", "Refresh list"))
 	content.WriteString(helpLine("w", "Toggle auto-refresh (30s)"))
+	content.WriteString(helpLine("S", "Measure storage of rows"))
 	content.WriteString("\n")
 
 	content.WriteString(common.MutedStyle.Render("Press any key to close"))
@@ -24,14 +24,17 @@ func (m Model) renderTable() string {
 		BorderForeground(common.BorderColor)
 
 	colWidths := m.getColumnWidths()
-	header := fmt.Sprintf("%-*s  %-*s  %-*s  %-*s  %-*s  %-*s",
+	header := fmt.Sprintf("%-*s  %-*s  %-*s  %-*s  %-*s  ",
 		colWidths[0], "STATUS",
 		colWidths[1], "ID",
 		colWidths[2], "NAME",
 		colWidths[3], "SUBMITTED",
 		colWidths[4], "DURATION",
-		colWidths[5], "LABELS",
 	)
+	if m.showStorageColumn() {
+		header += fmt.Sprintf("%-*s  ", storageColumnWidth, "STORAGE")
+	}
+	header += fmt.Sprintf("%-*s", colWidths[5], "LABELS")
 	header = common.TruncateWidth(header, m.width-6)
 	b.WriteString(headerStyle.Render(header) + "\n")
 
@@ -87,6 +90,9 @@ func (m Model) renderWorkflowRow(wf workflow.Workflow, colWidths []int, selected
 		common.PadRight(submitted, colWidths[3]),
 		common.PadLeft(duration, colWidths[4]),
 	}
+	if m.showStorageColumn() {
+		cells = append(cells, common.PadLeft(m.storageCell(wf.ID), storageColumnWidth))
+	}
 
 	// Labels get whatever width remains, so the row never overflows the panel
 	base := strings.Join(cells, "  ") + "  "
@@ -111,17 +117,25 @@ func (m Model) renderWorkflowRow(wf workflow.Workflow, colWidths []int, selected
 		common.ValueStyle.Render(cells[2]),
 		common.MutedStyle.Render(cells[3]),
 		common.MutedStyle.Render(cells[4]),
-		common.MutedStyle.Render(labels),
 	}
+	if m.showStorageColumn() {
+		parts = append(parts, common.ValueStyle.Render(cells[5]))
+	}
+	parts = append(parts, common.MutedStyle.Render(labels))
 	return common.TruncateANSI(strings.Join(parts, "  "), maxRowWidth)
 }
 
-// getColumnWidths calculates the width of each table column based on available space.
+// getColumnWidths calculates the width of each table column based on available
+// space. The optional STORAGE column has a fixed width and is not included.
 func (m Model) getColumnWidths() []int {
 	// STATUS(12) + ID(9) + SUBMITTED(15) + DURATION(8) = 44 fixed columns,
 	// plus 5 separators of 2 cells = 54. NAME and LABELS share the rest.
+	// Once storage is measured, STORAGE and its separator take from them.
 	maxRowWidth := m.width - 6
 	available := maxRowWidth - 54
+	if m.showStorageColumn() {
+		available -= storageColumnWidth + 2
+	}
 
 	// Distribute remaining space: 30% NAME, 70% LABELS. The row renderer gives
 	// LABELS whatever is left, so only NAME needs clamping here. The floor of
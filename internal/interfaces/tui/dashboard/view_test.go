@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 
+	workflowapp "github.com/lmtani/pumbaa/internal/application/workflow"
 	"github.com/lmtani/pumbaa/internal/domain/workflow"
 )
 
@@ -92,3 +93,26 @@ func TestApplyLocalFilter(t *testing.T) {
 		t.Errorf("empty filter shows %d workflows, want full list (2)", len(m.workflows))
 	}
 }
+
+// TestViewWithStorageColumnFillsTerminal ensures the STORAGE column, shown
+// once storage is measured, takes its width from NAME and LABELS.
+func TestViewWithStorageColumnFillsTerminal(t *testing.T) {
+	for _, w := range []int{80, 120} {
+		m := testModel(w, 24)
+		m.storage = map[string]*workflowapp.WorkflowStorage{
+			m.workflows[0].ID: nil, // still being measured
+			m.workflows[1].ID: {WorkflowID: m.workflows[1].ID, Bytes: 3 << 40, MonthlyCost: 61.44},
+		}
+
+		view := m.View()
+		if got := lipgloss.Height(view); got != 24 {
+			t.Errorf("View() at width %d with storage has height %d, want 24", w, got)
+		}
+		if got := lipgloss.Width(view); got > w {
+			t.Errorf("View() at width %d with storage has width %d", w, got)
+		}
+		if got := m.storageCell(m.workflows[1].ID); got != "3.0 TB $61.44" {
+			t.Errorf("storageCell() = %q, want %q", got, "3.0 TB $61.44")
+		}
+	}
+}
@@ -57,21 +57,21 @@ func (m Model) collectChatContext() tea.Cmd {
 
 		// Collect stderr
 		if sel.Stderr && node.CallData.Stderr != "" {
-			content, err := m.fileProvider.Read(ctx, node.CallData.Stderr)
+			page, err := m.logReader.Tail(ctx, node.CallData.Stderr)
 			if err != nil {
 				errors = append(errors, fmt.Sprintf("Stderr: %v", err))
 			} else {
-				data.StderrContent = content
+				data.StderrContent = page.Content
 			}
 		}
 
 		// Collect stdout
 		if sel.Stdout && node.CallData.Stdout != "" {
-			content, err := m.fileProvider.Read(ctx, node.CallData.Stdout)
+			page, err := m.logReader.Tail(ctx, node.CallData.Stdout)
 			if err != nil {
 				errors = append(errors, fmt.Sprintf("Stdout: %v", err))
 			} else {
-				data.StdoutContent = content
+				data.StdoutContent = page.Content
 			}
 		}
 
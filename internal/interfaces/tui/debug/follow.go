@@ -0,0 +1,175 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	workflowapp "github.com/lmtani/pumbaa/internal/application/workflow"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/tui/common"
+)
+
+// logFollowInterval is how often a followed log is polled for new bytes.
+const logFollowInterval = 2 * time.Second
+
+// logFollowStatusEvery is how many polls pass between checks of the call's
+// status, which needs a metadata fetch.
+const logFollowStatusEvery = 5
+
+type logFollowTickMsg struct{}
+
+// logFollowMsg carries what a followed log gained since the loaded window
+// ended, and the call's status when it was checked.
+type logFollowMsg struct {
+	page        workflowapp.LogPage
+	highlighted string
+	status      workflow.Status
+	err         error
+}
+
+func logFollowTick() tea.Cmd {
+	return tea.Tick(logFollowInterval, func(time.Time) tea.Msg {
+		return logFollowTickMsg{}
+	})
+}
+
+// owningWorkflowID returns the ID of the workflow or subworkflow a node's
+// call belongs to, whose metadata holds the call's status.
+func (m Model) owningWorkflowID(node *TreeNode) string {
+	for p := node.Parent; p != nil; p = p.Parent {
+		if p.Type == NodeTypeSubWorkflow && p.SubWorkflowID != "" {
+			return p.SubWorkflowID
+		}
+	}
+	return m.metadata.ID
+}
+
+// toggleLogFollow turns following of the open task log on or off. Following
+// keeps the view at the end of the log as the task writes to it, and stops
+// by itself when the call attempt finishes.
+func (m Model) toggleLogFollow() (tea.Model, tea.Cmd) {
+	if m.logFollowing {
+		m.logFollowing = false
+		m.setStatusMessage("Follow stopped")
+		return m, getClearStatusCmd()
+	}
+
+	switch {
+	case m.logCall == nil:
+		m.setStatusMessage("Only task logs can be followed")
+	case m.logCall.IsTerminal():
+		m.setStatusMessage(fmt.Sprintf("Call already finished (%s) — nothing to follow", m.logCall.Status))
+	case m.fetcher == nil:
+		m.setStatusMessage("Follow requires a server connection (open with --id)")
+	case strings.HasSuffix(m.logModalPath, ".gz"):
+		m.setStatusMessage("Compressed logs cannot be followed")
+	default:
+		m.logFollowing = true
+		m.logFollowPolls = 0
+		m.setStatusMessage(fmt.Sprintf("Following log (every %ds) until the call finishes", int(logFollowInterval.Seconds())))
+		cmds := []tea.Cmd{getClearStatusCmd(), logFollowTick()}
+		if m.logModalEnd < m.logModalSize && !m.logModalPaging {
+			m.logModalPaging = true
+			cmds = append(cmds, m.jumpLogPage(false))
+		} else {
+			m.logModalViewport.GotoBottom()
+		}
+		return m, tea.Batch(cmds...)
+	}
+	return m, getClearStatusCmd()
+}
+
+// pollFollowedLog returns a command reading what the log gained past the
+// loaded window, checking the call's status first when checkStatus is set:
+// a call seen finished has written all it will before the log is read.
+func (m Model) pollFollowedLog(checkStatus bool) tea.Cmd {
+	reader, fetcher := m.logReader, m.fetcher
+	path, end := m.logModalPath, m.logModalEnd
+	call, workflowID := *m.logCall, m.logCallWorkflowID
+	return func() tea.Msg {
+		ctx := context.Background()
+		var msg logFollowMsg
+		if checkStatus {
+			data, err := fetcher.GetRawMetadataWithOptions(ctx, workflowID, false)
+			if err != nil {
+				return logFollowMsg{err: err}
+			}
+			wf, err := fetcher.ParseMetadata(data)
+			if err != nil {
+				return logFollowMsg{err: err}
+			}
+			for _, c := range wf.Calls[call.Name] {
+				if c.ShardIndex == call.ShardIndex && c.Attempt == call.Attempt {
+					msg.status = c.Status
+				}
+			}
+		}
+		page, err := reader.PageAfter(ctx, path, end)
+		if err != nil {
+			return logFollowMsg{err: err}
+		}
+		msg.page, msg.highlighted = page, highlightLogPage(page)
+		return msg
+	}
+}
+
+// handleLogFollowMsg polls the followed log on each tick and appends what it
+// gained, keeping the view at the end when it was there.
+func (m Model) handleLogFollowMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.logFollowing || m.activeModal != ModalLog {
+		m.logFollowing = false
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case logFollowTickMsg:
+		if m.logModalPaging {
+			return m, logFollowTick()
+		}
+		m.logModalPaging = true
+		m.logFollowPolls++
+		return m, m.pollFollowedLog(m.logFollowPolls%logFollowStatusEvery == 0)
+
+	case logFollowMsg:
+		m.logModalPaging = false
+		if msg.err != nil {
+			m.logFollowing = false
+			m.setStatusMessage("Follow failed: " + common.Truncate(msg.err.Error(), 60))
+			return m, getClearStatusCmd()
+		}
+		if msg.page.Path != m.logModalPath {
+			return m, logFollowTick()
+		}
+		if msg.status != "" {
+			m.logCall.Status = msg.status
+		}
+
+		if msg.page.Size < m.logModalEnd {
+			// The log was rewritten; start again from its tail.
+			m.logModalPaging = true
+			return m, tea.Batch(m.jumpLogPage(false), logFollowTick())
+		}
+		atBottom := m.logModalViewport.AtBottom()
+		m.extendLogWindow(msg.page, msg.highlighted, false)
+		if atBottom {
+			m.logModalViewport.GotoBottom()
+		}
+
+		if !msg.page.AtEnd() {
+			// More was written than a page holds: keep reading.
+			m.logModalPaging = true
+			return m, m.pollFollowedLog(false)
+		}
+		if m.logCall.IsTerminal() {
+			m.logFollowing = false
+			m.setStatusMessage(fmt.Sprintf("Call finished (%s) — follow stopped", m.logCall.Status))
+			return m, getClearStatusCmd()
+		}
+		return m, logFollowTick()
+	}
+	return m, nil
+}
@@ -210,6 +210,20 @@ func (m Model) loadResourceAnalysis(path string) tea.Cmd {
 	}
 }
 
+// formatBytes formats a byte count as a human-readable size.
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
 // copyToClipboard creates a tea.Cmd that copies text to the system clipboard.
 func copyToClipboard(text, ctx string) tea.Cmd {
 	return common.CopyToClipboard(text, ctx)
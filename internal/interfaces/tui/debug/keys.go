@@ -34,6 +34,7 @@ type KeyMap struct {
 	NextMatch      key.Binding
 	PrevMatch      key.Binding
 	Cost           key.Binding
+	Browse         key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings.
@@ -159,6 +160,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("$"),
 			key.WithHelp("$", "cost by task"),
 		),
+		Browse: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "browse files"),
+		),
 	}
 }
 
@@ -175,7 +180,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 		{k.Details, k.ExpandAll, k.CollapseAll},
 		{k.ExpandFailures, k.NextFailure, k.PrevFailure, k.Watch, k.FailureSummary},
 		{k.Home, k.End, k.PageUp, k.PageDown},
-		{k.NextMatch, k.PrevMatch, k.ErrorDetail, k.Cost},
+		{k.NextMatch, k.PrevMatch, k.ErrorDetail, k.Cost, k.Browse},
 		{k.Copy, k.Chat, k.SplitNarrow, k.SplitWiden},
 		{k.Help, k.Quit},
 	}
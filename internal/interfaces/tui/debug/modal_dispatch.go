@@ -101,6 +101,12 @@ func (m Model) modalDispatches() []modalDispatch {
 			handle: Model.handleCostModalKeys,
 			resize: func(m *Model) { m.resizeStandardModalViewport(&m.costViewport) },
 		},
+		{
+			active: func(m Model) bool { return m.activeModal == ModalFileBrowser },
+			view:   Model.renderFileBrowserModal,
+			handle: Model.handleFileBrowserModalKeys,
+			resize: func(m *Model) { m.resizeStandardModalViewport(&m.browserPreviewViewport) },
+		},
 	}
 }
 
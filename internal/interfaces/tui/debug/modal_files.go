@@ -0,0 +1,314 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/interfaces/tui/common"
+)
+
+// dirListedMsg carries the entries of a directory opened in the file browser.
+type dirListedMsg struct {
+	dir     string
+	entries []ports.FileEntry
+}
+
+// dirListErrorMsg reports a failure to list a directory in the file browser.
+type dirListErrorMsg struct {
+	dir string
+	err error
+}
+
+// filePreviewLoadedMsg carries the content of a file previewed in the file
+// browser.
+type filePreviewLoadedMsg struct {
+	path    string
+	content string
+}
+
+// filePreviewErrorMsg reports a failure to read a previewed file.
+type filePreviewErrorMsg struct {
+	path string
+	err  error
+}
+
+// browserRootFor returns the directory the file browser opens for a node:
+// the call root of a task or shard, the workflow root of a workflow.
+func (m Model) browserRootFor(node *TreeNode) string {
+	switch node.Type {
+	case NodeTypeWorkflow, NodeTypeSubWorkflow:
+		return m.workflowMetaFor(node).WorkflowRoot
+	default:
+		if node.CallData != nil {
+			return node.CallData.CallRoot
+		}
+	}
+	return ""
+}
+
+// openFileBrowser opens the file browser on the selected node's root. The
+// browser never leaves that root, so ← at the top closes nothing by mistake.
+func (m Model) openFileBrowser() (tea.Model, tea.Cmd) {
+	if m.cursor >= len(m.nodes) {
+		return m, nil
+	}
+	root := strings.TrimSuffix(m.browserRootFor(m.nodes[m.cursor]), "/")
+	switch {
+	case root == "":
+		m.setStatusMessage("No call root to browse for this node")
+		return m, getClearStatusCmd()
+	case m.fileLister == nil:
+		m.setStatusMessage("Browsing is not supported by this file provider")
+		return m, getClearStatusCmd()
+	}
+
+	m.activeModal = ModalFileBrowser
+	m.browserRoot = root
+	m.browserPreviewPath = ""
+	return m, m.browseDir(root)
+}
+
+// browseDir starts listing dir and resets the listing state.
+func (m *Model) browseDir(dir string) tea.Cmd {
+	m.browserDir = dir
+	m.browserEntries = nil
+	m.browserCursor = 0
+	m.browserLoading = true
+	m.browserError = ""
+	return m.listDir(dir)
+}
+
+// listDir returns a command listing a directory, directories first.
+func (m Model) listDir(dir string) tea.Cmd {
+	lister := m.fileLister
+	return func() tea.Msg {
+		entries, err := lister.List(context.Background(), dir)
+		if err != nil {
+			return dirListErrorMsg{dir: dir, err: err}
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].IsDir != entries[j].IsDir {
+				return entries[i].IsDir
+			}
+			return entries[i].Path < entries[j].Path
+		})
+		return dirListedMsg{dir: dir, entries: entries}
+	}
+}
+
+// previewFile returns a command reading a file for preview. Read refuses
+// files over 1 MB, which then show as an error instead of a preview.
+func (m Model) previewFile(path string) tea.Cmd {
+	fp := m.fileProvider
+	return func() tea.Msg {
+		if fp == nil {
+			return filePreviewErrorMsg{path: path, err: fmt.Errorf("file provider not initialized")}
+		}
+		content, err := fp.Read(context.Background(), path)
+		if err != nil {
+			return filePreviewErrorMsg{path: path, err: err}
+		}
+		return filePreviewLoadedMsg{path: path, content: content}
+	}
+}
+
+// handleFileBrowserMsg applies the result of a listing or preview, dropping
+// results for a directory or file the user has since moved away from.
+func (m Model) handleFileBrowserMsg(msg tea.Msg) Model {
+	switch msg := msg.(type) {
+	case dirListedMsg:
+		if msg.dir == m.browserDir {
+			m.browserLoading = false
+			m.browserEntries = msg.entries
+		}
+	case dirListErrorMsg:
+		if msg.dir == m.browserDir {
+			m.browserLoading = false
+			m.browserError = msg.err.Error()
+			m.lastError = msg.err.Error()
+		}
+	case filePreviewLoadedMsg:
+		if msg.path == m.browserPreviewPath {
+			m.browserPreviewLoading = false
+			m.browserPreviewViewport.SetContent(truncateLinesToWidth(previewContent(msg.content, msg.path), m.browserPreviewViewport.Width))
+		}
+	case filePreviewErrorMsg:
+		if msg.path == m.browserPreviewPath {
+			m.browserPreviewLoading = false
+			m.browserPreviewError = msg.err.Error()
+		}
+	}
+	return m
+}
+
+// previewContent highlights a file for the preview, or describes it when it
+// is binary.
+func previewContent(content, path string) string {
+	if strings.ContainsRune(content, 0) {
+		return mutedStyle.Render(fmt.Sprintf("Binary file (%s) — copy the path to inspect it elsewhere", formatBytes(int64(len(content)))))
+	}
+	if content == "" {
+		return mutedStyle.Render("Empty file")
+	}
+	return common.HighlightWithFilename(content, path, 0)
+}
+
+// browserEntryName is the last segment of an entry's path.
+func browserEntryName(entry ports.FileEntry) string {
+	p := strings.TrimSuffix(entry.Path, "/")
+	if i := strings.LastIndex(p, "/"); i >= 0 {
+		return p[i+1:]
+	}
+	return p
+}
+
+// browserRelative shows a path relative to the browser root.
+func (m Model) browserRelative(path string) string {
+	rel := strings.TrimPrefix(strings.TrimPrefix(path, m.browserRoot), "/")
+	if rel == "" {
+		return "."
+	}
+	return rel
+}
+
+// browserListHeight is how many entries fit in the modal at once.
+func (m Model) browserListHeight() int {
+	return max(m.height-12, 3)
+}
+
+func (m Model) renderFileBrowserModal() string {
+	if m.browserPreviewPath != "" {
+		title := titleStyle.Render("Preview: " + m.browserRelative(m.browserPreviewPath))
+		content := renderModalViewportContent(m.browserPreviewViewport.View(), m.browserPreviewViewport.Width, m.browserPreviewLoading, m.browserPreviewError)
+		footer := m.modalFooterWithHints("↑↓ scroll", "y copy path", "←/esc back")
+		return m.renderStandardModal(title, content, footer)
+	}
+
+	title := titleStyle.Render("Files: " + m.browserRelative(m.browserDir))
+	footer := m.modalFooterWithHints("↑↓ navigate", "enter open", "← up", "y copy path", "esc close")
+
+	var content string
+	switch {
+	case m.browserError != "":
+		content = errorStyle.Render("Error: " + m.browserError)
+	case m.browserLoading:
+		content = mutedStyle.Render("Loading...")
+	case len(m.browserEntries) == 0:
+		content = mutedStyle.Render("Empty directory")
+	default:
+		content = m.renderBrowserEntries(m.width - 14)
+	}
+
+	header := mutedStyle.Render(truncatePath(m.browserDir, m.width-14))
+	return m.renderStandardModal(title, header+"\n\n"+content, footer)
+}
+
+// renderBrowserEntries renders the window of entries around the cursor.
+func (m Model) renderBrowserEntries(width int) string {
+	height := m.browserListHeight()
+	start := 0
+	if m.browserCursor >= height {
+		start = m.browserCursor - height + 1
+	}
+	end := min(start+height, len(m.browserEntries))
+
+	nameWidth := max(width-16, 10)
+	var lines []string
+	for i := start; i < end; i++ {
+		entry := m.browserEntries[i]
+		name, size := browserEntryName(entry), formatBytes(entry.Size)
+		icon := "📄"
+		if entry.IsDir {
+			icon, name, size = "📁", name+"/", ""
+		}
+		name = common.PadRight(common.Truncate(name, nameWidth), nameWidth)
+		if i == m.browserCursor {
+			lines = append(lines, fmt.Sprintf("▶ %s %s %s", icon, selectedStyle.Render(name), valueStyle.Render(fmt.Sprintf("%10s", size))))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s %s %s", icon, valueStyle.Render(name), mutedStyle.Render(fmt.Sprintf("%10s", size))))
+		}
+	}
+	if len(m.browserEntries) > height {
+		lines = append(lines, mutedStyle.Render(fmt.Sprintf("  %d/%d", m.browserCursor+1, len(m.browserEntries))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m Model) handleFileBrowserModalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.browserPreviewPath != "" {
+		return m.handleFilePreviewKeys(msg)
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.activeModal = ModalNone
+		return m, nil
+
+	case key.Matches(msg, m.keys.Up):
+		if m.browserCursor > 0 {
+			m.browserCursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.browserCursor < len(m.browserEntries)-1 {
+			m.browserCursor++
+		}
+
+	case key.Matches(msg, m.keys.PageUp):
+		m.browserCursor = max(m.browserCursor-m.browserListHeight(), 0)
+
+	case key.Matches(msg, m.keys.PageDown):
+		m.browserCursor = max(min(m.browserCursor+m.browserListHeight(), len(m.browserEntries)-1), 0)
+
+	case key.Matches(msg, m.keys.Home):
+		m.browserCursor = 0
+
+	case key.Matches(msg, m.keys.End):
+		m.browserCursor = max(len(m.browserEntries)-1, 0)
+
+	case key.Matches(msg, m.keys.Left), msg.String() == "backspace":
+		if m.browserDir != m.browserRoot {
+			return m, m.browseDir(m.browserDir[:strings.LastIndex(m.browserDir, "/")])
+		}
+
+	case key.Matches(msg, m.keys.Right), key.Matches(msg, m.keys.Enter):
+		if m.browserCursor >= len(m.browserEntries) {
+			return m, nil
+		}
+		entry := m.browserEntries[m.browserCursor]
+		if entry.IsDir {
+			return m, m.browseDir(strings.TrimSuffix(entry.Path, "/"))
+		}
+		m.browserPreviewPath = entry.Path
+		m.browserPreviewLoading = true
+		m.browserPreviewError = ""
+		m.browserPreviewViewport = viewport.New(m.width-10, m.height-8)
+		return m, m.previewFile(entry.Path)
+
+	case key.Matches(msg, m.keys.Copy):
+		if m.browserCursor < len(m.browserEntries) {
+			return m, copyToClipboard(m.browserEntries[m.browserCursor].Path, "path")
+		}
+		return m, copyToClipboard(m.browserDir, "path")
+	}
+	return m, nil
+}
+
+func (m Model) handleFilePreviewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	actions := viewportModalActions{
+		onClose: func(m *Model) { m.browserPreviewPath = "" },
+		onLeft:  func(m *Model) { m.browserPreviewPath = "" },
+		onCopy: func(m *Model) tea.Cmd {
+			return copyToClipboard(m.browserPreviewPath, "path")
+		},
+	}
+	cmd, _ := m.handleViewportModalKeys(msg, &m.browserPreviewViewport, actions)
+	return m, cmd
+}
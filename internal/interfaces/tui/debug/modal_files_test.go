@@ -0,0 +1,151 @@
+package debug
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// fakeFiles is a FileProvider and FileLister over an in-memory tree.
+type fakeFiles struct {
+	dirs  map[string][]ports.FileEntry
+	files map[string]string
+}
+
+func (f fakeFiles) Read(_ context.Context, path string) (string, error) {
+	content, ok := f.files[path]
+	if !ok {
+		return "", ports.ErrFileNotFound
+	}
+	return content, nil
+}
+
+func (f fakeFiles) ReadBytes(ctx context.Context, path string) ([]byte, error) {
+	content, err := f.Read(ctx, path)
+	return []byte(content), err
+}
+
+func (f fakeFiles) GetSize(_ context.Context, path string) (int64, error) {
+	return int64(len(f.files[path])), nil
+}
+
+func (f fakeFiles) GetContentDigests(context.Context, string) (ports.FileDigests, error) {
+	return ports.FileDigests{}, ports.ErrHashUnavailable
+}
+
+func (f fakeFiles) List(_ context.Context, prefix string) ([]ports.FileEntry, error) {
+	entries, ok := f.dirs[prefix]
+	if !ok {
+		return nil, ports.ErrFileNotFound
+	}
+	return entries, nil
+}
+
+// runCmd executes a command and feeds its message back into the model.
+func runCmd(t *testing.T, m Model, cmd tea.Cmd) Model {
+	t.Helper()
+	if cmd == nil {
+		return m
+	}
+	updated, _ := m.Update(cmd())
+	return updated.(Model)
+}
+
+func pressKey(t *testing.T, m Model, k string) Model {
+	t.Helper()
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	switch k {
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "left":
+		msg = tea.KeyMsg{Type: tea.KeyLeft}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	updated, cmd := m.Update(msg)
+	return runCmd(t, updated.(Model), cmd)
+}
+
+func TestFileBrowserNavigatesCallRoot(t *testing.T) {
+	root := "gs://bucket/wf/id/call-Align"
+	files := fakeFiles{
+		dirs: map[string][]ports.FileEntry{
+			root: {
+				{Path: root + "/stdout", Size: 12},
+				{Path: root + "/execution", IsDir: true},
+			},
+			root + "/execution": {
+				{Path: root + "/execution/script", Size: 2048},
+			},
+		},
+		files: map[string]string{root + "/execution/script": "#!/bin/bash\necho hi\n"},
+	}
+	wf := &workflow.Workflow{
+		ID:     "id",
+		Name:   "wf",
+		Status: workflow.StatusSucceeded,
+		Calls:  map[string][]workflow.Call{"wf.Align": {{Name: "wf.Align", ShardIndex: -1, CallRoot: root + "/"}}},
+	}
+	m := NewModel(wf, nil, nil, files, nil)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	m.changeSelectedNode(1)
+
+	m = pressKey(t, m, "b")
+	if m.activeModal != ModalFileBrowser || m.browserDir != root {
+		t.Fatalf("browser not opened on the call root: modal %v, dir %q", m.activeModal, m.browserDir)
+	}
+	if len(m.browserEntries) != 2 || !m.browserEntries[0].IsDir {
+		t.Fatalf("entries = %+v, want the directory listed first", m.browserEntries)
+	}
+	if view := m.View(); !strings.Contains(view, "execution/") || !strings.Contains(view, "12 B") {
+		t.Errorf("listing does not show names and sizes:\n%s", view)
+	}
+
+	m = pressKey(t, m, "enter")
+	if m.browserDir != root+"/execution" || len(m.browserEntries) != 1 {
+		t.Fatalf("did not enter execution/: dir %q, entries %+v", m.browserDir, m.browserEntries)
+	}
+	if view := m.View(); !strings.Contains(view, "2.0 KB") {
+		t.Errorf("size of script not shown:\n%s", view)
+	}
+
+	m = pressKey(t, m, "enter")
+	if m.browserPreviewPath != root+"/execution/script" || m.browserPreviewLoading {
+		t.Fatalf("preview not loaded: path %q, loading %v", m.browserPreviewPath, m.browserPreviewLoading)
+	}
+	if view := m.View(); !strings.Contains(view, "echo hi") {
+		t.Errorf("preview does not show the file:\n%s", view)
+	}
+
+	// ← leaves the preview, then goes up, but never above the call root.
+	m = pressKey(t, m, "left")
+	if m.browserPreviewPath != "" {
+		t.Fatal("← did not leave the preview")
+	}
+	m = pressKey(t, m, "left")
+	m = pressKey(t, m, "left")
+	if m.browserDir != root {
+		t.Errorf("dir = %q, want the call root %q", m.browserDir, root)
+	}
+
+	m = pressKey(t, m, "esc")
+	if m.activeModal != ModalNone {
+		t.Errorf("esc did not close the browser")
+	}
+}
+
+func TestFileBrowserWithoutCallRoot(t *testing.T) {
+	m := testModel(t, 80, 24)
+	m.fileLister = fakeFiles{}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	if updated.(Model).activeModal != ModalNone {
+		t.Errorf("browser opened for a workflow without a root")
+	}
+}
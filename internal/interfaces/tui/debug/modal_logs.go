@@ -1,15 +1,39 @@
 package debug
 
 import (
+	"fmt"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/muesli/reflow/ansi"
+
+	workflowapp "github.com/lmtani/pumbaa/internal/application/workflow"
+	"github.com/lmtani/pumbaa/internal/interfaces/tui/common"
 )
 
+// maxLogWindow caps how much of a log the viewer holds at once. Scrolling
+// past the window loads the next page and drops one from the other end.
+const maxLogWindow = 4 * workflowapp.LogPageSize
+
 // renderLogModal renders the log modal.
 func (m Model) renderLogModal() string {
 	// Modal title with scroll indicator
 	titleText := "" + m.logModalTitle
+	if m.logModalSize > 0 {
+		titleText += " · " + formatBytes(m.logModalSize)
+	}
+	if m.logModalOffset > 0 || m.logModalEnd < m.logModalSize {
+		// Only part of the log is loaded: show where the view is.
+		titleText += fmt.Sprintf(" · %d%%", m.logVisibleOffset()*100/m.logModalSize)
+	}
+	switch {
+	case m.logFollowing:
+		titleText += " · ● following"
+	case m.logSearching:
+		titleText += " · searching..."
+	case m.logModalPaging:
+		titleText += " · loading..."
+	}
 	if m.logModalHScrollOffset > 0 {
 		titleText += " ◀"
 	}
@@ -27,7 +51,210 @@ func (m Model) renderLogModal() string {
 
 // logModalFooter generates the footer for log modals with horizontal scroll hint
 func (m Model) logModalFooter() string {
-	return m.modalFooterWithHints("↑↓ scroll", "←→ pan", "y copy", "esc close")
+	if m.logSearchActive {
+		return common.KeyStyle.Render("/") + " " + common.DescStyle.Render(m.logSearchQuery+"█") + "  " + mutedStyle.Render("enter search • esc cancel")
+	}
+	hints := []string{"↑↓ scroll", "←→ pan", "/ search"}
+	if m.logSearchQuery != "" {
+		hints = append(hints, "n next")
+	}
+	hints = append(hints, "g/G start/end")
+	if m.logCall != nil {
+		hints = append(hints, "f follow")
+	}
+	hints = append(hints, "y copy", "esc close")
+	return m.modalFooterWithHints(hints...)
+}
+
+// highlightLogPage highlights a page of a log by the log's name; a .gz log
+// by the name of the file inside. It is slow on large pages, so it runs in
+// the command that loads the page rather than in Update.
+func highlightLogPage(page workflowapp.LogPage) string {
+	return common.HighlightWithFilename(page.Content, strings.TrimSuffix(page.Path, ".gz"), 0)
+}
+
+// setLogWindow replaces the loaded log window with a page.
+func (m *Model) setLogWindow(page workflowapp.LogPage, highlighted string) {
+	m.logModalPath = page.Path
+	m.logModalOffset, m.logModalEnd, m.logModalSize = page.Offset, page.End, page.Size
+	m.setLogContent(page.Content, highlighted)
+}
+
+// setLogContent shows the content of the window, keeping the horizontal
+// scroll.
+func (m *Model) setLogContent(raw, highlighted string) {
+	viewportWidth := m.logModalViewport.Width
+	m.logModalRawContent = raw // Keep raw content for clipboard
+	m.logModalContent = highlighted
+	scrolledContent := applyHorizontalScroll(m.logModalContent, m.logModalHScrollOffset, viewportWidth)
+	m.logModalViewport.SetContent(truncateLinesToWidth(scrolledContent, viewportWidth))
+}
+
+// extendLogWindow adds a page before or after the loaded window, dropping
+// lines from the other end beyond maxLogWindow. The view stays on the same
+// lines. Highlighting keeps lines intact, so the raw and highlighted content
+// are cut at the same line.
+func (m *Model) extendLogWindow(page workflowapp.LogPage, highlighted string, before bool) {
+	raw, content := m.logModalRawContent, m.logModalContent
+	yOffset := m.logModalViewport.YOffset
+	if before {
+		if page.End != m.logModalOffset {
+			return // the window moved since the page was asked for
+		}
+		raw, content = page.Content+raw, highlighted+content
+		m.logModalOffset = page.Offset
+		yOffset += strings.Count(page.Content, "\n")
+		if int64(len(raw)) > maxLogWindow {
+			if cut := strings.LastIndexByte(raw[:maxLogWindow], '\n') + 1; cut > 0 {
+				lines := strings.Count(raw[:cut], "\n")
+				raw, content = raw[:cut], content[:lineEnd(content, lines)]
+				m.logModalEnd = m.logModalOffset + int64(cut)
+			}
+		}
+	} else {
+		if page.Offset != m.logModalEnd {
+			return
+		}
+		raw, content = raw+page.Content, content+highlighted
+		m.logModalEnd = page.End
+		if excess := int64(len(raw)) - maxLogWindow; excess > 0 {
+			if i := strings.IndexByte(raw[excess:], '\n'); i >= 0 {
+				cut := int(excess) + i + 1
+				lines := strings.Count(raw[:cut], "\n")
+				yOffset -= lines
+				raw, content = raw[cut:], content[lineEnd(content, lines):]
+				m.logModalOffset += int64(cut)
+			}
+		}
+	}
+	m.logModalSize = page.Size
+	m.setLogContent(raw, content)
+	m.logModalViewport.SetYOffset(max(yOffset, 0))
+}
+
+// lineEnd returns the index just past the nth newline of s, or len(s).
+func lineEnd(s string, n int) int {
+	end := 0
+	for range n {
+		i := strings.IndexByte(s[end:], '\n')
+		if i < 0 {
+			return len(s)
+		}
+		end += i + 1
+	}
+	return end
+}
+
+// logVisibleOffset is the offset in the log of the top line in view.
+func (m Model) logVisibleOffset() int64 {
+	return m.logModalOffset + int64(lineEnd(m.logModalRawContent, m.logModalViewport.YOffset))
+}
+
+// maybeExtendLogWindow loads the next page when the view reaches an edge of
+// the loaded window that is not an end of the log.
+func (m *Model) maybeExtendLogWindow() tea.Cmd {
+	if m.logModalPaging || m.logReader == nil || m.logModalPath == "" {
+		return nil
+	}
+	switch {
+	case m.logModalViewport.AtTop() && m.logModalOffset > 0:
+		m.logModalPaging = true
+		return m.loadLogPage(true)
+	case m.logModalViewport.AtBottom() && m.logModalEnd < m.logModalSize:
+		m.logModalPaging = true
+		return m.loadLogPage(false)
+	}
+	return nil
+}
+
+// handleLogPageMsg applies a page or search result to the log window,
+// dropping results for a log that has since been closed.
+func (m Model) handleLogPageMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case logPageLoadedMsg:
+		if m.activeModal != ModalLog || msg.page.Path != m.logModalPath {
+			return m, nil
+		}
+		m.logModalPaging = false
+		if !msg.replace {
+			m.extendLogWindow(msg.page, msg.highlighted, msg.before)
+			return m, nil
+		}
+		m.setLogWindow(msg.page, msg.highlighted)
+		if msg.page.AtStart() {
+			m.logModalViewport.GotoTop()
+		} else {
+			m.logModalViewport.GotoBottom()
+		}
+
+	case logPageErrorMsg:
+		if msg.path != m.logModalPath {
+			return m, nil
+		}
+		m.logModalPaging = false
+		m.logSearching = false
+		m.lastError = msg.err.Error()
+		m.setStatusMessage("Error reading log: " + common.Truncate(msg.err.Error(), 60))
+		return m, getClearStatusCmd()
+
+	case logSearchResultMsg:
+		if m.activeModal != ModalLog || msg.path != m.logModalPath || msg.query != m.logSearchQuery {
+			return m, nil
+		}
+		m.logSearching = false
+		if msg.match == nil {
+			m.setStatusMessage(fmt.Sprintf("%q not found", msg.query))
+			return m, getClearStatusCmd()
+		}
+		m.logSearchMatch = msg.match
+		m.logModalPaging = false
+		m.setLogWindow(msg.page, msg.highlighted)
+		// Show the match near the top, with a little context above it
+		line := strings.Count(msg.page.Content[:msg.match.Offset-msg.page.Offset], "\n")
+		m.logModalViewport.SetYOffset(max(line-2, 0))
+		if msg.match.Wrapped {
+			m.setStatusMessage("Search wrapped to the start of the log")
+			return m, getClearStatusCmd()
+		}
+	}
+	return m, nil
+}
+
+// handleLogSearchKeys edits the log search query.
+func (m Model) handleLogSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.logSearchActive = false
+	case tea.KeyEnter:
+		m.logSearchActive = false
+		if m.logSearchQuery == "" || m.logReader == nil {
+			return m, nil
+		}
+		m.logSearching = true
+		m.logSearchMatch = nil
+		return m, m.searchLog(m.logSearchQuery, m.logVisibleOffset())
+	case tea.KeyBackspace, tea.KeyCtrlH:
+		m.logSearchQuery = removeLastRune(m.logSearchQuery)
+	case tea.KeySpace:
+		m.logSearchQuery += " "
+	case tea.KeyRunes:
+		m.logSearchQuery += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// nextLogMatch searches for the next match of the current query, from the
+// line after the last match or from the top of the view.
+func (m Model) nextLogMatch() (tea.Model, tea.Cmd) {
+	if m.logSearchQuery == "" || m.logSearching || m.logReader == nil {
+		return m, nil
+	}
+	from := m.logVisibleOffset()
+	if m.logSearchMatch != nil {
+		from = m.logSearchMatch.Next()
+	}
+	m.logSearching = true
+	return m, m.searchLog(m.logSearchQuery, from)
 }
 
 // truncateLinesToWidth truncates each line to the specified visible width while preserving ANSI codes
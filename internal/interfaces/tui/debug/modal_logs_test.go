@@ -0,0 +1,195 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lmtani/pumbaa/internal/application/ports"
+	workflowapp "github.com/lmtani/pumbaa/internal/application/workflow"
+	"github.com/lmtani/pumbaa/internal/domain/workflow"
+)
+
+// bigLog is a FileProvider and RangeReader holding one log far over the 1 MB
+// limit of Read.
+type bigLog struct {
+	data []byte
+}
+
+func (b bigLog) Read(context.Context, string) (string, error) {
+	return "", fmt.Errorf("file too large")
+}
+
+func (b bigLog) ReadBytes(context.Context, string) ([]byte, error) { return b.data, nil }
+
+func (b bigLog) GetSize(context.Context, string) (int64, error) { return int64(len(b.data)), nil }
+
+func (b bigLog) GetContentDigests(context.Context, string) (ports.FileDigests, error) {
+	return ports.FileDigests{}, ports.ErrHashUnavailable
+}
+
+func (b bigLog) ReadRange(_ context.Context, _ string, offset, length int64) ([]byte, error) {
+	offset = min(offset, int64(len(b.data)))
+	return b.data[offset:min(offset+length, int64(len(b.data)))], nil
+}
+
+func newBigLogModel(t *testing.T) Model {
+	t.Helper()
+	var b strings.Builder
+	for i := range 300000 {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	m := testModel(t, 120, 40)
+	m.logReader = workflowapp.NewLogReader(bigLog{data: []byte(b.String())})
+	// A .txt log is not highlighted, which keeps paging through it quick.
+	return runCmd(t, m, m.openLogTail("call-Align/output.txt", "output.txt"))
+}
+
+// visibleLog returns the raw lines of the log shown in the viewport, free of
+// the highlighting in its View.
+func visibleLog(m Model) string {
+	lines := strings.Split(m.logModalRawContent, "\n")
+	start := min(m.logModalViewport.YOffset, len(lines))
+	end := min(start+m.logModalViewport.Height, len(lines))
+	return strings.Join(lines[start:end], "\n")
+}
+
+func TestLogModalPagesFromTheTail(t *testing.T) {
+	m := newBigLogModel(t)
+	if m.activeModal != ModalLog {
+		t.Fatalf("log modal not opened (error %q)", m.lastError)
+	}
+	if !strings.Contains(visibleLog(m), "line 299999") {
+		t.Errorf("log does not open at its tail")
+	}
+	if m.logModalOffset == 0 || m.logModalEnd != m.logModalSize {
+		t.Fatalf("window = [%d, %d) of %d, want the last page", m.logModalOffset, m.logModalEnd, m.logModalSize)
+	}
+
+	// Scrolling to the top of the window loads the page before it and keeps
+	// the view on the same line.
+	offset := m.logModalOffset
+	m.logModalViewport.GotoTop()
+	top := m.logVisibleOffset()
+	m = pressKey(t, m, "k")
+	if m.logModalOffset >= offset {
+		t.Fatalf("window start = %d, want before %d", m.logModalOffset, offset)
+	}
+	if got := m.logVisibleOffset(); got != top {
+		t.Errorf("view moved from offset %d to %d when the page was added", top, got)
+	}
+
+	// The window never grows past its cap.
+	for range 10 {
+		m.logModalViewport.GotoTop()
+		m = pressKey(t, m, "k")
+	}
+	if int64(len(m.logModalRawContent)) > maxLogWindow {
+		t.Errorf("window holds %d bytes, more than %d", len(m.logModalRawContent), maxLogWindow)
+	}
+
+	// g and G jump to the ends of the log, not just of the window.
+	m = pressKey(t, m, "g")
+	if m.logModalOffset != 0 || !strings.HasPrefix(visibleLog(m), "line 0\n") {
+		t.Errorf("g did not jump to the start: window starts at %d", m.logModalOffset)
+	}
+	m = pressKey(t, m, "G")
+	if m.logModalEnd != m.logModalSize {
+		t.Errorf("G did not jump to the end: window ends at %d of %d", m.logModalEnd, m.logModalSize)
+	}
+}
+
+func TestLogModalSearch(t *testing.T) {
+	m := newBigLogModel(t)
+	m = pressKey(t, m, "g")
+
+	m = pressKey(t, m, "/")
+	for _, r := range "line 12345" {
+		m = pressKey(t, m, string(r))
+	}
+	if !m.logSearchActive || !strings.Contains(m.View(), "line 12345█") {
+		t.Fatalf("search prompt not shown")
+	}
+	m = pressKey(t, m, "enter")
+	if m.logSearchMatch == nil || m.logSearchMatch.Line != "line 12345" {
+		t.Fatalf("match = %+v, want line 12345", m.logSearchMatch)
+	}
+	if !strings.Contains(visibleLog(m), "line 12345\n") {
+		t.Errorf("view does not show the match")
+	}
+
+	m = pressKey(t, m, "n")
+	if m.logSearchMatch == nil || m.logSearchMatch.Line != "line 123450" {
+		t.Errorf("next match = %+v, want line 123450", m.logSearchMatch)
+	}
+
+	m = pressKey(t, m, "/")
+	for _, r := range "no such line" {
+		m = pressKey(t, m, string(r))
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.logSearching {
+		t.Fatal("search not started")
+	}
+}
+
+// callFetcher reports one call of a workflow, with a settable status.
+type callFetcher struct {
+	call *workflow.Call
+}
+
+func (f callFetcher) GetRawMetadataWithOptions(context.Context, string, bool) ([]byte, error) {
+	return nil, nil
+}
+func (f callFetcher) GetSubmittedInputs(context.Context, string) (string, error) { return "", nil }
+func (f callFetcher) GetWorkflowCost(context.Context, string) (float64, string, error) {
+	return 0, "", nil
+}
+func (f callFetcher) ParseMetadata([]byte) (*workflow.Workflow, error) {
+	return &workflow.Workflow{ID: "wf", Calls: map[string][]workflow.Call{f.call.Name: {*f.call}}}, nil
+}
+
+func TestLogModalFollow(t *testing.T) {
+	call := &workflow.Call{Name: "wf.Align", ShardIndex: -1, Attempt: 1, Status: workflow.StatusRunning}
+	log := &bigLog{data: []byte("start\n")}
+	m := testModel(t, 120, 40)
+	m.fetcher = callFetcher{call: call}
+	m.logReader = workflowapp.NewLogReader(log)
+	running := *call
+	m.logCall, m.logCallWorkflowID = &running, "wf"
+	m = runCmd(t, m, m.openLogTail("call-Align/stderr", "stderr"))
+
+	m = pressKey(t, m, "f")
+	if !m.logFollowing {
+		t.Fatalf("follow not started: %q", m.statusMessage)
+	}
+
+	// Each poll appends what the task wrote, and the view stays at the end.
+	log.data = append(log.data, "step 1\nstep 2\n"...)
+	updated, cmd := m.Update(logFollowTickMsg{})
+	m = runCmd(t, updated.(Model), cmd)
+	if m.logModalRawContent != string(log.data) || !m.logModalViewport.AtBottom() {
+		t.Errorf("window = %q, want the whole log with the view at its end", m.logModalRawContent)
+	}
+
+	// Once the call is seen finished, what it wrote last is read and
+	// following stops.
+	log.data = append(log.data, "done\n"...)
+	call.Status = "Done"
+	m.logFollowPolls = logFollowStatusEvery - 1
+	updated, cmd = m.Update(logFollowTickMsg{})
+	m = runCmd(t, updated.(Model), cmd)
+	if m.logFollowing || !strings.HasSuffix(m.logModalRawContent, "done\n") {
+		t.Errorf("following = %v, window %q, want it stopped after the last line", m.logFollowing, m.logModalRawContent)
+	}
+
+	// A finished call cannot be followed again.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if updated.(Model).logFollowing {
+		t.Error("follow started for a finished call")
+	}
+}
@@ -98,6 +98,25 @@ type Model struct {
 	logModalHScrollOffset int // Horizontal scroll offset for long lines
 	logCursor             int // 0 = stdout, 1 = stderr, 2 = monitoring, 3 = batch logs
 
+	// Paged log window: the loaded bytes [logModalOffset, logModalEnd) of a
+	// log of logModalSize bytes, extended a page at a time while scrolling.
+	logModalPath    string
+	logModalOffset  int64
+	logModalEnd     int64
+	logModalSize    int64
+	logModalPaging  bool // a page fetch is in flight
+	logSearchActive bool // typing a search query
+	logSearchQuery  string
+	logSearching    bool // a search is in flight
+	logSearchMatch  *workflowapp.LogMatch
+
+	// Follow mode (f in a task log): the open log's call, polled for new
+	// bytes until the call attempt finishes
+	logCall           *workflow.Call // nil for a workflow log
+	logCallWorkflowID string         // the (sub)workflow the call belongs to
+	logFollowing      bool
+	logFollowPolls    int
+
 	// Inputs/Outputs modal state
 	inputsModalViewport  viewport.Model
 	outputsModalViewport viewport.Model
@@ -156,6 +175,20 @@ type Model struct {
 	costLoading   bool
 	costError     string
 
+	// File browser modal state (b key): browses the selected node's call or
+	// workflow root without leaving it. A non-empty browserPreviewPath shows
+	// that file instead of the listing.
+	browserRoot            string
+	browserDir             string
+	browserEntries         []ports.FileEntry
+	browserCursor          int
+	browserLoading         bool
+	browserError           string
+	browserPreviewPath     string
+	browserPreviewViewport viewport.Model
+	browserPreviewLoading  bool
+	browserPreviewError    string
+
 	// Components
 	keys           KeyMap
 	help           help.Model
@@ -168,6 +201,8 @@ type Model struct {
 	// Infrastructure
 	monitoringUC *workflowapp.MonitoringUseCase
 	fileProvider ports.FileProvider
+	fileLister   ports.FileLister // nil when the file provider cannot list
+	logReader    *workflowapp.LogReader
 	batchLogsUC  *workflowapp.GetBatchLogsUseCase
 
 	// Pre-computed preemption summary
@@ -222,6 +257,13 @@ func NewModelWithChat(wf *workflow.Workflow, fetcher ports.WorkflowMetadataFetch
 		canGoBack:          true, // Default to true, AppModel will set to false if started directly
 	}
 
+	if lister, ok := fp.(ports.FileLister); ok {
+		m.fileLister = lister
+	}
+	if fp != nil {
+		m.logReader = workflowapp.NewLogReader(fp)
+	}
+
 	// Add chat dependencies if provided
 	if chatDeps != nil {
 		m.llm = chatDeps.LLM
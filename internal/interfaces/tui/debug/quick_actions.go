@@ -127,6 +127,7 @@ func (m Model) openWorkflowLogModal(node *TreeNode) (tea.Model, tea.Cmd) {
 	m.isLoading = true
 	m.loadingMessage = "Loading workflow log..."
 	m.loadingStartTime = time.Now()
+	m.logCall = nil
 	return m, m.openWorkflowLog(meta.WorkflowLog)
 }
 
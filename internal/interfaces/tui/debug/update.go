@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/lmtani/pumbaa/internal/application/ports"
+	workflowapp "github.com/lmtani/pumbaa/internal/application/workflow"
 	workflowDomain "github.com/lmtani/pumbaa/internal/domain/workflow"
 	"github.com/lmtani/pumbaa/internal/interfaces/tui/common"
 	"github.com/lmtani/pumbaa/internal/interfaces/tui/debug/tree"
@@ -18,15 +19,40 @@ import (
 
 // Message types for async operations
 type logLoadedMsg struct {
-	content string
-	title   string
-	path    string
+	page        workflowapp.LogPage
+	highlighted string
+	title       string
 }
 
 type logErrorMsg struct {
 	err error
 }
 
+// logPageLoadedMsg carries a page extending the log window backwards
+// (before) or forwards, or replacing it (replace) on a jump to either end.
+type logPageLoadedMsg struct {
+	page        workflowapp.LogPage
+	highlighted string
+	before      bool
+	replace     bool
+}
+
+// logPageErrorMsg reports a failure to page or search the open log.
+type logPageErrorMsg struct {
+	path string
+	err  error
+}
+
+// logSearchResultMsg carries the result of a log search: the match and the
+// page around it, or a nil match when the query is not in the log.
+type logSearchResultMsg struct {
+	path        string
+	query       string
+	match       *workflowapp.LogMatch
+	page        workflowapp.LogPage
+	highlighted string
+}
+
 type subWorkflowLoadedMsg struct {
 	nodeID   string
 	metadata *WorkflowMetadata
@@ -181,22 +207,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case logLoadedMsg:
 		m.isLoading = false
 		m.loadingMessage = ""
-		m.logModalRawContent = msg.content                                         // Keep raw content for clipboard
-		m.logModalContent = common.HighlightWithFilename(msg.content, msg.path, 0) // No width limit for highlighting
 		m.logModalTitle = msg.title
 		m.logModalError = ""
 		m.logModalLoading = false
 		m.activeModal = ModalLog
 		m.logModalHScrollOffset = 0
+		m.logModalPaging = false
+		m.logSearchActive = false
+		m.logSearchMatch = nil
+		m.logFollowing = false
 		// Initialize the modal viewport with truncated content
 		// Modal uses: width-6, minus border (2), minus padding (4) = width-12
 		// Use width-14 for extra safety margin
 		viewportWidth := m.width - 14
 		m.logModalViewport = viewport.New(viewportWidth, m.height-10)
-		truncatedContent := truncateLinesToWidth(m.logModalContent, viewportWidth)
-		m.logModalViewport.SetContent(truncatedContent)
+		m.setLogWindow(msg.page, msg.highlighted)
+		// A log opens at its tail, where a failing task's error usually is
+		m.logModalViewport.GotoBottom()
 		return m, nil
 
+	case logPageLoadedMsg, logPageErrorMsg, logSearchResultMsg:
+		return m.handleLogPageMsg(msg)
+
+	case logFollowTickMsg, logFollowMsg:
+		return m.handleLogFollowMsg(msg)
+
 	case logErrorMsg:
 		m.isLoading = false
 		m.loadingMessage = ""
@@ -286,6 +321,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case dirListedMsg, dirListErrorMsg, filePreviewLoadedMsg, filePreviewErrorMsg:
+		return m.handleFileBrowserMsg(msg), nil
+
 	case chatContextLoadedMsg:
 		return m.handleChatContextLoaded(msg)
 
@@ -445,6 +483,9 @@ func (m Model) handleMainKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Cost):
 		return m.openCostModal()
 
+	case key.Matches(msg, m.keys.Browse):
+		return m.openFileBrowser()
+
 	case key.Matches(msg, m.keys.NextMatch):
 		if m.searchQuery != "" {
 			m.jumpToSearchMatch(true)
@@ -555,6 +596,8 @@ func (m Model) handleExpandOrOpenLog() (tea.Model, tea.Cmd) {
 				return m, getClearStatusCmd()
 			}
 			if logPath != "" {
+				call := *node.CallData
+				m.logCall, m.logCallWorkflowID = &call, m.owningWorkflowID(node)
 				m.isLoading = true
 				m.loadingMessage = "Loading log file..."
 				m.loadingStartTime = time.Now()
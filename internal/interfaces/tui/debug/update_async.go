@@ -35,45 +35,101 @@ func (m Model) fetchSubWorkflowMetadata(node *TreeNode) tea.Cmd {
 	}
 }
 
-// openLogFile returns a command to load a log file asynchronously
+// openLogFile returns a command to load the last page of a log file
+// asynchronously
 func (m Model) openLogFile(path string) tea.Cmd {
-	return func() tea.Msg {
-		var title string
-		switch m.logCursor {
-		case 0:
-			title = "stdout"
-		case 1:
-			title = "stderr"
-		case 2:
-			title = "monitoring"
-		}
+	var title string
+	switch m.logCursor {
+	case 0:
+		title = "stdout"
+	case 1:
+		title = "stderr"
+	case 2:
+		title = "monitoring"
+	}
+	return m.openLogTail(path, title)
+}
+
+// openWorkflowLog returns a command to load the last page of a workflow log
+// file asynchronously
+func (m Model) openWorkflowLog(path string) tea.Cmd {
+	return m.openLogTail(path, "Workflow Log")
+}
 
-		if m.fileProvider == nil {
+func (m Model) openLogTail(path, title string) tea.Cmd {
+	reader := m.logReader
+	return func() tea.Msg {
+		if reader == nil {
 			return logErrorMsg{err: fmt.Errorf("file provider not initialized")}
 		}
 
-		content, err := m.fileProvider.Read(context.Background(), path)
+		page, err := reader.Tail(context.Background(), path)
 		if err != nil {
 			return logErrorMsg{err: err}
 		}
-		return logLoadedMsg{content: content, title: title, path: path}
+		return logLoadedMsg{page: page, highlighted: highlightLogPage(page), title: title}
 	}
 }
 
-// openWorkflowLog returns a command to load a workflow log file asynchronously
-func (m Model) openWorkflowLog(path string) tea.Cmd {
+// loadLogPage returns a command to load the page before or after the loaded
+// log window, to extend it as the user scrolls past its edge.
+func (m Model) loadLogPage(before bool) tea.Cmd {
+	reader, path := m.logReader, m.logModalPath
+	offset, end := m.logModalOffset, m.logModalEnd
 	return func() tea.Msg {
-		title := "Workflow Log"
+		ctx := context.Background()
+		var page workflowapp.LogPage
+		var err error
+		if before {
+			page, err = reader.PageBefore(ctx, path, offset)
+		} else {
+			page, err = reader.PageAfter(ctx, path, end)
+		}
+		if err != nil {
+			return logPageErrorMsg{path: path, err: err}
+		}
+		return logPageLoadedMsg{page: page, highlighted: highlightLogPage(page), before: before}
+	}
+}
 
-		if m.fileProvider == nil {
-			return logErrorMsg{err: fmt.Errorf("file provider not initialized")}
+// jumpLogPage returns a command to load the first or the last page of the
+// open log, replacing the loaded window.
+func (m Model) jumpLogPage(toStart bool) tea.Cmd {
+	reader, path := m.logReader, m.logModalPath
+	return func() tea.Msg {
+		ctx := context.Background()
+		var page workflowapp.LogPage
+		var err error
+		if toStart {
+			page, err = reader.PageAfter(ctx, path, 0)
+		} else {
+			page, err = reader.Tail(ctx, path)
 		}
+		if err != nil {
+			return logPageErrorMsg{path: path, err: err}
+		}
+		return logPageLoadedMsg{page: page, highlighted: highlightLogPage(page), replace: true}
+	}
+}
 
-		content, err := m.fileProvider.Read(context.Background(), path)
+// searchLog returns a command searching a log from an offset, streaming it
+// in chunks, and loading the page around the match.
+func (m Model) searchLog(query string, from int64) tea.Cmd {
+	reader, path := m.logReader, m.logModalPath
+	return func() tea.Msg {
+		ctx := context.Background()
+		match, found, err := reader.Search(ctx, path, query, from)
 		if err != nil {
-			return logErrorMsg{err: err}
+			return logPageErrorMsg{path: path, err: err}
+		}
+		if !found {
+			return logSearchResultMsg{path: path, query: query}
+		}
+		page, err := reader.PageAround(ctx, path, match.Offset)
+		if err != nil {
+			return logPageErrorMsg{path: path, err: err}
 		}
-		return logLoadedMsg{content: content, title: title, path: path}
+		return logSearchResultMsg{path: path, query: query, match: &match, page: page, highlighted: highlightLogPage(page)}
 	}
 }
 
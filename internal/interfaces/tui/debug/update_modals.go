@@ -84,6 +84,29 @@ func (m *Model) handleViewportModalKeys(msg tea.KeyMsg, navigator viewportNaviga
 }
 
 func (m Model) handleLogModalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.logSearchActive {
+		return m.handleLogSearchKeys(msg)
+	}
+
+	// Search and the jumps to either end of a paged log come first; a log
+	// loaded whole falls through to the plain viewport keys.
+	switch {
+	case msg.String() == "/":
+		m.logSearchActive = true
+		m.logSearchQuery = ""
+		return m, nil
+	case key.Matches(msg, m.keys.NextMatch):
+		return m.nextLogMatch()
+	case msg.String() == "f":
+		return m.toggleLogFollow()
+	case key.Matches(msg, m.keys.Home) && m.logModalOffset > 0 && !m.logModalPaging:
+		m.logModalPaging = true
+		return m, m.jumpLogPage(true)
+	case key.Matches(msg, m.keys.End) && m.logModalEnd < m.logModalSize && !m.logModalPaging:
+		m.logModalPaging = true
+		return m, m.jumpLogPage(false)
+	}
+
 	viewportWidth := m.logModalViewport.Width
 	actions := viewportModalActions{
 		onClose: func(m *Model) {
@@ -92,6 +115,12 @@ func (m Model) handleLogModalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.logModalRawContent = ""
 			m.logModalError = ""
 			m.logModalHScrollOffset = 0
+			m.logModalPath = ""
+			m.logModalPaging = false
+			m.logSearching = false
+			m.logSearchQuery = ""
+			m.logSearchMatch = nil
+			m.logFollowing = false
 		},
 		onCopy: func(m *Model) tea.Cmd {
 			if m.logModalRawContent != "" {
@@ -124,10 +153,16 @@ func (m Model) handleLogModalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		},
 	}
 	cmd, handled := m.handleViewportModalKeys(msg, &m.logModalViewport, actions)
-	if handled {
-		return m, cmd
+	if !handled {
+		return m, nil
 	}
-	return m, nil
+	switch {
+	case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.Down),
+		key.Matches(msg, m.keys.PageUp), key.Matches(msg, m.keys.PageDown):
+		// Scrolling to an edge of the loaded window loads the next page
+		cmd = m.maybeExtendLogWindow()
+	}
+	return m, cmd
 }
 
 func (m Model) handleInputsModalKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
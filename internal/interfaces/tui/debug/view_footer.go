@@ -89,6 +89,7 @@ func (m Model) footerHints() []string {
 		renderFooterHint("f", "failures"),
 		renderFooterHint("w", "watch"),
 		renderFooterHint("$", "cost"),
+		renderFooterHint("b", "files"),
 		renderFooterHint("/", "search"),
 	)
 
@@ -39,6 +39,7 @@ func (m Model) renderHelpOverlay() string {
 	content.WriteString(helpLine("w", "Watch (auto-refresh)"))
 	content.WriteString(helpLine("d", "Return to details view"))
 	content.WriteString(helpLine("$", "Cost breakdown by task"))
+	content.WriteString(helpLine("b", "Browse call root files"))
 	content.WriteString(helpLine("/", "Filter tree (name/status)"))
 	content.WriteString(helpLine("Ctrl+X", "Clear search"))
 	content.WriteString(helpLine("y", "Copy menu (ID, paths, cmd)"))
@@ -25,6 +25,7 @@ type Dependencies struct {
 	MonitoringUC *workflowapp.MonitoringUseCase
 	BatchLogsUC  *workflowapp.GetBatchLogsUseCase
 	CompareUC    *workflowapp.CompareUseCase
+	StorageUC    *workflowapp.StorageUsageUseCase
 
 	// UpdateChecker checks for newer releases (optional - nil disables it)
 	UpdateChecker ports.UpdateChecker
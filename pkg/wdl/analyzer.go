@@ -24,22 +24,47 @@ type DependencyNode struct {
 	ImportedBy   []string          // Files that import this file
 	Aliases      map[string]string // Import aliases (alias -> original)
 	ResolvedFrom string            // The import URI as written in the source
+	CachedPath   string            // Local copy of a remote import; Path is then its URL
+}
+
+// ContentPath is where the node's source can be read from.
+func (n *DependencyNode) ContentPath() string {
+	if n.CachedPath != "" {
+		return n.CachedPath
+	}
+	return n.Path
 }
 
 // AnalyzeDependencies analyzes a WDL document and builds a complete dependency graph
 // It resolves all imports (direct and transitive) and detects circular dependencies
+// Remote (http/https) imports are downloaded over the network into the
+// default import cache; use AnalyzeDependenciesWithOptions with Offline set to
+// resolve them from the cache only.
 func AnalyzeDependencies(doc *ast.Document, sourcePath string) (*DependencyGraph, error) {
-	analyzer := newDependencyAnalyzer()
+	return AnalyzeDependenciesWithOptions(doc, sourcePath, RemoteImportOptions{})
+}
+
+// AnalyzeDependenciesWithOptions is AnalyzeDependencies with control over how
+// remote imports are fetched and cached.
+func AnalyzeDependenciesWithOptions(doc *ast.Document, sourcePath string, remote RemoteImportOptions) (*DependencyGraph, error) {
+	analyzer := newDependencyAnalyzer(remote)
 	return analyzer.analyze(doc, sourcePath)
 }
 
-// AnalyzeDependenciesFromFile parses a WDL file and analyzes its dependencies
+// AnalyzeDependenciesFromFile parses a WDL file and analyzes its dependencies.
+// Like AnalyzeDependencies, it downloads remote imports over the network.
 func AnalyzeDependenciesFromFile(filePath string) (*DependencyGraph, error) {
+	return AnalyzeDependenciesFromFileWithOptions(filePath, RemoteImportOptions{})
+}
+
+// AnalyzeDependenciesFromFileWithOptions parses a WDL file and analyzes its
+// dependencies, fetching remote imports as configured.
+func AnalyzeDependenciesFromFileWithOptions(filePath string, remote RemoteImportOptions) (*DependencyGraph, error) {
 	doc, err := Parse(filePath)
 	if err != nil {
 		return nil, err
 	}
-	return AnalyzeDependencies(doc, filePath)
+	return AnalyzeDependenciesWithOptions(doc, filePath, remote)
 }
 
 // dependencyAnalyzer handles dependency analysis with caching
@@ -47,13 +72,15 @@ type dependencyAnalyzer struct {
 	cache   map[string]*DependencyNode // Cache of parsed documents
 	visited map[string]bool            // Tracks visited files during current traversal
 	stack   []string                   // Stack for cycle detection
+	remote  *remoteFetcher             // Fetches http(s) imports
 }
 
-func newDependencyAnalyzer() *dependencyAnalyzer {
+func newDependencyAnalyzer(remote RemoteImportOptions) *dependencyAnalyzer {
 	return &dependencyAnalyzer{
 		cache:   make(map[string]*DependencyNode),
 		visited: make(map[string]bool),
 		stack:   make([]string, 0),
+		remote:  newRemoteFetcher(remote),
 	}
 }
 
@@ -82,7 +109,7 @@ func (a *dependencyAnalyzer) analyze(doc *ast.Document, sourcePath string) (*Dep
 	graph.Dependencies[absPath] = rootNode
 
 	// Analyze imports recursively
-	err = a.analyzeImports(rootNode, graph, filepath.Dir(absPath))
+	err = a.analyzeImports(rootNode, graph)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +123,7 @@ func (a *dependencyAnalyzer) analyze(doc *ast.Document, sourcePath string) (*Dep
 	return graph, nil
 }
 
-func (a *dependencyAnalyzer) analyzeImports(node *DependencyNode, graph *DependencyGraph, baseDir string) error {
+func (a *dependencyAnalyzer) analyzeImports(node *DependencyNode, graph *DependencyGraph) error {
 	// Check for circular dependency
 	for _, stackPath := range a.stack {
 		if stackPath == node.Path {
@@ -116,7 +143,7 @@ func (a *dependencyAnalyzer) analyzeImports(node *DependencyNode, graph *Depende
 	}
 
 	for _, imp := range node.Document.Imports {
-		resolvedPath, err := a.resolveImportPath(imp.URI, baseDir)
+		resolvedPath, err := a.resolveImportPath(imp.URI, node.Path)
 		if err != nil {
 			return fmt.Errorf("failed to resolve import %s: %w", imp.URI, err)
 		}
@@ -125,7 +152,7 @@ func (a *dependencyAnalyzer) analyzeImports(node *DependencyNode, graph *Depende
 
 		// Store aliases
 		if imp.As != "" {
-			node.Aliases[imp.As] = filepath.Base(resolvedPath)
+			node.Aliases[imp.As] = importBaseName(resolvedPath)
 		}
 		for _, alias := range imp.Aliases {
 			node.Aliases[alias.Alias] = alias.Original
@@ -137,26 +164,31 @@ func (a *dependencyAnalyzer) analyzeImports(node *DependencyNode, graph *Depende
 			continue
 		}
 
-		// Parse the imported file
-		impDoc, err := Parse(resolvedPath)
-		if err != nil {
-			return fmt.Errorf("failed to parse import %s: %w", resolvedPath, err)
-		}
-
 		impNode := &DependencyNode{
 			Path:         resolvedPath,
-			Document:     impDoc,
 			DirectDeps:   make([]string, 0),
 			ImportedBy:   []string{node.Path},
 			Aliases:      make(map[string]string),
 			ResolvedFrom: imp.URI,
 		}
+		if isRemoteImport(resolvedPath) {
+			impNode.CachedPath, err = a.remote.fetch(resolvedPath)
+			if err != nil {
+				return fmt.Errorf("failed to resolve import %s: %w", imp.URI, err)
+			}
+		}
+
+		// Parse the imported file
+		impNode.Document, err = Parse(impNode.ContentPath())
+		if err != nil {
+			return fmt.Errorf("failed to parse import %s: %w", resolvedPath, err)
+		}
 
 		a.cache[resolvedPath] = impNode
 		graph.Dependencies[resolvedPath] = impNode
 
 		// Recursively analyze imports
-		err = a.analyzeImports(impNode, graph, filepath.Dir(resolvedPath))
+		err = a.analyzeImports(impNode, graph)
 		if err != nil {
 			return err
 		}
@@ -165,14 +197,14 @@ func (a *dependencyAnalyzer) analyzeImports(node *DependencyNode, graph *Depende
 	return nil
 }
 
-// resolveImportPath resolves an import URI to an absolute path
-func (a *dependencyAnalyzer) resolveImportPath(uri string, baseDir string) (string, error) {
-	// Check if it's a URL
-	if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
-		// For now, we don't support HTTP imports
-		// This could be extended to download and cache remote files
-		return "", fmt.Errorf("HTTP imports not yet supported: %s", uri)
+// resolveImportPath resolves an import URI, written in the file at importer,
+// to an absolute path — or to a URL for a remote import, including a relative
+// import inside a remote file.
+func (a *dependencyAnalyzer) resolveImportPath(uri string, importer string) (string, error) {
+	if isRemoteImport(uri) || isRemoteImport(importer) {
+		return resolveRemoteImport(uri, importer)
 	}
+	baseDir := filepath.Dir(importer)
 
 	// Check if it's a file:// URL
 	if strings.HasPrefix(uri, "file://") {
@@ -295,16 +327,24 @@ func (g *DependencyGraph) PrintGraph() string {
 
 	for _, path := range g.Imports {
 		node := g.Dependencies[path]
-		relPath, _ := filepath.Rel(filepath.Dir(g.Root), path)
-		fmt.Fprintf(&sb, "  - %s\n", relPath)
+		fmt.Fprintf(&sb, "  - %s\n", g.displayPath(path))
 		if len(node.DirectDeps) > 0 {
 			sb.WriteString("    imports:\n")
 			for _, dep := range node.DirectDeps {
-				depRel, _ := filepath.Rel(filepath.Dir(g.Root), dep)
-				fmt.Fprintf(&sb, "      - %s\n", depRel)
+				fmt.Fprintf(&sb, "      - %s\n", g.displayPath(dep))
 			}
 		}
 	}
 
 	return sb.String()
 }
+
+// displayPath shows a local dependency relative to the root, and a remote one
+// by its URL.
+func (g *DependencyGraph) displayPath(path string) string {
+	if isRemoteImport(path) {
+		return path
+	}
+	rel, _ := filepath.Rel(filepath.Dir(g.Root), path)
+	return rel
+}
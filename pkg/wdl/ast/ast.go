@@ -177,6 +177,8 @@ func (a *ArrayLiteral) ExpressionNode() {}
 // MapLiteral represents a map literal {a: b, c: d}
 type MapLiteral struct {
 	Entries map[Expression]Expression
+	// Keys are the keys of Entries in the order they were written.
+	Keys []Expression
 }
 
 func (m *MapLiteral) ExpressionNode() {}
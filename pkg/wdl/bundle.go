@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -15,6 +16,10 @@ import (
 // importRegex matches import statements: import "path" or import 'path'
 var importRegex = regexp.MustCompile(`import\s+["']([^"']+)["']`)
 
+// bundleEpoch is the timestamp given to every ZIP entry, so a bundle's bytes
+// depend only on its contents. It is the earliest time a ZIP can record.
+var bundleEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
 // maxExtractFileSize limits the size of files extracted from ZIP (100MB)
 const maxExtractFileSize = 100 * 1024 * 1024
 
@@ -22,6 +27,9 @@ const maxExtractFileSize = 100 * 1024 * 1024
 type BundleOptions struct {
 	// IncludeMetadata includes a manifest.json file in the bundle
 	IncludeMetadata bool
+	// Remote configures how http(s) imports are fetched. Fetched files are
+	// bundled like local ones and the imports rewritten to point at them.
+	Remote RemoteImportOptions
 }
 
 // DefaultBundleOptions returns the default bundle options
@@ -34,7 +42,7 @@ func DefaultBundleOptions() BundleOptions {
 // BundleMetadata contains information about the bundle
 type BundleMetadata struct {
 	Version      string    `json:"version"`
-	CreatedAt    time.Time `json:"created_at"`
+	CreatedAt    time.Time `json:"created_at,omitzero"` // unset in reproducible bundles
 	MainWorkflow string    `json:"main_workflow"`
 	WDLVersion   string    `json:"wdl_version"`
 	Dependencies []string  `json:"dependencies"`
@@ -47,6 +55,8 @@ type BundleResult struct {
 	MainWDLPath string
 	// DependenciesZipPath is the path to the ZIP file containing all dependencies
 	DependenciesZipPath string
+	// LockfilePath is the path to the lockfile describing the bundle
+	LockfilePath string
 	// Dependencies is the list of dependency paths included in the ZIP
 	Dependencies []string
 	// TotalFiles is the total number of files (main WDL + dependencies)
@@ -63,91 +73,148 @@ func CreateBundle(mainWorkflow string, outputDir string) (*BundleResult, error)
 	return CreateBundleWithOptions(mainWorkflow, outputDir, DefaultBundleOptions())
 }
 
-// CreateBundleWithOptions creates a bundle with custom options
+// CreateBundleWithOptions creates a bundle with custom options.
+//
+// The output is reproducible: the same sources always produce byte-identical
+// files. Besides the main WDL and the ZIP it writes a lockfile,
+// <name>.lock.json, recording the hash and origin of every bundled file; a copy
+// also travels inside the ZIP as bundle.lock.json.
 func CreateBundleWithOptions(mainWorkflow string, outputDir string, opts BundleOptions) (*BundleResult, error) {
 	// Create output directory if it doesn't exist
 	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Parse and analyze dependencies
-	graph, err := AnalyzeDependenciesFromFile(mainWorkflow)
+	contents, err := AssembleBundle(mainWorkflow, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to analyze dependencies: %w", err)
+		return nil, err
 	}
 
 	// Derive output filenames from main workflow basename
-	baseName := strings.TrimSuffix(filepath.Base(graph.Root), filepath.Ext(graph.Root))
+	baseName := strings.TrimSuffix(contents.MainName, filepath.Ext(contents.MainName))
 	mainWDLPath := filepath.Join(outputDir, baseName+".wdl")
 	zipPath := filepath.Join(outputDir, baseName+".zip")
+	lockPath := filepath.Join(outputDir, baseName+LockfileSuffix)
 
-	// No dependencies - just copy the main workflow
-	if len(graph.Imports) == 0 {
-		mainContent, err := os.ReadFile(graph.Root)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read main workflow: %w", err)
-		}
+	lockJSON, err := contents.Lock.Marshal()
+	if err != nil {
+		return nil, err
+	}
 
-		// Write main WDL without changes
-		if err := os.WriteFile(mainWDLPath, mainContent, 0644); err != nil {
-			return nil, fmt.Errorf("failed to write main WDL: %w", err)
-		}
+	// Write main WDL, its imports rewritten to point inside the ZIP
+	if err := os.WriteFile(mainWDLPath, contents.Main, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write main WDL: %w", err)
+	}
+	if err := os.WriteFile(lockPath, lockJSON, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write lockfile: %w", err)
+	}
 
+	// No dependencies - the main workflow is all there is
+	if len(contents.Files) == 0 {
 		return &BundleResult{
 			MainWDLPath:         mainWDLPath,
 			DependenciesZipPath: "",
+			LockfilePath:        lockPath,
 			Dependencies:        []string{},
 			TotalFiles:          1,
 		}, nil
 	}
 
-	// Build import mapping: original import path -> flattened name in ZIP
-	importMapping := buildImportMapping(graph)
-
-	// Rewrite main workflow imports
-	mainContent, err := os.ReadFile(graph.Root)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read main workflow: %w", err)
-	}
-
-	rewrittenMain := rewriteImports(string(mainContent), graph.Root, importMapping)
-
-	// Write rewritten main WDL
-	if err := os.WriteFile(mainWDLPath, []byte(rewrittenMain), 0644); err != nil {
-		return nil, fmt.Errorf("failed to write main WDL: %w", err)
-	}
-
 	// Create ZIP with dependencies
-	if err := createDependenciesZip(graph, importMapping, zipPath, opts); err != nil {
+	if err := writeBundleZip(contents, lockJSON, zipPath, opts); err != nil {
 		// Clean up main WDL on failure
 		_ = os.Remove(mainWDLPath)
+		_ = os.Remove(lockPath)
 		return nil, fmt.Errorf("failed to create dependencies ZIP: %w", err)
 	}
 
-	deps := append([]string{}, graph.Imports...)
+	deps := append([]string{}, contents.Graph.Imports...)
 
 	return &BundleResult{
 		MainWDLPath:         mainWDLPath,
 		DependenciesZipPath: zipPath,
+		LockfilePath:        lockPath,
 		Dependencies:        deps,
-		TotalFiles:          len(graph.Imports) + 1,
+		TotalFiles:          len(deps) + 1,
 	}, nil
 }
 
-// buildImportMapping creates a mapping from absolute paths to flattened names for the ZIP
+// BundleContents is a bundle assembled in memory, before anything is written.
+type BundleContents struct {
+	// MainName is the main workflow's file name.
+	MainName string
+	// Main is the main workflow with its imports rewritten.
+	Main []byte
+	// Files maps each dependency's name in the ZIP to its rewritten content.
+	Files map[string][]byte
+	Lock  *BundleLock
+	Graph *DependencyGraph
+}
+
+// AssembleBundle resolves a workflow's imports and builds the bundle in
+// memory: the files CreateBundleWithOptions would write, and their lock.
+func AssembleBundle(mainWorkflow string, opts BundleOptions) (*BundleContents, error) {
+	// Parse and analyze dependencies
+	graph, err := AnalyzeDependenciesFromFileWithOptions(mainWorkflow, opts.Remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze dependencies: %w", err)
+	}
+
+	// Build import mapping: original import path -> flattened name in ZIP
+	importMapping := buildImportMapping(graph)
+
+	mainContent, err := os.ReadFile(graph.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read main workflow: %w", err)
+	}
+
+	contents := &BundleContents{
+		MainName: filepath.Base(graph.Root),
+		Main:     []byte(rewriteImports(string(mainContent), graph.Dependencies[graph.Root], importMapping)),
+		Files:    make(map[string][]byte, len(importMapping)),
+		Graph:    graph,
+	}
+	contents.Lock = &BundleLock{
+		Version:      lockVersion,
+		MainWorkflow: contents.MainName,
+		Files:        []LockedFile{lockEntry(contents.MainName, contents.MainName, "", mainContent, contents.Main)},
+	}
+
+	for _, depPath := range graph.Imports {
+		node := graph.Dependencies[depPath]
+		content, err := os.ReadFile(node.ContentPath())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", depPath, err)
+		}
+
+		// Rewrite imports in this dependency too
+		name := importMapping[depPath]
+		contents.Files[name] = []byte(rewriteImports(string(content), node, importMapping))
+		contents.Lock.Files = append(contents.Lock.Files,
+			lockEntry(name, lockSource(graph, depPath), node.ResolvedFrom, content, contents.Files[name]))
+	}
+	sort.Slice(contents.Lock.Files[1:], func(i, j int) bool {
+		return contents.Lock.Files[i+1].Name < contents.Lock.Files[j+1].Name
+	})
+
+	return contents, nil
+}
+
+// buildImportMapping creates a mapping from absolute paths (or URLs, for
+// remote imports) to flattened names for the ZIP
 func buildImportMapping(graph *DependencyGraph) map[string]string {
 	mapping := make(map[string]string)
 	usedNames := make(map[string]int)
 
 	for _, depPath := range graph.Imports {
-		baseName := filepath.Base(depPath)
+		baseName := importBaseName(depPath)
 
 		// Handle name collisions
 		if count, exists := usedNames[baseName]; exists {
 			ext := filepath.Ext(baseName)
 			nameWithoutExt := strings.TrimSuffix(baseName, ext)
 			baseName = fmt.Sprintf("%s_%d%s", nameWithoutExt, count+1, ext)
-			usedNames[filepath.Base(depPath)] = count + 1
+			usedNames[importBaseName(depPath)] = count + 1
 		} else {
 			usedNames[baseName] = 1
 		}
@@ -158,9 +225,20 @@ func buildImportMapping(graph *DependencyGraph) map[string]string {
 	return mapping
 }
 
-// rewriteImports rewrites import statements in WDL content
-func rewriteImports(content string, filePath string, importMapping map[string]string) string {
-	fileDir := filepath.Dir(filePath)
+// rewriteImports rewrites import statements in WDL content. Each import is
+// matched to its target through the analyzed node — the i-th import resolved
+// to the i-th direct dependency — so remote imports, and relative imports
+// inside remote files, are rewritten like local ones.
+func rewriteImports(content string, node *DependencyNode, importMapping map[string]string) string {
+	resolved := make(map[string]string, len(node.DirectDeps))
+	if node.Document != nil {
+		for i, imp := range node.Document.Imports {
+			if i < len(node.DirectDeps) {
+				resolved[imp.URI] = node.DirectDeps[i]
+			}
+		}
+	}
+	fileDir := filepath.Dir(node.Path)
 
 	return importRegex.ReplaceAllStringFunc(content, func(match string) string {
 		submatches := importRegex.FindStringSubmatch(match)
@@ -171,10 +249,12 @@ func rewriteImports(content string, filePath string, importMapping map[string]st
 		importPath := submatches[1]
 
 		// Resolve to absolute path
-		var absPath string
-		if filepath.IsAbs(importPath) {
+		absPath, ok := resolved[importPath]
+		switch {
+		case ok:
+		case filepath.IsAbs(importPath):
 			absPath = importPath
-		} else {
+		default:
 			absPath = filepath.Clean(filepath.Join(fileDir, importPath))
 		}
 
@@ -188,8 +268,9 @@ func rewriteImports(content string, filePath string, importMapping map[string]st
 	})
 }
 
-// createDependenciesZip creates a ZIP file containing all dependencies
-func createDependenciesZip(graph *DependencyGraph, importMapping map[string]string, zipPath string, opts BundleOptions) (err error) {
+// writeBundleZip writes the dependencies ZIP. Entries are sorted by name and
+// carry a fixed timestamp, so the same contents always give the same bytes.
+func writeBundleZip(contents *BundleContents, lockJSON []byte, zipPath string, opts BundleOptions) (err error) {
 	outFile, err := os.Create(zipPath)
 	if err != nil {
 		return fmt.Errorf("failed to create zip file: %w", err)
@@ -203,40 +284,36 @@ func createDependenciesZip(graph *DependencyGraph, importMapping map[string]stri
 		}
 	}()
 
-	// Write each dependency with rewritten imports
-	for depPath, flatName := range importMapping {
-		content, err := os.ReadFile(depPath)
-		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", depPath, err)
-		}
-
-		// Rewrite imports in this dependency too
-		rewrittenContent := rewriteImports(string(content), depPath, importMapping)
+	names := make([]string, 0, len(contents.Files))
+	for name := range contents.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-		if err := writeFileToZip(zipWriter, flatName, []byte(rewrittenContent)); err != nil {
-			return fmt.Errorf("failed to write %s to zip: %w", flatName, err)
+	for _, name := range names {
+		if err := writeFileToZip(zipWriter, name, contents.Files[name]); err != nil {
+			return fmt.Errorf("failed to write %s to zip: %w", name, err)
 		}
 	}
 
+	if err := writeFileToZip(zipWriter, LockfileName, lockJSON); err != nil {
+		return fmt.Errorf("failed to write lockfile: %w", err)
+	}
+
 	// Write metadata if requested
 	if opts.IncludeMetadata {
 		wdlVersion := ""
+		graph := contents.Graph
 		if rootNode := graph.Dependencies[graph.Root]; rootNode != nil && rootNode.Document != nil {
 			wdlVersion = rootNode.Document.Version
 		}
 
-		deps := make([]string, 0, len(importMapping))
-		for _, flatName := range importMapping {
-			deps = append(deps, flatName)
-		}
-
 		metadata := &BundleMetadata{
 			Version:      "1.0",
-			CreatedAt:    time.Now().UTC(),
-			MainWorkflow: filepath.Base(graph.Root),
+			MainWorkflow: contents.MainName,
 			WDLVersion:   wdlVersion,
-			Dependencies: deps,
-			TotalFiles:   len(deps) + 1,
+			Dependencies: names,
+			TotalFiles:   len(names) + 1,
 		}
 
 		metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
@@ -257,7 +334,7 @@ func writeFileToZip(zw *zip.Writer, filename string, content []byte) error {
 	header := &zip.FileHeader{
 		Name:     filename,
 		Method:   zip.Deflate,
-		Modified: time.Now(),
+		Modified: bundleEpoch,
 	}
 
 	writer, err := zw.CreateHeader(header)
@@ -0,0 +1,176 @@
+package wdl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	// LockfileName is the lockfile's name inside a dependencies ZIP.
+	LockfileName = "bundle.lock.json"
+	// LockfileSuffix names the lockfile written beside a bundle:
+	// <workflow>.lock.json.
+	LockfileSuffix = ".lock.json"
+
+	lockVersion = 1
+)
+
+// BundleLock records exactly what went into a bundle, so a bundle can be
+// traced back to, and checked against, the sources it was built from.
+type BundleLock struct {
+	Version      int          `json:"version"`
+	MainWorkflow string       `json:"main_workflow"`
+	Files        []LockedFile `json:"files"`
+}
+
+// LockedFile is one bundled file. The main workflow comes first, then the
+// dependencies sorted by name.
+type LockedFile struct {
+	// Name is the file's name in the bundle.
+	Name string `json:"name"`
+	// Source is where it was read from: a path relative to the main
+	// workflow's directory, or a URL.
+	Source string `json:"source"`
+	// ImportURI is the import as first written in the importing file, empty
+	// for the main workflow.
+	ImportURI string `json:"import_uri,omitempty"`
+	// SHA256 is the hash of the bundled content, imports rewritten.
+	SHA256 string `json:"sha256"`
+	// SourceSHA256 is the hash of the content as read from Source.
+	SourceSHA256 string `json:"source_sha256"`
+}
+
+// Marshal encodes the lock as indented JSON.
+func (l *BundleLock) Marshal() ([]byte, error) {
+	out, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal lockfile: %w", err)
+	}
+	return append(out, '\n'), nil
+}
+
+// ParseBundleLock decodes a lockfile.
+func ParseBundleLock(data []byte) (*BundleLock, error) {
+	var lock BundleLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("invalid lockfile: %w", err)
+	}
+	if lock.Version != lockVersion {
+		return nil, fmt.Errorf("unsupported lockfile version %d", lock.Version)
+	}
+	return &lock, nil
+}
+
+func lockEntry(name, source, importURI string, original, bundled []byte) LockedFile {
+	return LockedFile{
+		Name:         name,
+		Source:       source,
+		ImportURI:    importURI,
+		SHA256:       sha256Hex(bundled),
+		SourceSHA256: sha256Hex(original),
+	}
+}
+
+// lockSource is how a dependency's origin is recorded: relative to the main
+// workflow so the lock does not depend on where the checkout lives.
+func lockSource(graph *DependencyGraph, path string) string {
+	if isRemoteImport(path) {
+		return path
+	}
+	rel, err := filepath.Rel(filepath.Dir(graph.Root), path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Bundle file verification outcomes.
+const (
+	BundleFileChanged    = "changed"
+	BundleFileMissing    = "missing"
+	BundleFileUnexpected = "unexpected"
+)
+
+// BundleFileDiff is one file where a bundle and its sources disagree.
+type BundleFileDiff struct {
+	Name   string
+	Status string // BundleFileChanged, BundleFileMissing or BundleFileUnexpected
+	// Source is where the expected file comes from, when there is one.
+	Source string
+}
+
+// BundleVerification is the result of checking a bundle against its sources.
+type BundleVerification struct {
+	// Checked is the number of files compared.
+	Checked     int
+	Differences []BundleFileDiff
+}
+
+// OK reports whether the bundle matches its sources exactly.
+func (v *BundleVerification) OK() bool {
+	return len(v.Differences) == 0
+}
+
+// VerifyBundle compares a bundle with what its sources produce today.
+//
+// main is the submitted main workflow; nil skips it, for a ZIP checked on its
+// own. deps are the dependency WDL files keyed by their name in the bundle.
+// Files are compared by content hash, so a byte-identical rebuild is required:
+// reformatting a source file counts as a change.
+func VerifyBundle(expected *BundleContents, main []byte, deps map[string][]byte) *BundleVerification {
+	sources := make(map[string]string, len(expected.Lock.Files))
+	for _, f := range expected.Lock.Files {
+		sources[f.Name] = f.Source
+	}
+
+	v := &BundleVerification{}
+	if main != nil {
+		v.Checked++
+		if sha256Hex(main) != sha256Hex(expected.Main) {
+			v.Differences = append(v.Differences, BundleFileDiff{
+				Name: expected.MainName, Status: BundleFileChanged, Source: sources[expected.MainName],
+			})
+		}
+	}
+
+	names := make([]string, 0, len(expected.Files)+len(deps))
+	for name := range expected.Files {
+		names = append(names, name)
+	}
+	for name := range deps {
+		if _, ok := expected.Files[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		want, expectedOK := expected.Files[name]
+		got, present := deps[name]
+		v.Checked++
+		switch {
+		case !present:
+			v.Differences = append(v.Differences, BundleFileDiff{Name: name, Status: BundleFileMissing, Source: sources[name]})
+		case !expectedOK:
+			v.Differences = append(v.Differences, BundleFileDiff{Name: name, Status: BundleFileUnexpected})
+		case sha256Hex(got) != sha256Hex(want):
+			v.Differences = append(v.Differences, BundleFileDiff{Name: name, Status: BundleFileChanged, Source: sources[name]})
+		}
+	}
+	return v
+}
+
+// BundleZipFiles reads the WDL files of a dependencies ZIP, keyed by name, for
+// VerifyBundle.
+func BundleZipFiles(zipData []byte) (map[string][]byte, error) {
+	return readZipWDL(zipData)
+}
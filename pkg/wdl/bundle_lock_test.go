@@ -0,0 +1,171 @@
+package wdl
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeBundleSources(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.wdl":       "version 1.0\n\nimport \"tasks/a.wdl\" as a\nimport \"tasks/b.wdl\" as b\n\nworkflow Main {\n    call a.A {}\n    call b.B {}\n}\n",
+		"tasks/a.wdl":    "version 1.0\n\ntask A {\n    command { echo a }\n}\n",
+		"tasks/b.wdl":    "version 1.0\n\nimport \"../lib/c.wdl\" as c\n\ntask B {\n    command { echo b }\n}\n",
+		"lib/c.wdl":      "version 1.0\n\ntask C {\n    command { echo c }\n}\n",
+		"unrelated.wdl":  "version 1.0\n",
+		"tasks/note.txt": "not WDL",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, "main.wdl")
+}
+
+func TestCreateBundleIsReproducible(t *testing.T) {
+	mainPath := writeBundleSources(t)
+
+	first, err := CreateBundle(mainPath, t.TempDir())
+	if err != nil {
+		t.Fatalf("CreateBundle() error = %v", err)
+	}
+	second, err := CreateBundle(mainPath, t.TempDir())
+	if err != nil {
+		t.Fatalf("CreateBundle() error = %v", err)
+	}
+
+	for _, pair := range [][2]string{
+		{first.DependenciesZipPath, second.DependenciesZipPath},
+		{first.LockfilePath, second.LockfilePath},
+		{first.MainWDLPath, second.MainWDLPath},
+	} {
+		a, err := os.ReadFile(pair[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("%s differs between two bundles of the same sources", filepath.Base(pair[0]))
+		}
+	}
+}
+
+func TestAssembleBundleLock(t *testing.T) {
+	contents, err := AssembleBundle(writeBundleSources(t), DefaultBundleOptions())
+	if err != nil {
+		t.Fatalf("AssembleBundle() error = %v", err)
+	}
+
+	type entry struct{ name, source, importURI string }
+	var got []entry
+	for _, f := range contents.Lock.Files {
+		got = append(got, entry{f.Name, f.Source, f.ImportURI})
+		if f.SHA256 == "" || f.SourceSHA256 == "" {
+			t.Errorf("%s has no hashes", f.Name)
+		}
+	}
+	want := []entry{
+		{"main.wdl", "main.wdl", ""},
+		{"a.wdl", "tasks/a.wdl", "tasks/a.wdl"},
+		{"b.wdl", "tasks/b.wdl", "tasks/b.wdl"},
+		{"c.wdl", "lib/c.wdl", "../lib/c.wdl"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lock files = %v, want %v", got, want)
+	}
+
+	// b.wdl's import was rewritten, so its bundled hash moves off the source's.
+	b := contents.Lock.Files[2]
+	if b.SHA256 == b.SourceSHA256 {
+		t.Error("b.wdl's imports were rewritten, so the hashes should differ")
+	}
+
+	encoded, err := contents.Lock.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ParseBundleLock(encoded)
+	if err != nil {
+		t.Fatalf("ParseBundleLock() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, contents.Lock) {
+		t.Errorf("lock does not round-trip: %+v", decoded)
+	}
+}
+
+func TestVerifyBundle(t *testing.T) {
+	contents, err := AssembleBundle(writeBundleSources(t), DefaultBundleOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	copyFiles := func() map[string][]byte {
+		out := make(map[string][]byte, len(contents.Files))
+		for name, content := range contents.Files {
+			out[name] = content
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		main   []byte
+		deps   func() map[string][]byte
+		want   []BundleFileDiff
+		checks int
+	}{
+		{
+			name:   "identical",
+			deps:   copyFiles,
+			checks: 3,
+		},
+		{
+			name: "changed, missing and extra files",
+			deps: func() map[string][]byte {
+				deps := copyFiles()
+				deps["a.wdl"] = []byte("version 1.0\n")
+				delete(deps, "c.wdl")
+				deps["stale.wdl"] = []byte("version 1.0\n")
+				return deps
+			},
+			want: []BundleFileDiff{
+				{Name: "a.wdl", Status: BundleFileChanged, Source: "tasks/a.wdl"},
+				{Name: "c.wdl", Status: BundleFileMissing, Source: "lib/c.wdl"},
+				{Name: "stale.wdl", Status: BundleFileUnexpected},
+			},
+			checks: 4,
+		},
+		{
+			name:   "submitted main workflow differs",
+			main:   []byte("version 1.0\nworkflow Main {}\n"),
+			deps:   copyFiles,
+			want:   []BundleFileDiff{{Name: "main.wdl", Status: BundleFileChanged, Source: "main.wdl"}},
+			checks: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := VerifyBundle(contents, tt.main, tt.deps())
+			if !reflect.DeepEqual(v.Differences, tt.want) {
+				t.Errorf("Differences = %+v, want %+v", v.Differences, tt.want)
+			}
+			if v.Checked != tt.checks {
+				t.Errorf("Checked = %d, want %d", v.Checked, tt.checks)
+			}
+			if v.OK() != (len(tt.want) == 0) {
+				t.Errorf("OK() = %v", v.OK())
+			}
+		})
+	}
+}
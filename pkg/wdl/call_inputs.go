@@ -0,0 +1,182 @@
+package wdl
+
+import (
+	"strings"
+
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// callInput is an input a call leaves unbound, which Cromwell lets the inputs
+// JSON set as "Workflow.call.input": typically a task's memory, disk or docker
+// knob, or a subworkflow's own input.
+type callInput struct {
+	spec InputSpec
+	decl *ast.Declaration
+}
+
+// callInputSet is every call-level input of a workflow, plus what is needed to
+// judge a key that does not match one.
+type callInputSet struct {
+	inputs []callInput
+	// calls maps each call path to the inputs its callee declares, bound or
+	// not, or nil when the callee could not be read.
+	calls map[string]map[string]bool
+	// unresolved lists the calls whose callee could not be read, in order.
+	unresolved []string
+}
+
+// collectCallInputs walks the workflow's calls — top-level ones first, then
+// those inside scatters and conditionals — and lists the callee inputs each
+// call leaves unbound.
+//
+// Calls inside subworkflows are only followed when the workflow's meta sets
+// allowNestedInputs: without it Cromwell accepts inputs one level down, on the
+// subworkflow call itself, and nothing deeper.
+func collectCallInputs(doc *ast.Document, docs *documentSet) *callInputSet {
+	set := &callInputSet{calls: make(map[string]map[string]bool)}
+	if doc.Workflow == nil {
+		return set
+	}
+	nested := allowsNestedInputs(doc.Workflow)
+
+	var walk func(d *ast.Document, prefix string, depth int)
+	walk = func(d *ast.Document, prefix string, depth int) {
+		if depth > maxImportDepth {
+			return
+		}
+		ns := namespaces(d)
+		for _, sc := range collectCalls(d.Workflow) {
+			path := prefix + callName(sc.call)
+			decls, meta, sub, ok := resolveCallee(d, sc.call.Target, ns, docs)
+			if !ok {
+				set.calls[path] = nil
+				set.unresolved = append(set.unresolved, path)
+				continue
+			}
+
+			declared := make(map[string]bool, len(decls))
+			for _, decl := range decls {
+				if decl == nil || decl.Type == nil {
+					continue
+				}
+				declared[decl.Name] = true
+				if _, bound := sc.call.Inputs[decl.Name]; bound {
+					continue
+				}
+				set.inputs = append(set.inputs, callInput{
+					spec: InputSpec{
+						Name:        doc.Workflow.Name + "." + path + "." + decl.Name,
+						Type:        decl.Type.String(),
+						Optional:    decl.Type.Optional,
+						Default:     renderExpression(decl.Expression),
+						HasDefault:  decl.Expression != nil,
+						Description: ParameterMetaDescription(meta, decl.Name),
+						Call:        path,
+					},
+					decl: decl,
+				})
+			}
+			set.calls[path] = declared
+
+			if sub != nil && nested {
+				walk(sub, path+".", depth+1)
+			}
+		}
+	}
+	walk(doc, "", 0)
+	return set
+}
+
+// resolveCallee finds what a call targets: a task in the same document, or a
+// task or workflow in an imported one. It returns the callee's input
+// declarations and parameter_meta, and the subworkflow's document when the
+// callee is a workflow. ok is false when the definition cannot be read.
+func resolveCallee(d *ast.Document, target string, ns map[string]string, docs *documentSet) (
+	decls []*ast.Declaration, meta map[string]any, sub *ast.Document, ok bool,
+) {
+	namespace, name := splitTarget(target)
+	callee := d
+	if namespace != "" {
+		uri, known := ns[namespace]
+		if !known {
+			return nil, nil, nil, false
+		}
+		if callee, ok = docs.document(uri); !ok {
+			return nil, nil, nil, false
+		}
+	}
+	for _, t := range callee.Tasks {
+		if t != nil && t.Name == name {
+			return t.Inputs, t.ParameterMeta, nil, true
+		}
+	}
+	if namespace != "" && callee.Workflow != nil && callee.Workflow.Name == name {
+		return callee.Workflow.Inputs, callee.Workflow.ParameterMeta, callee, true
+	}
+	return nil, nil, nil, false
+}
+
+// allowsNestedInputs reports whether the workflow opts into setting inputs of
+// calls inside its subworkflows (meta { allowNestedInputs: true }).
+func allowsNestedInputs(wf *ast.Workflow) bool {
+	switch v := wf.Meta["allowNestedInputs"].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+// matchCall finds the call a qualified input key addresses, preferring the
+// longest call path so a nested call wins over its subworkflow. It returns the
+// call path and the input name after it.
+func (s *callInputSet) matchCall(key, workflowName string) (call, input string, ok bool) {
+	rest, found := strings.CutPrefix(key, workflowName+".")
+	if !found {
+		return "", "", false
+	}
+	for path := range s.calls {
+		name, matched := strings.CutPrefix(rest, path+".")
+		if !matched || strings.Contains(name, ".") {
+			continue
+		}
+		if len(path) > len(call) {
+			call, input, ok = path, name, true
+		}
+	}
+	return call, input, ok
+}
+
+// closestName suggests the candidate a mistyped name most likely meant, or ""
+// when none is close enough to be a plausible typo.
+func closestName(name string, candidates map[string]bool) string {
+	best, bestDistance := "", 3
+	for c := range candidates {
+		if d := editDistance(name, c); d < bestDistance || (d == bestDistance && best != "" && c < best) {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,154 @@
+package wdl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const callInputsWDL = `version 1.0
+
+import "qc.wdl" as qc
+
+workflow Wf {
+    input { File reads }
+    scatter (r in [reads]) {
+        call BwaMem { input: reads = r }
+    }
+    call qc.Report { input: bam = reads }
+}
+
+task BwaMem {
+    input {
+        File reads
+        String sample
+        Int mem_gb = 8
+        Int disk_gb = ceil(size(reads, "GB")) + 10
+        String? docker
+    }
+    parameter_meta { mem_gb: "Memory in GB" }
+    command <<< bwa ~{reads} >>>
+}
+`
+
+const callInputsQC = `version 1.0
+
+workflow Report {
+    input {
+        File bam
+        Int min_quality = 20
+    }
+    call Plot {}
+}
+
+task Plot {
+    input { String title = "qc" }
+    command <<< plot >>>
+}
+`
+
+func TestScaffoldInputsListsCallInputs(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		opts       ScaffoldOptions
+		want       []string
+		unresolved []string
+	}{
+		{
+			name:       "required call inputs are always listed",
+			source:     callInputsWDL,
+			want:       []string{"Wf.reads", "Wf.BwaMem.sample"},
+			unresolved: []string{"Report"},
+		},
+		{
+			name:   "all overridable inputs, grouped by call",
+			source: callInputsWDL,
+			opts:   ScaffoldOptions{IncludeOptional: true, IncludeCalls: true, Sources: SourceSet{"qc.wdl": []byte(callInputsQC)}},
+			want: []string{
+				"Wf.reads", "Wf.Report.min_quality", "Wf.BwaMem.sample", "Wf.BwaMem.mem_gb", "Wf.BwaMem.disk_gb", "Wf.BwaMem.docker",
+			},
+		},
+		{
+			name:   "nested inputs follow into subworkflows when allowed",
+			source: strings.Replace(callInputsWDL, "input { File reads }", "input { File reads }\n    meta { allowNestedInputs: true }", 1),
+			opts:   ScaffoldOptions{IncludeCalls: true, Sources: SourceSet{"qc.wdl": []byte(callInputsQC)}},
+			want: []string{
+				"Wf.reads", "Wf.Report.min_quality", "Wf.Report.Plot.title", "Wf.BwaMem.sample", "Wf.BwaMem.mem_gb", "Wf.BwaMem.disk_gb", "Wf.BwaMem.docker",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ScaffoldInputs([]byte(tt.source), tt.opts)
+			if err != nil {
+				t.Fatalf("ScaffoldInputs() error = %v", err)
+			}
+			var got []string
+			for _, in := range s.Inputs {
+				got = append(got, in.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Inputs = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(s.UnresolvedCalls, tt.unresolved) {
+				t.Errorf("UnresolvedCalls = %v, want %v", s.UnresolvedCalls, tt.unresolved)
+			}
+		})
+	}
+}
+
+func TestScaffoldInputsCallTemplate(t *testing.T) {
+	s, err := ScaffoldInputs([]byte(callInputsWDL), ScaffoldOptions{IncludeCalls: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A computed default has no value to write, so disk_gb is listed but
+	// left out of the template.
+	want := `{
+  "Wf.reads": "<FILL: File>",
+  "Wf.BwaMem.sample": "<FILL: String>",
+  "Wf.BwaMem.mem_gb": 8,
+  "Wf.BwaMem.docker": null
+}
+`
+	if string(s.Template) != want {
+		t.Errorf("Template =\n%s\nwant\n%s", s.Template, want)
+	}
+}
+
+func TestCheckInputsCallInputs(t *testing.T) {
+	inputs := `{
+  "Wf.reads": "gs://b/r.fq",
+  "Wf.BwaMem.mem_gb": "lots",
+  "Wf.BwaMem.mem_gbb": 4,
+  "Wf.BwaMem.reads": "gs://b/other.fq",
+  "Wf.Report.min_quality": 30,
+  "Wf.Nope.x": 1
+}`
+	report := CheckInputs([]byte(callInputsWDL), []byte(inputs))
+
+	got := make(map[string]string)
+	for _, f := range report.Findings {
+		got[f.Input] = string(f.Severity) + ": " + f.Message
+	}
+	want := map[string]string{
+		"Wf.BwaMem.sample":      "error: required input of call BwaMem is missing (type String)",
+		"Wf.BwaMem.mem_gb":      "error: is a string, but Int is expected",
+		"Wf.BwaMem.mem_gbb":     `warning: call BwaMem has no input "mem_gbb" — did you mean mem_gb?`,
+		"Wf.BwaMem.reads":       "warning: is already set by call BwaMem in the WDL, so this value is not used",
+		"Wf.Report.min_quality": "warning: sets an input of call Report, whose definition could not be read to check it (bundle the imports)",
+		"Wf.Nope.x":             "warning: not declared by this workflow — check for a typo",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings =\n%v\nwant\n%v", got, want)
+	}
+
+	// With the import available, the subworkflow's input checks cleanly.
+	withSources := CheckInputsWithSources([]byte(callInputsWDL), []byte(inputs), SourceSet{"qc.wdl": []byte(callInputsQC)})
+	for _, f := range withSources.Findings {
+		if f.Input == "Wf.Report.min_quality" {
+			t.Errorf("unexpected finding for a valid subworkflow input: %+v", f)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package wdl
 
 import (
+	"fmt"
 	"slices"
 	"sort"
 
@@ -40,6 +41,31 @@ type CallNode struct {
 	// Bindings records, per input, the leaves its value can be built from,
 	// already translated into the top-level workflow's namespace.
 	Bindings map[string]ResolvedBinding
+	// Enclosures are the blocks the call sits in, outermost first: scatters,
+	// conditionals and the subworkflow calls it was flattened out of.
+	Enclosures []Enclosure
+}
+
+// EnclosureKind is the kind of block enclosing a call.
+type EnclosureKind string
+
+const (
+	EnclosureScatter     EnclosureKind = "scatter"
+	EnclosureConditional EnclosureKind = "if"
+	EnclosureSubworkflow EnclosureKind = "subworkflow"
+)
+
+// Enclosure is one block around a call. Calls in the same block share its ID,
+// which is what lets a renderer draw the block once around all of them.
+type Enclosure struct {
+	// ID is unique within the graph: the subworkflow call's path, or the
+	// block's position within its workflow ("Sub.scatter-2").
+	ID   string        `json:"id"`
+	Kind EnclosureKind `json:"kind"`
+	// Label is the block's header as written: "sample in samples" for a
+	// scatter, the condition for an if, the called workflow's name for a
+	// subworkflow.
+	Label string `json:"label"`
 }
 
 // CallGraph is a workflow's calls, flattened across subworkflows and indexed
@@ -110,7 +136,7 @@ func CallGraphFromDocument(doc *ast.Document, deps SourceSet) *CallGraph {
 		subOutputs: make(map[string]map[string]string),
 	}
 	g.InputDefaults = staticDefaults(doc.Workflow.Inputs)
-	b.addWorkflow(doc, "", nil, 0)
+	b.addWorkflow(doc, "", nil, nil, 0)
 	b.rewireSubworkflowOutputs()
 	b.deriveDependencies()
 	return g
@@ -134,8 +160,9 @@ type afterEdge struct{ from, to string }
 //
 // outer maps this workflow's input names to bindings already expressed in the
 // top-level namespace; it is how a value passed into a subworkflow is followed
-// through to the leaf that consumes it.
-func (b *graphBuilder) addWorkflow(doc *ast.Document, prefix string, outer map[string]ResolvedBinding, depth int) {
+// through to the leaf that consumes it. enclosing are the blocks around the
+// subworkflow call, which every call inside it inherits.
+func (b *graphBuilder) addWorkflow(doc *ast.Document, prefix string, outer map[string]ResolvedBinding, enclosing []Enclosure, depth int) {
 	if depth > maxImportDepth || doc.Workflow == nil {
 		return
 	}
@@ -149,6 +176,15 @@ func (b *graphBuilder) addWorkflow(doc *ast.Document, prefix string, outer map[s
 	}
 
 	calls := collectCalls(wf)
+	for i := range calls {
+		chain := make([]Enclosure, 0, len(enclosing)+len(calls[i].blocks))
+		chain = append(chain, enclosing...)
+		for _, blk := range calls[i].blocks {
+			blk.ID = prefix + blk.ID
+			chain = append(chain, blk)
+		}
+		calls[i].blocks = chain
+	}
 	callNames := make(map[string]bool, len(calls))
 	for _, c := range calls {
 		callNames[callName(c.call)] = true
@@ -260,10 +296,12 @@ func (b *graphBuilder) addCall(
 			b.graph.Nodes[path] = &CallNode{
 				Name: path, Task: target, Scattered: c.scatter != nil,
 				Unresolved: true, Subworkflow: true, Bindings: translated,
+				Enclosures: c.blocks,
 			}
 			return
 		}
-		b.addWorkflow(sub, path+".", translated, depth+1)
+		enclosing := append(slices.Clip(c.blocks), Enclosure{ID: path, Kind: EnclosureSubworkflow, Label: target})
+		b.addWorkflow(sub, path+".", translated, enclosing, depth+1)
 		b.subOutputs[path] = subworkflowOutputs(sub.Workflow, path+".")
 		return
 	}
@@ -275,6 +313,7 @@ func (b *graphBuilder) addCall(
 		Fanout:     res.fanout,
 		Unresolved: !b.taskIsVisible(namespace, target, ns, localTasks),
 		Bindings:   translated,
+		Enclosures: c.blocks,
 	}
 }
 
@@ -431,6 +470,9 @@ type Fanout struct {
 type scopedCall struct {
 	call    *ast.Call
 	scatter *ast.Scatter
+	// blocks are every scatter and conditional around the call, outermost
+	// first, with IDs local to the workflow.
+	blocks []Enclosure
 }
 
 // collectCalls gathers every call in a workflow, including those nested in
@@ -439,43 +481,55 @@ func collectCalls(wf *ast.Workflow) []scopedCall {
 	seen := make(map[*ast.Call]bool)
 	var out []scopedCall
 
-	add := func(c *ast.Call, scatter *ast.Scatter) {
+	add := func(c *ast.Call, scatter *ast.Scatter, blocks []Enclosure) {
 		if c == nil || seen[c] {
 			return
 		}
 		seen[c] = true
-		out = append(out, scopedCall{call: c, scatter: scatter})
+		out = append(out, scopedCall{call: c, scatter: scatter, blocks: blocks})
+	}
+
+	// Blocks are numbered in the order they are met, which is stable for a
+	// given source.
+	count := 0
+	enter := func(blocks []Enclosure, kind EnclosureKind, label string) []Enclosure {
+		count++
+		return append(slices.Clip(blocks), Enclosure{ID: fmt.Sprintf("%s-%d", kind, count), Kind: kind, Label: label})
 	}
 
 	// Nesting keeps the innermost scatter: that is the one whose index the body
 	// addresses, and reasoning about instances of an outer scatter would need a
 	// product of positions this does not model.
-	var walk func(body []ast.WorkflowElement, scatter *ast.Scatter)
-	walk = func(body []ast.WorkflowElement, scatter *ast.Scatter) {
+	var walk func(body []ast.WorkflowElement, scatter *ast.Scatter, blocks []Enclosure)
+	walk = func(body []ast.WorkflowElement, scatter *ast.Scatter, blocks []Enclosure) {
 		for _, el := range body {
 			switch e := el.(type) {
 			case *ast.Call:
-				add(e, scatter)
+				add(e, scatter, blocks)
 			case *ast.Scatter:
-				walk(e.Body, e)
+				walk(e.Body, e, enter(blocks, EnclosureScatter, scatterLabel(e)))
 			case *ast.Conditional:
-				walk(e.Body, scatter)
+				walk(e.Body, scatter, enter(blocks, EnclosureConditional, ExpressionText(e.Condition)))
 			}
 		}
 	}
 
 	for _, c := range wf.Calls {
-		add(c, nil)
+		add(c, nil, nil)
 	}
 	for _, s := range wf.Scatters {
-		walk(s.Body, s)
+		walk(s.Body, s, enter(nil, EnclosureScatter, scatterLabel(s)))
 	}
 	for _, c := range wf.Conditionals {
-		walk(c.Body, nil)
+		walk(c.Body, nil, enter(nil, EnclosureConditional, ExpressionText(c.Condition)))
 	}
 	return out
 }
 
+func scatterLabel(s *ast.Scatter) string {
+	return s.Variable + " in " + ExpressionText(s.Expression)
+}
+
 // collectDeclarations gathers a workflow's intermediate declarations by name,
 // including those nested in scatter and conditional blocks, so an expression
 // that reads one can be followed to its own leaves.
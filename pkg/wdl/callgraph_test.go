@@ -289,6 +289,44 @@ task T {
 	}
 }
 
+func TestTaskSpecsRecordSignature(t *testing.T) {
+	const src = `version 1.0
+
+task T {
+  input {
+    File bam
+    Int? threads
+    String docker = "samtools:1.17"
+  }
+  command <<< samtools index ~{bam} >>>
+  runtime {
+    docker: docker
+    cpu: select_first([threads, 2])
+  }
+  output { File bai = bam + ".bai" }
+}
+`
+	specs, err := TaskSpecs([]byte(src))
+	if err != nil {
+		t.Fatalf("TaskSpecs() error: %v", err)
+	}
+	spec := specs["T"]
+	wantInputs := map[string]string{"bam": "File", "threads": "Int?", "docker": "String"}
+	if !reflect.DeepEqual(spec.InputTypes, wantInputs) {
+		t.Errorf("InputTypes = %v, want %v", spec.InputTypes, wantInputs)
+	}
+	if got := spec.OutputTypes["bai"]; got != "File" {
+		t.Errorf("OutputTypes[bai] = %q, want File", got)
+	}
+	if got := spec.OutputExpressions["bai"]; got != `bam + ".bai"` {
+		t.Errorf("OutputExpressions[bai] = %q", got)
+	}
+	wantRuntime := map[string]string{"docker": "docker", "cpu": "select_first([threads, 2])"}
+	if !reflect.DeepEqual(spec.RuntimeExpressions, wantRuntime) {
+		t.Errorf("RuntimeExpressions = %v, want %v", spec.RuntimeExpressions, wantRuntime)
+	}
+}
+
 func TestStaticValueRejectsInputDependentExpressions(t *testing.T) {
 	const src = `version 1.0
 
@@ -0,0 +1,876 @@
+package wdl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// FileSizer reports the size of a file, which is all the evaluator needs from
+// storage to answer size(). The application's FileProvider satisfies it.
+type FileSizer interface {
+	GetSize(ctx context.Context, path string) (int64, error)
+}
+
+// ErrUnevaluable reports an expression whose value only the run itself can
+// produce — a call's output, a file written by a task — or one that reads a
+// value that was never supplied. It is distinct from an expression that is
+// simply wrong, such as select_first over nothing but nulls, which the run
+// would fail on too.
+var ErrUnevaluable = errors.New("cannot be evaluated before the run")
+
+// unevaluableError carries its reason as its whole message, so the chain of
+// "why" reads naturally when it surfaces three declarations away from the
+// cause.
+type unevaluableError struct{ reason string }
+
+func (e unevaluableError) Error() string        { return e.reason }
+func (e unevaluableError) Is(target error) bool { return target == ErrUnevaluable }
+
+func unevaluable(format string, args ...any) error {
+	return unevaluableError{reason: fmt.Sprintf(format, args...)}
+}
+
+// Pair is the value of a WDL Pair. The other values an evaluation produces are
+// a Map, or plain Go values: int64, float64, string (also for File), bool,
+// []any, map[string]any (for Object and structs), and nil for None.
+type Pair struct {
+	Left  any
+	Right any
+}
+
+// Map is the value of a WDL Map. Keys holds the keys, rendered as strings, in
+// the order the entries were added, which keys() and as_pairs() follow as the
+// spec requires. A Map decoded from JSON has lost that order, so its keys are
+// sorted instead.
+type Map struct {
+	Keys   []string
+	Values map[string]any
+}
+
+// set adds an entry at the end, or replaces the value of an existing key in
+// place.
+func (m *Map) set(key string, value any) {
+	if m.Values == nil {
+		m.Values = make(map[string]any)
+	}
+	if _, ok := m.Values[key]; !ok {
+		m.Keys = append(m.Keys, key)
+	}
+	m.Values[key] = value
+}
+
+// mapFromObject builds a Map from a JSON-decoded object, in key order.
+func mapFromObject(obj map[string]any) Map {
+	var m Map
+	for _, k := range sortedKeys(obj) {
+		m.set(k, obj[k])
+	}
+	return m
+}
+
+// Scope binds the names an expression can read.
+//
+// Declarations are bound lazily and evaluated on first read, because the
+// parser keeps a workflow's declarations apart from its calls and scatters, so
+// their order in the text is not available — and WDL does not require it to
+// be anything in particular anyway.
+type Scope struct {
+	parent  *Scope
+	values  map[string]any
+	pending map[string]*ast.Declaration
+	blocked map[string]error
+	busy    map[string]bool
+}
+
+// NewScope creates an empty top-level scope.
+func NewScope() *Scope {
+	return &Scope{
+		values:  make(map[string]any),
+		pending: make(map[string]*ast.Declaration),
+		blocked: make(map[string]error),
+		busy:    make(map[string]bool),
+	}
+}
+
+// Child creates a scope nested in this one, as a scatter or conditional body
+// is nested in its workflow.
+func (s *Scope) Child() *Scope {
+	child := NewScope()
+	child.parent = s
+	return child
+}
+
+// Set binds a name to a value.
+func (s *Scope) Set(name string, value any) {
+	delete(s.pending, name)
+	delete(s.blocked, name)
+	s.values[name] = value
+}
+
+// Declare binds a name to a declaration, evaluated when first read.
+func (s *Scope) Declare(decl *ast.Declaration) {
+	if decl == nil || decl.Expression == nil {
+		return
+	}
+	s.pending[decl.Name] = decl
+}
+
+// Block marks a name whose value cannot be known before the run; reading it
+// fails with err.
+func (s *Scope) Block(name string, err error) {
+	delete(s.pending, name)
+	delete(s.values, name)
+	s.blocked[name] = err
+}
+
+// Evaluator computes the value of WDL expressions.
+type Evaluator struct {
+	files   FileSizer
+	structs map[string]*ast.Struct
+	// sizes remembers what storage answered, so a file read by several
+	// expressions is looked up once.
+	sizes map[string]int64
+	// placeholders caches the parsed body of each string placeholder.
+	placeholders map[string]ast.Expression
+}
+
+// NewEvaluator creates an evaluator. files may be nil, in which case size()
+// is unevaluable.
+func NewEvaluator(files FileSizer) *Evaluator {
+	return &Evaluator{
+		files:        files,
+		structs:      make(map[string]*ast.Struct),
+		sizes:        make(map[string]int64),
+		placeholders: make(map[string]ast.Expression),
+	}
+}
+
+// Eval computes an expression's value in the given scope.
+func (e *Evaluator) Eval(ctx context.Context, expr ast.Expression, scope *Scope) (any, error) {
+	switch x := expr.(type) {
+	case nil:
+		return nil, unevaluable("no expression")
+
+	case *ast.Literal:
+		switch v := x.Value.(type) {
+		case string:
+			return e.interpolate(ctx, v, scope)
+		case int:
+			return int64(v), nil
+		}
+		return x.Value, nil
+
+	case *ast.StringLiteral:
+		return e.interpolate(ctx, x.Value, scope)
+
+	case *ast.StringInterpolation:
+		var b strings.Builder
+		for _, part := range x.Parts {
+			switch p := part.(type) {
+			case *ast.StringLiteral:
+				b.WriteString(p.Value)
+			case *ast.StringPlaceholder:
+				v, err := e.Eval(ctx, p.Expression, scope)
+				if err != nil {
+					return nil, err
+				}
+				b.WriteString(formatValue(v))
+			}
+		}
+		return b.String(), nil
+
+	case *ast.Identifier:
+		return e.lookup(ctx, scope, x.Name)
+
+	case *ast.MemberAccess:
+		return e.member(ctx, x, scope)
+
+	case *ast.IndexAccess:
+		return e.index(ctx, x, scope)
+
+	case *ast.UnaryOp:
+		return e.unary(ctx, x, scope)
+
+	case *ast.BinaryOp:
+		return e.binary(ctx, x, scope)
+
+	case *ast.TernaryOp:
+		cond, err := e.Eval(ctx, x.Condition, scope)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := cond.(bool)
+		if !ok {
+			return nil, fmt.Errorf("if-then-else condition is a %s, not a Boolean", valueKind(cond))
+		}
+		if b {
+			return e.Eval(ctx, x.IfTrue, scope)
+		}
+		return e.Eval(ctx, x.IfFalse, scope)
+
+	case *ast.ArrayLiteral:
+		out := make([]any, 0, len(x.Elements))
+		for _, el := range x.Elements {
+			v, err := e.Eval(ctx, el, scope)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+
+	case *ast.PairLiteral:
+		left, err := e.Eval(ctx, x.Left, scope)
+		if err != nil {
+			return nil, err
+		}
+		right, err := e.Eval(ctx, x.Right, scope)
+		if err != nil {
+			return nil, err
+		}
+		return Pair{Left: left, Right: right}, nil
+
+	case *ast.MapLiteral:
+		keys := x.Keys
+		if len(keys) != len(x.Entries) {
+			// Built by hand rather than parsed: the order is unknown.
+			keys = make([]ast.Expression, 0, len(x.Entries))
+			for k := range x.Entries {
+				keys = append(keys, k)
+			}
+		}
+		out := Map{Values: make(map[string]any, len(keys))}
+		for _, k := range keys {
+			key, err := e.Eval(ctx, k, scope)
+			if err != nil {
+				return nil, err
+			}
+			value, err := e.Eval(ctx, x.Entries[k], scope)
+			if err != nil {
+				return nil, err
+			}
+			out.set(formatValue(key), value)
+		}
+		return out, nil
+
+	case *ast.ObjectLiteral:
+		out := make(map[string]any, len(x.Members))
+		for k, v := range x.Members {
+			value, err := e.Eval(ctx, v, scope)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = value
+		}
+		return out, nil
+
+	case *ast.FunctionCall:
+		return e.call(ctx, x, scope)
+	}
+	return nil, unevaluable("uses an expression form the evaluator does not model")
+}
+
+// lookup resolves a name through the scope chain, evaluating a pending
+// declaration in the scope that declared it.
+func (e *Evaluator) lookup(ctx context.Context, scope *Scope, name string) (any, error) {
+	for s := scope; s != nil; s = s.parent {
+		if v, ok := s.values[name]; ok {
+			return v, nil
+		}
+		if err, ok := s.blocked[name]; ok {
+			return nil, err
+		}
+		decl, ok := s.pending[name]
+		if !ok {
+			continue
+		}
+		if s.busy[name] {
+			return nil, fmt.Errorf("%s is defined in terms of itself", name)
+		}
+		s.busy[name] = true
+		v, err := e.Eval(ctx, decl.Expression, s)
+		delete(s.busy, name)
+		if err != nil {
+			s.Block(name, err)
+			return nil, err
+		}
+		if v, err = e.coerce(v, decl.Type); err != nil {
+			s.Block(name, err)
+			return nil, err
+		}
+		s.Set(name, v)
+		return v, nil
+	}
+	return nil, unevaluable("reads %s, which is not defined here", name)
+}
+
+func (e *Evaluator) member(ctx context.Context, x *ast.MemberAccess, scope *Scope) (any, error) {
+	base, err := e.Eval(ctx, x.Expression, scope)
+	if err != nil {
+		return nil, err
+	}
+	switch v := base.(type) {
+	case Pair:
+		switch x.Member {
+		case "left":
+			return v.Left, nil
+		case "right":
+			return v.Right, nil
+		}
+	case map[string]any:
+		if m, ok := v[x.Member]; ok {
+			return m, nil
+		}
+		return nil, fmt.Errorf("has no member %q", x.Member)
+	}
+	return nil, fmt.Errorf("reads .%s from a %s", x.Member, valueKind(base))
+}
+
+func (e *Evaluator) index(ctx context.Context, x *ast.IndexAccess, scope *Scope) (any, error) {
+	base, err := e.Eval(ctx, x.Expression, scope)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := e.Eval(ctx, x.Index, scope)
+	if err != nil {
+		return nil, err
+	}
+	switch v := base.(type) {
+	case []any:
+		i, ok := idx.(int64)
+		if !ok {
+			return nil, fmt.Errorf("indexes an Array with a %s", valueKind(idx))
+		}
+		if i < 0 || i >= int64(len(v)) {
+			return nil, fmt.Errorf("index %d is out of range for an Array of %d", i, len(v))
+		}
+		return v[i], nil
+	case Map:
+		key := formatValue(idx)
+		if m, ok := v.Values[key]; ok {
+			return m, nil
+		}
+		return nil, fmt.Errorf("key %q is not in the Map", key)
+	case map[string]any:
+		key := formatValue(idx)
+		if m, ok := v[key]; ok {
+			return m, nil
+		}
+		return nil, fmt.Errorf("key %q is not in the Map", key)
+	}
+	return nil, fmt.Errorf("indexes a %s", valueKind(base))
+}
+
+func (e *Evaluator) unary(ctx context.Context, x *ast.UnaryOp, scope *Scope) (any, error) {
+	v, err := e.Eval(ctx, x.Expression, scope)
+	if err != nil {
+		return nil, err
+	}
+	switch x.Operator {
+	case "!":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("negates a %s", valueKind(v))
+		}
+		return !b, nil
+	case "-":
+		switch n := v.(type) {
+		case int64:
+			return -n, nil
+		case float64:
+			return -n, nil
+		}
+		return nil, fmt.Errorf("negates a %s", valueKind(v))
+	case "+":
+		return v, nil
+	}
+	return nil, unevaluable("uses the unknown operator %s", x.Operator)
+}
+
+func (e *Evaluator) binary(ctx context.Context, x *ast.BinaryOp, scope *Scope) (any, error) {
+	left, err := e.Eval(ctx, x.Left, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// The logical operators short-circuit, so the right side is only read
+	// when it decides the outcome — as the engine does.
+	if x.Operator == "&&" || x.Operator == "||" {
+		l, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("applies %s to a %s", x.Operator, valueKind(left))
+		}
+		if (x.Operator == "&&" && !l) || (x.Operator == "||" && l) {
+			return l, nil
+		}
+		right, err := e.Eval(ctx, x.Right, scope)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("applies %s to a %s", x.Operator, valueKind(right))
+		}
+		return r, nil
+	}
+
+	right, err := e.Eval(ctx, x.Right, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	switch x.Operator {
+	case "==":
+		return valuesEqual(left, right), nil
+	case "!=":
+		return !valuesEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compareValues(x.Operator, left, right)
+	case "+":
+		_, ls := left.(string)
+		_, rs := right.(string)
+		if ls || rs {
+			if left == nil || right == nil {
+				return nil, fmt.Errorf("concatenates a String with None")
+			}
+			return formatValue(left) + formatValue(right), nil
+		}
+	}
+	return arithmetic(x.Operator, left, right)
+}
+
+// arithmetic applies a numeric operator. Two Ints give an Int — including
+// for division, which truncates as it does in the engine — and any Float
+// makes the result a Float.
+func arithmetic(op string, left, right any) (any, error) {
+	li, lInt := left.(int64)
+	ri, rInt := right.(int64)
+	if lInt && rInt {
+		switch op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		case "/", "%":
+			if ri == 0 {
+				return nil, fmt.Errorf("divides by zero")
+			}
+			if op == "/" {
+				return li / ri, nil
+			}
+			return li % ri, nil
+		}
+	}
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("applies %s to a %s and a %s", op, valueKind(left), valueKind(right))
+	}
+	switch op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, fmt.Errorf("divides by zero")
+		}
+		return lf / rf, nil
+	case "%":
+		if rf == 0 {
+			return nil, fmt.Errorf("divides by zero")
+		}
+		return math.Mod(lf, rf), nil
+	}
+	return nil, unevaluable("uses the unknown operator %s", op)
+}
+
+func compareValues(op string, left, right any) (any, error) {
+	var c int
+	if ls, ok := left.(string); ok {
+		rs, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("compares a String with a %s", valueKind(right))
+		}
+		c = strings.Compare(ls, rs)
+	} else {
+		lf, lok := toFloat(left)
+		rf, rok := toFloat(right)
+		if !lok || !rok {
+			return nil, fmt.Errorf("compares a %s with a %s", valueKind(left), valueKind(right))
+		}
+		switch {
+		case lf < rf:
+			c = -1
+		case lf > rf:
+			c = 1
+		}
+	}
+	switch op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
+
+func valuesEqual(left, right any) bool {
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	if lok && rok {
+		return lf == rf
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// interpolate renders a string literal, evaluating its placeholders and
+// decoding escapes. The parser leaves placeholders inside the literal text,
+// so they are found here rather than in the tree.
+func (e *Evaluator) interpolate(ctx context.Context, raw string, scope *Scope) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c == '\\' && i+1 < len(raw) {
+			i++
+			b.WriteString(unescape(raw[i]))
+			continue
+		}
+		if (c == '~' || c == '$') && i+1 < len(raw) && raw[i+1] == '{' {
+			end := placeholderEnd(raw, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated placeholder in %q", raw)
+			}
+			s, err := e.placeholder(ctx, raw[i+2:end], scope)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(s)
+			i = end
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
+
+// unescape decodes the character after a backslash. Unknown escapes are kept
+// as written.
+func unescape(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 't':
+		return "\t"
+	case '\\', '"', '\'', '~', '$':
+		return string(c)
+	}
+	return "\\" + string(c)
+}
+
+// placeholderEnd finds the brace closing a placeholder whose body starts at
+// from, skipping over nested braces and quoted strings.
+func placeholderEnd(raw string, from int) int {
+	depth := 0
+	var quote byte
+	for i := from; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// placeholderOptionForm matches one leading `sep=`, `default=`, `true=` or
+// `false=` option with its quoted value.
+var placeholderOptionForm = regexp.MustCompile(`^(sep|default|true|false)\s*=\s*("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')\s*`)
+
+// placeholder evaluates and renders one placeholder body, honouring its
+// options.
+func (e *Evaluator) placeholder(ctx context.Context, body string, scope *Scope) (string, error) {
+	options := make(map[string]string)
+	rest := strings.TrimSpace(body)
+	for {
+		m := placeholderOptionForm.FindStringSubmatch(rest)
+		if m == nil {
+			break
+		}
+		value, err := e.interpolate(ctx, m[2][1:len(m[2])-1], scope)
+		if err != nil {
+			return "", err
+		}
+		options[m[1]] = value
+		rest = rest[len(m[0]):]
+	}
+
+	expr, ok := e.placeholders[rest]
+	if !ok {
+		parsed, err := ParseExpression(rest)
+		if err != nil {
+			return "", unevaluable("interpolates %s, which could not be parsed", rest)
+		}
+		expr = parsed
+		e.placeholders[rest] = expr
+	}
+
+	v, err := e.Eval(ctx, expr, scope)
+	if err != nil {
+		return "", err
+	}
+	switch x := v.(type) {
+	case nil:
+		return options["default"], nil
+	case bool:
+		if t, ok := options["true"]; ok && x {
+			return t, nil
+		}
+		if f, ok := options["false"]; ok && !x {
+			return f, nil
+		}
+	case []any:
+		sep, ok := options["sep"]
+		if !ok {
+			return "", fmt.Errorf("interpolates the Array %s without a sep option", rest)
+		}
+		parts := make([]string, len(x))
+		for i, item := range x {
+			parts[i] = formatValue(item)
+		}
+		return strings.Join(parts, sep), nil
+	}
+	return formatValue(v), nil
+}
+
+// formatValue renders a value the way the engine writes it into a string.
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case int:
+		return strconv.Itoa(x)
+	case float64:
+		s := strconv.FormatFloat(x, 'f', -1, 64)
+		if !strings.ContainsAny(s, ".eEn") {
+			s += ".0"
+		}
+		return s
+	case Pair:
+		return "(" + formatValue(x.Left) + ", " + formatValue(x.Right) + ")"
+	case []any:
+		parts := make([]string, len(x))
+		for i, item := range x {
+			parts[i] = quoteIfString(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case Map:
+		parts := make([]string, len(x.Keys))
+		for i, k := range x.Keys {
+			parts[i] = strconv.Quote(k) + ": " + quoteIfString(x.Values[k])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = strconv.Quote(k) + ": " + quoteIfString(x[k])
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+	return fmt.Sprint(v)
+}
+
+func quoteIfString(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return formatValue(v)
+}
+
+// valueKind names a value's WDL type for messages.
+func valueKind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "None"
+	case string:
+		return "String"
+	case bool:
+		return "Boolean"
+	case int64:
+		return "Int"
+	case float64:
+		return "Float"
+	case []any:
+		return "Array"
+	case Map:
+		return "Map"
+	case map[string]any:
+		return "Object"
+	case Pair:
+		return "Pair"
+	}
+	return "value"
+}
+
+// coerce shapes a value to its declared type: JSON carries every number as a
+// float, and a Pair arrives as an object with left and right members. Values
+// that do not fit are returned unchanged, leaving type errors to the
+// expressions that read them. An object typed with a struct that is not
+// defined here cannot be shaped, since its members' types are unknown.
+func (e *Evaluator) coerce(v any, t *ast.Type) (any, error) {
+	if t == nil || v == nil {
+		return v, nil
+	}
+	switch t.Base {
+	case "Int":
+		if f, ok := v.(float64); ok && f == math.Trunc(f) {
+			return int64(f), nil
+		}
+	case "Float":
+		if i, ok := v.(int64); ok {
+			return float64(i), nil
+		}
+	case "String", "File", "Directory", "Boolean", "Object":
+	case "Array":
+		items, ok := v.([]any)
+		if !ok {
+			return v, nil
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			c, err := e.coerce(item, t.ArrayType)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = c
+		}
+		return out, nil
+	case "Map":
+		m, ok := v.(Map)
+		if obj, isObject := v.(map[string]any); isObject {
+			m, ok = mapFromObject(obj), true
+		}
+		if !ok {
+			return v, nil
+		}
+		out := Map{Values: make(map[string]any, len(m.Keys))}
+		for _, k := range m.Keys {
+			c, err := e.coerce(m.Values[k], t.MapValue)
+			if err != nil {
+				return nil, err
+			}
+			out.set(k, c)
+		}
+		return out, nil
+	case "Pair":
+		m, ok := v.(map[string]any)
+		if !ok {
+			return v, nil
+		}
+		left, err := e.coerce(m["left"], t.PairLeft)
+		if err != nil {
+			return nil, err
+		}
+		right, err := e.coerce(m["right"], t.PairRight)
+		if err != nil {
+			return nil, err
+		}
+		return Pair{Left: left, Right: right}, nil
+	default:
+		_, isLiteral := v.(Map)
+		_, isObject := v.(map[string]any)
+		if !isLiteral && !isObject {
+			return v, nil
+		}
+		def, ok := e.structs[t.Base]
+		if !ok {
+			return nil, unevaluable("holds a %s, a struct that is not defined here", t.Base)
+		}
+		if lit, isLiteral := v.(Map); isLiteral {
+			// A struct written as a Map literal.
+			v = lit.Values
+		}
+		m := v.(map[string]any)
+		out := make(map[string]any, len(m))
+		for k, item := range m {
+			out[k] = item
+		}
+		for _, member := range def.Members {
+			if member != nil {
+				if item, ok := m[member.Name]; ok {
+					c, err := e.coerce(item, member.Type)
+					if err != nil {
+						return nil, err
+					}
+					out[member.Name] = c
+				}
+			}
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// DefineStructs registers struct definitions, so values typed with them are
+// coerced member by member.
+func (e *Evaluator) DefineStructs(structs []*ast.Struct) {
+	for _, s := range structs {
+		if s != nil {
+			e.structs[s.Name] = s
+		}
+	}
+}
+
+// Coerce shapes a JSON-decoded value to a declared WDL type. See coerce.
+func (e *Evaluator) Coerce(v any, t *ast.Type) (any, error) {
+	return e.coerce(v, t)
+}
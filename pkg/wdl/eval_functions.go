@@ -0,0 +1,450 @@
+package wdl
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// runtimeOnlyFunctions read or write files that exist only inside a running
+// task, so no amount of input makes them evaluable beforehand.
+var runtimeOnlyFunctions = map[string]bool{
+	"stdout": true, "stderr": true, "glob": true,
+	"read_lines": true, "read_tsv": true, "read_map": true, "read_object": true,
+	"read_objects": true, "read_json": true, "read_int": true, "read_string": true,
+	"read_float": true, "read_boolean": true,
+	"write_lines": true, "write_tsv": true, "write_map": true, "write_object": true,
+	"write_objects": true, "write_json": true,
+}
+
+// sizeUnits maps the units size() accepts to their multiple of a byte. Decimal
+// and binary prefixes are both valid WDL, and mixing them up is the classic
+// way a disk request comes out 7% short.
+var sizeUnits = map[string]float64{
+	"B": 1,
+	"K": 1e3, "KB": 1e3,
+	"M": 1e6, "MB": 1e6,
+	"G": 1e9, "GB": 1e9,
+	"T": 1e12, "TB": 1e12,
+	"KI": 1 << 10, "KIB": 1 << 10,
+	"MI": 1 << 20, "MIB": 1 << 20,
+	"GI": 1 << 30, "GIB": 1 << 30,
+	"TI": 1 << 40, "TIB": 1 << 40,
+}
+
+// call evaluates a standard-library function.
+func (e *Evaluator) call(ctx context.Context, fc *ast.FunctionCall, scope *Scope) (any, error) {
+	if runtimeOnlyFunctions[fc.Name] {
+		return nil, unevaluable("calls %s(), which only works inside the running task", fc.Name)
+	}
+
+	args := make([]any, len(fc.Arguments))
+	for i, a := range fc.Arguments {
+		v, err := e.Eval(ctx, a, scope)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	if fc.Name == "size" {
+		return e.size(ctx, args)
+	}
+	fn, ok := stdlib[fc.Name]
+	if !ok {
+		return nil, unevaluable("calls %s(), which the evaluator does not implement", fc.Name)
+	}
+	if len(args) < fn.minArgs || len(args) > fn.maxArgs {
+		return nil, fmt.Errorf("%s() takes %s, got %d", fc.Name, arity(fn.minArgs, fn.maxArgs), len(args))
+	}
+	v, err := fn.apply(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", fc.Name, err)
+	}
+	return v, nil
+}
+
+func arity(lo, hi int) string {
+	if lo == hi {
+		return fmt.Sprintf("%d argument(s)", lo)
+	}
+	return fmt.Sprintf("%d to %d arguments", lo, hi)
+}
+
+// size sums the sizes of a file, an optional file or a collection of files,
+// asking storage once per path.
+func (e *Evaluator) size(ctx context.Context, args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("size() takes 1 to 2 arguments, got %d", len(args))
+	}
+	divisor := 1.0
+	if len(args) == 2 {
+		unit, ok := args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("size(): unit is a %s, not a String", valueKind(args[1]))
+		}
+		divisor, ok = sizeUnits[strings.ToUpper(strings.TrimSpace(unit))]
+		if !ok {
+			return nil, fmt.Errorf("size(): unknown unit %q", unit)
+		}
+	}
+
+	var paths []string
+	if err := collectPaths(args[0], &paths); err != nil {
+		return nil, fmt.Errorf("size(): %w", err)
+	}
+	var total int64
+	for _, p := range paths {
+		n, ok := e.sizes[p]
+		if !ok {
+			if e.files == nil {
+				return nil, unevaluable("reads the size of %s, and no storage is available to ask", p)
+			}
+			var err error
+			n, err = e.files.GetSize(ctx, p)
+			if err != nil {
+				return nil, unevaluable("reads the size of %s: %v", p, err)
+			}
+			e.sizes[p] = n
+		}
+		total += n
+	}
+	return float64(total) / divisor, nil
+}
+
+// collectPaths gathers every file path inside a value; None counts as an
+// absent file of size zero.
+func collectPaths(v any, out *[]string) error {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case string:
+		*out = append(*out, x)
+		return nil
+	case []any:
+		for _, item := range x {
+			if err := collectPaths(item, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	case Pair:
+		if err := collectPaths(x.Left, out); err != nil {
+			return err
+		}
+		return collectPaths(x.Right, out)
+	case Map:
+		for _, k := range x.Keys {
+			if err := collectPaths(x.Values[k], out); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]any:
+		for _, item := range x {
+			if err := collectPaths(item, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("a %s is not a file", valueKind(v))
+}
+
+// maxRange is the longest array range() builds. A longer one would hold
+// memory out of all proportion to a runtime estimate; no real scatter is that
+// wide.
+const maxRange = 1_000_000
+
+// builtin is one pure standard-library function.
+type builtin struct {
+	minArgs, maxArgs int
+	apply            func(args []any) (any, error)
+}
+
+// stdlib holds the functions whose result depends only on their arguments.
+var stdlib = map[string]builtin{
+	"ceil":  {1, 1, rounding(math.Ceil)},
+	"floor": {1, 1, rounding(math.Floor)},
+	"round": {1, 1, rounding(math.Round)},
+	"min":   {2, 2, extremum(math.Min)},
+	"max":   {2, 2, extremum(math.Max)},
+	"length": {1, 1, func(args []any) (any, error) {
+		items, err := array(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return int64(len(items)), nil
+	}},
+	"defined": {1, 1, func(args []any) (any, error) {
+		return args[0] != nil, nil
+	}},
+	"select_first": {1, 1, func(args []any) (any, error) {
+		items, err := array(args[0])
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item != nil {
+				return item, nil
+			}
+		}
+		return nil, fmt.Errorf("none of the values is defined")
+	}},
+	"select_all": {1, 1, func(args []any) (any, error) {
+		items, err := array(args[0])
+		if err != nil {
+			return nil, err
+		}
+		out := make([]any, 0, len(items))
+		for _, item := range items {
+			if item != nil {
+				out = append(out, item)
+			}
+		}
+		return out, nil
+	}},
+	"basename": {1, 2, func(args []any) (any, error) {
+		p, err := str(args[0])
+		if err != nil {
+			return nil, err
+		}
+		base := path.Base(p)
+		if len(args) == 2 {
+			suffix, err := str(args[1])
+			if err != nil {
+				return nil, err
+			}
+			base = strings.TrimSuffix(base, suffix)
+		}
+		return base, nil
+	}},
+	"sub": {3, 3, func(args []any) (any, error) {
+		s, err := str(args[0])
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := str(args[1])
+		if err != nil {
+			return nil, err
+		}
+		repl, err := str(args[2])
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		return re.ReplaceAllString(s, repl), nil
+	}},
+	"range": {1, 1, func(args []any) (any, error) {
+		n, ok := args[0].(int64)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("takes a non-negative Int, not %s", formatValue(args[0]))
+		}
+		if n > maxRange {
+			return nil, unevaluable("calls range(%d), longer than the %d elements the evaluator builds", n, maxRange)
+		}
+		out := make([]any, n)
+		for i := range out {
+			out[i] = int64(i)
+		}
+		return out, nil
+	}},
+	"flatten": {1, 1, func(args []any) (any, error) {
+		outer, err := array(args[0])
+		if err != nil {
+			return nil, err
+		}
+		var out []any
+		for _, inner := range outer {
+			items, err := array(inner)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, items...)
+		}
+		return out, nil
+	}},
+	"prefix": {2, 2, affix(func(a, s string) string { return a + s })},
+	"suffix": {2, 2, affix(func(a, s string) string { return s + a })},
+	"quote":  {1, 1, wrapEach(`"`)},
+	"squote": {1, 1, wrapEach(`'`)},
+	"sep": {2, 2, func(args []any) (any, error) {
+		sep, err := str(args[0])
+		if err != nil {
+			return nil, err
+		}
+		items, err := array(args[1])
+		if err != nil {
+			return nil, err
+		}
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = formatValue(item)
+		}
+		return strings.Join(parts, sep), nil
+	}},
+	"zip": {2, 2, func(args []any) (any, error) {
+		left, err := array(args[0])
+		if err != nil {
+			return nil, err
+		}
+		right, err := array(args[1])
+		if err != nil {
+			return nil, err
+		}
+		if len(left) != len(right) {
+			return nil, fmt.Errorf("arrays differ in length (%d and %d)", len(left), len(right))
+		}
+		out := make([]any, len(left))
+		for i := range left {
+			out[i] = Pair{Left: left[i], Right: right[i]}
+		}
+		return out, nil
+	}},
+	"cross": {2, 2, func(args []any) (any, error) {
+		left, err := array(args[0])
+		if err != nil {
+			return nil, err
+		}
+		right, err := array(args[1])
+		if err != nil {
+			return nil, err
+		}
+		out := make([]any, 0, len(left)*len(right))
+		for _, l := range left {
+			for _, r := range right {
+				out = append(out, Pair{Left: l, Right: r})
+			}
+		}
+		return out, nil
+	}},
+	"keys": {1, 1, func(args []any) (any, error) {
+		m, ok := args[0].(Map)
+		if !ok {
+			return nil, fmt.Errorf("takes a Map, not a %s", valueKind(args[0]))
+		}
+		out := make([]any, len(m.Keys))
+		for i, k := range m.Keys {
+			out[i] = k
+		}
+		return out, nil
+	}},
+	"as_pairs": {1, 1, func(args []any) (any, error) {
+		m, ok := args[0].(Map)
+		if !ok {
+			return nil, fmt.Errorf("takes a Map, not a %s", valueKind(args[0]))
+		}
+		out := make([]any, len(m.Keys))
+		for i, k := range m.Keys {
+			out[i] = Pair{Left: k, Right: m.Values[k]}
+		}
+		return out, nil
+	}},
+	"as_map": {1, 1, func(args []any) (any, error) {
+		items, err := array(args[0])
+		if err != nil {
+			return nil, err
+		}
+		out := Map{Values: make(map[string]any, len(items))}
+		for _, item := range items {
+			p, ok := item.(Pair)
+			if !ok {
+				return nil, fmt.Errorf("takes an Array of Pairs, found a %s", valueKind(item))
+			}
+			out.set(formatValue(p.Left), p.Right)
+		}
+		return out, nil
+	}},
+}
+
+func rounding(f func(float64) float64) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		n, ok := toFloat(args[0])
+		if !ok {
+			return nil, fmt.Errorf("takes a number, not a %s", valueKind(args[0]))
+		}
+		return int64(f(n)), nil
+	}
+}
+
+func extremum(f func(a, b float64) float64) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		a, aInt := args[0].(int64)
+		b, bInt := args[1].(int64)
+		if aInt && bInt {
+			return int64(f(float64(a), float64(b))), nil
+		}
+		x, xok := toFloat(args[0])
+		y, yok := toFloat(args[1])
+		if !xok || !yok {
+			return nil, fmt.Errorf("takes numbers, not a %s and a %s", valueKind(args[0]), valueKind(args[1]))
+		}
+		return f(x, y), nil
+	}
+}
+
+func affix(join func(affix, s string) string) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		a, err := str(args[0])
+		if err != nil {
+			return nil, err
+		}
+		items, err := array(args[1])
+		if err != nil {
+			return nil, err
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			out[i] = join(a, formatValue(item))
+		}
+		return out, nil
+	}
+}
+
+func wrapEach(q string) func([]any) (any, error) {
+	return func(args []any) (any, error) {
+		items, err := array(args[0])
+		if err != nil {
+			return nil, err
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			out[i] = q + formatValue(item) + q
+		}
+		return out, nil
+	}
+}
+
+func array(v any) ([]any, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("takes an Array, not a %s", valueKind(v))
+	}
+	return items, nil
+}
+
+func str(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("takes a String, not a %s", valueKind(v))
+	}
+	return s, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,279 @@
+package wdl
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// fakeSizes answers size() from a fixed table and counts lookups.
+type fakeSizes struct {
+	sizes map[string]int64
+	calls int
+}
+
+func (f *fakeSizes) GetSize(_ context.Context, path string) (int64, error) {
+	f.calls++
+	n, ok := f.sizes[path]
+	if !ok {
+		return 0, errors.New("no such object")
+	}
+	return n, nil
+}
+
+func evalText(t *testing.T, e *Evaluator, scope *Scope, text string) (any, error) {
+	t.Helper()
+	expr, err := ParseExpression(text)
+	if err != nil {
+		t.Fatalf("ParseExpression(%q) error = %v", text, err)
+	}
+	return e.Eval(context.Background(), expr, scope)
+}
+
+func TestEvalExpressions(t *testing.T) {
+	files := &fakeSizes{sizes: map[string]int64{
+		"gs://b/sample.bam": 30_000_000_000,
+		"gs://b/ref.fa":     3_000_000_000,
+	}}
+	scope := NewScope()
+	scope.Set("bam", "gs://b/sample.bam")
+	scope.Set("ref", "gs://b/ref.fa")
+	scope.Set("mem_gb", int64(8))
+	scope.Set("ratio", 1.5)
+	scope.Set("maybe", nil)
+	scope.Set("names", []any{"a", "b", "c"})
+	scope.Set("paired", true)
+	scope.Set("pair", Pair{Left: "x", Right: int64(2)})
+	scope.Set("settings", map[string]any{"threads": int64(4)})
+
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{`ceil(size(bam, "GB") * 2) + 20`, int64(80)},
+		{`ceil(size([bam, ref], "GB"))`, int64(33)},
+		{`size(maybe)`, 0.0},
+		{`"~{mem_gb} GB"`, "8 GB"},
+		{`"${mem_gb * 2}G"`, "16G"},
+		{`"~{sep=',' names}"`, "a,b,c"},
+		{`"~{true='-p' false='' paired}"`, "-p"},
+		{`"~{default='none' maybe}"`, "none"},
+		{`"~{if mem_gb > 4 then 'big' else 'small'}"`, "big"},
+		{`"a\"b"`, `a"b`},
+		{`select_first([maybe, mem_gb])`, int64(8)},
+		{`defined(maybe)`, false},
+		{`length(names)`, int64(3)},
+		{`basename(bam, ".bam")`, "sample"},
+		{`sub(basename(bam), "\\.bam$", ".cram")`, "sample.cram"},
+		{`mem_gb / 3`, int64(2)},
+		{`mem_gb * ratio`, 12.0},
+		{`floor(ratio)`, int64(1)},
+		{`pair.right + 1`, int64(3)},
+		{`settings.threads`, int64(4)},
+		{`names[1]`, "b"},
+		{`range(3)`, []any{int64(0), int64(1), int64(2)}},
+		{`prefix("-I ", names)`, []any{"-I a", "-I b", "-I c"}},
+		{`max(mem_gb, 16)`, int64(16)},
+		{`mem_gb > 4 && !paired`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalText(t, NewEvaluator(files), scope, tt.expr)
+			if err != nil {
+				t.Fatalf("Eval error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eval = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalSizeAsksStorageOncePerPath(t *testing.T) {
+	files := &fakeSizes{sizes: map[string]int64{"gs://b/x": 10}}
+	scope := NewScope()
+	scope.Set("x", "gs://b/x")
+	e := NewEvaluator(files)
+
+	for i := 0; i < 3; i++ {
+		if _, err := evalText(t, e, scope, `size(x) + size([x, x])`); err != nil {
+			t.Fatalf("Eval error = %v", err)
+		}
+	}
+	if files.calls != 1 {
+		t.Errorf("storage asked %d times, want 1", files.calls)
+	}
+}
+
+func TestEvalDeclarationsAreLazy(t *testing.T) {
+	doc, err := ParseBytes([]byte(`version 1.0
+workflow W {
+  input { Int base = 2 }
+  Int doubled = tripled - base
+  Int tripled = base * 3
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := NewScope()
+	for _, d := range doc.Workflow.Inputs {
+		scope.Declare(d)
+	}
+	for _, d := range doc.Workflow.Declarations {
+		scope.Declare(d)
+	}
+
+	got, err := evalText(t, NewEvaluator(nil), scope, "doubled")
+	if err != nil {
+		t.Fatalf("Eval error = %v", err)
+	}
+	if got != int64(4) {
+		t.Errorf("doubled = %#v, want 4 (declarations read before they are written)", got)
+	}
+}
+
+func TestEvalUnevaluable(t *testing.T) {
+	scope := NewScope()
+	scope.Block("Align", unevaluable("reads an output of Align, which only exists once it has run"))
+	scope.Set("empty", []any{nil})
+
+	tests := []struct {
+		name        string
+		expr        string
+		unevaluable bool
+	}{
+		{"call output", `ceil(size(Align.bam, "GB"))`, true},
+		{"undefined name", `nowhere + 1`, true},
+		{"file contents", `read_int("n.txt")`, true},
+		{"size without storage", `size("gs://b/x")`, true},
+		{"select_first over nulls is a real error", `select_first(empty)`, false},
+		{"type error is a real error", `"a" - 1`, false},
+		{"range beyond the cap", `range(1000001)`, true},
+		{"length of a Map", `length({"a": 1})`, false},
+		{"length of a String", `length("abc")`, false},
+		{"keys of an Array", `keys([1, 2])`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := evalText(t, NewEvaluator(nil), scope, tt.expr)
+			if err == nil {
+				t.Fatal("Eval succeeded, want an error")
+			}
+			if got := errors.Is(err, ErrUnevaluable); got != tt.unevaluable {
+				t.Errorf("errors.Is(ErrUnevaluable) = %v, want %v (err: %v)", got, tt.unevaluable, err)
+			}
+		})
+	}
+}
+
+func TestEvalMapFunctionsKeepOrder(t *testing.T) {
+	scope := NewScope()
+	scope.Set("ordered", Map{Keys: []string{"z", "a"}, Values: map[string]any{"z": int64(1), "a": int64(2)}})
+
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{`keys({"c": 1, "a": 2, "b": 3})`, []any{"c", "a", "b"}},
+		{`as_pairs({"c": 1, "a": 2})`, []any{Pair{Left: "c", Right: int64(1)}, Pair{Left: "a", Right: int64(2)}}},
+		{`keys(as_map([("z", 1), ("y", 2), ("x", 3)]))`, []any{"z", "y", "x"}},
+		{`keys(as_map(as_pairs({"b": 1, "a": 2})))`, []any{"b", "a"}},
+		{`keys(ordered)`, []any{"z", "a"}},
+		{`"~{sep=' ' keys({'2': 0, '10': 0, '1': 0})}"`, "2 10 1"},
+		{`{"c": 1, "a": 2}["a"]`, int64(2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalText(t, NewEvaluator(nil), scope, tt.expr)
+			if err != nil {
+				t.Fatalf("Eval error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eval = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalCoercesJSONValues(t *testing.T) {
+	doc, err := ParseBytes([]byte(`version 1.0
+struct Sample { String id  Int reads }
+workflow W {
+  input {
+    Int n
+    Pair[String, Int] p
+    Array[Sample] samples
+    Map[String, Int] counts
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEvaluator(nil)
+	e.DefineStructs(doc.Structs)
+	in := doc.Workflow.Inputs
+	coerce := func(v any, typ *ast.Type) any {
+		t.Helper()
+		got, err := e.Coerce(v, typ)
+		if err != nil {
+			t.Fatalf("Coerce error = %v", err)
+		}
+		return got
+	}
+
+	if got := coerce(3.0, in[0].Type); got != int64(3) {
+		t.Errorf("Int from JSON = %#v, want int64(3)", got)
+	}
+	if got := coerce(map[string]any{"left": "a", "right": 2.0}, in[1].Type); got != (Pair{Left: "a", Right: int64(2)}) {
+		t.Errorf("Pair from JSON = %#v", got)
+	}
+	got := coerce([]any{map[string]any{"id": "s1", "reads": 10.0}}, in[2].Type)
+	want := []any{map[string]any{"id": "s1", "reads": int64(10)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("struct array from JSON = %#v, want %#v", got, want)
+	}
+	// JSON objects carry no order, so a Map decoded from one is in key order.
+	gotMap := coerce(map[string]any{"b": 2.0, "a": 1.0}, in[3].Type)
+	wantMap := Map{Keys: []string{"a", "b"}, Values: map[string]any{"a": int64(1), "b": int64(2)}}
+	if !reflect.DeepEqual(gotMap, wantMap) {
+		t.Errorf("Map from JSON = %#v, want %#v", gotMap, wantMap)
+	}
+}
+
+func TestEvalStructsNeedTheirDefinition(t *testing.T) {
+	doc, err := ParseBytes([]byte(`version 1.0
+struct Sample { String id  Int reads }
+workflow W {
+  Sample known = {"id": "s1", "reads": 10}
+  Missing unknown = {"id": "s1", "reads": 10}
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEvaluator(nil)
+	e.DefineStructs(doc.Structs)
+	scope := NewScope()
+	for _, d := range doc.Workflow.Declarations {
+		scope.Declare(d)
+	}
+
+	got, err := evalText(t, e, scope, "known.reads")
+	if err != nil {
+		t.Fatalf("Eval error = %v", err)
+	}
+	if got != int64(10) {
+		t.Errorf("known.reads = %#v, want int64(10)", got)
+	}
+
+	for _, expr := range []string{"unknown", "unknown.reads"} {
+		if _, err := evalText(t, e, scope, expr); !errors.Is(err, ErrUnevaluable) {
+			t.Errorf("%s: error = %v, want ErrUnevaluable", expr, err)
+		}
+	}
+	if _, err := e.Coerce(map[string]any{"id": "s1"}, doc.Workflow.Declarations[1].Type); !errors.Is(err, ErrUnevaluable) {
+		t.Errorf("Coerce of a JSON object: error = %v, want ErrUnevaluable", err)
+	}
+}
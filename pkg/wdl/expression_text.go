@@ -0,0 +1,96 @@
+package wdl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// ExpressionText renders an expression back as compact WDL, for labels and
+// messages. It is not a formatter: parentheses are only added around nested
+// operators, and whitespace is normalised.
+func ExpressionText(expr ast.Expression) string {
+	switch e := expr.(type) {
+	case nil:
+		return ""
+	case *ast.Literal:
+		switch v := e.Value.(type) {
+		case nil:
+			return "None"
+		case string:
+			return `"` + v + `"`
+		default:
+			return formatValue(v)
+		}
+	case *ast.StringLiteral:
+		return `"` + e.Value + `"`
+	case *ast.StringInterpolation:
+		var b strings.Builder
+		b.WriteByte('"')
+		for _, part := range e.Parts {
+			switch p := part.(type) {
+			case *ast.StringLiteral:
+				b.WriteString(p.Value)
+			case *ast.StringPlaceholder:
+				b.WriteString("~{" + ExpressionText(p.Expression) + "}")
+			}
+		}
+		b.WriteByte('"')
+		return b.String()
+	case *ast.Identifier:
+		return e.Name
+	case *ast.MemberAccess:
+		return ExpressionText(e.Expression) + "." + e.Member
+	case *ast.IndexAccess:
+		return ExpressionText(e.Expression) + "[" + ExpressionText(e.Index) + "]"
+	case *ast.FunctionCall:
+		return e.Name + "(" + joinExpressions(e.Arguments) + ")"
+	case *ast.BinaryOp:
+		return operandText(e.Left) + " " + e.Operator + " " + operandText(e.Right)
+	case *ast.UnaryOp:
+		return e.Operator + operandText(e.Expression)
+	case *ast.TernaryOp:
+		return "if " + ExpressionText(e.Condition) + " then " + ExpressionText(e.IfTrue) + " else " + ExpressionText(e.IfFalse)
+	case *ast.ArrayLiteral:
+		return "[" + joinExpressions(e.Elements) + "]"
+	case *ast.PairLiteral:
+		return "(" + ExpressionText(e.Left) + ", " + ExpressionText(e.Right) + ")"
+	case *ast.MapLiteral:
+		entries := make([]string, 0, len(e.Entries))
+		for k, v := range e.Entries {
+			entries = append(entries, ExpressionText(k)+": "+ExpressionText(v))
+		}
+		sort.Strings(entries)
+		return "{" + strings.Join(entries, ", ") + "}"
+	case *ast.ObjectLiteral:
+		entries := make([]string, 0, len(e.Members))
+		for k, v := range e.Members {
+			entries = append(entries, k+": "+ExpressionText(v))
+		}
+		sort.Strings(entries)
+		return "object {" + strings.Join(entries, ", ") + "}"
+	default:
+		return fmt.Sprintf("%T", expr)
+	}
+}
+
+// operandText parenthesises an operand that is itself an operator, so the
+// rendered text keeps the tree's precedence.
+func operandText(expr ast.Expression) string {
+	switch expr.(type) {
+	case *ast.BinaryOp, *ast.TernaryOp:
+		return "(" + ExpressionText(expr) + ")"
+	default:
+		return ExpressionText(expr)
+	}
+}
+
+func joinExpressions(exprs []ast.Expression) string {
+	parts := make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = ExpressionText(e)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,280 @@
+package wdl
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GraphFormat is an output format for RenderCallGraph.
+type GraphFormat string
+
+const (
+	GraphDOT     GraphFormat = "dot"
+	GraphMermaid GraphFormat = "mermaid"
+	GraphJSON    GraphFormat = "json"
+)
+
+// GraphFormats lists the supported formats.
+var GraphFormats = []GraphFormat{GraphDOT, GraphMermaid, GraphJSON}
+
+// RenderCallGraph draws a call graph: one node per call, an edge from each
+// producer to its consumers, and scatters, conditionals and subworkflows as
+// nested clusters around the calls they hold.
+//
+// statuses optionally maps a call path to the status it has in a run
+// ("Done", "Running", "Failed"...). Nodes are coloured by it; calls without an
+// entry are drawn as not started.
+func RenderCallGraph(g *CallGraph, format GraphFormat, statuses map[string]string) ([]byte, error) {
+	switch format {
+	case GraphDOT:
+		return []byte(renderDOT(g, statuses)), nil
+	case GraphMermaid:
+		return []byte(renderMermaid(g, statuses)), nil
+	case GraphJSON:
+		return renderGraphJSON(g, statuses)
+	default:
+		return nil, fmt.Errorf("unknown graph format %q (want dot, mermaid or json)", format)
+	}
+}
+
+// statusClass buckets an engine status into the handful of colours a graph
+// can usefully show.
+func statusClass(status string) string {
+	switch strings.ToLower(status) {
+	case "":
+		return ""
+	case "done", "succeeded":
+		return "done"
+	case "failed":
+		return "failed"
+	case "aborted", "aborting":
+		return "aborted"
+	case "running", "starting", "submitted", "queuedincromwell", "waitingforreturncode":
+		return "running"
+	default:
+		return "pending"
+	}
+}
+
+// statusColors are the fill colours per status class, shared by DOT and
+// Mermaid so both read the same.
+var statusColors = map[string]string{
+	"done":    "#c8e6c9",
+	"failed":  "#ffcdd2",
+	"aborted": "#e0e0e0",
+	"running": "#fff59d",
+	"pending": "#ffffff",
+}
+
+// graphGroup is one cluster in the drawing, holding the calls directly inside
+// it and the clusters nested in it.
+type graphGroup struct {
+	enclosure Enclosure
+	calls     []string
+	children  []*graphGroup
+	byID      map[string]*graphGroup
+}
+
+// groupCalls arranges the graph's calls into nested clusters, following each
+// call's enclosures from the outside in.
+func groupCalls(g *CallGraph) *graphGroup {
+	root := &graphGroup{byID: map[string]*graphGroup{}}
+	for _, name := range g.Names() {
+		group := root
+		for _, e := range g.Nodes[name].Enclosures {
+			child, ok := group.byID[e.ID]
+			if !ok {
+				child = &graphGroup{enclosure: e, byID: map[string]*graphGroup{}}
+				group.byID[e.ID] = child
+				group.children = append(group.children, child)
+			}
+			group = child
+		}
+		group.calls = append(group.calls, name)
+	}
+	return root
+}
+
+// groupLabel is the header drawn on a cluster.
+func groupLabel(e Enclosure) string {
+	switch e.Kind {
+	case EnclosureScatter:
+		return "scatter (" + e.Label + ")"
+	case EnclosureConditional:
+		return "if (" + e.Label + ")"
+	default:
+		name := shortName(e.ID)
+		if name == e.Label {
+			return name
+		}
+		return name + " (" + e.Label + ")"
+	}
+}
+
+// shortName is a call's name within its own workflow.
+func shortName(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+// nodeLines are the lines of a call's label: its name, and its task when the
+// call is aliased.
+func nodeLines(n *CallNode) []string {
+	lines := []string{shortName(n.Name)}
+	if n.Task != "" && n.Task != lines[0] {
+		lines = append(lines, n.Task)
+	}
+	if n.Unresolved {
+		lines = append(lines, "(not resolved)")
+	}
+	return lines
+}
+
+func renderDOT(g *CallGraph, statuses map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(g.Workflow))
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\", fontname=\"Helvetica\"];\n")
+	b.WriteString("  graph [fontname=\"Helvetica\"];\n")
+
+	var group func(grp *graphGroup, indent string)
+	group = func(grp *graphGroup, indent string) {
+		for _, name := range grp.calls {
+			n := g.Nodes[name]
+			attrs := []string{"label=" + dotQuote(strings.Join(nodeLines(n), "\n"))}
+			if class := statusClass(statuses[name]); class != "" {
+				attrs = append(attrs, "fillcolor="+dotQuote(statusColors[class]))
+			}
+			if n.Unresolved {
+				attrs = append(attrs, `style="rounded,filled,dashed"`)
+			}
+			fmt.Fprintf(&b, "%s%s [%s];\n", indent, dotQuote(name), strings.Join(attrs, ", "))
+		}
+		for _, child := range grp.children {
+			style := "rounded"
+			switch child.enclosure.Kind {
+			case EnclosureScatter:
+				style = "dashed"
+			case EnclosureConditional:
+				style = "dotted"
+			}
+			fmt.Fprintf(&b, "%ssubgraph %s {\n", indent, dotQuote("cluster_"+child.enclosure.ID))
+			fmt.Fprintf(&b, "%s  label=%s;\n%s  style=%s;\n", indent, dotQuote(groupLabel(child.enclosure)), indent, dotQuote(style))
+			group(child, indent+"  ")
+			fmt.Fprintf(&b, "%s}\n", indent)
+		}
+	}
+	group(groupCalls(g), "  ")
+
+	for _, name := range g.Names() {
+		for _, dep := range g.Nodes[name].DependsOn {
+			fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(dep), dotQuote(name))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func renderMermaid(g *CallGraph, statuses map[string]string) string {
+	// Mermaid IDs must be plain words; call paths hold dots.
+	ids := make(map[string]string, len(g.Nodes))
+	for i, name := range g.Names() {
+		ids[name] = fmt.Sprintf("c%d", i)
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+
+	classes := make(map[string][]string)
+	groups := 0
+	var group func(grp *graphGroup, indent string)
+	group = func(grp *graphGroup, indent string) {
+		for _, name := range grp.calls {
+			n := g.Nodes[name]
+			fmt.Fprintf(&b, "%s%s[%s]\n", indent, ids[name], mermaidQuote(strings.Join(nodeLines(n), "<br/>")))
+			if class := statusClass(statuses[name]); class != "" {
+				classes[class] = append(classes[class], ids[name])
+			}
+			if n.Unresolved {
+				classes["unresolved"] = append(classes["unresolved"], ids[name])
+			}
+		}
+		for _, child := range grp.children {
+			groups++
+			fmt.Fprintf(&b, "%ssubgraph g%d[%s]\n", indent, groups, mermaidQuote(groupLabel(child.enclosure)))
+			group(child, indent+"  ")
+			fmt.Fprintf(&b, "%send\n", indent)
+		}
+	}
+	group(groupCalls(g), "  ")
+
+	for _, name := range g.Names() {
+		for _, dep := range g.Nodes[name].DependsOn {
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[dep], ids[name])
+		}
+	}
+
+	for _, class := range []string{"done", "running", "failed", "aborted", "pending"} {
+		if len(classes[class]) > 0 {
+			fmt.Fprintf(&b, "  classDef %s fill:%s\n", class, statusColors[class])
+			fmt.Fprintf(&b, "  class %s %s\n", strings.Join(classes[class], ","), class)
+		}
+	}
+	if len(classes["unresolved"]) > 0 {
+		b.WriteString("  classDef unresolved stroke-dasharray: 5 5\n")
+		fmt.Fprintf(&b, "  class %s unresolved\n", strings.Join(classes["unresolved"], ","))
+	}
+	return b.String()
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// graphDocument is the JSON form of a call graph.
+type graphDocument struct {
+	Workflow string      `json:"workflow"`
+	Calls    []graphCall `json:"calls"`
+}
+
+type graphCall struct {
+	Name        string      `json:"name"`
+	Task        string      `json:"task"`
+	DependsOn   []string    `json:"depends_on"`
+	Enclosures  []Enclosure `json:"enclosures,omitempty"`
+	Unresolved  bool        `json:"unresolved,omitempty"`
+	Subworkflow bool        `json:"subworkflow,omitempty"`
+	Status      string      `json:"status,omitempty"`
+}
+
+func renderGraphJSON(g *CallGraph, statuses map[string]string) ([]byte, error) {
+	doc := graphDocument{Workflow: g.Workflow, Calls: []graphCall{}}
+	for _, name := range g.Names() {
+		n := g.Nodes[name]
+		deps := n.DependsOn
+		if deps == nil {
+			deps = []string{}
+		}
+		doc.Calls = append(doc.Calls, graphCall{
+			Name:        n.Name,
+			Task:        n.Task,
+			DependsOn:   deps,
+			Enclosures:  n.Enclosures,
+			Unresolved:  n.Unresolved,
+			Subworkflow: n.Subworkflow,
+			Status:      statuses[name],
+		})
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
@@ -0,0 +1,150 @@
+package wdl
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const blocksWDL = `version 1.0
+
+import "align_sample.wdl" as align
+
+workflow Cohort {
+  input {
+    Array[File] reads
+    Boolean qc = true
+  }
+
+  scatter (r in reads) {
+    call align.AlignSample { input: reads = r, sample = basename(r) }
+    if (qc) {
+      call Report { input: bam = AlignSample.sorted, sample = "x" }
+    }
+  }
+}
+
+task Report {
+  input { File bam String sample }
+  command <<< echo ~{bam} >>>
+  output { File out = "~{sample}.txt" }
+}
+`
+
+func TestBuildCallGraphRecordsEnclosures(t *testing.T) {
+	g, err := BuildCallGraphWithSources([]byte(blocksWDL), subSources())
+	if err != nil {
+		t.Fatalf("BuildCallGraphWithSources() error: %v", err)
+	}
+
+	scatter := Enclosure{ID: "scatter-1", Kind: EnclosureScatter, Label: "r in reads"}
+	tests := []struct {
+		call string
+		want []Enclosure
+	}{
+		{"Report", []Enclosure{scatter, {ID: "if-2", Kind: EnclosureConditional, Label: "qc"}}},
+		// Calls flattened out of a subworkflow keep the blocks around the
+		// subworkflow call, then the subworkflow itself.
+		{"AlignSample.Sort", []Enclosure{scatter, {ID: "AlignSample", Kind: EnclosureSubworkflow, Label: "AlignSample"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.call, func(t *testing.T) {
+			n, ok := g.Nodes[tt.call]
+			if !ok {
+				t.Fatalf("no node %s in %v", tt.call, g.Names())
+			}
+			if !reflect.DeepEqual(n.Enclosures, tt.want) {
+				t.Errorf("Enclosures = %+v, want %+v", n.Enclosures, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderCallGraphDOT(t *testing.T) {
+	g, err := BuildCallGraphWithSources([]byte(blocksWDL), subSources())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := RenderCallGraph(g, GraphDOT, map[string]string{"AlignSample.Align": "Done", "Report": "Failed"})
+	if err != nil {
+		t.Fatalf("RenderCallGraph() error = %v", err)
+	}
+	dot := string(out)
+
+	for _, want := range []string{
+		`digraph "Cohort" {`,
+		`subgraph "cluster_scatter-1" {`,
+		`label="scatter (r in reads)";`,
+		`label="if (qc)";`,
+		`subgraph "cluster_AlignSample" {`,
+		`"AlignSample.Align" [label="Align", fillcolor="#c8e6c9"];`,
+		`"Report" [label="Report", fillcolor="#ffcdd2"];`,
+		`"AlignSample.Align" -> "AlignSample.Sort";`,
+		`"AlignSample.Sort" -> "Report";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT output lacks %q:\n%s", want, dot)
+		}
+	}
+	// Clusters nest: the conditional sits inside the scatter.
+	if strings.Index(dot, "cluster_if-2") < strings.Index(dot, "cluster_scatter-1") {
+		t.Error("the if cluster should be drawn inside the scatter cluster")
+	}
+}
+
+func TestRenderCallGraphMermaid(t *testing.T) {
+	g, err := BuildCallGraph([]byte(chainedWDL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := RenderCallGraph(g, GraphMermaid, map[string]string{"IndexVcf": "Done", "StatsVcf": "Running"})
+	if err != nil {
+		t.Fatalf("RenderCallGraph() error = %v", err)
+	}
+	want := `flowchart TD
+  c0["IndexVcf"]
+  c1["StatsVcf"]
+  c0 --> c1
+  classDef done fill:#c8e6c9
+  class c0 done
+  classDef running fill:#fff59d
+  class c1 running
+`
+	if string(out) != want {
+		t.Errorf("Mermaid output =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestRenderCallGraphJSON(t *testing.T) {
+	g, err := BuildCallGraph([]byte(chainedWDL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := RenderCallGraph(g, GraphJSON, nil)
+	if err != nil {
+		t.Fatalf("RenderCallGraph() error = %v", err)
+	}
+	var doc struct {
+		Workflow string `json:"workflow"`
+		Calls    []struct {
+			Name      string   `json:"name"`
+			DependsOn []string `json:"depends_on"`
+		} `json:"calls"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if doc.Workflow != "VcfIndexAndStats" || len(doc.Calls) != 2 {
+		t.Fatalf("decoded = %+v", doc)
+	}
+	if got := doc.Calls[1].DependsOn; !reflect.DeepEqual(got, []string{"IndexVcf"}) {
+		t.Errorf("StatsVcf depends_on = %v, want [IndexVcf]", got)
+	}
+}
+
+func TestRenderCallGraphUnknownFormat(t *testing.T) {
+	if _, err := RenderCallGraph(&CallGraph{}, "svg", nil); err == nil {
+		t.Error("an unknown format should be an error")
+	}
+}
@@ -28,11 +28,13 @@ func (s SourceSet) Add(path string, content []byte) {
 	s[filepath.Base(path)] = content
 }
 
-// Get resolves an import URI to a source. Remote imports (http/https) are not
-// fetched — Cromwell resolves those itself.
+// Get resolves an import URI to a source. Remote imports (http/https) resolve
+// only by their full URL, as WithRemoteImports adds them; nothing is fetched
+// here.
 func (s SourceSet) Get(uri string) ([]byte, bool) {
-	if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
-		return nil, false
+	if isRemoteImport(uri) {
+		content, ok := s[uri]
+		return content, ok
 	}
 	content, ok := s[filepath.Base(uri)]
 	return content, ok
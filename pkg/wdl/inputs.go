@@ -19,13 +19,17 @@ type InputSpec struct {
 	Name        string // Qualified as Cromwell expects it: "workflow.input"
 	Type        string // Rendered WDL type: "File", "Array[File]+", "Int?"
 	Optional    bool   // Declared with a "?" suffix
-	Default     string // Rendered default expression, empty when unbound
+	Default     string // Rendered default expression, empty when unbound or computed
+	HasDefault  bool   // Declared with a default, literal or computed
 	Description string // From the workflow's parameter_meta, when documented
+	// Call is the call path for an input set on a call ("Align" for
+	// "Main.Align.mem_gb"), empty for a workflow input.
+	Call string
 }
 
 // Required reports whether the input must be provided: no "?" and no default.
 func (s InputSpec) Required() bool {
-	return !s.Optional && s.Default == ""
+	return !s.Optional && !s.HasDefault
 }
 
 // WorkflowInputs returns the declared inputs of the workflow in the document,
@@ -41,6 +45,25 @@ func WorkflowInputs(source []byte) ([]InputSpec, error) {
 	return workflowInputSpecs(doc.Workflow), nil
 }
 
+// DeclaredInputs returns every input an inputs JSON may set: the workflow's,
+// then those its calls leave unbound ("Workflow.call.input"), grouped by call.
+// sources resolves imported tasks and subworkflows; calls it cannot resolve
+// contribute nothing.
+func DeclaredInputs(source []byte, sources SourceSet) ([]InputSpec, error) {
+	doc, err := ParseBytes(source)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Workflow == nil {
+		return nil, nil
+	}
+	specs := workflowInputSpecs(doc.Workflow)
+	for _, ci := range collectCallInputs(doc, newDocumentSet(sources)).inputs {
+		specs = append(specs, ci.spec)
+	}
+	return specs, nil
+}
+
 func workflowInputSpecs(wf *ast.Workflow) []InputSpec {
 	specs := make([]InputSpec, 0, len(wf.Inputs))
 	for _, in := range wf.Inputs {
@@ -52,16 +75,17 @@ func workflowInputSpecs(wf *ast.Workflow) []InputSpec {
 			Type:        in.Type.String(),
 			Optional:    in.Type.Optional,
 			Default:     renderExpression(in.Expression),
-			Description: parameterMetaDescription(wf.ParameterMeta, in.Name),
+			HasDefault:  in.Expression != nil,
+			Description: ParameterMetaDescription(wf.ParameterMeta, in.Name),
 		})
 	}
 	return specs
 }
 
-// parameterMetaDescription extracts an input's documentation from
+// ParameterMetaDescription extracts a declaration's documentation from
 // parameter_meta, which WDL allows as either a bare string or an object
 // carrying a "description" (or "help") field.
-func parameterMetaDescription(meta map[string]any, name string) string {
+func ParameterMetaDescription(meta map[string]any, name string) string {
 	raw, ok := meta[name]
 	if !ok {
 		return ""
@@ -131,7 +155,11 @@ type Scaffold struct {
 	Template []byte
 	// Inputs describes every declared input, including the optional ones
 	// left out of the template, so callers can explain them to the user.
+	// Call-level inputs follow the workflow's, grouped by call.
 	Inputs []InputSpec
+	// UnresolvedCalls lists calls whose definition could not be read, so
+	// their inputs are missing from the template.
+	UnresolvedCalls []string
 }
 
 // ScaffoldOptions controls how the inputs template is rendered.
@@ -139,12 +167,25 @@ type ScaffoldOptions struct {
 	// IncludeOptional adds optional inputs, rendered with their default
 	// value (or null when the default is not a literal).
 	IncludeOptional bool
+	// IncludeCalls adds the inputs calls leave unbound and that have a
+	// default or are optional, as "Workflow.call.input" keys. Unbound call
+	// inputs that are required are always in the template.
+	IncludeCalls bool
+	// Sources resolves imported tasks and subworkflows, so their inputs can
+	// be listed too.
+	Sources SourceSet
+	// Values replaces the placeholder or default of the inputs it names (by
+	// qualified name). Optional inputs given a value are included even
+	// without IncludeOptional or IncludeCalls; keys matching no input are
+	// written last, as given.
+	Values map[string]any
 }
 
 // ScaffoldInputs renders an inputs JSON template for the workflow, with
 // required inputs first, in declaration order. Values of required inputs are
 // placeholders (see PlaceholderPrefix) carrying the type and, when the WDL
-// documents it, the input's description.
+// documents it, the input's description. Struct, Pair and Map inputs get a
+// nested skeleton with a placeholder per member instead.
 func ScaffoldInputs(source []byte, opts ScaffoldOptions) (*Scaffold, error) {
 	doc, err := ParseBytes(source)
 	if err != nil {
@@ -155,9 +196,27 @@ func ScaffoldInputs(source []byte, opts ScaffoldOptions) (*Scaffold, error) {
 	}
 
 	scaffold := &Scaffold{WorkflowName: doc.Workflow.Name}
+	docs := newDocumentSet(opts.Sources)
+	structs := collectStructs(doc, docs)
 	specs := workflowInputSpecs(doc.Workflow)
-	scaffold.Inputs = specs
-	if len(specs) == 0 {
+	calls := collectCallInputs(doc, docs)
+	scaffold.UnresolvedCalls = calls.unresolved
+
+	types := make(map[string]*ast.Type, len(doc.Workflow.Inputs)+len(calls.inputs))
+	for _, in := range doc.Workflow.Inputs {
+		if in != nil {
+			types[doc.Workflow.Name+"."+in.Name] = in.Type
+		}
+	}
+	var callSpecs []InputSpec
+	for _, ci := range calls.inputs {
+		types[ci.spec.Name] = ci.decl.Type
+		if _, given := opts.Values[ci.spec.Name]; given || ci.spec.Required() || opts.IncludeCalls {
+			callSpecs = append(callSpecs, ci.spec)
+		}
+	}
+	scaffold.Inputs = append(specs, callSpecs...)
+	if len(scaffold.Inputs) == 0 && len(opts.Values) == 0 {
 		scaffold.Template = []byte("{}\n")
 		return scaffold, nil
 	}
@@ -168,14 +227,45 @@ func ScaffoldInputs(source []byte, opts ScaffoldOptions) (*Scaffold, error) {
 	}
 	var entries []entry
 
-	for _, s := range specs {
-		if s.Required() {
-			entries = append(entries, entry{name: s.Name, value: placeholderFor(s)})
+	for _, group := range [][]InputSpec{specs, callSpecs} {
+		for _, s := range group {
+			if !s.Required() {
+				continue
+			}
+			if value, ok := opts.Values[s.Name]; ok {
+				entries = append(entries, entry{name: s.Name, value: value})
+			} else {
+				entries = append(entries, entry{name: s.Name, value: structs.skeleton(types[s.Name], s.Description)})
+			}
+		}
+	}
+	// Optional inputs with a given value follow the required ones.
+	known := make(map[string]bool, len(scaffold.Inputs))
+	for _, group := range [][]InputSpec{specs, callSpecs} {
+		for _, s := range group {
+			known[s.Name] = true
+			if value, ok := opts.Values[s.Name]; ok && !s.Required() {
+				entries = append(entries, entry{name: s.Name, value: value})
+			}
 		}
 	}
+	for _, name := range sortedKeys(opts.Values) {
+		if !known[name] {
+			entries = append(entries, entry{name: name, value: opts.Values[name]})
+		}
+	}
+	optional := [][]InputSpec{}
 	if opts.IncludeOptional {
-		for _, s := range specs {
-			if s.Required() {
+		optional = append(optional, specs)
+	}
+	if opts.IncludeCalls {
+		optional = append(optional, callSpecs)
+	}
+	for _, group := range optional {
+		for _, s := range group {
+			// A computed default has no value to show, and null would
+			// override it; the input is listed but left to its default.
+			if _, given := opts.Values[s.Name]; given || s.Required() || (s.Default == "" && !s.Optional) {
 				continue
 			}
 			entries = append(entries, entry{name: s.Name, value: defaultOrNull(s)})
@@ -195,7 +285,12 @@ func ScaffoldInputs(source []byte, opts ScaffoldOptions) (*Scaffold, error) {
 		if err != nil {
 			return nil, err
 		}
-		fmt.Fprintf(&buf, "  %s: %s", key, value)
+		// Nested skeletons are indented under their key.
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, []byte(value), "  ", "  "); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "  %s: %s", key, indented.String())
 		if i < len(entries)-1 {
 			buf.WriteString(",")
 		}
@@ -219,15 +314,6 @@ func encodeJSON(v any) (string, error) {
 	return strings.TrimRight(buf.String(), "\n"), nil
 }
 
-// placeholderFor builds the sentinel value for a required input.
-func placeholderFor(s InputSpec) string {
-	p := PlaceholderPrefix + " " + s.Type
-	if s.Description != "" {
-		p += " — " + s.Description
-	}
-	return p + ">"
-}
-
 // defaultOrNull renders an optional input's value: its literal default when
 // it has one, null otherwise (which means "not provided" to Cromwell).
 func defaultOrNull(s InputSpec) any {
@@ -241,9 +327,29 @@ func defaultOrNull(s InputSpec) any {
 	return v
 }
 
-// IsPlaceholder reports whether a JSON value is a scaffolded placeholder the
-// user has not replaced yet.
+// IsPlaceholder reports whether a JSON value is, or still contains, a
+// scaffolded placeholder the user has not replaced yet: struct, Pair and Map
+// skeletons nest them in members, elements and keys.
 func IsPlaceholder(v any) bool {
-	s, ok := v.(string)
-	return ok && strings.HasPrefix(s, PlaceholderPrefix)
+	switch v := v.(type) {
+	case string:
+		return isPlaceholderString(v)
+	case []any:
+		for _, item := range v {
+			if IsPlaceholder(item) {
+				return true
+			}
+		}
+	case map[string]any:
+		for key, item := range v {
+			if isPlaceholderString(key) || IsPlaceholder(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isPlaceholderString(s string) bool {
+	return strings.HasPrefix(s, PlaceholderPrefix)
 }
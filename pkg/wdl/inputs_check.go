@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -65,6 +66,14 @@ func (r *InputsReport) HasErrors() bool {
 // It performs no IO and never fails on WDL it cannot parse — that case is
 // reported as a warning and left to Cromwell.
 func CheckInputs(source, inputsJSON []byte) *InputsReport {
+	return CheckInputsWithSources(source, inputsJSON, nil)
+}
+
+// CheckInputsWithSources is CheckInputs for a workflow whose imports are
+// available, so "Workflow.call.input" keys that set inputs of imported tasks
+// and subworkflows are checked too. Calls to local tasks are checked either
+// way.
+func CheckInputsWithSources(source, inputsJSON []byte, sources SourceSet) *InputsReport {
 	report := &InputsReport{}
 
 	doc, err := ParseBytes(source)
@@ -87,6 +96,9 @@ func CheckInputs(source, inputsJSON []byte) *InputsReport {
 		return report
 	}
 
+	docs := newDocumentSet(sources)
+	structs := collectStructs(doc, docs)
+
 	declared := make(map[string]*ast.Declaration, len(doc.Workflow.Inputs))
 	prefix := doc.Workflow.Name + "."
 	for _, in := range doc.Workflow.Inputs {
@@ -107,39 +119,76 @@ func CheckInputs(source, inputsJSON []byte) *InputsReport {
 			}
 			continue
 		}
-		if IsPlaceholder(value) {
-			report.Findings = append(report.Findings, Finding{
-				Severity: SeverityError,
-				Input:    spec.Name,
-				Message:  fmt.Sprintf("still holds the scaffold placeholder — replace it with a value of type %s", spec.Type),
-			})
+		decl := declared[spec.Name]
+		report.Findings = append(report.Findings, structs.checkValue(spec.Name, decl.Type, value)...)
+		report.Files = append(report.Files, structs.collectFiles(spec.Name, decl.Type, value)...)
+	}
+
+	calls := collectCallInputs(doc, docs)
+	callDecls := make(map[string]*ast.Declaration, len(calls.inputs))
+	for _, ci := range calls.inputs {
+		callDecls[ci.spec.Name] = ci.decl
+		value, ok := provided[ci.spec.Name]
+		if !ok {
+			if ci.spec.Required() {
+				report.Findings = append(report.Findings, Finding{
+					Severity: SeverityError,
+					Input:    ci.spec.Name,
+					Message:  fmt.Sprintf("required input of call %s is missing (type %s)", ci.spec.Call, ci.spec.Type),
+				})
+			}
 			continue
 		}
-		decl := declared[spec.Name]
-		report.Findings = append(report.Findings, checkValue(spec.Name, decl.Type, value)...)
-		report.Files = append(report.Files, collectFiles(spec.Name, decl.Type, value)...)
+		report.Findings = append(report.Findings, structs.checkValue(ci.spec.Name, ci.decl.Type, value)...)
+		report.Files = append(report.Files, structs.collectFiles(ci.spec.Name, ci.decl.Type, value)...)
 	}
 
-	for name := range provided {
+	for _, name := range sortedKeys(provided) {
 		if _, ok := declared[name]; ok {
 			continue
 		}
-		report.Findings = append(report.Findings, Finding{
-			Severity: SeverityWarning,
-			Input:    name,
-			Message:  "not declared by this workflow — check for a typo (subworkflow-qualified inputs are not modelled here)",
-		})
+		if _, ok := callDecls[name]; ok {
+			continue
+		}
+		report.Findings = append(report.Findings, undeclaredInput(name, doc.Workflow.Name, calls))
 	}
 
 	sortFindings(report.Findings)
 	return report
 }
 
-// checkValue compares a JSON value against a declared WDL type. It only
-// reports an error when no reasonable coercion exists: being wrong here
-// would block a valid submission, which is worse than staying quiet.
-func checkValue(name string, t *ast.Type, value any) []Finding {
-	if t == nil {
+// undeclaredInput explains a key that matches no input the workflow or its
+// calls accept.
+func undeclaredInput(name, workflowName string, calls *callInputSet) Finding {
+	finding := Finding{Severity: SeverityWarning, Input: name}
+	call, input, ok := calls.matchCall(name, workflowName)
+	switch {
+	case !ok:
+		finding.Message = "not declared by this workflow — check for a typo"
+	case calls.calls[call] == nil:
+		finding.Message = fmt.Sprintf("sets an input of call %s, whose definition could not be read to check it (bundle the imports)", call)
+	case calls.calls[call][input]:
+		finding.Message = fmt.Sprintf("is already set by call %s in the WDL, so this value is not used", call)
+	default:
+		finding.Message = fmt.Sprintf("call %s has no input %q", call, input)
+		if suggestion := closestName(input, calls.calls[call]); suggestion != "" {
+			finding.Message += fmt.Sprintf(" — did you mean %s?", suggestion)
+		}
+	}
+	return finding
+}
+
+// checkValue compares a JSON value against a declared WDL type, descending
+// into arrays, Maps, Pairs and structs so each finding names the nested value
+// it is about ("Wf.sample.reads", "Wf.pairs[0].left"). It only reports an
+// error when no reasonable coercion exists: being wrong here would block a
+// valid submission, which is worse than staying quiet.
+func (s structTable) checkValue(name string, t *ast.Type, value any) []Finding {
+	return s.checkValueAt(name, t, value, 0)
+}
+
+func (s structTable) checkValueAt(name string, t *ast.Type, value any, depth int) []Finding {
+	if t == nil || depth > maxTypeDepth {
 		return nil
 	}
 	if value == nil {
@@ -152,6 +201,13 @@ func checkValue(name string, t *ast.Type, value any) []Finding {
 			Message:  fmt.Sprintf("is null, but %s is required", t.String()),
 		}}
 	}
+	if str, ok := value.(string); ok && isPlaceholderString(str) {
+		return []Finding{{
+			Severity: SeverityError,
+			Input:    name,
+			Message:  fmt.Sprintf("still holds the scaffold placeholder — replace it with a value of type %s", t.String()),
+		}}
+	}
 
 	switch t.Base {
 	case "Int":
@@ -198,26 +254,161 @@ func checkValue(name string, t *ast.Type, value any) []Finding {
 		}
 		var findings []Finding
 		for i, item := range items {
-			findings = append(findings, checkValue(fmt.Sprintf("%s[%d]", name, i), t.ArrayType, item)...)
+			findings = append(findings, s.checkValueAt(fmt.Sprintf("%s[%d]", name, i), t.ArrayType, item, depth+1)...)
 		}
 		return findings
-	case "Map", "Object":
+	case "Map":
+		return s.checkMap(name, t, value, depth)
+	case "Object":
 		if _, ok := value.(map[string]any); !ok {
 			return mismatch(name, t, value)
 		}
 		return nil
 	case "Pair":
-		switch value.(type) {
-		case map[string]any, []any:
-			return nil
-		}
-		return mismatch(name, t, value)
+		return s.checkPair(name, t, value, depth)
 	}
 
-	// Unknown base (custom struct types): nothing reliable to check.
+	if def, ok := s[t.Base]; ok {
+		return s.checkStruct(name, t, def, value, depth)
+	}
+	// A struct whose definition could not be read: nothing reliable to check.
 	return nil
 }
 
+// checkMap validates a Map's keys against the key type — JSON keys are always
+// strings, so an Int key must at least parse as one — and each value against
+// the value type.
+func (s structTable) checkMap(name string, t *ast.Type, value any, depth int) []Finding {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return mismatch(name, t, value)
+	}
+	var findings []Finding
+	for _, key := range sortedKeys(m) {
+		entry := fmt.Sprintf("%s[%q]", name, key)
+		if problem := mapKeyProblem(key, t.MapKey); problem != "" {
+			findings = append(findings, Finding{Severity: SeverityError, Input: entry, Message: problem})
+			continue
+		}
+		findings = append(findings, s.checkValueAt(entry, t.MapValue, m[key], depth+1)...)
+	}
+	return findings
+}
+
+// mapKeyProblem explains why a JSON object key is not a valid Map key of the
+// given type, or returns "" when it is.
+func mapKeyProblem(key string, t *ast.Type) string {
+	if isPlaceholderString(key) {
+		return "still has the scaffold placeholder as its key — replace it with a real key"
+	}
+	if t == nil {
+		return ""
+	}
+	valid := true
+	switch t.Base {
+	case "Int":
+		_, err := strconv.ParseInt(key, 10, 64)
+		valid = err == nil
+	case "Float":
+		_, err := strconv.ParseFloat(key, 64)
+		valid = err == nil
+	case "Boolean":
+		valid = key == "true" || key == "false"
+	case "File", "Directory":
+		valid = strings.TrimSpace(key) != ""
+	}
+	if !valid {
+		return fmt.Sprintf("key is not a valid %s", t.String())
+	}
+	return ""
+}
+
+// checkPair validates a Pair, which Cromwell reads from an object with
+// exactly a "left" and a "right" member.
+func (s structTable) checkPair(name string, t *ast.Type, value any, depth int) []Finding {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return []Finding{{
+			Severity: SeverityError,
+			Input:    name,
+			Message:  fmt.Sprintf(`is a %s, but %s is expected as {"left": ..., "right": ...}`, jsonKind(value), t.String()),
+		}}
+	}
+	var findings []Finding
+	for _, side := range []struct {
+		key string
+		typ *ast.Type
+	}{{"left", t.PairLeft}, {"right", t.PairRight}} {
+		item, ok := m[side.key]
+		if !ok {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Input:    name,
+				Message:  fmt.Sprintf("has no %q member, which %s requires", side.key, t.String()),
+			})
+			continue
+		}
+		findings = append(findings, s.checkValueAt(name+"."+side.key, side.typ, item, depth+1)...)
+	}
+	for _, key := range sortedKeys(m) {
+		if key != "left" && key != "right" {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Input:    name,
+				Message:  fmt.Sprintf("has a %q member, but a Pair only has left and right", key),
+			})
+		}
+	}
+	return findings
+}
+
+// checkStruct validates a struct value member by member: required members
+// present, each of its declared type, and no members the struct lacks.
+func (s structTable) checkStruct(name string, t *ast.Type, def *ast.Struct, value any, depth int) []Finding {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return mismatch(name, t, value)
+	}
+	var findings []Finding
+	members := make(map[string]bool, len(def.Members))
+	for _, member := range def.Members {
+		if member == nil || member.Type == nil {
+			continue
+		}
+		members[member.Name] = true
+		item, ok := m[member.Name]
+		if !ok {
+			if !member.Type.Optional {
+				findings = append(findings, Finding{
+					Severity: SeverityError,
+					Input:    name + "." + member.Name,
+					Message:  fmt.Sprintf("required member of struct %s is missing (type %s)", def.Name, member.Type.String()),
+				})
+			}
+			continue
+		}
+		findings = append(findings, s.checkValueAt(name+"."+member.Name, member.Type, item, depth+1)...)
+	}
+	for _, key := range sortedKeys(m) {
+		if members[key] {
+			continue
+		}
+		message := fmt.Sprintf("struct %s has no member %q", def.Name, key)
+		if suggestion := closestName(key, members); suggestion != "" {
+			message += fmt.Sprintf(" — did you mean %s?", suggestion)
+		}
+		findings = append(findings, Finding{Severity: SeverityError, Input: name + "." + key, Message: message})
+	}
+	return findings
+}
+
+// Quoted Int and Float values CheckInputs accepts, the same the inputs schema
+// accepts.
+var (
+	quotedInt   = regexp.MustCompile(scalarStringPatterns["Int"])
+	quotedFloat = regexp.MustCompile(scalarStringPatterns["Float"])
+)
+
 // checkNumber validates Int/Float values, tolerating the coercions Cromwell
 // itself performs.
 func checkNumber(name string, t *ast.Type, value any, integral bool) []Finding {
@@ -232,7 +423,11 @@ func checkNumber(name string, t *ast.Type, value any, integral bool) []Finding {
 		}
 		return nil
 	case string:
-		if _, err := strconv.ParseFloat(v, 64); err == nil {
+		quoted := quotedFloat
+		if integral {
+			quoted = quotedInt
+		}
+		if quoted.MatchString(v) {
 			return warn(name, "is the quoted number %q where %s is expected", v, t.String())
 		}
 	}
@@ -271,28 +466,54 @@ func jsonKind(v any) string {
 }
 
 // collectFiles returns every File-typed path in a value, recursing into
-// arrays so scattered inputs are covered too.
-func collectFiles(name string, t *ast.Type, value any) []FileRef {
-	if t == nil || value == nil {
+// arrays, Maps, Pairs and structs so scattered and nested inputs are covered
+// too. Placeholders are skipped: they are reported, not looked up.
+func (s structTable) collectFiles(name string, t *ast.Type, value any) []FileRef {
+	return s.collectFilesAt(name, t, value, 0)
+}
+
+func (s structTable) collectFilesAt(name string, t *ast.Type, value any, depth int) []FileRef {
+	if t == nil || value == nil || depth > maxTypeDepth {
 		return nil
 	}
+	var refs []FileRef
 	switch t.Base {
 	case "File", "Directory":
-		if s, ok := value.(string); ok && strings.TrimSpace(s) != "" {
-			return []FileRef{{Input: name, Path: s}}
+		if str, ok := value.(string); ok && strings.TrimSpace(str) != "" && !isPlaceholderString(str) {
+			refs = append(refs, FileRef{Input: name, Path: str})
 		}
 	case "Array":
-		items, ok := value.([]any)
-		if !ok {
+		items, _ := value.([]any)
+		for i, item := range items {
+			refs = append(refs, s.collectFilesAt(fmt.Sprintf("%s[%d]", name, i), t.ArrayType, item, depth+1)...)
+		}
+	case "Map":
+		m, _ := value.(map[string]any)
+		for _, key := range sortedKeys(m) {
+			if isPlaceholderString(key) {
+				continue
+			}
+			entry := fmt.Sprintf("%s[%q]", name, key)
+			refs = append(refs, s.collectFilesAt(entry, t.MapKey, key, depth+1)...)
+			refs = append(refs, s.collectFilesAt(entry, t.MapValue, m[key], depth+1)...)
+		}
+	case "Pair":
+		m, _ := value.(map[string]any)
+		refs = append(refs, s.collectFilesAt(name+".left", t.PairLeft, m["left"], depth+1)...)
+		refs = append(refs, s.collectFilesAt(name+".right", t.PairRight, m["right"], depth+1)...)
+	default:
+		def, ok := s[t.Base]
+		m, isObject := value.(map[string]any)
+		if !ok || !isObject {
 			return nil
 		}
-		var refs []FileRef
-		for i, item := range items {
-			refs = append(refs, collectFiles(fmt.Sprintf("%s[%d]", name, i), t.ArrayType, item)...)
+		for _, member := range def.Members {
+			if member != nil {
+				refs = append(refs, s.collectFilesAt(name+"."+member.Name, member.Type, m[member.Name], depth+1)...)
+			}
 		}
-		return refs
 	}
-	return nil
+	return refs
 }
 
 // parseInputValues decodes the inputs JSON into plain Go values.
@@ -305,6 +305,9 @@ workflow T {
 		{"int rejects decimal", `{"T.i": 5.5}`, SeverityError, "T.i"},
 		{"int warns on quoted number", `{"T.i": "5"}`, SeverityWarning, "T.i"},
 		{"int rejects text", `{"T.i": "many"}`, SeverityError, "T.i"},
+		{"int rejects quoted decimal", `{"T.i": "5.5"}`, SeverityError, "T.i"},
+		{"float warns on quoted number", `{"T.f": "2.5e3"}`, SeverityWarning, "T.f"},
+		{"float rejects quoted infinity", `{"T.f": "Inf"}`, SeverityError, "T.f"},
 		{"float accepts whole number", `{"T.f": 3}`, "", "T.f"},
 		{"float accepts decimal", `{"T.f": 3.5}`, "", "T.f"},
 		{"boolean rejects number", `{"T.b": 1}`, SeverityError, "T.b"},
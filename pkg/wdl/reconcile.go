@@ -0,0 +1,220 @@
+package wdl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// ChangeKind says what happened to an input when a previous run's inputs were
+// carried over to the current WDL.
+type ChangeKind string
+
+const (
+	// ChangeRenamed: the input is no longer declared under its old name, and
+	// its value moved to the input that most plausibly replaced it.
+	ChangeRenamed ChangeKind = "renamed"
+	// ChangeRetyped: the input is still declared, but its value no longer
+	// fits the declared type; the template has a placeholder instead.
+	ChangeRetyped ChangeKind = "retyped"
+	// ChangeDropped: the input is no longer declared and nothing replaced it.
+	ChangeDropped ChangeKind = "dropped"
+	// ChangeAdded: a required input the previous run did not set.
+	ChangeAdded ChangeKind = "added"
+	// ChangeUnchecked: the value sets an input of a call whose definition
+	// could not be read, so it is kept as it was.
+	ChangeUnchecked ChangeKind = "unchecked"
+)
+
+// InputChange is one input that could not be carried over as it was.
+type InputChange struct {
+	Kind ChangeKind
+	// Input is the key in the new template; empty for a dropped input.
+	Input string
+	// Previous is the key the previous run used; empty for an added input.
+	Previous string
+	// Value is the previous run's value, so a dropped or re-typed one can be
+	// moved by hand.
+	Value any
+	// Detail explains the change for the user.
+	Detail string
+}
+
+// Reconciliation is a template filled from a previous run's inputs.
+type Reconciliation struct {
+	*Scaffold
+	// PreviousWorkflow is the workflow name the previous run used, when the
+	// workflow has been renamed since; its keys were re-qualified.
+	PreviousWorkflow string
+	// Kept lists the inputs whose value was carried over unchanged.
+	Kept []string
+	// Changes lists every other input: renamed, re-typed, dropped, added and
+	// unchecked, in the previous document's key order, added ones last.
+	Changes []InputChange
+}
+
+// ReconcileInputs renders the template for the workflow in source starting
+// from previous, the inputs JSON of an earlier run: values of inputs that are
+// still declared and still fit their type are kept, inputs that were renamed
+// follow their new name, and new required inputs get placeholders. opts
+// controls the rest of the template as for ScaffoldInputs; its Values are
+// replaced.
+//
+// A renamed input is matched by name: the closest declared name in the same
+// scope (the workflow, or the same call), or else the only input elsewhere
+// with the same name, which follows a renamed call. The value must fit the
+// new input's type too.
+func ReconcileInputs(source, previous []byte, opts ScaffoldOptions) (*Reconciliation, error) {
+	doc, err := ParseBytes(source)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Workflow == nil {
+		return nil, fmt.Errorf("no workflow found in the WDL (only tasks?); nothing to scaffold")
+	}
+	prev, err := parseInputValues(previous)
+	if err != nil {
+		return nil, fmt.Errorf("the previous inputs are not valid JSON: %w", err)
+	}
+
+	docs := newDocumentSet(opts.Sources)
+	structs := collectStructs(doc, docs)
+	calls := collectCallInputs(doc, docs)
+	name := doc.Workflow.Name
+
+	types := make(map[string]*ast.Type)
+	specs := workflowInputSpecs(doc.Workflow)
+	for _, in := range doc.Workflow.Inputs {
+		if in != nil && in.Type != nil {
+			types[name+"."+in.Name] = in.Type
+		}
+	}
+	for _, ci := range calls.inputs {
+		specs = append(specs, ci.spec)
+		types[ci.spec.Name] = ci.decl.Type
+	}
+
+	r := &Reconciliation{}
+	// Re-qualify the keys when the workflow itself was renamed.
+	rekeyed := make(map[string]string, len(prev))
+	for _, key := range sortedKeys(prev) {
+		old, rest, ok := strings.Cut(key, ".")
+		if ok && old != name {
+			r.PreviousWorkflow = old
+			rekeyed[key] = name + "." + rest
+		} else {
+			rekeyed[key] = key
+		}
+	}
+
+	fits := func(key string, t *ast.Type, value any) (bool, string) {
+		for _, f := range structs.checkValue(key, t, value) {
+			if f.Severity == SeverityError {
+				return false, f.Message
+			}
+		}
+		return true, ""
+	}
+
+	values := make(map[string]any)
+	taken := make(map[string]bool)
+	var unmatched []string
+	for _, key := range sortedKeys(prev) {
+		target, value := rekeyed[key], prev[key]
+		t, declared := types[target]
+		if !declared {
+			unmatched = append(unmatched, key)
+			continue
+		}
+		taken[target] = true
+		if ok, problem := fits(target, t, value); ok {
+			values[target] = value
+			r.Kept = append(r.Kept, target)
+		} else {
+			r.Changes = append(r.Changes, InputChange{
+				Kind: ChangeRetyped, Input: target, Previous: key, Value: value,
+				Detail: fmt.Sprintf("is now %s: the previous value %s", t.String(), problem),
+			})
+		}
+	}
+
+	for _, key := range unmatched {
+		target, value := rekeyed[key], prev[key]
+		if call, _, ok := calls.matchCall(target, name); ok && calls.calls[call] == nil {
+			values[target] = value
+			r.Changes = append(r.Changes, InputChange{
+				Kind: ChangeUnchecked, Input: target, Previous: key, Value: value,
+				Detail: fmt.Sprintf("sets an input of call %s, whose definition could not be read to check it", call),
+			})
+			continue
+		}
+		if renamed := renameTarget(target, types, taken); renamed != "" {
+			if ok, _ := fits(renamed, types[renamed], value); ok {
+				taken[renamed] = true
+				values[renamed] = value
+				r.Changes = append(r.Changes, InputChange{
+					Kind: ChangeRenamed, Input: renamed, Previous: key, Value: value,
+					Detail: fmt.Sprintf("%s is no longer declared; its value moved to %s", target, renamed),
+				})
+				continue
+			}
+		}
+		r.Changes = append(r.Changes, InputChange{
+			Kind: ChangeDropped, Previous: key, Value: value,
+			Detail: "is no longer declared by the workflow",
+		})
+	}
+
+	for _, spec := range specs {
+		if spec.Required() && !taken[spec.Name] {
+			r.Changes = append(r.Changes, InputChange{
+				Kind: ChangeAdded, Input: spec.Name,
+				Detail: fmt.Sprintf("is a new required input (type %s)", spec.Type),
+			})
+		}
+	}
+
+	opts.Values = values
+	if r.Scaffold, err = ScaffoldInputs(source, opts); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// renameTarget finds the declared, not yet used input that most plausibly
+// replaced key: a close name in the same scope, or else the only input with
+// the same name in another scope.
+func renameTarget(key string, types map[string]*ast.Type, taken map[string]bool) string {
+	scope, input := splitQualified(key)
+	sameScope := make(map[string]bool)
+	var sameName []string
+	for candidate := range types {
+		if taken[candidate] {
+			continue
+		}
+		s, in := splitQualified(candidate)
+		if s == scope {
+			sameScope[in] = true
+		} else if in == input {
+			sameName = append(sameName, candidate)
+		}
+	}
+	if closest := closestName(input, sameScope); closest != "" {
+		return scope + "." + closest
+	}
+	if len(sameName) == 1 {
+		return sameName[0]
+	}
+	return ""
+}
+
+// splitQualified splits "Wf.call.input" into its scope ("Wf.call") and the
+// input name.
+func splitQualified(key string) (scope, input string) {
+	i := strings.LastIndex(key, ".")
+	if i < 0 {
+		return "", key
+	}
+	return key[:i], key[i+1:]
+}
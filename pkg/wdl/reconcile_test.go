@@ -0,0 +1,99 @@
+package wdl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const reconcileWDL = `version 1.0
+
+workflow Align {
+    input {
+        File reads_r1
+        File reads_r2
+        Int threads = 4
+        Array[File] known_sites
+        String ref_name
+    }
+    call BwaMem { input: reads = reads_r1 }
+}
+
+task BwaMem {
+    input {
+        File reads
+        Int mem_gb = 8
+    }
+    command <<< bwa ~{reads} >>>
+}
+`
+
+func TestReconcileInputs(t *testing.T) {
+	previous := `{
+  "AlignV1.reads_1": "gs://b/S1_R1.fq",
+  "AlignV1.reads_r2": "gs://b/S1_R2.fq",
+  "AlignV1.threads": 16,
+  "AlignV1.known_sites": "gs://b/dbsnp.vcf",
+  "AlignV1.adapter": "AGATCGGAAGAGC",
+  "AlignV1.Bwa.mem_gb": 32
+}`
+	r, err := ReconcileInputs([]byte(reconcileWDL), []byte(previous), ScaffoldOptions{})
+	if err != nil {
+		t.Fatalf("ReconcileInputs() error = %v", err)
+	}
+
+	if r.PreviousWorkflow != "AlignV1" {
+		t.Errorf("PreviousWorkflow = %q, want AlignV1", r.PreviousWorkflow)
+	}
+	if !reflect.DeepEqual(r.Kept, []string{"Align.reads_r2", "Align.threads"}) {
+		t.Errorf("Kept = %v, want reads_r2 and threads", r.Kept)
+	}
+
+	got := make(map[string]string)
+	for _, c := range r.Changes {
+		got[string(c.Kind)+" "+c.Previous+" -> "+c.Input] = c.Detail
+	}
+	want := map[string]string{
+		"renamed AlignV1.reads_1 -> Align.reads_r1":         "Align.reads_1 is no longer declared; its value moved to Align.reads_r1",
+		"renamed AlignV1.Bwa.mem_gb -> Align.BwaMem.mem_gb": "Align.Bwa.mem_gb is no longer declared; its value moved to Align.BwaMem.mem_gb",
+		"retyped AlignV1.known_sites -> Align.known_sites":  "is now Array[File]: the previous value is a string, but Array[File] is expected",
+		"dropped AlignV1.adapter -> ":                       "is no longer declared by the workflow",
+		"added  -> Align.ref_name":                          "is a new required input (type String)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes =\n%v\nwant\n%v", got, want)
+	}
+
+	var template map[string]any
+	if err := json.Unmarshal(r.Template, &template); err != nil {
+		t.Fatalf("template is not valid JSON: %v", err)
+	}
+	wantTemplate := map[string]any{
+		"Align.reads_r1":      "gs://b/S1_R1.fq",
+		"Align.reads_r2":      "gs://b/S1_R2.fq",
+		"Align.known_sites":   "<FILL: Array[File]>",
+		"Align.ref_name":      "<FILL: String>",
+		"Align.threads":       float64(16),
+		"Align.BwaMem.mem_gb": float64(32),
+	}
+	if !reflect.DeepEqual(template, wantTemplate) {
+		t.Errorf("Template = %v, want %v", template, wantTemplate)
+	}
+}
+
+func TestReconcileInputsKeepsUncheckedCallInputs(t *testing.T) {
+	r, err := ReconcileInputs([]byte(callInputsWDL), []byte(`{"Wf.reads": "a.fq", "Wf.BwaMem.sample": "S1", "Wf.Report.min_quality": 30}`), ScaffoldOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Changes) != 1 || r.Changes[0].Kind != ChangeUnchecked || r.Changes[0].Input != "Wf.Report.min_quality" {
+		t.Errorf("Changes = %+v, want only the unchecked Report input", r.Changes)
+	}
+	var template map[string]any
+	if err := json.Unmarshal(r.Template, &template); err != nil {
+		t.Fatal(err)
+	}
+	if template["Wf.Report.min_quality"] != float64(30) {
+		t.Errorf("the unchecked value should be kept, template = %v", template)
+	}
+}
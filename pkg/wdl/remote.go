@@ -0,0 +1,271 @@
+package wdl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxRemoteImportSize limits the size of a fetched import (10MB); a WDL file
+// anywhere near it is almost certainly not WDL.
+const maxRemoteImportSize = 10 * 1024 * 1024
+
+// RemoteImportOptions configures how http(s) imports are fetched.
+type RemoteImportOptions struct {
+	// CacheDir holds fetched imports. Empty uses DefaultImportCacheDir.
+	CacheDir string
+	// Offline serves imports from the cache only, failing on a miss instead
+	// of touching the network.
+	Offline bool
+	// Client fetches imports. Nil uses a client with a 30 second timeout.
+	Client *http.Client
+}
+
+// DefaultImportCacheDir is where fetched imports are kept unless told
+// otherwise: ~/.pumbaa/imports.
+func DefaultImportCacheDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".pumbaa", "imports")
+}
+
+// remoteFetcher downloads http(s) imports into a content-addressed cache.
+//
+// Each file is stored as objects/<sha256 of content>/<basename>, so identical
+// content fetched from different URLs is kept once and the basename survives
+// for the bundler to name the file by. urls/<sha256 of URL> records which
+// object a URL last resolved to; it is what offline mode reads.
+type remoteFetcher struct {
+	dir     string
+	offline bool
+	client  *http.Client
+}
+
+func newRemoteFetcher(opts RemoteImportOptions) *remoteFetcher {
+	dir := opts.CacheDir
+	if dir == "" {
+		dir = DefaultImportCacheDir()
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &remoteFetcher{dir: dir, offline: opts.Offline, client: client}
+}
+
+// fetch returns the local path of a copy of rawURL. Online, the URL is always
+// downloaded again — a branch URL moves — and the cache is refreshed.
+// Offline, the last copy is used.
+func (f *remoteFetcher) fetch(rawURL string) (string, error) {
+	if f.offline {
+		local, ok := f.lookup(rawURL)
+		if !ok {
+			return "", fmt.Errorf("%s is not in the import cache (%s) and offline mode is on", rawURL, f.dir)
+		}
+		return local, nil
+	}
+
+	content, err := f.download(rawURL)
+	if err != nil {
+		if _, cached := f.lookup(rawURL); cached {
+			return "", fmt.Errorf("%w (a cached copy exists; rerun with --offline to use it)", err)
+		}
+		return "", err
+	}
+	return f.store(rawURL, content)
+}
+
+func (f *remoteFetcher) download(rawURL string) ([]byte, error) {
+	resp, err := f.client.Get(rawURL) //nolint:gosec // the URL is an import the user's workflow names
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", rawURL, resp.Status)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRemoteImportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+	if len(content) > maxRemoteImportSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", rawURL, maxRemoteImportSize)
+	}
+	return content, nil
+}
+
+// store writes content under its hash and points rawURL at it.
+func (f *remoteFetcher) store(rawURL string, content []byte) (string, error) {
+	sum := sha256.Sum256(content)
+	objectDir := filepath.Join(f.dir, "objects", hex.EncodeToString(sum[:]))
+	local := filepath.Join(objectDir, importBaseName(rawURL))
+
+	if _, err := os.Stat(local); err != nil {
+		if err := os.MkdirAll(objectDir, 0o755); err != nil {
+			return "", fmt.Errorf("failed to create import cache: %w", err)
+		}
+		if err := os.WriteFile(local, content, 0o644); err != nil {
+			return "", fmt.Errorf("failed to cache %s: %w", rawURL, err)
+		}
+	}
+
+	rel, err := filepath.Rel(f.dir, local)
+	if err != nil {
+		return "", err
+	}
+	index := f.indexPath(rawURL)
+	if err := os.MkdirAll(filepath.Dir(index), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create import cache: %w", err)
+	}
+	if err := os.WriteFile(index, []byte(rel), 0o644); err != nil {
+		return "", fmt.Errorf("failed to index %s: %w", rawURL, err)
+	}
+	return local, nil
+}
+
+// lookup returns the cached copy of rawURL, if there is one.
+func (f *remoteFetcher) lookup(rawURL string) (string, bool) {
+	rel, err := os.ReadFile(f.indexPath(rawURL))
+	if err != nil {
+		return "", false
+	}
+	local := filepath.Join(f.dir, strings.TrimSpace(string(rel)))
+	if !isPathWithinDir(local, f.dir) {
+		return "", false
+	}
+	if _, err := os.Stat(local); err != nil {
+		return "", false
+	}
+	return local, true
+}
+
+func (f *remoteFetcher) indexPath(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(f.dir, "urls", hex.EncodeToString(sum[:]))
+}
+
+// resolveRemoteImport resolves an import written in a remote file against
+// that file's URL, so a relative import inside a fetched file is fetched from
+// beside it.
+func resolveRemoteImport(uri, importerURL string) (string, error) {
+	base, err := url.Parse(importerURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid import URL %s: %w", importerURL, err)
+	}
+	ref, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid import %s: %w", uri, err)
+	}
+	if ref.Scheme != "" && ref.Scheme != "http" && ref.Scheme != "https" {
+		return "", fmt.Errorf("%s imports %s, which is not an http(s) URL", importerURL, uri)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// importBaseName is the file name an import is known by: the last element of
+// a URL's path, or of a file path.
+func importBaseName(p string) string {
+	if isRemoteImport(p) {
+		if u, err := url.Parse(p); err == nil {
+			if name := path.Base(u.Path); name != "/" && name != "." {
+				return name
+			}
+		}
+		return "import.wdl"
+	}
+	return filepath.Base(p)
+}
+
+// RemoteImportReport is the outcome of WithRemoteImports.
+type RemoteImportReport struct {
+	// Fetched lists the URLs resolved, in the order they were reached.
+	Fetched []string
+	// Findings are the imports that could not be fetched, as warnings:
+	// Cromwell fetches remote imports itself and may well reach them.
+	Findings []Finding
+}
+
+// WithRemoteImports returns a copy of sources extended with the http(s)
+// imports of source, and of the files they import in turn, fetched through
+// the import cache as opts configures. Each fetched file is added under its
+// URL, and under its basename unless that name is taken, so relative imports
+// inside remote files resolve too. It touches the network unless opts.Offline
+// is set.
+func WithRemoteImports(source []byte, sources SourceSet, opts RemoteImportOptions) (SourceSet, *RemoteImportReport) {
+	out := make(SourceSet, len(sources))
+	for name, content := range sources {
+		out[name] = content
+	}
+	report := &RemoteImportReport{}
+	fetcher := newRemoteFetcher(opts)
+
+	type pending struct {
+		content  []byte
+		importer string // URL of a remote file; empty for a local one
+	}
+	queue := []pending{{content: source}}
+	visited := make(map[string]bool)
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for _, imp := range extractImportPaths(next.content) {
+			if next.importer == "" && !isRemoteImport(imp) {
+				// A local import is followed into the zip, which may
+				// import remote files itself.
+				base := filepath.Base(imp)
+				if content, ok := out[base]; ok && !visited[base] {
+					visited[base] = true
+					queue = append(queue, pending{content: content})
+				}
+				continue
+			}
+			target := imp
+			if next.importer != "" {
+				resolved, err := resolveRemoteImport(imp, next.importer)
+				if err != nil {
+					report.Findings = append(report.Findings, Finding{Severity: SeverityWarning, Message: err.Error()})
+					continue
+				}
+				target = resolved
+			}
+			if visited[target] {
+				continue
+			}
+			visited[target] = true
+
+			content, err := fetchContent(fetcher, target)
+			if err != nil {
+				report.Findings = append(report.Findings, Finding{Severity: SeverityWarning, Message: err.Error()})
+				continue
+			}
+			report.Fetched = append(report.Fetched, target)
+			out[target] = content
+			if _, taken := out[importBaseName(target)]; !taken {
+				out[importBaseName(target)] = content
+			}
+			queue = append(queue, pending{content: content, importer: target})
+		}
+	}
+	return out, report
+}
+
+// fetchContent fetches rawURL through the cache and reads the copy.
+func fetchContent(f *remoteFetcher, rawURL string) ([]byte, error) {
+	local, err := f.fetch(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(local) //nolint:gosec // a file inside the import cache
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cached copy of %s: %w", rawURL, err)
+	}
+	return content, nil
+}
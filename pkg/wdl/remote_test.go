@@ -0,0 +1,200 @@
+package wdl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// remoteWDLServer serves a pipeline whose remote file imports a sibling by a
+// relative path.
+func remoteWDLServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	files := map[string]string{
+		"/org/repo/main/align.wdl": `version 1.0
+
+import "lib/common.wdl" as common
+
+task Align {
+    command { echo align }
+}
+`,
+		"/org/repo/main/lib/common.wdl": `version 1.0
+
+task Common {
+    command { echo common }
+}
+`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeRemoteMain(t *testing.T, dir, serverURL string) string {
+	t.Helper()
+	main := `version 1.0
+
+import "` + serverURL + `/org/repo/main/align.wdl" as align
+
+workflow Main {
+    call align.Align {}
+}
+`
+	mainPath := filepath.Join(dir, "main.wdl")
+	if err := os.WriteFile(mainPath, []byte(main), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return mainPath
+}
+
+func TestCreateBundleFetchesRemoteImports(t *testing.T) {
+	server := remoteWDLServer(t)
+	tmpDir := t.TempDir()
+	mainPath := writeRemoteMain(t, tmpDir, server.URL)
+	cacheDir := filepath.Join(tmpDir, "cache")
+
+	opts := DefaultBundleOptions()
+	opts.Remote = RemoteImportOptions{CacheDir: cacheDir}
+	result, err := CreateBundleWithOptions(mainPath, filepath.Join(tmpDir, "out"), opts)
+	if err != nil {
+		t.Fatalf("CreateBundleWithOptions() error = %v", err)
+	}
+	want := []string{server.URL + "/org/repo/main/lib/common.wdl", server.URL + "/org/repo/main/align.wdl"}
+	if len(result.Dependencies) != 2 || result.Dependencies[0] != want[0] || result.Dependencies[1] != want[1] {
+		t.Errorf("Dependencies = %v, want %v", result.Dependencies, want)
+	}
+
+	mainContent, err := os.ReadFile(result.MainWDLPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(mainContent), `import "align.wdl" as align`) {
+		t.Errorf("remote import not rewritten:\n%s", mainContent)
+	}
+
+	extractDir := filepath.Join(tmpDir, "extracted")
+	if err := ExtractBundle(result.DependenciesZipPath, extractDir); err != nil {
+		t.Fatal(err)
+	}
+	align, err := os.ReadFile(filepath.Join(extractDir, "align.wdl"))
+	if err != nil {
+		t.Fatalf("align.wdl not bundled: %v", err)
+	}
+	if !strings.Contains(string(align), `import "common.wdl" as common`) {
+		t.Errorf("relative import inside the remote file not rewritten:\n%s", align)
+	}
+	if _, err := os.Stat(filepath.Join(extractDir, "common.wdl")); err != nil {
+		t.Errorf("common.wdl not bundled: %v", err)
+	}
+
+	// Offline, the cached copies are enough even with the server gone.
+	server.Close()
+	opts.Remote.Offline = true
+	if _, err := CreateBundleWithOptions(mainPath, filepath.Join(tmpDir, "offline"), opts); err != nil {
+		t.Errorf("offline bundle from cache error = %v", err)
+	}
+}
+
+func TestAnalyzeDependenciesRemoteErrors(t *testing.T) {
+	server := remoteWDLServer(t)
+	tmpDir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		main    string
+		offline bool
+		wantErr string
+	}{
+		{
+			name:    "offline cache miss",
+			main:    writeRemoteMain(t, tmpDir, server.URL),
+			offline: true,
+			wantErr: "not in the import cache",
+		},
+		{
+			name:    "missing remote file",
+			main:    writeRemoteMain(t, t.TempDir(), server.URL+"/gone"),
+			wantErr: "404",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AnalyzeDependenciesFromFileWithOptions(tt.main, RemoteImportOptions{
+				CacheDir: filepath.Join(t.TempDir(), "cache"),
+				Offline:  tt.offline,
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRemoteFetcherStoresByContent(t *testing.T) {
+	f := newRemoteFetcher(RemoteImportOptions{CacheDir: t.TempDir()})
+	a, err := f.store("https://example.org/a/tasks.wdl", []byte("version 1.0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := f.store("https://mirror.example.org/b/tasks.wdl", []byte("version 1.0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("identical content stored twice: %s and %s", a, b)
+	}
+	if got, ok := f.lookup("https://mirror.example.org/b/tasks.wdl"); !ok || got != b {
+		t.Errorf("lookup() = %q, %v; want %q", got, ok, b)
+	}
+}
+
+func TestWithRemoteImports(t *testing.T) {
+	server := remoteWDLServer(t)
+	main := []byte(`version 1.0
+
+import "` + server.URL + `/org/repo/main/align.wdl" as align
+import "local.wdl" as local
+`)
+	zipped := SourceSet{"local.wdl": []byte(`version 1.0
+
+task Local {
+    command { echo local }
+}
+`)}
+
+	sources, report := WithRemoteImports(main, zipped, RemoteImportOptions{CacheDir: t.TempDir()})
+	if len(report.Findings) != 0 {
+		t.Fatalf("Findings = %+v, want none", report.Findings)
+	}
+	if len(report.Fetched) != 2 {
+		t.Errorf("Fetched = %v, want align.wdl and the common.wdl it imports", report.Fetched)
+	}
+	if _, ok := sources.Get(server.URL + "/org/repo/main/align.wdl"); !ok {
+		t.Error("remote import does not resolve by its URL")
+	}
+	if _, ok := sources.Get("lib/common.wdl"); !ok {
+		t.Error("relative import inside the remote file does not resolve")
+	}
+	if _, ok := sources.Get("local.wdl"); !ok {
+		t.Error("zip source lost")
+	}
+	if len(zipped) != 1 {
+		t.Error("WithRemoteImports modified the sources it was given")
+	}
+
+	_, report = WithRemoteImports(main, zipped, RemoteImportOptions{CacheDir: t.TempDir(), Offline: true})
+	if len(report.Fetched) != 0 || len(report.Findings) != 1 || report.Findings[0].Severity != SeverityWarning {
+		t.Errorf("offline with an empty cache: %+v, want one warning", report)
+	}
+}
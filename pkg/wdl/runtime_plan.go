@@ -0,0 +1,324 @@
+package wdl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// CallRuntime is the runtime one call will request, worked out from the
+// submission's inputs before anything runs.
+type CallRuntime struct {
+	// Call is the call's path from the top-level workflow, as in CallGraph.
+	Call string
+	// Task is the name of the task being called.
+	Task string
+	// Attributes holds every runtime attribute that could be evaluated,
+	// rendered as the engine reads it: "8 GB", "local-disk 120 HDD", "4".
+	Attributes map[string]string
+	// Unevaluated explains, per attribute, why its value is only known once
+	// the run is under way — typically because it reads another call's output.
+	Unevaluated map[string]string
+	// Scattered marks a call inside a scatter. Its attributes are those of
+	// the first shard, which is representative unless the runtime is sized
+	// from the element itself.
+	Scattered bool
+	// Shards is how many instances of the call will run: 1 outside scatters,
+	// the product of the collection lengths of every enclosing scatter inside
+	// them. It is 0 when a collection's length cannot be known before the run,
+	// and for a skipped call.
+	Shards int
+	// Skipped marks a call inside a conditional that evaluates to false, or a
+	// scatter over an empty collection: it will not run at all.
+	Skipped bool
+	// Unresolved marks a call whose task definition could not be read.
+	Unresolved bool
+}
+
+// RuntimePlan is the evaluated runtime of every call in a workflow.
+type RuntimePlan struct {
+	Workflow string
+	// Calls are sorted by path.
+	Calls []CallRuntime
+}
+
+// EvaluateRuntimes evaluates each call's runtime section against an inputs
+// JSON, following values through workflow declarations, call inputs, task
+// defaults and private declarations. deps resolves imported tasks and
+// subworkflows; files answers size() and may be nil.
+//
+// Evaluation never fails as a whole: an attribute that cannot be computed is
+// reported in Unevaluated with the reason, so a caller can show what is known
+// and explain the rest.
+func EvaluateRuntimes(ctx context.Context, source []byte, deps SourceSet, inputsJSON []byte, files FileSizer) (*RuntimePlan, error) {
+	doc, err := ParseBytes(source)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Workflow == nil {
+		return nil, fmt.Errorf("no workflow found in the WDL (only tasks?); nothing to evaluate")
+	}
+	inputs, err := parseInputValues(inputsJSON)
+	if err != nil {
+		return nil, fmt.Errorf("inputs file is not valid JSON: %w", err)
+	}
+
+	p := &runtimePlanner{
+		eval:   NewEvaluator(files),
+		docs:   newDocumentSet(deps),
+		inputs: inputs,
+		root:   doc.Workflow.Name,
+		plan:   &RuntimePlan{Workflow: doc.Workflow.Name},
+	}
+	p.eval.DefineStructs(doc.Structs)
+
+	scope := NewScope()
+	p.bindInputs(scope, doc.Workflow.Inputs, p.root+".", nil)
+	p.workflow(ctx, doc, "", scope, block{shards: 1}, 0)
+
+	sort.Slice(p.plan.Calls, func(i, j int) bool { return p.plan.Calls[i].Call < p.plan.Calls[j].Call })
+	return p.plan, nil
+}
+
+// runtimePlanner walks a workflow and the workflows it calls, carrying values
+// from the inputs down to each task's runtime section.
+type runtimePlanner struct {
+	eval   *Evaluator
+	docs   *documentSet
+	inputs map[string]any
+	root   string
+	plan   *RuntimePlan
+}
+
+// block is what the enclosing scatters and conditionals say about a call.
+type block struct {
+	scattered bool
+	skipped   bool
+	// shards multiplies the lengths of the enclosing scatters; 0 once any of
+	// them is unknown.
+	shards int
+}
+
+// bindInputs binds a workflow's or task's inputs: a value the caller passed
+// wins, then the inputs JSON under the given key prefix, then the declared
+// default. A required input with none of those cannot be read.
+func (p *runtimePlanner) bindInputs(scope *Scope, decls []*ast.Declaration, keyPrefix string, passed map[string]bool) {
+	for _, d := range decls {
+		if d == nil || passed[d.Name] {
+			continue
+		}
+		if v, ok := p.inputs[keyPrefix+d.Name]; ok {
+			if v, err := p.eval.Coerce(v, d.Type); err != nil {
+				scope.Block(d.Name, err)
+			} else {
+				scope.Set(d.Name, v)
+			}
+			continue
+		}
+		switch {
+		case d.Expression != nil:
+			scope.Declare(d)
+		case d.Type != nil && d.Type.Optional:
+			scope.Set(d.Name, nil)
+		default:
+			scope.Block(d.Name, unevaluable("reads %s%s, which the inputs do not supply", keyPrefix, d.Name))
+		}
+	}
+}
+
+// workflow evaluates every call in a workflow, in the scope holding its
+// inputs.
+func (p *runtimePlanner) workflow(ctx context.Context, doc *ast.Document, prefix string, scope *Scope, outer block, depth int) {
+	if depth > maxImportDepth || doc.Workflow == nil {
+		return
+	}
+	p.eval.DefineStructs(doc.Structs)
+	wf := doc.Workflow
+
+	for _, d := range wf.Declarations {
+		scope.Declare(d)
+	}
+	for _, c := range collectCalls(wf) {
+		name := callName(c.call)
+		scope.Block(name, unevaluable("reads an output of %s%s, which only exists once it has run", prefix, name))
+	}
+
+	for _, c := range wf.Calls {
+		p.call(ctx, doc, c, prefix, scope, outer, depth)
+	}
+	for _, s := range wf.Scatters {
+		p.scatter(ctx, doc, s, prefix, scope, outer, depth)
+	}
+	for _, c := range wf.Conditionals {
+		p.conditional(ctx, doc, c, prefix, scope, outer, depth)
+	}
+}
+
+func (p *runtimePlanner) body(ctx context.Context, doc *ast.Document, elements []ast.WorkflowElement, prefix string, scope *Scope, blk block, depth int) {
+	for _, el := range elements {
+		if d, ok := el.(*ast.Declaration); ok {
+			scope.Declare(d)
+		}
+	}
+	for _, el := range elements {
+		switch e := el.(type) {
+		case *ast.Call:
+			p.call(ctx, doc, e, prefix, scope, blk, depth)
+		case *ast.Scatter:
+			p.scatter(ctx, doc, e, prefix, scope, blk, depth)
+		case *ast.Conditional:
+			p.conditional(ctx, doc, e, prefix, scope, blk, depth)
+		}
+	}
+}
+
+// scatter binds the iteration variable to the first element, so the body is
+// evaluated as its first shard would be, and multiplies the shard count by the
+// collection's length.
+func (p *runtimePlanner) scatter(ctx context.Context, doc *ast.Document, s *ast.Scatter, prefix string, scope *Scope, blk block, depth int) {
+	inner := scope.Child()
+	blk.scattered = true
+
+	collection, err := p.eval.Eval(ctx, s.Expression, scope)
+	items, isArray := collection.([]any)
+	switch {
+	case err != nil:
+		blk.shards = 0
+		inner.Block(s.Variable, err)
+	case !isArray:
+		blk.shards = 0
+		inner.Block(s.Variable, fmt.Errorf("scatters over a %s, not an Array", valueKind(collection)))
+	case len(items) == 0:
+		blk.shards = 0
+		blk.skipped = true
+		inner.Block(s.Variable, unevaluable("scatters over an empty collection"))
+	default:
+		blk.shards *= len(items)
+		inner.Set(s.Variable, items[0])
+	}
+	p.body(ctx, doc, s.Body, prefix, inner, blk, depth)
+}
+
+// conditional skips the body when its condition is known to be false. An
+// unknown condition is evaluated anyway: the calls may well run.
+func (p *runtimePlanner) conditional(ctx context.Context, doc *ast.Document, c *ast.Conditional, prefix string, scope *Scope, blk block, depth int) {
+	if v, err := p.eval.Eval(ctx, c.Condition, scope); err == nil {
+		if b, ok := v.(bool); ok && !b {
+			blk.skipped = true
+			blk.shards = 0
+		}
+	}
+	p.body(ctx, doc, c.Body, prefix, scope.Child(), blk, depth)
+}
+
+// call evaluates the call's inputs in the caller's scope, then either the
+// task's runtime section or, for a subworkflow, the subworkflow's calls.
+func (p *runtimePlanner) call(ctx context.Context, doc *ast.Document, c *ast.Call, prefix string, scope *Scope, blk block, depth int) {
+	namespace, target := splitTarget(c.Target)
+	path := prefix + callName(c)
+
+	callee := NewScope()
+	passed := make(map[string]bool, len(c.Inputs))
+	for name, expr := range c.Inputs {
+		passed[name] = true
+		v, err := p.eval.Eval(ctx, expr, scope)
+		if err != nil {
+			callee.Block(name, err)
+			continue
+		}
+		callee.Set(name, v)
+	}
+
+	task, sub := p.resolve(doc, namespace, target)
+	if sub != nil {
+		p.eval.DefineStructs(sub.Structs)
+		p.bindInputs(callee, sub.Workflow.Inputs, p.root+"."+path+".", passed)
+		p.workflow(ctx, sub, path+".", callee, blk, depth+1)
+		return
+	}
+
+	out := CallRuntime{
+		Call:        path,
+		Task:        target,
+		Attributes:  make(map[string]string),
+		Unevaluated: make(map[string]string),
+		Scattered:   blk.scattered,
+		Shards:      blk.shards,
+		Skipped:     blk.skipped,
+	}
+	if task == nil {
+		out.Unresolved = true
+		p.plan.Calls = append(p.plan.Calls, out)
+		return
+	}
+
+	// Values passed by the call were already coerced by the caller's own
+	// declarations; coercing to the task's types keeps an Int an Int.
+	for _, d := range task.Inputs {
+		if d != nil && passed[d.Name] {
+			if v, ok := callee.values[d.Name]; ok {
+				if v, err := p.eval.Coerce(v, d.Type); err != nil {
+					callee.Block(d.Name, err)
+				} else {
+					callee.Set(d.Name, v)
+				}
+			}
+		}
+	}
+	p.bindInputs(callee, task.Inputs, p.root+"."+path+".", passed)
+	for _, d := range task.Declarations {
+		callee.Declare(d)
+	}
+
+	for attr, expr := range task.Runtime {
+		v, err := p.eval.Eval(ctx, expr, callee)
+		if err != nil {
+			out.Unevaluated[attr] = err.Error()
+			continue
+		}
+		out.Attributes[attr] = formatRuntimeValue(v)
+	}
+	p.plan.Calls = append(p.plan.Calls, out)
+}
+
+// resolve finds a call's target: a task, a subworkflow, or neither when the
+// definition is not among the sources.
+func (p *runtimePlanner) resolve(doc *ast.Document, namespace, target string) (*ast.Task, *ast.Document) {
+	source := doc
+	if namespace != "" {
+		uri, ok := namespaces(doc)[namespace]
+		if !ok {
+			return nil, nil
+		}
+		imported, ok := p.docs.document(uri)
+		if !ok {
+			return nil, nil
+		}
+		source = imported
+	}
+	for _, t := range source.Tasks {
+		if t != nil && t.Name == target {
+			return t, nil
+		}
+	}
+	if namespace != "" && source.Workflow != nil && source.Workflow.Name == target {
+		return nil, source
+	}
+	return nil, nil
+}
+
+// formatRuntimeValue renders an attribute's value. Arrays — zones, a list of
+// disks — become the space-separated form the engine also accepts.
+func formatRuntimeValue(v any) string {
+	if items, ok := v.([]any); ok {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = formatValue(item)
+		}
+		return strings.Join(parts, " ")
+	}
+	return formatValue(v)
+}
@@ -0,0 +1,215 @@
+package wdl
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const runtimeWDL = `version 1.0
+
+import "lib.wdl" as lib
+
+workflow Align {
+  input {
+    File bam
+    Array[File] shards
+    Int mem_gb = 8
+    Boolean run_qc = false
+  }
+
+  Int extra_disk = 20
+
+  call Sort {
+    input:
+      bam = bam,
+      disk_gb = ceil(size(bam, "GB") * 2) + extra_disk
+  }
+
+  scatter (shard in shards) {
+    call lib.Call as CallShard { input: input_file = shard }
+  }
+
+  if (run_qc) {
+    call Sort as QC { input: bam = Sort.sorted, disk_gb = 10 }
+  }
+
+  call Merge { input: parts = CallShard.vcf, bam = Sort.sorted }
+}
+
+task Sort {
+  input {
+    File bam
+    Int disk_gb
+    Int mem_gb = 4
+  }
+  command <<< samtools sort ~{bam} >>>
+  runtime {
+    cpu: 2
+    memory: "~{mem_gb} GB"
+    disks: "local-disk ~{disk_gb} HDD"
+    docker: "samtools:1.19"
+  }
+  output { File sorted = "sorted.bam" }
+}
+
+task Merge {
+  input {
+    Array[File] parts
+    File bam
+  }
+  command <<< merge >>>
+  runtime {
+    memory: "2 GB"
+    disks: "local-disk ~{ceil(size(bam, 'GB')) + 10} SSD"
+  }
+  output { File merged = "merged.vcf" }
+}
+`
+
+const runtimeLib = `version 1.0
+
+task Call {
+  input {
+    File input_file
+    Int threads = 4
+  }
+  Int mem = threads * 2
+  command <<< call ~{input_file} >>>
+  runtime {
+    cpu: threads
+    memory: "~{mem} GB"
+    disks: "local-disk ~{ceil(size(input_file, 'GiB')) + 5} SSD"
+    zones: ["us-central1-a", "us-central1-b"]
+  }
+  output { File vcf = "out.vcf" }
+}
+`
+
+func TestEvaluateRuntimes(t *testing.T) {
+	files := &fakeSizes{sizes: map[string]int64{
+		"gs://b/sample.bam": 10_000_000_000,
+		"gs://b/shard0.bam": 2 << 30,
+		"gs://b/shard1.bam": 3 << 30,
+	}}
+	inputs := `{
+		"Align.bam": "gs://b/sample.bam",
+		"Align.shards": ["gs://b/shard0.bam", "gs://b/shard1.bam"],
+		"Align.CallShard.threads": 8
+	}`
+	deps := SourceSet{"lib.wdl": []byte(runtimeLib)}
+
+	plan, err := EvaluateRuntimes(context.Background(), []byte(runtimeWDL), deps, []byte(inputs), files)
+	if err != nil {
+		t.Fatalf("EvaluateRuntimes() error = %v", err)
+	}
+	calls := make(map[string]CallRuntime, len(plan.Calls))
+	for _, c := range plan.Calls {
+		calls[c.Call] = c
+	}
+
+	sort := calls["Sort"]
+	// The task's own default wins: the workflow's mem_gb is not passed down.
+	if got := sort.Attributes["memory"]; got != "4 GB" {
+		t.Errorf("Sort memory = %q, want 4 GB", got)
+	}
+	if got := sort.Attributes["disks"]; got != "local-disk 40 HDD" {
+		t.Errorf("Sort disks = %q, want local-disk 40 HDD", got)
+	}
+	if got := sort.Attributes["cpu"]; got != "2" {
+		t.Errorf("Sort cpu = %q, want 2", got)
+	}
+
+	shard := calls["CallShard"]
+	if !shard.Scattered {
+		t.Error("CallShard should be marked scattered")
+	}
+	if shard.Shards != 2 {
+		t.Errorf("CallShard shards = %d, want 2", shard.Shards)
+	}
+	if sort.Shards != 1 {
+		t.Errorf("Sort shards = %d, want 1", sort.Shards)
+	}
+	// threads comes from a call-level override in the inputs.
+	if got := shard.Attributes["cpu"]; got != "8" {
+		t.Errorf("CallShard cpu = %q, want 8 (call-level input override)", got)
+	}
+	if got := shard.Attributes["memory"]; got != "16 GB" {
+		t.Errorf("CallShard memory = %q, want 16 GB (private declaration)", got)
+	}
+	if got := shard.Attributes["disks"]; got != "local-disk 7 SSD" {
+		t.Errorf("CallShard disks = %q, want the first shard's local-disk 7 SSD", got)
+	}
+	if got := shard.Attributes["zones"]; got != "us-central1-a us-central1-b" {
+		t.Errorf("CallShard zones = %q", got)
+	}
+
+	if qc := calls["QC"]; !qc.Skipped || qc.Shards != 0 {
+		t.Errorf("QC sits under a false condition: skipped = %v, shards = %d", qc.Skipped, qc.Shards)
+	}
+
+	merge := calls["Merge"]
+	if got := merge.Attributes["memory"]; got != "2 GB" {
+		t.Errorf("Merge memory = %q, want 2 GB", got)
+	}
+	reason, ok := merge.Unevaluated["disks"]
+	if !ok || !strings.Contains(reason, "Sort") {
+		t.Errorf("Merge disks should be unevaluable because it reads Sort's output, got %q (ok=%v)", reason, ok)
+	}
+}
+
+func TestEvaluateRuntimesMissingImportIsUnresolved(t *testing.T) {
+	plan, err := EvaluateRuntimes(context.Background(), []byte(runtimeWDL), nil,
+		[]byte(`{"Align.bam": "gs://b/sample.bam", "Align.shards": ["x"]}`), nil)
+	if err != nil {
+		t.Fatalf("EvaluateRuntimes() error = %v", err)
+	}
+	for _, c := range plan.Calls {
+		if c.Call == "CallShard" && !c.Unresolved {
+			t.Error("a call into a missing import should be unresolved")
+		}
+		if c.Call == "Sort" {
+			if _, ok := c.Unevaluated["disks"]; !ok {
+				t.Error("size() without storage should leave the disk unevaluated")
+			}
+		}
+	}
+}
+
+func TestEvaluateRuntimesNestedScatterMultipliesShards(t *testing.T) {
+	src := `version 1.0
+workflow W {
+  input {
+    Array[String] samples
+    Array[Array[File]] lanes
+  }
+  scatter (s in samples) {
+    scatter (l in lanes) {
+      call T { input: x = s }
+    }
+  }
+  scatter (r in read_lines("chunks.txt")) {
+    call T as Late { input: x = r }
+  }
+}
+task T {
+  input { String x }
+  command <<< echo ~{x} >>>
+}
+`
+	inputs := `{"W.samples": ["a", "b", "c"], "W.lanes": [["l1"], ["l2"]]}`
+	plan, err := EvaluateRuntimes(context.Background(), []byte(src), nil, []byte(inputs), nil)
+	if err != nil {
+		t.Fatalf("EvaluateRuntimes() error = %v", err)
+	}
+	shards := make(map[string]int)
+	for _, c := range plan.Calls {
+		shards[c.Call] = c.Shards
+	}
+	if shards["T"] != 6 {
+		t.Errorf("T shards = %d, want 3 samples × 2 lanes = 6", shards["T"])
+	}
+	if shards["Late"] != 0 {
+		t.Errorf("Late shards = %d, want 0 (collection only known at run time)", shards["Late"])
+	}
+}
@@ -0,0 +1,215 @@
+package wdl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// SampleFiles is one sample's data files, sorted so that the first read of a
+// pair comes before the second.
+type SampleFiles struct {
+	Name  string
+	Files []string
+}
+
+// BatchInputs fills a workflow's inputs from a batch of samples, for one
+// inputs file covering them all. Only collections can hold a batch:
+//
+//   - Array[File] takes every file of every sample;
+//   - Array[String] named like a sample ("samples", "sample_ids") takes the
+//     sample names;
+//   - Array[X] takes one X per sample, and Map[String, X] one X per sample
+//     keyed by its name, for any X SampleInputs can fill: a struct, a Pair or
+//     an Array[File].
+//
+// It returns the values by qualified input name, ready for
+// ScaffoldOptions.Values. Inputs with a default are never filled.
+func BatchInputs(source []byte, sources SourceSet, samples []SampleFiles) (map[string]any, error) {
+	return fillInputs(source, sources, func(structs structTable, name string, t *ast.Type) (any, bool) {
+		return batchValue(structs, samples, name, t)
+	})
+}
+
+// SampleInputs fills a workflow's inputs from one sample, for an inputs file
+// per sample. The sample's files are handed out in declaration order: each
+// File input takes the next one, an Array[File] the rest, a Pair[File, File]
+// two; a String named like a sample ("sample", "sample_name", "name", "id")
+// takes the sample's name. Struct inputs are filled member by member the same
+// way, and an array of structs or Pairs gets a one-element list. Inputs
+// declared after the files run out keep their placeholder, so a workflow's
+// sample files should come before its reference files.
+func SampleInputs(source []byte, sources SourceSet, sample SampleFiles) (map[string]any, error) {
+	filler := &sampleFiller{sample: sample}
+	return fillInputs(source, sources, func(structs structTable, name string, t *ast.Type) (any, bool) {
+		filler.structs = structs
+		return filler.value(name, t, 0)
+	})
+}
+
+// fillInputs applies fill to each workflow input without a default, in
+// declaration order.
+func fillInputs(source []byte, sources SourceSet, fill func(structTable, string, *ast.Type) (any, bool)) (map[string]any, error) {
+	doc, err := ParseBytes(source)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Workflow == nil {
+		return nil, fmt.Errorf("no workflow found in the WDL (only tasks?); nothing to fill")
+	}
+
+	structs := collectStructs(doc, newDocumentSet(sources))
+	values := make(map[string]any)
+	for _, in := range doc.Workflow.Inputs {
+		if in == nil || in.Type == nil || in.Expression != nil {
+			continue
+		}
+		if value, ok := fill(structs, in.Name, in.Type); ok {
+			values[doc.Workflow.Name+"."+in.Name] = value
+		}
+	}
+	return values, nil
+}
+
+// batchValue is the value of a collection input holding every sample, or
+// false when t cannot hold a batch.
+func batchValue(structs structTable, samples []SampleFiles, name string, t *ast.Type) (any, bool) {
+	switch t.Base {
+	case "Array":
+		elem := t.ArrayType
+		if elem == nil {
+			return nil, false
+		}
+		switch {
+		case elem.Base == "File":
+			var files []string
+			for _, s := range samples {
+				files = append(files, s.Files...)
+			}
+			return files, len(files) > 0
+		case elem.Base == "String" && namesSample(name):
+			names := make([]string, len(samples))
+			for i, s := range samples {
+				names[i] = s.Name
+			}
+			return names, true
+		}
+		values := make([]any, 0, len(samples))
+		for _, s := range samples {
+			value, ok := perSampleValue(structs, s, elem)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, value)
+		}
+		return values, len(values) > 0
+	case "Map":
+		if t.MapKey == nil || t.MapKey.Base != "String" {
+			return nil, false
+		}
+		obj := make(orderedObject, 0, len(samples))
+		for _, s := range samples {
+			value, ok := perSampleValue(structs, s, t.MapValue)
+			if !ok {
+				return nil, false
+			}
+			obj = append(obj, orderedField{s.Name, value})
+		}
+		return obj, len(obj) > 0
+	}
+	return nil, false
+}
+
+// perSampleValue is one sample's element of a batch collection. A lone File
+// is not one: a batch of them is an Array[File], filled flat.
+func perSampleValue(structs structTable, sample SampleFiles, t *ast.Type) (any, bool) {
+	if t == nil || t.Base == "File" {
+		return nil, false
+	}
+	filler := &sampleFiller{structs: structs, sample: sample}
+	return filler.value("", t, 1)
+}
+
+// sampleFiller hands one sample's files out to the File slots of a type, in
+// the order it meets them.
+type sampleFiller struct {
+	structs structTable
+	sample  SampleFiles
+	// next is the first file no slot has taken yet.
+	next int
+}
+
+// value fills a value of type t declared as name, or reports false when t has
+// no slot for the sample's data. Parts of a compound value left unfilled get
+// their scaffold skeleton.
+func (f *sampleFiller) value(name string, t *ast.Type, depth int) (any, bool) {
+	if t == nil || depth > maxTypeDepth {
+		return nil, false
+	}
+	switch t.Base {
+	case "File":
+		if f.next >= len(f.sample.Files) {
+			return nil, false
+		}
+		f.next++
+		return f.sample.Files[f.next-1], true
+	case "String":
+		if namesSample(name) {
+			return f.sample.Name, true
+		}
+	case "Array":
+		if t.ArrayType != nil && t.ArrayType.Base == "File" && f.next < len(f.sample.Files) {
+			rest := append([]string(nil), f.sample.Files[f.next:]...)
+			f.next = len(f.sample.Files)
+			return rest, true
+		}
+		// A workflow taking a batch of structs or Pairs gets a batch of one.
+		if f.structs.isCompound(t.ArrayType) {
+			if value, ok := f.value(name, t.ArrayType, depth+1); ok {
+				return []any{value}, true
+			}
+		}
+	case "Pair":
+		left, leftOK := f.value("", t.PairLeft, depth+1)
+		right, rightOK := f.value("", t.PairRight, depth+1)
+		if !leftOK && !rightOK {
+			return nil, false
+		}
+		if !leftOK {
+			left = f.structs.memberSkeleton(t.PairLeft, depth)
+		}
+		if !rightOK {
+			right = f.structs.memberSkeleton(t.PairRight, depth)
+		}
+		return orderedObject{{"left", left}, {"right", right}}, true
+	default:
+		def, ok := f.structs[t.Base]
+		if !ok {
+			return nil, false
+		}
+		obj := make(orderedObject, 0, len(def.Members))
+		filled := false
+		for _, m := range def.Members {
+			if m == nil {
+				continue
+			}
+			value, ok := f.value(m.Name, m.Type, depth+1)
+			if ok {
+				filled = true
+			} else {
+				value = f.structs.memberSkeleton(m.Type, depth)
+			}
+			obj = append(obj, orderedField{m.Name, value})
+		}
+		return obj, filled
+	}
+	return nil, false
+}
+
+// namesSample reports whether a String declared as name holds a sample's
+// name.
+func namesSample(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.Contains(lower, "sample") || lower == "name" || lower == "id"
+}
@@ -0,0 +1,164 @@
+package wdl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const batchInputsWDL = `version 1.0
+
+struct Reads {
+    String name
+    File r1
+    File? r2
+    Int min_length
+}
+
+workflow Align {
+    input {
+        Array[Reads] batch
+        Array[File] all_fastqs
+        Array[String] sample_names
+        Array[Pair[File, File]] pairs
+        Map[String, Array[File]] by_sample
+        Array[String] tags
+        File reference
+        Int threads = 4
+    }
+}
+`
+
+const sampleInputsWDL = `version 1.0
+
+workflow Single {
+    input {
+        String sample_name
+        File fastq_r1
+        File? fastq_r2
+        File reference
+        Int threads = 4
+    }
+}
+`
+
+var testSamples = []SampleFiles{
+	{Name: "S1", Files: []string{"gs://b/S1_R1.fq.gz", "gs://b/S1_R2.fq.gz"}},
+	{Name: "S2", Files: []string{"gs://b/S2_R1.fq.gz", "gs://b/S2_R2.fq.gz"}},
+}
+
+// asJSON round-trips values through JSON, so ordered objects compare as maps.
+func asJSON(t *testing.T, v any) any {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return out
+}
+
+func TestBatchInputs(t *testing.T) {
+	got, err := BatchInputs([]byte(batchInputsWDL), nil, testSamples)
+	if err != nil {
+		t.Fatalf("BatchInputs() error = %v", err)
+	}
+
+	want := map[string]any{
+		"Align.batch": []any{
+			map[string]any{"name": "S1", "r1": "gs://b/S1_R1.fq.gz", "r2": "gs://b/S1_R2.fq.gz", "min_length": "<FILL: Int>"},
+			map[string]any{"name": "S2", "r1": "gs://b/S2_R1.fq.gz", "r2": "gs://b/S2_R2.fq.gz", "min_length": "<FILL: Int>"},
+		},
+		"Align.all_fastqs":   []any{"gs://b/S1_R1.fq.gz", "gs://b/S1_R2.fq.gz", "gs://b/S2_R1.fq.gz", "gs://b/S2_R2.fq.gz"},
+		"Align.sample_names": []any{"S1", "S2"},
+		"Align.pairs": []any{
+			map[string]any{"left": "gs://b/S1_R1.fq.gz", "right": "gs://b/S1_R2.fq.gz"},
+			map[string]any{"left": "gs://b/S2_R1.fq.gz", "right": "gs://b/S2_R2.fq.gz"},
+		},
+		"Align.by_sample": map[string]any{
+			"S1": []any{"gs://b/S1_R1.fq.gz", "gs://b/S1_R2.fq.gz"},
+			"S2": []any{"gs://b/S2_R1.fq.gz", "gs://b/S2_R2.fq.gz"},
+		},
+	}
+	if g := asJSON(t, got); !reflect.DeepEqual(g, asJSON(t, want)) {
+		t.Errorf("BatchInputs() =\n%v\nwant\n%v", g, want)
+	}
+}
+
+func TestSampleInputs(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		sample SampleFiles
+		want   map[string]any
+	}{
+		{
+			name:   "paired reads fill the File inputs in order",
+			sample: testSamples[0],
+			want: map[string]any{
+				"Single.sample_name": "S1",
+				"Single.fastq_r1":    "gs://b/S1_R1.fq.gz",
+				"Single.fastq_r2":    "gs://b/S1_R2.fq.gz",
+			},
+		},
+		{
+			name:   "single-end reads leave the second read unset",
+			sample: SampleFiles{Name: "S3", Files: []string{"gs://b/S3.fq.gz"}},
+			want: map[string]any{
+				"Single.sample_name": "S3",
+				"Single.fastq_r1":    "gs://b/S3.fq.gz",
+			},
+		},
+		{
+			name:   "an array of structs is a batch of one",
+			source: batchInputsWDL,
+			sample: testSamples[0],
+			want: map[string]any{
+				"Align.batch": []any{
+					map[string]any{"name": "S1", "r1": "gs://b/S1_R1.fq.gz", "r2": "gs://b/S1_R2.fq.gz", "min_length": "<FILL: Int>"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.source
+			if source == "" {
+				source = sampleInputsWDL
+			}
+			got, err := SampleInputs([]byte(source), nil, tt.sample)
+			if err != nil {
+				t.Fatalf("SampleInputs() error = %v", err)
+			}
+			if g := asJSON(t, got); !reflect.DeepEqual(g, asJSON(t, tt.want)) {
+				t.Errorf("SampleInputs() = %v, want %v", g, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaffoldInputsWithValues(t *testing.T) {
+	values, err := SampleInputs([]byte(sampleInputsWDL), nil, testSamples[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := ScaffoldInputs([]byte(sampleInputsWDL), ScaffoldOptions{Values: values})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The optional second read is included because it has a value; the
+	// reference keeps its placeholder.
+	want := `{
+  "Single.sample_name": "S1",
+  "Single.fastq_r1": "gs://b/S1_R1.fq.gz",
+  "Single.reference": "<FILL: File>",
+  "Single.fastq_r2": "gs://b/S1_R2.fq.gz"
+}
+`
+	if string(s.Template) != want {
+		t.Errorf("Template =\n%s\nwant\n%s", s.Template, want)
+	}
+}
@@ -0,0 +1,532 @@
+package wdl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// JSONSchemaDialect is the JSON Schema version InputsSchema generates.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Patterns a scalar written as a JSON string must match: a Map key always is
+// one, and an Int or Float may be quoted, which CheckInputs warns about and
+// Cromwell coerces.
+var scalarStringPatterns = map[string]string{
+	"Int":     `^-?[0-9]+$`,
+	"Float":   `^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`,
+	"Boolean": `^(true|false)$`,
+}
+
+// JSONSchema is the subset of JSON Schema (draft 2020-12) needed to describe
+// a workflow's inputs file. Validate checks a document against it.
+type JSONSchema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// WDLType is the declared WDL type, for forms that want to show it.
+	WDLType string      `json:"x-wdl-type,omitempty"`
+	Type    SchemaTypes `json:"type,omitempty"`
+	Default any         `json:"default,omitempty"`
+
+	MinLength *int   `json:"minLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+
+	Items    *JSONSchema `json:"items,omitempty"`
+	MinItems *int        `json:"minItems,omitempty"`
+
+	Properties           SchemaProperties `json:"properties,omitempty"`
+	Required             []string         `json:"required,omitempty"`
+	AdditionalProperties *JSONSchema      `json:"additionalProperties,omitempty"`
+	PropertyNames        *JSONSchema      `json:"propertyNames,omitempty"`
+
+	AnyOf []*JSONSchema          `json:"anyOf,omitempty"`
+	Defs  map[string]*JSONSchema `json:"$defs,omitempty"`
+
+	// Reject is the "false" schema, which no value matches; it is how
+	// "additionalProperties": false is represented.
+	Reject bool `json:"-"`
+}
+
+// MarshalJSON renders the schema, writing a rejecting schema as false.
+func (s *JSONSchema) MarshalJSON() ([]byte, error) {
+	if s.Reject {
+		return []byte("false"), nil
+	}
+	type plain JSONSchema
+	data, err := encodeJSON((*plain)(s))
+	return []byte(data), err
+}
+
+// UnmarshalJSON reads a schema, including the boolean true and false forms.
+func (s *JSONSchema) UnmarshalJSON(data []byte) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "false":
+		*s = JSONSchema{Reject: true}
+		return nil
+	case "true":
+		*s = JSONSchema{}
+		return nil
+	}
+	type plain JSONSchema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// SchemaTypes is a schema's "type", written as a string when there is only
+// one.
+type SchemaTypes []string
+
+// MarshalJSON renders a single type as a string and several as a list.
+func (t SchemaTypes) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON accepts both forms.
+func (t *SchemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaTypes{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// SchemaProperty is one named member of an object schema.
+type SchemaProperty struct {
+	Name   string
+	Schema *JSONSchema
+}
+
+// SchemaProperties keeps an object's members in declaration order, which a
+// form rendering the schema shows them in.
+type SchemaProperties []SchemaProperty
+
+// MarshalJSON renders the properties as an object, in order.
+func (p SchemaProperties) MarshalJSON() ([]byte, error) {
+	obj := make(orderedObject, len(p))
+	for i, prop := range p {
+		obj[i] = orderedField{prop.Name, prop.Schema}
+	}
+	return obj.MarshalJSON()
+}
+
+// UnmarshalJSON reads the properties object, keeping its key order.
+func (p *SchemaProperties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("properties must be an object")
+	}
+	var props SchemaProperties
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := tok.(string)
+		var schema JSONSchema
+		if err := dec.Decode(&schema); err != nil {
+			return fmt.Errorf("property %q: %w", name, err)
+		}
+		props = append(props, SchemaProperty{Name: name, Schema: &schema})
+	}
+	*p = props
+	return nil
+}
+
+// get returns the named property's schema, or nil.
+func (p SchemaProperties) get(name string) *JSONSchema {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Schema
+		}
+	}
+	return nil
+}
+
+// ParseJSONSchema reads a schema document, such as one InputsSchema wrote.
+func ParseJSONSchema(data []byte) (*JSONSchema, error) {
+	var s JSONSchema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	return &s, nil
+}
+
+// SchemaResult is a generated inputs schema.
+type SchemaResult struct {
+	Schema *JSONSchema
+	// UnresolvedCalls lists calls whose definition could not be read. Their
+	// inputs are missing from the schema, so it allows unknown keys.
+	UnresolvedCalls []string
+}
+
+// InputsSchema describes the inputs JSON of the workflow in source as a JSON
+// Schema: every workflow input and every input its calls leave unbound (as
+// "Workflow.call.input"), with its type, default, and parameter_meta
+// description. Required inputs are listed as required, structs become
+// definitions under $defs, and keys the workflow does not declare are
+// rejected. sources resolves imported tasks, subworkflows and structs.
+func InputsSchema(source []byte, sources SourceSet) (*SchemaResult, error) {
+	doc, err := ParseBytes(source)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Workflow == nil {
+		return nil, fmt.Errorf("no workflow found in the WDL (only tasks?); nothing to describe")
+	}
+
+	docs := newDocumentSet(sources)
+	b := &schemaBuilder{structs: collectStructs(doc, docs), defs: make(map[string]*JSONSchema)}
+	calls := collectCallInputs(doc, docs)
+
+	root := &JSONSchema{
+		Schema:      JSONSchemaDialect,
+		Title:       doc.Workflow.Name + " inputs",
+		Description: fmt.Sprintf("Inputs JSON for the WDL workflow %s.", doc.Workflow.Name),
+		Type:        SchemaTypes{"object"},
+	}
+	add := func(spec InputSpec, t *ast.Type) {
+		prop := b.typeSchema(t, 0)
+		prop.Description = spec.Description
+		prop.WDLType = spec.Type
+		if value := defaultOrNull(spec); value != nil {
+			prop.Default = value
+		}
+		root.Properties = append(root.Properties, SchemaProperty{Name: spec.Name, Schema: prop})
+		if spec.Required() {
+			root.Required = append(root.Required, spec.Name)
+		}
+	}
+	types := make(map[string]*ast.Type, len(doc.Workflow.Inputs))
+	for _, in := range doc.Workflow.Inputs {
+		if in != nil {
+			types[doc.Workflow.Name+"."+in.Name] = in.Type
+		}
+	}
+	for _, spec := range workflowInputSpecs(doc.Workflow) {
+		add(spec, types[spec.Name])
+	}
+	for _, ci := range calls.inputs {
+		add(ci.spec, ci.decl.Type)
+	}
+
+	if len(calls.unresolved) == 0 {
+		root.AdditionalProperties = &JSONSchema{Reject: true}
+	}
+	if len(b.defs) > 0 {
+		root.Defs = b.defs
+	}
+	return &SchemaResult{Schema: root, UnresolvedCalls: calls.unresolved}, nil
+}
+
+// schemaBuilder turns WDL types into schemas, defining each struct it meets
+// once under $defs.
+type schemaBuilder struct {
+	structs structTable
+	defs    map[string]*JSONSchema
+}
+
+func (b *schemaBuilder) typeSchema(t *ast.Type, depth int) *JSONSchema {
+	if t == nil || depth > maxTypeDepth {
+		return &JSONSchema{}
+	}
+	s := b.baseSchema(t, depth)
+	if !t.Optional {
+		return s
+	}
+	if len(s.Type) > 0 {
+		s.Type = append(s.Type, "null")
+		return s
+	}
+	if s.Ref != "" {
+		return &JSONSchema{AnyOf: []*JSONSchema{s, {Type: SchemaTypes{"null"}}}}
+	}
+	if len(s.AnyOf) > 0 {
+		s.AnyOf = append(s.AnyOf, &JSONSchema{Type: SchemaTypes{"null"}})
+		return s
+	}
+	// An unconstrained schema already accepts null.
+	return s
+}
+
+func (b *schemaBuilder) baseSchema(t *ast.Type, depth int) *JSONSchema {
+	one := 1
+	switch t.Base {
+	case "Int":
+		return quotableNumber("integer", scalarStringPatterns["Int"])
+	case "Float":
+		return quotableNumber("number", scalarStringPatterns["Float"])
+	case "Boolean":
+		return &JSONSchema{Type: SchemaTypes{"boolean"}}
+	case "String":
+		return &JSONSchema{Type: SchemaTypes{"string"}}
+	case "File", "Directory":
+		return &JSONSchema{Type: SchemaTypes{"string"}, MinLength: &one}
+	case "Array":
+		s := &JSONSchema{Type: SchemaTypes{"array"}, Items: b.typeSchema(t.ArrayType, depth+1)}
+		if t.NonEmpty {
+			s.MinItems = &one
+		}
+		return s
+	case "Map":
+		s := &JSONSchema{Type: SchemaTypes{"object"}, AdditionalProperties: b.typeSchema(t.MapValue, depth+1)}
+		if t.MapKey != nil {
+			if pattern, ok := scalarStringPatterns[t.MapKey.Base]; ok {
+				s.PropertyNames = &JSONSchema{Pattern: pattern}
+			}
+		}
+		return s
+	case "Pair":
+		return &JSONSchema{
+			Type: SchemaTypes{"object"},
+			Properties: SchemaProperties{
+				{Name: "left", Schema: b.typeSchema(t.PairLeft, depth+1)},
+				{Name: "right", Schema: b.typeSchema(t.PairRight, depth+1)},
+			},
+			Required:             []string{"left", "right"},
+			AdditionalProperties: &JSONSchema{Reject: true},
+		}
+	case "Object":
+		return &JSONSchema{Type: SchemaTypes{"object"}}
+	}
+
+	def, ok := b.structs[t.Base]
+	if !ok {
+		// A struct whose definition could not be read: accept anything.
+		return &JSONSchema{}
+	}
+	if _, defined := b.defs[t.Base]; !defined {
+		// Registered before the members so a self-reference cannot loop.
+		s := &JSONSchema{Title: def.Name, Type: SchemaTypes{"object"}, AdditionalProperties: &JSONSchema{Reject: true}}
+		b.defs[t.Base] = s
+		for _, m := range def.Members {
+			if m == nil || m.Type == nil {
+				continue
+			}
+			s.Properties = append(s.Properties, SchemaProperty{Name: m.Name, Schema: b.typeSchema(m.Type, depth+1)})
+			if !m.Type.Optional {
+				s.Required = append(s.Required, m.Name)
+			}
+		}
+	}
+	return &JSONSchema{Ref: "#/$defs/" + t.Base}
+}
+
+// quotableNumber accepts a JSON number of the given type, or a string holding
+// one, the same values CheckInputs lets through.
+func quotableNumber(typ, pattern string) *JSONSchema {
+	return &JSONSchema{AnyOf: []*JSONSchema{
+		{Type: SchemaTypes{typ}},
+		{Type: SchemaTypes{"string"}, Pattern: pattern},
+	}}
+}
+
+// Validate checks an inputs JSON against the schema, returning one error
+// finding per violation, named like CheckInputs names them
+// ("Wf.sample.reads", "Wf.lanes[0]", `Wf.refs["hg38"]`).
+func (s *JSONSchema) Validate(inputsJSON []byte) []Finding {
+	value, err := parseInputValues(inputsJSON)
+	if err != nil {
+		return []Finding{{Severity: SeverityError, Message: fmt.Sprintf("inputs file is not valid JSON: %v", err)}}
+	}
+	v := &schemaValidator{root: s, patterns: make(map[string]*regexp.Regexp)}
+	findings := v.validate(s, "", value, 0)
+	sortFindings(findings)
+	return findings
+}
+
+type schemaValidator struct {
+	root     *JSONSchema
+	patterns map[string]*regexp.Regexp
+}
+
+func (v *schemaValidator) validate(s *JSONSchema, path string, value any, depth int) []Finding {
+	if s == nil || depth > maxTypeDepth {
+		return nil
+	}
+	if s.Reject {
+		return schemaError(path, "is not declared by the schema")
+	}
+	if s.Ref != "" {
+		target := v.resolve(s.Ref)
+		if target == nil {
+			return schemaError(path, fmt.Sprintf("refers to %s, which the schema does not define", s.Ref))
+		}
+		return v.validate(target, path, value, depth+1)
+	}
+	if len(s.AnyOf) > 0 {
+		return v.validateAnyOf(s, path, value, depth)
+	}
+
+	if len(s.Type) > 0 && !typeAllows(s.Type, value) {
+		return schemaError(path, fmt.Sprintf("is a %s, but %s is expected", jsonKind(value), strings.Join(s.Type, " or ")))
+	}
+
+	var findings []Finding
+	switch val := value.(type) {
+	case string:
+		if s.MinLength != nil && len([]rune(val)) < *s.MinLength {
+			findings = append(findings, schemaError(path, "is empty")...)
+		}
+		if s.Pattern != "" && !v.matches(s.Pattern, val) {
+			findings = append(findings, schemaError(path, fmt.Sprintf("does not match the pattern %s", s.Pattern))...)
+		}
+	case []any:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			findings = append(findings, schemaError(path, fmt.Sprintf("has %d element(s), but at least %d are required", len(val), *s.MinItems))...)
+		}
+		for i, item := range val {
+			findings = append(findings, v.validate(s.Items, fmt.Sprintf("%s[%d]", path, i), item, depth+1)...)
+		}
+	case map[string]any:
+		findings = append(findings, v.validateObject(s, path, val, depth)...)
+	}
+	return findings
+}
+
+// validateObject checks required members, declared members, and the members
+// the schema leaves to additionalProperties and propertyNames.
+func (v *schemaValidator) validateObject(s *JSONSchema, path string, obj map[string]any, depth int) []Finding {
+	var findings []Finding
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			findings = append(findings, schemaError(memberPath(path, name), "is required but missing")...)
+		}
+	}
+	for _, name := range sortedKeys(obj) {
+		if s.PropertyNames != nil {
+			for _, f := range v.validate(s.PropertyNames, "", name, depth+1) {
+				findings = append(findings, Finding{Severity: SeverityError, Input: entryPath(path, name), Message: "key " + f.Message})
+			}
+		}
+		if prop := s.Properties.get(name); prop != nil {
+			findings = append(findings, v.validate(prop, memberPath(path, name), obj[name], depth+1)...)
+			continue
+		}
+		if s.AdditionalProperties != nil {
+			sub := entryPath(path, name)
+			if len(s.Properties) > 0 || s.AdditionalProperties.Reject {
+				sub = memberPath(path, name)
+			}
+			findings = append(findings, v.validate(s.AdditionalProperties, sub, obj[name], depth+1)...)
+		}
+	}
+	return findings
+}
+
+// validateAnyOf passes when one branch matches. Otherwise it reports the last
+// branch whose type fits the value, or the first branch when none does: for
+// "T or null" that is T's findings, and for a quotable Int a string's pattern
+// mismatch but a boolean's type mismatch.
+func (v *schemaValidator) validateAnyOf(s *JSONSchema, path string, value any, depth int) []Finding {
+	var best []Finding
+	for _, branch := range s.AnyOf {
+		findings := v.validate(branch, path, value, depth+1)
+		if len(findings) == 0 {
+			return nil
+		}
+		if best == nil || len(branch.Type) == 0 || typeAllows(branch.Type, value) {
+			best = findings
+		}
+	}
+	return best
+}
+
+func (v *schemaValidator) resolve(ref string) *JSONSchema {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil
+	}
+	return v.root.Defs[name]
+}
+
+func (v *schemaValidator) matches(pattern, s string) bool {
+	re, ok := v.patterns[pattern]
+	if !ok {
+		// An invalid pattern matches everything rather than failing the run.
+		re, _ = regexp.Compile(pattern)
+		v.patterns[pattern] = re
+	}
+	return re == nil || re.MatchString(s)
+}
+
+// typeAllows reports whether a decoded JSON value is one of the types.
+func typeAllows(types SchemaTypes, value any) bool {
+	for _, t := range types {
+		switch val := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && val == math.Trunc(val)) {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case []any:
+			if t == "array" {
+				return true
+			}
+		case map[string]any:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// memberPath names a struct member or top-level input; entryPath names a Map
+// entry.
+func memberPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func entryPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return fmt.Sprintf("%s[%q]", path, key)
+}
+
+func schemaError(path, message string) []Finding {
+	return []Finding{{Severity: SeverityError, Input: path, Message: message}}
+}
+
+// MarshalIndent renders the schema for writing to a file.
+func (s *JSONSchema) MarshalIndent() ([]byte, error) {
+	data, err := encodeJSON(s)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(data), "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,177 @@
+package wdl
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInputsSchema(t *testing.T) {
+	result, err := InputsSchema([]byte(structInputsWDL), SourceSet{"types.wdl": []byte(structInputsTypes)})
+	if err != nil {
+		t.Fatalf("InputsSchema() error = %v", err)
+	}
+	data, err := result.Schema.MarshalIndent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("schema is not valid JSON: %v\n%s", err, data)
+	}
+	if doc["additionalProperties"] != false {
+		t.Errorf("additionalProperties = %v, want false", doc["additionalProperties"])
+	}
+	if got := doc["required"]; !reflect.DeepEqual(got, []any{"Wf.sample", "Wf.tumor", "Wf.references"}) {
+		t.Errorf("required = %v", got)
+	}
+
+	// Properties keep declaration order, which forms rely on.
+	order := []string{`"Wf.sample"`, `"Wf.tumor"`, `"Wf.references"`, `"Wf.cohort"`}
+	last := -1
+	for _, key := range order {
+		i := strings.Index(string(data), key)
+		if i < last {
+			t.Errorf("%s is out of declaration order", key)
+		}
+		last = i
+	}
+
+	props := result.Schema.Properties
+	if got := props.get("Wf.sample").Ref; got != "#/$defs/Sample" {
+		t.Errorf("Wf.sample $ref = %q", got)
+	}
+	if got := props.get("Wf.cohort").Default; !reflect.DeepEqual(got, []any{}) {
+		t.Errorf("Wf.cohort default = %v, want []", got)
+	}
+	library := result.Schema.Defs["Sample"].Properties.get("library")
+	if len(library.AnyOf) != 2 || library.AnyOf[0].Ref != "#/$defs/Lib" {
+		t.Errorf("optional struct member should be Lib or null: %+v", library)
+	}
+	if _, ok := result.Schema.Defs["Lib"]; !ok {
+		t.Error("imported aliased struct Lib is not defined")
+	}
+
+	// The written schema reads back to the same thing.
+	parsed, err := ParseJSONSchema(data)
+	if err != nil {
+		t.Fatalf("ParseJSONSchema() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed, result.Schema) {
+		t.Errorf("schema does not round-trip:\n%s", data)
+	}
+}
+
+func TestJSONSchemaValidate(t *testing.T) {
+	result, err := InputsSchema([]byte(structInputsWDL), SourceSet{"types.wdl": []byte(structInputsTypes)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		inputs string
+		want   []string
+	}{
+		{
+			name: "valid",
+			inputs: `{
+  "Wf.sample": {"name": "s1", "reads": "gs://b/s1.fq", "library": null},
+  "Wf.tumor": {"left": "t1", "right": "gs://b/t1.bam"},
+  "Wf.references": {"hg38": "gs://b/hg38.fa"}
+}`,
+		},
+		{
+			name: "violations are named by path",
+			inputs: `{
+  "Wf.sample": {"name": "s1", "reads": "", "library": {"id": 7, "lanes": []}},
+  "Wf.tumor": {"left": "t1"},
+  "Wf.references": {"hg38": 1},
+  "Wf.cohort": [{"name": "s2", "reads": "gs://b/s2.fq", "extra": 1}],
+  "Wf.typo": true
+}`,
+			want: []string{
+				"Wf.cohort[0].extra: is not declared by the schema",
+				`Wf.references["hg38"]: is a number, but string is expected`,
+				"Wf.sample.library.id: is a number, but string is expected",
+				"Wf.sample.reads: is empty",
+				"Wf.tumor.right: is required but missing",
+				"Wf.typo: is not declared by the schema",
+			},
+		},
+		{
+			name:   "required inputs missing",
+			inputs: `{}`,
+			want: []string{
+				"Wf.sample: is required but missing",
+				"Wf.tumor: is required but missing",
+				"Wf.references: is required but missing",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range result.Schema.Validate([]byte(tt.inputs)) {
+				got = append(got, f.Input+": "+f.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings =\n%v\nwant\n%v", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestJSONSchemaValidateMapKeys(t *testing.T) {
+	source := "version 1.0\nworkflow Wf {\n    input {\n        Map[Int, Float] weights\n        Array[Int]+ sizes\n    }\n}\n"
+	result, err := InputsSchema([]byte(source), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range result.Schema.Validate([]byte(`{"Wf.weights": {"1": 0.5, "x": 1}, "Wf.sizes": [1.5]}`)) {
+		got = append(got, f.Input+": "+f.Message)
+	}
+	want := []string{
+		`Wf.sizes[0]: is a number, but integer is expected`,
+		`Wf.weights["x"]: key does not match the pattern ^-?[0-9]+$`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+}
+
+func TestJSONSchemaNumbersMatchCheckInputs(t *testing.T) {
+	source := "version 1.0\nworkflow Wf {\n    input {\n        Int n\n        Float? f\n    }\n}\n"
+	result, err := InputsSchema([]byte(source), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		inputs string
+		want   []string
+	}{
+		{inputs: `{"Wf.n": 4, "Wf.f": 0.5}`},
+		{inputs: `{"Wf.n": "4", "Wf.f": "-2.5e3"}`},
+		{inputs: `{"Wf.n": 4, "Wf.f": null}`},
+		{inputs: `{"Wf.n": "4.5"}`, want: []string{`Wf.n: does not match the pattern ^-?[0-9]+$`}},
+		{inputs: `{"Wf.n": true}`, want: []string{"Wf.n: is a boolean, but integer is expected"}},
+		{inputs: `{"Wf.n": 4, "Wf.f": "Inf"}`, want: []string{`Wf.f: does not match the pattern ^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.inputs, func(t *testing.T) {
+			var got []string
+			for _, f := range result.Schema.Validate([]byte(tt.inputs)) {
+				got = append(got, f.Input+": "+f.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+			if report := CheckInputs([]byte(source), []byte(tt.inputs)); report.HasErrors() != (len(tt.want) > 0) {
+				t.Errorf("CheckInputs findings = %v, schema findings = %v", report.Findings, got)
+			}
+		})
+	}
+}
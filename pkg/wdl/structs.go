@@ -0,0 +1,171 @@
+package wdl
+
+import (
+	"bytes"
+
+	"github.com/lmtani/pumbaa/pkg/wdl/ast"
+)
+
+// maxTypeDepth bounds how deep compound types are expanded. WDL forbids
+// recursive structs, but a malformed document must not loop forever.
+const maxTypeDepth = 16
+
+// structTable maps the struct names a workflow can use to their definitions:
+// its own structs and, since WDL puts imported structs in the global
+// namespace, those of every document it imports (renamed by "alias A as B").
+type structTable map[string]*ast.Struct
+
+// collectStructs builds the struct table for a document. Imports that cannot
+// be read contribute nothing; their struct-typed values are then left
+// unchecked rather than reported.
+func collectStructs(doc *ast.Document, docs *documentSet) structTable {
+	table := make(structTable)
+	visited := make(map[*ast.Document]bool)
+
+	var walk func(d *ast.Document, aliases map[string]string, depth int)
+	walk = func(d *ast.Document, aliases map[string]string, depth int) {
+		if d == nil || visited[d] || depth > maxImportDepth {
+			return
+		}
+		visited[d] = true
+		for _, s := range d.Structs {
+			if s == nil {
+				continue
+			}
+			name := s.Name
+			if alias, ok := aliases[name]; ok {
+				name = alias
+			}
+			if _, exists := table[name]; !exists {
+				table[name] = s
+			}
+		}
+		for _, imp := range d.Imports {
+			if imp == nil {
+				continue
+			}
+			imported, ok := docs.document(imp.URI)
+			if !ok {
+				continue
+			}
+			renamed := make(map[string]string, len(imp.Aliases))
+			for _, a := range imp.Aliases {
+				if a != nil {
+					renamed[a.Original] = a.Alias
+				}
+			}
+			walk(imported, renamed, depth+1)
+		}
+	}
+	walk(doc, nil, 0)
+	return table
+}
+
+// skeleton builds the scaffold value for a required input of type t: a
+// placeholder for plain types, and a nested object for structs, Pairs and
+// Maps whose leaves are placeholders, so the user sees the shape Cromwell
+// expects. Arrays of those are a one-element list of that object.
+// description, when set, is carried by a plain placeholder only.
+func (s structTable) skeleton(t *ast.Type, description string) any {
+	return s.skeletonAt(t, description, 0)
+}
+
+func (s structTable) skeletonAt(t *ast.Type, description string, depth int) any {
+	if t == nil || depth > maxTypeDepth {
+		return placeholder("value", description)
+	}
+	switch t.Base {
+	case "Pair":
+		return orderedObject{
+			{"left", s.memberSkeleton(t.PairLeft, depth)},
+			{"right", s.memberSkeleton(t.PairRight, depth)},
+		}
+	case "Map":
+		return orderedObject{
+			{placeholder(typeName(t.MapKey), ""), s.memberSkeleton(t.MapValue, depth)},
+		}
+	case "Array":
+		if s.isCompound(t.ArrayType) {
+			return []any{s.skeletonAt(t.ArrayType, "", depth+1)}
+		}
+	default:
+		if def, ok := s[t.Base]; ok {
+			obj := make(orderedObject, 0, len(def.Members))
+			for _, m := range def.Members {
+				if m != nil {
+					obj = append(obj, orderedField{m.Name, s.memberSkeleton(m.Type, depth)})
+				}
+			}
+			return obj
+		}
+	}
+	return placeholder(t.String(), description)
+}
+
+// memberSkeleton is the skeleton of a value nested in a compound type, where
+// an optional member is shown as null rather than asked for.
+func (s structTable) memberSkeleton(t *ast.Type, depth int) any {
+	if t != nil && t.Optional {
+		return nil
+	}
+	return s.skeletonAt(t, "", depth+1)
+}
+
+// isCompound reports whether values of t are JSON objects with a known shape.
+func (s structTable) isCompound(t *ast.Type) bool {
+	if t == nil {
+		return false
+	}
+	if t.Base == "Pair" || t.Base == "Map" {
+		return true
+	}
+	_, ok := s[t.Base]
+	return ok
+}
+
+// placeholder renders the sentinel string for a value still to be filled in.
+func placeholder(typ, description string) string {
+	p := PlaceholderPrefix + " " + typ
+	if description != "" {
+		p += " — " + description
+	}
+	return p + ">"
+}
+
+func typeName(t *ast.Type) string {
+	if t == nil {
+		return "value"
+	}
+	return t.String()
+}
+
+// orderedObject is a JSON object that keeps its keys in order, so a struct
+// skeleton lists members as the WDL declares them.
+type orderedObject []orderedField
+
+type orderedField struct {
+	key   string
+	value any
+}
+
+// MarshalJSON renders the fields in order.
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, err := encodeJSON(f.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := encodeJSON(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(key + ":" + value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,126 @@
+package wdl
+
+import (
+	"reflect"
+	"testing"
+)
+
+const structInputsWDL = `version 1.0
+
+import "types.wdl" alias Library as Lib
+
+struct Sample {
+    String name
+    File reads
+    Lib? library
+}
+
+workflow Wf {
+    input {
+        Sample sample
+        Pair[String, File] tumor
+        Map[String, File] references
+        Array[Sample] cohort = []
+    }
+}
+`
+
+const structInputsTypes = `version 1.0
+
+struct Library {
+    String id
+    Array[File] lanes
+}
+`
+
+func TestScaffoldInputsCompoundSkeletons(t *testing.T) {
+	s, err := ScaffoldInputs([]byte(structInputsWDL), ScaffoldOptions{})
+	if err != nil {
+		t.Fatalf("ScaffoldInputs() error = %v", err)
+	}
+	want := `{
+  "Wf.sample": {
+    "name": "<FILL: String>",
+    "reads": "<FILL: File>",
+    "library": null
+  },
+  "Wf.tumor": {
+    "left": "<FILL: String>",
+    "right": "<FILL: File>"
+  },
+  "Wf.references": {
+    "<FILL: String>": "<FILL: File>"
+  }
+}
+`
+	if string(s.Template) != want {
+		t.Errorf("Template =\n%s\nwant\n%s", s.Template, want)
+	}
+
+	// The untouched template is reported member by member, not accepted.
+	report := CheckInputs([]byte(structInputsWDL), s.Template)
+	if !report.HasErrors() {
+		t.Error("an unfilled skeleton should not pass the check")
+	}
+	if len(report.Files) != 0 {
+		t.Errorf("placeholders should not be collected as files: %v", report.Files)
+	}
+}
+
+func TestCheckInputsCompoundValues(t *testing.T) {
+	inputs := `{
+  "Wf.sample": {"name": "s1", "reads": 3, "extra": true, "library": {"id": "L1", "lanes": ["gs://b/l1.fq"]}},
+  "Wf.tumor": ["t1", "gs://b/t1.bam"],
+  "Wf.references": {"hg38": "gs://b/hg38.fa", "<FILL: String>": "gs://b/x.fa"},
+  "Wf.cohort": [{"name": "s2", "library": {"id": "L2", "lanez": []}}]
+}`
+	report := CheckInputsWithSources([]byte(structInputsWDL), []byte(inputs), SourceSet{"types.wdl": []byte(structInputsTypes)})
+
+	got := make(map[string]string)
+	for _, f := range report.Findings {
+		got[f.Input] = string(f.Severity) + ": " + f.Message
+	}
+	want := map[string]string{
+		"Wf.sample.reads":                 "error: is a number, but File is expected",
+		"Wf.sample.extra":                 `error: struct Sample has no member "extra"`,
+		"Wf.tumor":                        `error: is a list, but Pair[String, File] is expected as {"left": ..., "right": ...}`,
+		`Wf.references["<FILL: String>"]`: "error: still has the scaffold placeholder as its key — replace it with a real key",
+		"Wf.cohort[0].reads":              "error: required member of struct Sample is missing (type File)",
+		"Wf.cohort[0].library.lanes":      "error: required member of struct Library is missing (type Array[File])",
+		"Wf.cohort[0].library.lanez":      `error: struct Library has no member "lanez" — did you mean lanes?`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings =\n%v\nwant\n%v", got, want)
+	}
+
+	var files []string
+	for _, f := range report.Files {
+		files = append(files, f.Input+"="+f.Path)
+	}
+	wantFiles := []string{
+		"Wf.sample.library.lanes[0]=gs://b/l1.fq",
+		`Wf.references["hg38"]=gs://b/hg38.fa`,
+	}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("Files = %v, want %v", files, wantFiles)
+	}
+}
+
+func TestCheckInputsMapKeys(t *testing.T) {
+	source := "version 1.0\nworkflow Wf {\n    input {\n        Map[Int, Pair[File, Float]] lanes\n    }\n}\n"
+	inputs := `{"Wf.lanes": {"1": {"left": "gs://b/1.fq", "right": 0.5}, "two": {"left": "gs://b/2.fq", "right": 1}, "3": {"left": "gs://b/3.fq", "rigth": 1}}}`
+
+	report := CheckInputs([]byte(source), []byte(inputs))
+	var got []string
+	for _, f := range report.Findings {
+		got = append(got, f.Input+": "+f.Message)
+	}
+	want := []string{
+		`Wf.lanes["3"]: has no "right" member, which Pair[File, Float] requires`,
+		`Wf.lanes["3"]: has a "rigth" member, but a Pair only has left and right`,
+		`Wf.lanes["two"]: key is not a valid Int`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings =\n%v\nwant\n%v", got, want)
+	}
+}
@@ -28,6 +28,17 @@ type TaskSpec struct {
 	DynamicRuntime map[string]string
 	// InputDefaults holds statically-resolvable defaults for task inputs.
 	InputDefaults map[string]string
+	// RuntimeExpressions holds every runtime attribute as written, literal or
+	// not, so two versions of a task can be compared attribute by attribute.
+	RuntimeExpressions map[string]string
+	// InputTypes and OutputTypes are the declared types of the task's inputs
+	// and outputs ("File?", "Array[String]"): its signature, whose every entry
+	// Cromwell folds into the call-cache fingerprint.
+	InputTypes  map[string]string
+	OutputTypes map[string]string
+	// OutputExpressions holds each output's expression as written; Cromwell
+	// hashes it together with the output's type and name.
+	OutputExpressions map[string]string
 }
 
 // CommandHash reproduces the hash Cromwell records for this task's command
@@ -200,13 +211,18 @@ func TaskSpecsFromDocument(doc *ast.Document) map[string]TaskSpec {
 			continue
 		}
 		spec := TaskSpec{
-			Name:           t.Name,
-			Command:        t.Command,
-			Runtime:        make(map[string]string),
-			DynamicRuntime: make(map[string]string),
-			InputDefaults:  make(map[string]string),
+			Name:               t.Name,
+			Command:            t.Command,
+			Runtime:            make(map[string]string),
+			DynamicRuntime:     make(map[string]string),
+			InputDefaults:      make(map[string]string),
+			RuntimeExpressions: make(map[string]string),
+			InputTypes:         make(map[string]string),
+			OutputTypes:        make(map[string]string),
+			OutputExpressions:  make(map[string]string),
 		}
 		for attr, expr := range t.Runtime {
+			spec.RuntimeExpressions[attr] = ExpressionText(expr)
 			if v, ok := StaticValue(expr); ok {
 				spec.Runtime[attr] = v
 				continue
@@ -218,13 +234,30 @@ func TaskSpecsFromDocument(doc *ast.Document) map[string]TaskSpec {
 			}
 		}
 		for _, in := range t.Inputs {
-			if in == nil || in.Expression == nil {
+			if in == nil {
+				continue
+			}
+			if in.Type != nil {
+				spec.InputTypes[in.Name] = in.Type.String()
+			}
+			if in.Expression == nil {
 				continue
 			}
 			if v, ok := StaticValue(in.Expression); ok {
 				spec.InputDefaults[in.Name] = v
 			}
 		}
+		for _, o := range t.Outputs {
+			if o == nil {
+				continue
+			}
+			if o.Type != nil {
+				spec.OutputTypes[o.Name] = o.Type.String()
+			}
+			if o.Expression != nil {
+				spec.OutputExpressions[o.Name] = ExpressionText(o.Expression)
+			}
+		}
 		out[t.Name] = spec
 	}
 	return out
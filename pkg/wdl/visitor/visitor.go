@@ -119,9 +119,13 @@ func (v *WDLVisitor) VisitImport_alias(ctx *parser.Import_aliasContext) interfac
 	return &ast.Alias{}
 }
 
-// visitStringValue extracts the string content without quotes
+// visitStringValue extracts the string content without quotes.
+//
+// Like the command body, the text is taken from the source rather than from
+// GetText(), which drops the whitespace inside placeholders and turns
+// `~{if big then 'a' else 'b'}` into something no longer parseable.
 func (v *WDLVisitor) visitStringValue(ctx *parser.StringContext) string {
-	text := ctx.GetText()
+	text := sourceText(ctx)
 	// Remove surrounding quotes
 	if len(text) >= 2 {
 		if (text[0] == '"' && text[len(text)-1] == '"') ||
@@ -249,6 +253,16 @@ func (v *WDLVisitor) VisitTask_command(ctx *parser.Task_commandContext) interfac
 	return sb.String()
 }
 
+// sourceText returns the original text a rule spans, falling back to the
+// token reconstruction when the input stream is unavailable.
+func sourceText(ctx antlr.ParserRuleContext) string {
+	start, stop := ctx.GetStart(), ctx.GetStop()
+	if start == nil || stop == nil || start.GetInputStream() == nil {
+		return ctx.GetText()
+	}
+	return start.GetInputStream().GetText(start.GetStart(), stop.GetStop())
+}
+
 // commandBody returns the original text between a command block's delimiters,
 // supporting both the `<<< >>>` and `{ }` forms.
 func commandBody(ctx *parser.Task_commandContext) (string, bool) {
@@ -944,6 +958,7 @@ func (v *WDLVisitor) visitMapLiteral(ctx *parser.Map_literalContext) ast.Express
 		key := v.VisitExpr(exprs[i].(*parser.ExprContext)).(ast.Expression)
 		value := v.VisitExpr(exprs[i+1].(*parser.ExprContext)).(ast.Expression)
 		m.Entries[key] = value
+		m.Keys = append(m.Keys, key)
 	}
 	return m
 }
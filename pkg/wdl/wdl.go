@@ -101,3 +101,19 @@ func (l *errorListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol
 	line, column int, msg string, e antlr.RecognitionException) {
 	l.errors = append(l.errors, fmt.Sprintf("line %d:%d %s", line, column, msg))
 }
+
+// ParseExpression parses a single WDL expression, such as the body of a string
+// placeholder.
+//
+// The grammar has no entry point for a bare expression, so the text is parsed
+// as the value of a declaration in a synthetic workflow and read back out.
+func ParseExpression(text string) (ast.Expression, error) {
+	doc, err := ParseBytes([]byte("version 1.0\nworkflow expression {\n  String value = " + text + "\n}\n"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", text, err)
+	}
+	if doc.Workflow == nil || len(doc.Workflow.Declarations) != 1 {
+		return nil, fmt.Errorf("invalid expression %q", text)
+	}
+	return doc.Workflow.Declarations[0].Expression, nil
+}